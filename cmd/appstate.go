@@ -7,11 +7,9 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path"
-	"time"
+	"reflect"
 
 	"github.com/cosmos/relayer/v2/relayer"
-	"github.com/juju/fslock"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -30,6 +28,10 @@ type appState struct {
 	HomePath string
 	Debug    bool
 	Config   *Config
+
+	// configBase is the snapshot of the config file that Config was loaded from,
+	// which OverwriteConfig merges the changes of Config against.
+	configBase *configSnapshot
 }
 
 // AddPathFromFile modifies a.config.Paths to include the content stored in the given file.
@@ -126,41 +128,83 @@ func (a *appState) AddPathFromUserInput(ctx context.Context, stdin io.Reader, st
 	return a.Config.Paths.Add(name, path)
 }
 
-// OverwriteConfig overwrites the config files on disk with the serialization of cfg,
-// and it replaces a.Config with cfg.
+// OverwriteConfig writes cfg to the config file on disk, and it replaces a.Config with cfg.
+//
+// Changes made to the config file since a.Config was loaded are kept:
+// the changes of cfg relative to the loaded config are merged into the current config file,
+// and cfg wins where both changed the same chain, path or global settings.
+// In that case a.Config is reloaded from the merged config file.
 //
 // It is possible to use a brand new Config argument,
 // but typically the argument is a.Config.
 func (a *appState) OverwriteConfig(cfg *Config) error {
-	cfgPath := path.Join(a.HomePath, "config", "config.yaml")
+	cfgPath := configFilePath(a.HomePath)
 	if _, err := os.Stat(cfgPath); err != nil {
 		return fmt.Errorf("failed to check existence of config file at %s: %w", cfgPath, err)
 	}
 
-	a.Viper.SetConfigFile(cfgPath)
-	if err := a.Viper.ReadInConfig(); err != nil {
-		// TODO: if we failed to read in the new config, should we restore the old config?
-		return fmt.Errorf("failed to read config file at %s: %w", cfgPath, err)
-	}
-
 	// ensure validateConfig runs properly
 	if err := validateConfig(cfg); err != nil {
 		return fmt.Errorf("failed to validate config at %s: %w", cfgPath, err)
 	}
 
-	// marshal the new config
-	out, err := yaml.Marshal(cfg.Wrapped())
+	unlock, err := a.lockConfig()
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	// re-read the config file, it may have changed since a.Config was loaded.
+	file, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file at %s: %w", cfgPath, err)
+	}
+	current, err := decodeConfig(file)
+	if err != nil {
+		return fmt.Errorf("failed to read config file at %s: %w", cfgPath, err)
+	}
 
-	// Overwrite the config file.
-	if err := os.WriteFile(a.Viper.ConfigFileUsed(), out, 0600); err != nil {
-		return fmt.Errorf("failed to write config file at %s: %w", cfgPath, err)
+	theirs, err := snapshotConfig(current.Wrapped())
+	if err != nil {
+		return err
+	}
+	ours, err := snapshotConfig(cfg.Wrapped())
+	if err != nil {
+		return err
+	}
+	base := a.configBase
+	if base == nil {
+		// Without the loaded config, cfg replaces the config file.
+		base = theirs
+	}
+	merged := mergeConfig(a.Log, base, ours, theirs)
+
+	// round trip the merged config through the config types to write it in their field order.
+	bz, err := yaml.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	mergedCfg, err := decodeConfig(bz)
+	if err != nil {
+		return fmt.Errorf("failed to merge config with config file at %s: %w", cfgPath, err)
+	}
+	out, err := yaml.Marshal(mergedCfg.Wrapped())
+	if err != nil {
+		return err
+	}
+
+	if err := a.writeConfigLocked(out); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(merged, ours) {
+		// Write the merged config back into the app state.
+		return a.loadConfig(io.Discard, out)
 	}
 
 	// Write the config back into the app state.
 	a.Config = cfg
+	a.configBase = ours
 	return nil
 }
 
@@ -176,20 +220,36 @@ func (a *appState) OverwriteConfigOnTheFly(
 		return errors.New("empty path name not allowed")
 	}
 
+	return a.UpdateConfigOnTheFly(cmd, func(cfg *Config) error {
+		path, ok := cfg.Paths[pathName]
+		if !ok {
+			return fmt.Errorf("config does not exist for that path: %s", pathName)
+		}
+		if clientSrc != "" {
+			path.Src.ClientID = clientSrc
+		}
+		if clientDst != "" {
+			path.Dst.ClientID = clientDst
+		}
+		if connectionSrc != "" {
+			path.Src.ConnectionID = connectionSrc
+		}
+		if connectionDst != "" {
+			path.Dst.ConnectionID = connectionDst
+		}
+		return nil
+	})
+}
+
+// UpdateConfigOnTheFly applies update to the config file concurrently,
+// locking to read, modify, then write the config.
+func (a *appState) UpdateConfigOnTheFly(cmd *cobra.Command, update func(cfg *Config) error) error {
 	// use lock file to guard concurrent access to config.yaml
-	lockFilePath := path.Join(a.HomePath, "config", "config.lock")
-	lock := fslock.New(lockFilePath)
-	err := lock.LockWithTimeout(10 * time.Second)
+	unlock, err := a.lockConfig()
 	if err != nil {
-		return fmt.Errorf("failed to acquire config lock: %w", err)
+		return err
 	}
-	defer func() {
-		if err := lock.Unlock(); err != nil {
-			a.Log.Error("error unlocking config file lock, please manually delete",
-				zap.String("filepath", lockFilePath),
-			)
-		}
-	}()
+	defer unlock()
 
 	// load config from file and validate it. don't want to miss
 	// any changes that may have been made while unlocked.
@@ -197,21 +257,8 @@ func (a *appState) OverwriteConfigOnTheFly(
 		return fmt.Errorf("failed to initialize config from file: %w", err)
 	}
 
-	path, ok := a.Config.Paths[pathName]
-	if !ok {
-		return fmt.Errorf("config does not exist for that path: %s", pathName)
-	}
-	if clientSrc != "" {
-		path.Src.ClientID = clientSrc
-	}
-	if clientDst != "" {
-		path.Dst.ClientID = clientDst
-	}
-	if connectionSrc != "" {
-		path.Src.ConnectionID = connectionSrc
-	}
-	if connectionDst != "" {
-		path.Dst.ConnectionID = connectionDst
+	if err := update(a.Config); err != nil {
+		return err
 	}
 
 	// marshal the new config
@@ -220,12 +267,10 @@ func (a *appState) OverwriteConfigOnTheFly(
 		return err
 	}
 
-	cfgPath := a.Viper.ConfigFileUsed()
-
-	// Overwrite the config file.
-	if err := os.WriteFile(cfgPath, out, 0600); err != nil {
-		return fmt.Errorf("failed to write config file at %s: %w", cfgPath, err)
+	if err := a.writeConfigLocked(out); err != nil {
+		return err
 	}
 
-	return nil
+	a.configBase, err = snapshotConfig(a.Config.Wrapped())
+	return err
 }
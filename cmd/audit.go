@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cosmos/relayer/v2/relayer/audit"
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+func auditCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the transaction audit log written by 'rly start --audit-log'",
+	}
+
+	cmd.AddCommand(
+		auditSummarizeCmd(a),
+	)
+
+	return cmd
+}
+
+// auditSummarizeCmd represents the `audit summarize` command
+func auditSummarizeCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "summarize audit_log_file",
+		Aliases: []string{"sum"},
+		Short:   "Aggregate the fees, gas and packets in an audit log by day, path and channel",
+		Args:    withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s audit summarize ~/.relayer/audit.jsonl
+$ %s audit sum ~/.relayer/audit.jsonl --json`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			entries, err := audit.ReadEntries(f)
+			if err != nil {
+				return err
+			}
+
+			rows := audit.Summarize(entries, a.Config.pathForClient, a.Config.pathForChannel(cmd.Context()))
+
+			if jsn, _ := cmd.Flags().GetBool(flagJSON); jsn {
+				out, err := json.Marshal(rows)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "DAY\tPATH\tCHAIN\tCHANNEL\tTXS\tFAILED\tPACKETS\tGAS USED\tGAS WANTED\tFEES")
+			for _, r := range rows {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\n",
+					r.Day, orDash(r.Path), r.ChainID, orDash(r.Channel),
+					r.Txs, r.FailedTxs, r.Packets, r.GasUsed, r.GasWanted, orDash(r.Fees.String()),
+				)
+			}
+			return w.Flush()
+		},
+	}
+
+	return jsonFlag(a.Viper, cmd)
+}
+
+// pathForClient returns the name of the configured path which uses the client on the chain,
+// or an empty string if there is no such path.
+func (c *Config) pathForClient(chainID, clientID string) string {
+	if c == nil {
+		return ""
+	}
+	for name, p := range c.Paths {
+		if p.Src.ChainID == chainID && p.Src.ClientID == clientID {
+			return name
+		}
+		if p.Dst.ChainID == chainID && p.Dst.ClientID == clientID {
+			return name
+		}
+	}
+	return ""
+}
+
+// pathForChannel returns a resolver for the name of the configured path which uses a channel on a chain.
+// The client of the channel is queried from the chain through the channel's connection and looked up with pathForClient.
+// Results are cached per channel, and channels which cannot be queried resolve to an empty string.
+func (c *Config) pathForChannel(ctx context.Context) audit.ChannelPathResolver {
+	type channelKey struct {
+		chainID, portID, channelID string
+	}
+	cache := make(map[channelKey]string)
+
+	return func(chainID, portID, channelID string) string {
+		if c == nil || channelID == "" {
+			return ""
+		}
+		k := channelKey{chainID: chainID, portID: portID, channelID: channelID}
+		if p, ok := cache[k]; ok {
+			return p
+		}
+
+		var path string
+		if clientID := c.channelClient(ctx, chainID, portID, channelID); clientID != "" {
+			path = c.pathForClient(chainID, clientID)
+		}
+		cache[k] = path
+		return path
+	}
+}
+
+// channelClient queries the client of a channel on a chain through the channel's connection,
+// returning an empty string if the chain is not configured or the channel cannot be queried.
+func (c *Config) channelClient(ctx context.Context, chainID, portID, channelID string) string {
+	chain, err := c.Chains.Get(chainID)
+	if err != nil {
+		return ""
+	}
+	channel, err := chain.ChainProvider.QueryChannel(ctx, 0, channelID, portID)
+	if err != nil || channel.Channel == nil || len(channel.Channel.ConnectionHops) == 0 {
+		return ""
+	}
+	connection, err := chain.ChainProvider.QueryConnection(ctx, 0, channel.Channel.ConnectionHops[0])
+	if err != nil || connection.Connection == nil {
+		return ""
+	}
+	return connection.Connection.ClientId
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
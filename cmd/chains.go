@@ -14,7 +14,6 @@ import (
 	"github.com/cosmos/relayer/v2/relayer"
 	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
 	"github.com/spf13/cobra"
-	registry "github.com/strangelove-ventures/lens/client/chain_registry"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -158,7 +157,11 @@ func chainsRegistryList(a *appState) *cobra.Command {
 				return err
 			}
 
-			chains, err := registry.DefaultChainRegistry(a.Log).ListChains(cmd.Context())
+			chainRegistry, err := a.chainRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			chains, err := chainRegistry.ListChains(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -188,7 +191,7 @@ func chainsRegistryList(a *appState) *cobra.Command {
 			return nil
 		},
 	}
-	return yamlFlag(a.Viper, jsonFlag(a.Viper, cmd))
+	return registryFlags(a.Viper, yamlFlag(a.Viper, jsonFlag(a.Viper, cmd)))
 }
 
 func chainsListCmd(a *appState) *cobra.Command {
@@ -275,8 +278,9 @@ func chainsAddCmd(a *appState) *cobra.Command {
 		Args: withUsage(cobra.MinimumNArgs(0)),
 		Example: fmt.Sprintf(` $ %s chains add cosmoshub
  $ %s chains add cosmoshub osmosis
+ $ %s chains add --registry-dir ~/chain-registry --registry-commit 3f1a2b4 cosmoshub osmosis
  $ %s chains add --file chains/ibc0.json ibc0
- $ %s chains add --url https://relayer.com/ibc0.json ibc0`, appName, appName, appName, appName),
+ $ %s chains add --url https://relayer.com/ibc0.json ibc0`, appName, appName, appName, appName, appName),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			file, url, err := getAddInputs(cmd)
 			if err != nil {
@@ -311,7 +315,11 @@ func chainsAddCmd(a *appState) *cobra.Command {
 					return err
 				}
 			default:
-				if err := addChainsFromRegistry(cmd.Context(), a, args); err != nil {
+				chainRegistry, err := a.chainRegistry(cmd)
+				if err != nil {
+					return err
+				}
+				if err := addChainsFromRegistry(cmd.Context(), a, chainRegistry, args); err != nil {
 					return err
 				}
 			}
@@ -423,8 +431,7 @@ func addChainFromURL(a *appState, chainName string, rawurl string) error {
 	return nil
 }
 
-func addChainsFromRegistry(ctx context.Context, a *appState, chains []string) error {
-	chainRegistry := registry.DefaultChainRegistry(a.Log)
+func addChainsFromRegistry(ctx context.Context, a *appState, chainRegistry *chainRegistry, chains []string) error {
 
 	var existed, failed, added []string
 
@@ -450,7 +457,7 @@ func addChainsFromRegistry(ctx context.Context, a *appState, chains []string) er
 			continue
 		}
 
-		chainConfig, err := chainInfo.GetChainConfig(ctx)
+		chainConfig, err := chainRegistry.ChainConfig(ctx, chainInfo)
 		if err != nil {
 			a.Log.Warn(
 				"Error generating chain config",
@@ -477,6 +484,10 @@ func addChainsFromRegistry(ctx context.Context, a *appState, chains []string) er
 			SignModeStr:    chainConfig.SignModeStr,
 			ExtraCodecs:    chainConfig.ExtraCodecs,
 		}
+		// Chains with the Ethereum coin type use eth_secp256k1 keys.
+		if chainInfo.Slip44 == int(ethCoinType) {
+			pcfg.KeyAlgorithm = cosmos.KeyAlgorithmEthSecp256k1
+		}
 
 		prov, err := pcfg.NewProvider(
 			a.Log.With(zap.String("provider_type", "cosmos")),
@@ -26,11 +26,14 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/alert"
 	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/chains/plugin"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -47,6 +50,8 @@ func configCmd(a *appState) *cobra.Command {
 	cmd.AddCommand(
 		configShowCmd(a),
 		configInitCmd(a),
+		configRollbackCmd(a),
+		configImportHermesCmd(a),
 	)
 	return cmd
 }
@@ -143,22 +148,10 @@ $ %s cfg i`, appName, defaultHome, appName)),
 					}
 				}
 
-				// Then create the file...
-				f, err := os.Create(cfgPath)
-				if err != nil {
-					return err
-				}
-				defer f.Close()
-
 				memo, _ := cmd.Flags().GetString(flagMemo)
 
-				// And write the default config to that location...
-				if _, err = f.Write(defaultConfigYAML(memo)); err != nil {
-					return err
-				}
-
-				// And return no error...
-				return nil
+				// Then write the default config to that location...
+				return a.writeConfig(defaultConfigYAML(memo))
 			}
 
 			// Otherwise, the config file exists, and an error is returned...
@@ -169,6 +162,75 @@ $ %s cfg i`, appName, defaultHome, appName)),
 	return cmd
 }
 
+// Command for restoring a previous version of the config file
+func configRollbackCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [version]",
+		Short: "Restores a previous version of the configuration file",
+		Long: strings.TrimSpace(fmt.Sprintf(`Restores a previous version of the configuration file.
+
+Every change to the configuration file keeps the replaced file as a previous version,
+up to the %d most recent ones. Versions are numbered from 1, the most recent, and
+the most recent version is restored by default. The restored configuration file
+replaces the current one, which is kept as the most recent version.`, configVersionsKept)),
+		Args: withUsage(cobra.MaximumNArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config rollback --list
+$ %s config rollback
+$ %s cfg rollback 3`, appName, appName, appName)),
+		// The current config is not needed to restore a previous one, and may be what is being rolled back.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := cmd.Root().PersistentPreRunE(cmd, args); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Ignoring invalid current config: %v\n", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			versions, err := a.configVersions()
+			if err != nil {
+				return err
+			}
+
+			list, err := cmd.Flags().GetBool(flagList)
+			if err != nil {
+				return err
+			}
+			if list {
+				if len(versions) == 0 {
+					fmt.Fprintln(cmd.ErrOrStderr(), "no previous config versions found")
+				}
+				for i, v := range versions {
+					fmt.Fprintf(cmd.OutOrStdout(), "%d: replaced %s (%s)\n", i+1, v.Replaced.Format(time.RFC3339), v.Path)
+				}
+				return nil
+			}
+
+			n := 1
+			if len(args) == 1 {
+				if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+					return fmt.Errorf("invalid version %q: expected a version number from %s config rollback --list", args[0], appName)
+				}
+			}
+			if n > len(versions) {
+				return fmt.Errorf("config version %d does not exist, there are %d previous versions", n, len(versions))
+			}
+			v := versions[n-1]
+
+			bz, err := os.ReadFile(v.Path)
+			if err != nil {
+				return err
+			}
+			if err := a.writeConfig(bz); err != nil {
+				return fmt.Errorf("failed to restore config version %d: %w", n, err)
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Restored config version %d, replaced %s\n", n, v.Replaced.Format(time.RFC3339))
+			return nil
+		},
+	}
+	return listFlag(a.Viper, cmd)
+}
+
 // addChainsFromDirectory finds all JSON-encoded config files in dir,
 // and optimistically adds them to a's chains.
 //
@@ -341,6 +403,7 @@ type ProviderConfigYAMLWrapper struct {
 func (pcw *ProviderConfigWrapper) UnmarshalJSON(data []byte) error {
 	customTypes := map[string]reflect.Type{
 		"cosmos": reflect.TypeOf(cosmos.CosmosProviderConfig{}),
+		"plugin": reflect.TypeOf(plugin.ProviderConfig{}),
 	}
 	val, err := UnmarshalJSONProviderConfig(data, customTypes)
 	if err != nil {
@@ -393,6 +456,8 @@ func (iw *ProviderConfigYAMLWrapper) UnmarshalYAML(n *yaml.Node) error {
 	switch iw.Type {
 	case "cosmos":
 		iw.Value = new(cosmos.CosmosProviderConfig)
+	case "plugin":
+		iw.Value = new(plugin.ProviderConfig)
 	default:
 		return fmt.Errorf("%s is an invalid chain type, check your config file", iw.Type)
 	}
@@ -450,6 +515,14 @@ type GlobalConfig struct {
 	Timeout        string `yaml:"timeout" json:"timeout"`
 	Memo           string `yaml:"memo" json:"memo"`
 	LightCacheSize int    `yaml:"light-cache-size" json:"light-cache-size"`
+
+	// Alerts configures notifications for operational events while relaying. Nil disables alerting.
+	Alerts *alert.Config `yaml:"alerts,omitempty" json:"alerts,omitempty"`
+
+	// RegistryDir is a local checkout of the chain-registry that chains and paths are read from instead of GitHub.
+	RegistryDir string `yaml:"registry-dir,omitempty" json:"registry-dir,omitempty"`
+	// RegistryCommit pins the chain-registry commit that chains and paths are read from.
+	RegistryCommit string `yaml:"registry-commit,omitempty" json:"registry-commit,omitempty"`
 }
 
 // newDefaultGlobalConfig returns a global config with defaults set
@@ -525,6 +598,37 @@ func (c *Config) AddPath(name string, path *relayer.Path) (err error) {
 	return nil
 }
 
+// ChangeChainID modifies c in-place to change the chain ID of the chain with oldChainID,
+// and of the path ends on it, to newChainID, e.g. after an upgrade changed its revision.
+func (c *Config) ChangeChainID(oldChainID, newChainID string) error {
+	found := false
+	for _, chain := range c.Chains {
+		if chain.ChainID() != oldChainID {
+			continue
+		}
+		p, ok := chain.ChainProvider.(*cosmos.CosmosProvider)
+		if !ok {
+			return fmt.Errorf("cannot change the chain ID of %s chain %s", chain.ChainProvider.Type(), oldChainID)
+		}
+		p.PCfg.ChainID = newChainID
+		chain.Chainid = newChainID
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("chain with chain ID %s not found in config", oldChainID)
+	}
+
+	for _, p := range c.Paths {
+		if p.Src.ChainID == oldChainID {
+			p.Src.ChainID = newChainID
+		}
+		if p.Dst.ChainID == oldChainID {
+			p.Dst.ChainID = newChainID
+		}
+	}
+	return nil
+}
+
 // DeleteChain modifies c in-place to remove any chains that have the given name.
 func (c *Config) DeleteChain(chain string) {
 	delete(c.Chains, chain)
@@ -537,6 +641,12 @@ func validateConfig(c *Config) error {
 		return fmt.Errorf("did you remember to run 'rly config init' error:%w", err)
 	}
 
+	if c.Global.Alerts != nil {
+		if err := c.Global.Alerts.Validate(); err != nil {
+			return fmt.Errorf("invalid alerts config: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -566,21 +676,17 @@ func initConfig(cmd *cobra.Command, a *appState) error {
 		return err
 	}
 
-	// unmarshall them into the wrapper struct
-	cfgWrapper := &ConfigInputWrapper{}
-	err = yaml.Unmarshal(file, cfgWrapper)
+	return a.loadConfig(cmd.ErrOrStderr(), file)
+}
+
+// loadConfig decodes and validates the config file bytes into a.Config.
+func (a *appState) loadConfig(stderr io.Writer, file []byte) error {
+	cfgWrapper, err := decodeConfig(file)
 	if err != nil {
-		fmt.Fprintln(cmd.ErrOrStderr(), "Error unmarshalling config:", err)
+		fmt.Fprintln(stderr, "Error parsing config:", err)
 		return err
 	}
 
-	// verify that the channel filter rule is valid for every path in the config
-	for _, p := range cfgWrapper.Paths {
-		if err := p.ValidateChannelFilterRule(); err != nil {
-			return fmt.Errorf("error initializing the relayer config for path %s: %w", p.String(), err)
-		}
-	}
-
 	// build the config struct
 	chains := make(relayer.Chains)
 	for chainName, pcfg := range cfgWrapper.ProviderConfigs {
@@ -602,13 +708,9 @@ func initConfig(cmd *cobra.Command, a *appState) error {
 		Paths:  cfgWrapper.Paths,
 	}
 
-	// ensure config has []*relayer.Chain used for all chain operations
-	if err := validateConfig(a.Config); err != nil {
-		fmt.Fprintln(cmd.ErrOrStderr(), "Error parsing chain config:", err)
-		return err
-	}
-
-	return nil
+	// remember what was loaded, for OverwriteConfig to merge changes against.
+	a.configBase, err = snapshotConfig(a.Config.Wrapped())
+	return err
 }
 
 // ValidatePath checks that a path is valid
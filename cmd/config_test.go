@@ -0,0 +1,118 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/cmd"
+	"github.com/cosmos/relayer/v2/internal/relayertest"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRollback(t *testing.T) {
+	t.Parallel()
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+
+	// There is nothing to roll back to before the config is changed.
+	res := sys.MustRun(t, "config", "rollback", "--list")
+	require.Empty(t, res.Stdout.String())
+	require.Contains(t, res.Stderr.String(), "no previous config versions found")
+	require.Error(t, sys.Run(nil, "config", "rollback").Err)
+
+	sys.MustAddChain(t, "testChain", cmd.ProviderConfigWrapper{
+		Type: "cosmos",
+		Value: cosmos.CosmosProviderConfig{
+			ChainID:        "testcosmos",
+			KeyringBackend: "test",
+			Timeout:        "10s",
+		},
+	})
+
+	res = sys.MustRun(t, "config", "rollback", "--list")
+	require.Regexp(t, `^1: replaced \S+ \(.+\)\n$`, res.Stdout.String())
+
+	_ = sys.MustRun(t, "config", "rollback")
+	res = sys.MustRun(t, "chains", "list")
+	require.Contains(t, res.Stderr.String(), "no chains found")
+
+	// The rolled back config is kept as the most recent version, so rolling back again restores it.
+	_ = sys.MustRun(t, "config", "rollback", "1")
+	res = sys.MustRun(t, "chains", "list")
+	require.Contains(t, res.Stdout.String(), "testcosmos")
+
+	require.Error(t, sys.Run(nil, "config", "rollback", "5").Err)
+	require.Error(t, sys.Run(nil, "config", "rollback", "latest").Err)
+}
+
+func TestConfigRollback_InvalidCurrentConfig(t *testing.T) {
+	t.Parallel()
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+	sys.MustAddChain(t, "testChain", cmd.ProviderConfigWrapper{
+		Type: "cosmos",
+		Value: cosmos.CosmosProviderConfig{
+			ChainID:        "testcosmos",
+			KeyringBackend: "test",
+			Timeout:        "10s",
+		},
+	})
+
+	// A broken config file, edited by hand, does not prevent rolling back.
+	cfgPath := filepath.Join(sys.HomeDir, "config", "config.yaml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte("global: [\n"), 0600))
+	require.Error(t, sys.Run(nil, "chains", "list").Err)
+
+	res := sys.MustRun(t, "config", "rollback")
+	require.Contains(t, res.Stderr.String(), "Ignoring invalid current config")
+
+	res = sys.MustRun(t, "chains", "list")
+	require.Empty(t, res.Stdout.String())
+	require.Contains(t, res.Stderr.String(), "no chains found")
+}
+
+func TestConfigImportHermes(t *testing.T) {
+	t.Parallel()
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+
+	hermesCfg := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(hermesCfg, []byte(`
+[[chains]]
+id = 'testcosmos'
+rpc_addr = 'http://localhost:26657'
+grpc_addr = 'http://localhost:9090'
+account_prefix = 'cosmos'
+key_name = 'testkey'
+gas_price = { price = 0.01, denom = 'stake' }
+
+[chains.packet_filter]
+policy = 'allow'
+list = [['transfer', 'channel-0']]
+`), 0600))
+
+	res := sys.MustRun(t, "config", "import-hermes", hermesCfg, "--connection", "testcosmos")
+	require.Equal(t, `Imported 1 chains and 0 paths
+  chain testcosmos
+Settings not imported:
+  chains[testcosmos].grpc_addr: the relayer queries over rpc-addr
+  connection testcosmos: skipped, expected chain-id:connection-id
+  chains[testcosmos].packet_filter: not applied, no imported path has the chain as its source
+`, res.Stdout.String())
+
+	res = sys.MustRun(t, "chains", "list")
+	require.Contains(t, res.Stdout.String(), "testcosmos")
+
+	// Importing again skips the chain that is now configured.
+	res = sys.MustRun(t, "config", "import-hermes", hermesCfg)
+	require.Contains(t, res.Stdout.String(), "Imported 0 chains and 0 paths")
+	require.Contains(t, res.Stdout.String(), "chains[testcosmos]: skipped, chain is already configured")
+}
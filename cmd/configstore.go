@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/juju/fslock"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// configLockTimeout is how long a writer waits for other writers to release the config lock.
+	configLockTimeout = 10 * time.Second
+
+	// configVersionsKept is the number of previous versions of the config file kept for `rly config rollback`.
+	configVersionsKept = 10
+
+	// configVersionLayout is the UTC timestamp in the file names of previous versions of the config file,
+	// which sort in the order the versions were replaced.
+	configVersionLayout = "20060102T150405.000000000Z"
+)
+
+// configFilePath returns the path of the config file in home.
+func configFilePath(home string) string {
+	return filepath.Join(home, "config", "config.yaml")
+}
+
+// configVersionsDir returns the directory of the previous versions of the config file in home.
+func configVersionsDir(home string) string {
+	return filepath.Join(home, "config", "versions")
+}
+
+// lockConfig takes the lock that guards config.yaml against concurrent writers.
+// The returned function releases the lock.
+func (a *appState) lockConfig() (func(), error) {
+	lockFilePath := filepath.Join(a.HomePath, "config", "config.lock")
+	lock := fslock.New(lockFilePath)
+	if err := lock.LockWithTimeout(configLockTimeout); err != nil {
+		return nil, fmt.Errorf("failed to acquire config lock: %w", err)
+	}
+	return func() {
+		if err := lock.Unlock(); err != nil {
+			a.Log.Error("error unlocking config file lock, please manually delete",
+				zap.String("filepath", lockFilePath),
+			)
+		}
+	}, nil
+}
+
+// writeConfig validates bz and atomically replaces the config file with it,
+// keeping the replaced config file as a previous version.
+func (a *appState) writeConfig(bz []byte) error {
+	unlock, err := a.lockConfig()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return a.writeConfigLocked(bz)
+}
+
+// writeConfigLocked is writeConfig for callers holding the config lock.
+func (a *appState) writeConfigLocked(bz []byte) error {
+	if _, err := decodeConfig(bz); err != nil {
+		return fmt.Errorf("refusing to write invalid config: %w", err)
+	}
+
+	cfgPath := configFilePath(a.HomePath)
+	if err := a.saveConfigVersion(); err != nil {
+		return err
+	}
+
+	// Write to a temporary file in the same directory and rename it over the config file,
+	// so that readers never see a partially written config.
+	f, err := os.CreateTemp(filepath.Dir(cfgPath), ".config.yaml.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(bz); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write temporary config file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync temporary config file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary config file: %w", err)
+	}
+	if err := os.Chmod(f.Name(), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), cfgPath); err != nil {
+		return fmt.Errorf("failed to write config file at %s: %w", cfgPath, err)
+	}
+
+	return a.pruneConfigVersions()
+}
+
+// saveConfigVersion copies the current config file, if any, to the previous versions.
+func (a *appState) saveConfigVersion() error {
+	bz, err := os.ReadFile(configFilePath(a.HomePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	dir := configVersionsDir(a.HomePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create config versions directory: %w", err)
+	}
+	name := "config-" + time.Now().UTC().Format(configVersionLayout) + ".yaml"
+	if err := os.WriteFile(filepath.Join(dir, name), bz, 0600); err != nil {
+		return fmt.Errorf("failed to save previous config version: %w", err)
+	}
+	return nil
+}
+
+// pruneConfigVersions removes all but the newest configVersionsKept previous versions.
+func (a *appState) pruneConfigVersions() error {
+	versions, err := a.configVersions()
+	if err != nil {
+		return err
+	}
+	if len(versions) <= configVersionsKept {
+		return nil
+	}
+	for _, v := range versions[configVersionsKept:] {
+		if err := os.Remove(v.Path); err != nil {
+			return fmt.Errorf("failed to remove previous config version: %w", err)
+		}
+	}
+	return nil
+}
+
+// configVersion is a previous version of the config file.
+type configVersion struct {
+	Path string
+	// Replaced is when the version was replaced by a newer config.
+	Replaced time.Time
+}
+
+// configVersions returns the previous versions of the config file, newest first.
+func (a *appState) configVersions() ([]configVersion, error) {
+	dir := configVersionsDir(a.HomePath)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config versions: %w", err)
+	}
+
+	var versions []configVersion
+	for _, e := range entries {
+		ts := strings.TrimSuffix(strings.TrimPrefix(e.Name(), "config-"), ".yaml")
+		replaced, err := time.Parse(configVersionLayout, ts)
+		if err != nil || e.IsDir() {
+			continue
+		}
+		versions = append(versions, configVersion{Path: filepath.Join(dir, e.Name()), Replaced: replaced})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Replaced.After(versions[j].Replaced)
+	})
+	return versions, nil
+}
+
+// decodeConfig decodes the config file bz and validates it.
+func decodeConfig(bz []byte) (*ConfigInputWrapper, error) {
+	cfgWrapper := &ConfigInputWrapper{}
+	if err := yaml.Unmarshal(bz, cfgWrapper); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	// verify that the channel filter rule is valid for every path in the config
+	for _, p := range cfgWrapper.Paths {
+		if err := p.ValidateChannelFilterRule(); err != nil {
+			return nil, fmt.Errorf("error initializing the relayer config for path %s: %w", p.String(), err)
+		}
+	}
+
+	for chainName, pcfg := range cfgWrapper.ProviderConfigs {
+		if err := pcfg.Value.(provider.ProviderConfig).Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config for chain %s: %w", chainName, err)
+		}
+	}
+
+	if err := validateConfig(&Config{Global: cfgWrapper.Global}); err != nil {
+		return nil, err
+	}
+
+	return cfgWrapper, nil
+}
+
+// Wrapped converts the parsed config file back to the wrapper that is written to disk.
+func (iw *ConfigInputWrapper) Wrapped() *ConfigOutputWrapper {
+	providers := make(ProviderConfigs, len(iw.ProviderConfigs))
+	for chainName, pcfg := range iw.ProviderConfigs {
+		providers[chainName] = &ProviderConfigWrapper{
+			Type:  pcfg.Type,
+			Value: pcfg.Value.(provider.ProviderConfig),
+		}
+	}
+	return &ConfigOutputWrapper{
+		Global:          iw.Global,
+		ProviderConfigs: providers,
+		Paths:           iw.Paths,
+	}
+}
+
+// configSnapshot is a config with its global settings, chains and paths as generic YAML values,
+// to compare and merge configs entry by entry.
+type configSnapshot struct {
+	Global any            `yaml:"global"`
+	Chains map[string]any `yaml:"chains"`
+	Paths  map[string]any `yaml:"paths"`
+}
+
+// snapshotConfig returns the snapshot of the serialization of w.
+func snapshotConfig(w *ConfigOutputWrapper) (*configSnapshot, error) {
+	bz, err := yaml.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	s := &configSnapshot{}
+	if err := yaml.Unmarshal(bz, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// mergeConfig applies the changes of ours relative to base onto theirs.
+// Where both ours and theirs changed an entry, ours wins.
+func mergeConfig(log *zap.Logger, base, ours, theirs *configSnapshot) *configSnapshot {
+	merged := &configSnapshot{
+		Global: theirs.Global,
+		Chains: mergeConfigEntries(log, "chain", base.Chains, ours.Chains, theirs.Chains),
+		Paths:  mergeConfigEntries(log, "path", base.Paths, ours.Paths, theirs.Paths),
+	}
+	if !reflect.DeepEqual(base.Global, ours.Global) {
+		if !reflect.DeepEqual(base.Global, theirs.Global) && !reflect.DeepEqual(ours.Global, theirs.Global) {
+			log.Warn("Overwriting concurrent change to global config")
+		}
+		merged.Global = ours.Global
+	}
+	return merged
+}
+
+func mergeConfigEntries(log *zap.Logger, kind string, base, ours, theirs map[string]any) map[string]any {
+	merged := make(map[string]any, len(theirs))
+	for name, entry := range theirs {
+		merged[name] = entry
+	}
+
+	for name, entry := range ours {
+		baseEntry, inBase := base[name]
+		if inBase && reflect.DeepEqual(baseEntry, entry) {
+			continue
+		}
+		if theirsEntry, ok := theirs[name]; ok && !reflect.DeepEqual(theirsEntry, entry) &&
+			(!inBase || !reflect.DeepEqual(baseEntry, theirsEntry)) {
+			log.Warn("Overwriting concurrent change to config", zap.String(kind, name))
+		}
+		merged[name] = entry
+	}
+
+	for name, baseEntry := range base {
+		if _, ok := ours[name]; ok {
+			continue
+		}
+		if theirsEntry, ok := theirs[name]; ok && !reflect.DeepEqual(baseEntry, theirsEntry) {
+			log.Warn("Deleting concurrently changed config", zap.String(kind, name))
+		}
+		delete(merged, name)
+	}
+
+	return merged
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// newConfigTestState returns an app state with the default config written to a new home directory.
+func newConfigTestState(t *testing.T) *appState {
+	a := &appState{Log: zaptest.NewLogger(t), Viper: viper.New(), HomePath: t.TempDir()}
+	require.NoError(t, os.MkdirAll(filepath.Join(a.HomePath, "config"), os.ModePerm))
+	require.NoError(t, a.writeConfig(defaultConfigYAML("")))
+	return a
+}
+
+// loadConfigFrom returns an app state that loaded the config file of home.
+func loadConfigFrom(t *testing.T, home string) *appState {
+	a := &appState{Log: zaptest.NewLogger(t), Viper: viper.New(), HomePath: home}
+	bz, err := os.ReadFile(configFilePath(home))
+	require.NoError(t, err)
+	require.NoError(t, a.loadConfig(io.Discard, bz))
+	return a
+}
+
+func testPath(src, dst string) *relayer.Path {
+	return &relayer.Path{
+		Src: &relayer.PathEnd{ChainID: src, ClientID: "07-tendermint-0"},
+		Dst: &relayer.PathEnd{ChainID: dst, ClientID: "07-tendermint-0"},
+	}
+}
+
+func TestOverwriteConfigMergesConcurrentChanges(t *testing.T) {
+	home := newConfigTestState(t).HomePath
+
+	setup := loadConfigFrom(t, home)
+	setup.Config.Paths["stale"] = testPath("a", "b")
+	setup.Config.Paths["changed"] = testPath("a", "b")
+	require.NoError(t, setup.OverwriteConfig(setup.Config))
+
+	// Two processes load the same config and change it concurrently.
+	first := loadConfigFrom(t, home)
+	second := loadConfigFrom(t, home)
+
+	first.Config.Paths["first"] = testPath("a", "b")
+	first.Config.Paths["changed"].Src.ClientID = "07-tendermint-1"
+	require.NoError(t, first.OverwriteConfig(first.Config))
+
+	second.Config.Paths["second"] = testPath("c", "d")
+	delete(second.Config.Paths, "stale")
+	second.Config.Global.Memo = "second"
+	require.NoError(t, second.OverwriteConfig(second.Config))
+
+	// Both sets of changes are kept, on disk and in the state of the second process.
+	for _, a := range []*appState{loadConfigFrom(t, home), second} {
+		require.ElementsMatch(t, []string{"first", "second", "changed"}, pathNames(a.Config))
+		require.Equal(t, "07-tendermint-1", a.Config.Paths["changed"].Src.ClientID)
+		require.Equal(t, "second", a.Config.Global.Memo)
+	}
+}
+
+func TestOverwriteConfigOursWins(t *testing.T) {
+	home := newConfigTestState(t).HomePath
+
+	first := loadConfigFrom(t, home)
+	second := loadConfigFrom(t, home)
+
+	first.Config.Paths["path"] = testPath("a", "b")
+	require.NoError(t, first.OverwriteConfig(first.Config))
+
+	second.Config.Paths["path"] = testPath("c", "d")
+	require.NoError(t, second.OverwriteConfig(second.Config))
+
+	require.Equal(t, "c", loadConfigFrom(t, home).Config.Paths["path"].Src.ChainID)
+}
+
+func TestWriteConfigRejectsInvalidConfig(t *testing.T) {
+	a := newConfigTestState(t)
+	before, err := os.ReadFile(configFilePath(a.HomePath))
+	require.NoError(t, err)
+
+	a.Config = DefaultConfig("")
+	a.Config.Global.Timeout = "soon"
+	require.Error(t, a.writeConfig([]byte("global:\n  timeout: soon\n")))
+	require.Error(t, a.OverwriteConfig(a.Config))
+
+	after, err := os.ReadFile(configFilePath(a.HomePath))
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestConfigVersions(t *testing.T) {
+	a := newConfigTestState(t)
+
+	versions, err := a.configVersions()
+	require.NoError(t, err)
+	require.Empty(t, versions)
+
+	for i := 0; i < configVersionsKept+3; i++ {
+		require.NoError(t, a.writeConfig(defaultConfigYAML(string(rune('a'+i)))))
+	}
+
+	versions, err = a.configVersions()
+	require.NoError(t, err)
+	require.Len(t, versions, configVersionsKept)
+
+	// The newest version is the config replaced by the last write.
+	bz, err := os.ReadFile(versions[0].Path)
+	require.NoError(t, err)
+	require.Equal(t, defaultConfigYAML(string(rune('a'+configVersionsKept+1))), bz)
+	for i := 1; i < len(versions); i++ {
+		require.True(t, versions[i-1].Replaced.After(versions[i].Replaced))
+	}
+}
+
+func pathNames(c *Config) []string {
+	var names []string
+	for name := range c.Paths {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestConfigChangeChainID(t *testing.T) {
+	cfg := &Config{
+		Chains: relayer.Chains{
+			"osmosis": relayer.NewChain(zaptest.NewLogger(t), &cosmos.CosmosProvider{
+				PCfg: cosmos.CosmosProviderConfig{ChainID: "osmosis-1"},
+			}, false),
+		},
+		Paths: relayer.Paths{
+			"hub-osmo": testPath("cosmoshub-4", "osmosis-1"),
+			"osmo-hub": testPath("osmosis-1", "cosmoshub-4"),
+		},
+	}
+
+	require.NoError(t, cfg.ChangeChainID("osmosis-1", "osmosis-2"))
+	require.Equal(t, "osmosis-2", cfg.Chains["osmosis"].ChainID())
+	require.Equal(t, "osmosis-2", cfg.Paths["hub-osmo"].Dst.ChainID)
+	require.Equal(t, "osmosis-2", cfg.Paths["osmo-hub"].Src.ChainID)
+	require.Equal(t, "cosmoshub-4", cfg.Paths["osmo-hub"].Dst.ChainID)
+
+	require.Error(t, cfg.ChangeChainID("osmosis-1", "osmosis-3"))
+}
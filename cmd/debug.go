@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// debugCmd represents the debug command
+func debugCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Inspect a running relayer through its debug server",
+	}
+
+	cmd.AddCommand(
+		debugStateCmd(a),
+	)
+
+	return cmd
+}
+
+// debugStateCmd represents the `debug state` command
+func debugStateCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state [path_name]",
+		Short: "Dump the runtime state of the path processors of a running relayer",
+		Long: `Dump the channel and connection state, cached packet flow messages,
+in-progress packet messages and client state of each path being relayed
+by a relayer started with 'rly start', as served on /relayer/state of its debug server.`,
+		Args: withUsage(cobra.MaximumNArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s debug state
+$ %s debug state demo-path --debug-addr localhost:7597`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			debugAddr, err := cmd.Flags().GetString(flagDebugAddr)
+			if err != nil {
+				return err
+			}
+
+			u := url.URL{Scheme: "http", Host: debugAddr, Path: "/relayer/state"}
+			if len(args) == 1 {
+				u.RawQuery = url.Values{"path": []string{args[0]}}.Encode()
+			}
+
+			req, err := http.NewRequestWithContext(cmd.Context(), http.MethodGet, u.String(), nil)
+			if err != nil {
+				return err
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("failed to query relayer state, is the relayer running with --%s %s? %w", flagDebugAddr, debugAddr, err)
+			}
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				return err
+			}
+			if res.StatusCode != http.StatusOK {
+				return fmt.Errorf("relayer state request failed with status %d: %s", res.StatusCode, bytes.TrimSpace(body))
+			}
+
+			var out bytes.Buffer
+			if err := json.Indent(&out, body, "", "  "); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(out.String()))
+			return nil
+		},
+	}
+
+	return debugAddrFlag(a.Viper, cmd)
+}
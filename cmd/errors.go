@@ -13,6 +13,10 @@ func errKeyDoesntExist(name string) error {
 	return fmt.Errorf("a key with name %s doesn't exist", name)
 }
 
+func errKeyReadOnly(name string) error {
+	return fmt.Errorf("key %s is held by a remote signer and is read-only", name)
+}
+
 func errChainNotFound(chainName string) error {
 	return fmt.Errorf("chain with name \"%s\" not found in config. consider running `rly chains add %s`", chainName, chainName)
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cosmos/relayer/v2/relayer"
@@ -47,12 +48,34 @@ const (
 	flagMemo                    = "memo"
 	flagFilterRule              = "filter-rule"
 	flagFilterChannels          = "filter-channels"
+	flagAuditLog                = "audit-log"
+	flagRecordFixtures          = "record-fixtures"
+	flagChaosConfig             = "chaos-config"
+	flagList                    = "list"
+	flagConnection              = "connection"
+	flagOutputDir               = "output-dir"
+	flagRegistryDir             = "registry-dir"
+	flagRegistryCommit          = "registry-commit"
+	flagChannelFilter           = "channel-filter"
+	flagCandidate               = "candidate"
+	flagMaxBacklog              = "max-backlog"
+	flagUpgradeClients          = "upgrade-clients"
+	flagSubstitute              = "substitute"
+	flagDeposit                 = "deposit"
+	flagDaemon                  = "daemon"
+	flagWatch                   = "watch"
+	flagDelayPeriod             = "delay-period"
+	flagKeySwitchToken          = "key-switch-token"
 )
 
 const (
 	// 7597 is "RLYR" on a telephone keypad.
 	// It also happens to be unassigned in the IANA port list.
 	defaultDebugAddr = "localhost:7597"
+
+	// keySwitchTokenEnv sets the key switch token instead of --key-switch-token,
+	// which keeps it out of the process arguments.
+	keySwitchTokenEnv = "RLY_KEY_SWITCH_TOKEN"
 )
 
 func ibcDenomFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
@@ -104,6 +127,30 @@ func yamlFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func listFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagList, "l", false, "list the previous versions of the configuration file")
+	if err := v.BindPFlag(flagList, cmd.Flags().Lookup(flagList)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func connectionFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringArray(flagConnection, nil, "chain-id:connection-id of a connection to derive a path from, may be repeated")
+	if err := v.BindPFlag(flagConnection, cmd.Flags().Lookup(flagConnection)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func outputDirFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagOutputDir, "", "directory to write the file to, instead of printing it")
+	if err := v.BindPFlag(flagOutputDir, cmd.Flags().Lookup(flagOutputDir)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func skipConfirm(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().BoolP(flagSkip, "y", false, "output using yaml")
 	if err := v.BindPFlag(flagSkip, cmd.Flags().Lookup(flagSkip)); err != nil {
@@ -115,6 +162,83 @@ func skipConfirm(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 func chainsAddFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	fileFlag(v, cmd)
 	urlFlag(v, cmd)
+	registryFlags(v, cmd)
+	return cmd
+}
+
+func registryFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagRegistryDir, "", "local checkout of the chain-registry to read from instead of GitHub (default: registry-dir of the global config)")
+	cmd.Flags().String(flagRegistryCommit, "", "chain-registry commit to read from (default: registry-commit of the global config, or the latest)")
+	if err := v.BindPFlag(flagRegistryDir, cmd.Flags().Lookup(flagRegistryDir)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagRegistryCommit, cmd.Flags().Lookup(flagRegistryCommit)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func channelFilterFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagChannelFilter, "", fmt.Sprintf(
+		"allowlist the channels of the chain-registry (%q, %q or %q; default: no filtering)",
+		registryChannelsPreferred, registryChannelsLive, registryChannelsAll,
+	))
+	if err := v.BindPFlag(flagChannelFilter, cmd.Flags().Lookup(flagChannelFilter)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func discoverFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagList, "l", false, "list the candidate paths without adding one")
+	cmd.Flags().Int(flagCandidate, 1, "number of the listed candidate path to add")
+	if err := v.BindPFlag(flagList, cmd.Flags().Lookup(flagList)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagCandidate, cmd.Flags().Lookup(flagCandidate)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func maxBacklogFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Int(flagMaxBacklog, -1, "number of unrelayed packets and acknowledgements of a channel above which it is a problem (-1 for no limit)")
+	if err := v.BindPFlag(flagMaxBacklog, cmd.Flags().Lookup(flagMaxBacklog)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func recoverClientFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagSubstitute, "", "ID of an existing substitute client, instead of creating a new one")
+	cmd.Flags().Duration(flagClientTrustingPeriod, 0, "trusting period of the substitute client (default: trusting period of the expired client)")
+	cmd.Flags().String(flagDeposit, "", "deposit of the proposal ex. 250000000uatom (default: minimum deposit of the chain)")
+	cmd.Flags().String(flagDaemon, "", "binary of the chain to submit the proposal with, ex. gaiad")
+	cmd.Flags().Bool(flagWatch, true, "keep the substitute client updated until the proposal passes, then update the recovered client")
+	if err := v.BindPFlag(flagSubstitute, cmd.Flags().Lookup(flagSubstitute)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagClientTrustingPeriod, cmd.Flags().Lookup(flagClientTrustingPeriod)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagDeposit, cmd.Flags().Lookup(flagDeposit)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagDaemon, cmd.Flags().Lookup(flagDaemon)); err != nil {
+		panic(err)
+	}
+	if err := v.BindPFlag(flagWatch, cmd.Flags().Lookup(flagWatch)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func upgradeClientsFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagUpgradeClients, false,
+		"upgrade the counterparty clients of the relayed chains after their scheduled upgrades with an upgraded client state")
+	if err := v.BindPFlag(flagUpgradeClients, cmd.Flags().Lookup(flagUpgradeClients)); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
@@ -306,6 +430,35 @@ func debugServerFlags(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func debugAddrFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagDebugAddr, defaultDebugAddr, "address of the debug server of the running relayer")
+	if err := v.BindPFlag(flagDebugAddr, cmd.Flags().Lookup(flagDebugAddr)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func keySwitchTokenFlag(v *viper.Viper, cmd *cobra.Command, usage string) *cobra.Command {
+	cmd.Flags().String(flagKeySwitchToken, "", usage+"; can also be set with "+keySwitchTokenEnv)
+	if err := v.BindPFlag(flagKeySwitchToken, cmd.Flags().Lookup(flagKeySwitchToken)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+// keySwitchToken returns the token of the /relayer/keys endpoint of the debug server,
+// from --key-switch-token or the environment.
+func keySwitchToken(cmd *cobra.Command) (string, error) {
+	token, err := cmd.Flags().GetString(flagKeySwitchToken)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		token = os.Getenv(keySwitchTokenEnv)
+	}
+	return token, nil
+}
+
 func processorFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().StringP(flagProcessor, "p", relayer.ProcessorEvents, "which relayer processor to use")
 	if err := v.BindPFlag(flagProcessor, cmd.Flags().Lookup(flagProcessor)); err != nil {
@@ -322,6 +475,38 @@ func initBlockFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func delayPeriodFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Duration(flagDelayPeriod, 0, "delay period of the connection, which must pass after a client update before packets can be proven with it ex. 10m")
+	if err := v.BindPFlag(flagDelayPeriod, cmd.Flags().Lookup(flagDelayPeriod)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func auditLogFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagAuditLog, "", "file to append a JSON line audit record of every broadcast transaction to. Set empty to disable.")
+	if err := v.BindPFlag(flagAuditLog, cmd.Flags().Lookup(flagAuditLog)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func chaosConfigFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagChaosConfig, "", "YAML file describing faults to inject into the calls to each chain, for resilience testing against local chains. Never use in production.")
+	if err := v.BindPFlag(flagChaosConfig, cmd.Flags().Lookup(flagChaosConfig)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func recordFixturesFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().String(flagRecordFixtures, "", "directory to record the block results, light blocks and ABCI queries of cosmos chains to, one subdirectory per chain ID, for replay in tests. Set empty to disable.")
+	if err := v.BindPFlag(flagRecordFixtures, cmd.Flags().Lookup(flagRecordFixtures)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func memoFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().String(flagMemo, "", "a memo to include in relayed packets")
 	if err := v.BindPFlag(flagMemo, cmd.Flags().Lookup(flagMemo)); err != nil {
@@ -332,7 +517,7 @@ func memoFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 
 func OverwriteConfigFlag(v *viper.Viper, cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().BoolP(flagOverwriteConfig, "o", false,
-		"overwrite already configured paths - will clear channel filter(s)")
+		"overwrite already configured paths - channel filter(s) are kept and merged")
 	if err := v.BindPFlag(flagOverwriteConfig, cmd.Flags().Lookup(flagOverwriteConfig)); err != nil {
 		panic(err)
 	}
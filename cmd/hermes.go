@@ -0,0 +1,450 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Command for importing chains and paths from a Hermes configuration file
+func configImportHermesCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-hermes config.toml",
+		Short: "Imports chains and paths from a Hermes configuration file",
+		Long: strings.TrimSpace(`Imports the chains of a Hermes config.toml into the configuration file.
+
+Each [[chains]] entry of type CosmosSdk is added as a cosmos chain named after its chain ID,
+with its RPC address, gas price, gas multiplier, account prefix and key name. Chains that
+are already configured are skipped.
+
+Hermes does not configure connections, so paths are derived from the connections given
+with --connection, by querying the chain of each connection for its client and counterparty.
+The packet_filter of the source chain of a path becomes its channel filter.
+
+Settings that cannot be mapped to the relayer configuration are reported.`),
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config import-hermes ~/.hermes/config.toml
+$ %s config import-hermes ~/.hermes/config.toml --connection cosmoshub-4:connection-257`, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if a.Config == nil {
+				return fmt.Errorf("config not initialized, consider running `%s config init`", appName)
+			}
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			imp, err := convertHermesConfig(bz)
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %w", args[0], err)
+			}
+
+			connections, err := cmd.Flags().GetStringArray(flagConnection)
+			if err != nil {
+				return err
+			}
+
+			chains, paths := a.importHermes(cmd.Context(), imp, connections)
+			if err := a.OverwriteConfig(a.Config); err != nil {
+				return err
+			}
+
+			imp.printReport(cmd.OutOrStdout(), chains, paths)
+			return nil
+		},
+	}
+	return connectionFlag(a.Viper, cmd)
+}
+
+// hermesConfig is the part of a Hermes config.toml that is imported.
+type hermesConfig struct {
+	Chains []hermesChain `toml:"chains"`
+}
+
+// hermesChain is a [[chains]] entry of a Hermes config.toml.
+type hermesChain struct {
+	ID            string              `toml:"id"`
+	Type          string              `toml:"type"`
+	RPCAddr       string              `toml:"rpc_addr"`
+	RPCTimeout    string              `toml:"rpc_timeout"`
+	AccountPrefix string              `toml:"account_prefix"`
+	KeyName       string              `toml:"key_name"`
+	GasPrice      *hermesGasPrice     `toml:"gas_price"`
+	GasMultiplier hermesNumber        `toml:"gas_multiplier"`
+	GasAdjustment hermesNumber        `toml:"gas_adjustment"`
+	AddressType   *hermesAddressType  `toml:"address_type"`
+	PacketFilter  *hermesPacketFilter `toml:"packet_filter"`
+}
+
+type hermesGasPrice struct {
+	Price hermesNumber `toml:"price"`
+	Denom string       `toml:"denom"`
+}
+
+// hermesNumber is a TOML integer or float, or nil if the setting is absent.
+type hermesNumber any
+
+func hermesFloat(n hermesNumber) (float64, bool) {
+	switch n := n.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+type hermesAddressType struct {
+	Derivation string `toml:"derivation"`
+	ProtoType  *struct {
+		PkType string `toml:"pk_type"`
+	} `toml:"proto_type"`
+}
+
+type hermesPacketFilter struct {
+	Policy string     `toml:"policy"`
+	List   [][]string `toml:"list"`
+}
+
+// hermesChainKeys are the settings of a Hermes chain that are imported.
+var hermesChainKeys = map[string]bool{
+	"id": true, "type": true, "rpc_addr": true, "rpc_timeout": true, "account_prefix": true, "key_name": true,
+	"gas_price": true, "gas_multiplier": true, "gas_adjustment": true, "address_type": true, "packet_filter": true,
+}
+
+// hermesUnmapped explains why Hermes chain settings are not imported, where the relayer has an alternative.
+var hermesUnmapped = map[string]string{
+	"grpc_addr":       "the relayer queries over rpc-addr",
+	"websocket_addr":  "the relayer polls rpc-addr for new blocks",
+	"event_source":    "the relayer polls rpc-addr for new blocks",
+	"max_msg_num":     "set with `" + appName + " start --" + flagMaxMsgLength + "`",
+	"max_tx_size":     "set with `" + appName + " start --" + flagMaxTxSize + "`",
+	"trusting_period": "set with `" + appName + " tx clients --" + flagClientTrustingPeriod + "`",
+	"memo_prefix":     "set the global memo with `" + appName + " start --" + flagMemo + "`",
+}
+
+// hermesImport is the result of converting a Hermes config.toml.
+type hermesImport struct {
+	// Chains are the converted chains by chain ID.
+	Chains map[string]*cosmos.CosmosProviderConfig
+	// Filters are the channel filters converted from the packet filters of the chains, by chain ID.
+	Filters map[string]relayer.ChannelFilter
+	// Report lists the settings that were not imported.
+	Report []string
+}
+
+func (imp *hermesImport) reportf(format string, args ...any) {
+	imp.Report = append(imp.Report, fmt.Sprintf(format, args...))
+}
+
+// convertHermesConfig converts the chains of the Hermes config.toml bz into cosmos provider configs.
+func convertHermesConfig(bz []byte) (*hermesImport, error) {
+	var cfg hermesConfig
+	if err := toml.Unmarshal(bz, &cfg); err != nil {
+		return nil, err
+	}
+	// The settings are also decoded generically, to report the ones that are not imported.
+	var raw struct {
+		Chains []map[string]any `toml:"chains"`
+	}
+	if err := toml.Unmarshal(bz, &raw); err != nil {
+		return nil, err
+	}
+	var sections map[string]any
+	if err := toml.Unmarshal(bz, &sections); err != nil {
+		return nil, err
+	}
+
+	imp := &hermesImport{
+		Chains:  make(map[string]*cosmos.CosmosProviderConfig),
+		Filters: make(map[string]relayer.ChannelFilter),
+	}
+
+	var names []string
+	for name := range sections {
+		if name != "chains" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		imp.reportf("[%s]: not supported", name)
+	}
+
+	for i, c := range cfg.Chains {
+		if c.ID == "" {
+			imp.reportf("chains[%d]: skipped, it has no id", i)
+			continue
+		}
+		if c.Type != "" && c.Type != "CosmosSdk" {
+			imp.reportf("chains[%s]: skipped, chain type %s is not supported", c.ID, c.Type)
+			continue
+		}
+		if _, ok := imp.Chains[c.ID]; ok {
+			imp.reportf("chains[%s]: skipped, duplicate chain id", c.ID)
+			continue
+		}
+
+		imp.Chains[c.ID] = imp.convertChain(c)
+		if filter, ok := imp.convertPacketFilter(c); ok {
+			imp.Filters[c.ID] = filter
+		}
+
+		var keys []string
+		for k := range raw.Chains[i] {
+			if !hermesChainKeys[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			reason, ok := hermesUnmapped[k]
+			if !ok {
+				reason = "not supported"
+			}
+			imp.reportf("chains[%s].%s: %s", c.ID, k, reason)
+		}
+	}
+
+	return imp, nil
+}
+
+func (imp *hermesImport) convertChain(c hermesChain) *cosmos.CosmosProviderConfig {
+	pcfg := &cosmos.CosmosProviderConfig{
+		Key:            c.KeyName,
+		ChainID:        c.ID,
+		RPCAddr:        c.RPCAddr,
+		AccountPrefix:  c.AccountPrefix,
+		KeyringBackend: "test",
+		GasAdjustment:  1.1,
+		Timeout:        "10s",
+		OutputFormat:   "json",
+		SignModeStr:    "direct",
+	}
+	if pcfg.Key == "" {
+		pcfg.Key = "default"
+	}
+
+	if c.RPCTimeout != "" {
+		if _, err := time.ParseDuration(c.RPCTimeout); err != nil {
+			imp.reportf("chains[%s].rpc_timeout: %q is not a duration the relayer supports, using %s", c.ID, c.RPCTimeout, pcfg.Timeout)
+		} else {
+			pcfg.Timeout = c.RPCTimeout
+		}
+	}
+
+	if c.GasPrice != nil {
+		if price, ok := hermesFloat(c.GasPrice.Price); ok {
+			pcfg.GasPrices = strconv.FormatFloat(price, 'f', -1, 64) + c.GasPrice.Denom
+		}
+	}
+	if multiplier, ok := hermesFloat(c.GasMultiplier); ok {
+		pcfg.GasAdjustment = multiplier
+	} else if adjustment, ok := hermesFloat(c.GasAdjustment); ok {
+		// Older versions of Hermes add gas_adjustment to the simulated gas, as a fraction of it.
+		pcfg.GasAdjustment = 1 + adjustment
+	}
+
+	if c.AddressType != nil {
+		switch c.AddressType.Derivation {
+		case "", "cosmos":
+		case "ethermint":
+			pcfg.KeyAlgorithm = cosmos.KeyAlgorithmEthSecp256k1
+			if c.AddressType.ProtoType != nil && strings.HasPrefix(c.AddressType.ProtoType.PkType, "/injective.") {
+				pcfg.ExtraCodecs = []string{"injective"}
+			}
+		default:
+			imp.reportf("chains[%s].address_type: derivation %s is not supported", c.ID, c.AddressType.Derivation)
+		}
+	}
+
+	return pcfg
+}
+
+// convertPacketFilter converts the packet filter of c into a channel filter.
+// The relayer filters exact channel IDs, so wildcard channels are reported instead.
+// Ports need not be matched, since channel IDs are unique on a chain.
+func (imp *hermesImport) convertPacketFilter(c hermesChain) (relayer.ChannelFilter, bool) {
+	pf := c.PacketFilter
+	if pf == nil {
+		return relayer.ChannelFilter{}, false
+	}
+
+	var filter relayer.ChannelFilter
+	switch pf.Policy {
+	case "allow":
+		filter.Rule = processor.RuleAllowList
+	case "deny":
+		filter.Rule = processor.RuleDenyList
+	case "", "allowall":
+		return relayer.ChannelFilter{}, false
+	default:
+		imp.reportf("chains[%s].packet_filter: policy %s is not supported", c.ID, pf.Policy)
+		return relayer.ChannelFilter{}, false
+	}
+
+	for _, entry := range pf.List {
+		if len(entry) != 2 {
+			imp.reportf("chains[%s].packet_filter: entry %v is not a port and channel", c.ID, entry)
+			continue
+		}
+		if strings.ContainsAny(entry[1], "*?") {
+			imp.reportf("chains[%s].packet_filter: %s entry %s/%s skipped, wildcard channels are not supported", c.ID, pf.Policy, entry[0], entry[1])
+			continue
+		}
+		filter.ChannelList = append(filter.ChannelList, entry[1])
+	}
+
+	// An empty allowlist would relay nothing, where the Hermes filter relays on the wildcard channels.
+	if filter.Rule == processor.RuleAllowList && len(filter.ChannelList) == 0 {
+		imp.reportf("chains[%s].packet_filter: no channels left to allow, the filter is not imported", c.ID)
+		return relayer.ChannelFilter{}, false
+	}
+	return filter, len(filter.ChannelList) > 0
+}
+
+// importHermes adds the chains of imp to a.Config, and the paths derived from connections,
+// given as chain-id:connection-id. It returns the names of the chains and paths that were added.
+func (a *appState) importHermes(ctx context.Context, imp *hermesImport, connections []string) (chains, paths []string) {
+	var chainIDs []string
+	for chainID := range imp.Chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+
+	for _, chainID := range chainIDs {
+		if _, ok := a.Config.Chains[chainID]; ok {
+			imp.reportf("chains[%s]: skipped, chain is already configured", chainID)
+			continue
+		}
+		prov, err := imp.Chains[chainID].NewProvider(
+			a.Log.With(zap.String("provider_type", "cosmos")),
+			a.HomePath, a.Debug, chainID,
+		)
+		if err == nil {
+			err = a.Config.AddChain(relayer.NewChain(a.Log, prov, a.Debug))
+		}
+		if err != nil {
+			imp.reportf("chains[%s]: skipped, %v", chainID, err)
+			continue
+		}
+		chains = append(chains, chainID)
+	}
+
+	filtered := make(map[string]bool)
+	for _, conn := range connections {
+		chainID, connectionID, ok := strings.Cut(conn, ":")
+		if !ok {
+			imp.reportf("connection %s: skipped, expected chain-id:connection-id", conn)
+			continue
+		}
+		p, err := hermesPath(ctx, a.Config.Chains, chainID, connectionID)
+		if err != nil {
+			imp.reportf("connection %s: skipped, %v", conn, err)
+			continue
+		}
+
+		name := p.Src.ChainID + "-" + p.Dst.ChainID
+		if _, err := a.Config.Paths.Get(name); err == nil {
+			imp.reportf("connection %s: skipped, path %s already exists", conn, name)
+			continue
+		}
+		if filter, ok := imp.Filters[p.Src.ChainID]; ok {
+			p.Filter = filter
+			filtered[p.Src.ChainID] = true
+		}
+		if _, ok := imp.Filters[p.Dst.ChainID]; ok {
+			imp.reportf("chains[%s].packet_filter: not applied to path %s, which filters channels of its source chain only", p.Dst.ChainID, name)
+		}
+		if err := a.Config.Paths.Add(name, p); err != nil {
+			imp.reportf("connection %s: skipped, %v", conn, err)
+			continue
+		}
+		paths = append(paths, name)
+	}
+
+	for _, chainID := range chainIDs {
+		if _, ok := imp.Filters[chainID]; ok && !filtered[chainID] {
+			imp.reportf("chains[%s].packet_filter: not applied, no imported path has the chain as its source", chainID)
+		}
+	}
+
+	return chains, paths
+}
+
+// hermesPath derives the path of the connection connectionID on the chain chainID
+// by querying the connection and its client.
+func hermesPath(ctx context.Context, chains relayer.Chains, chainID, connectionID string) (*relayer.Path, error) {
+	src, err := chains.Get(chainID)
+	if err != nil {
+		return nil, err
+	}
+	height, err := src.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := src.ChainProvider.QueryConnection(ctx, height, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	if conn.Connection == nil || conn.Connection.State != conntypes.OPEN {
+		return nil, fmt.Errorf("connection %s is not open on chain %s", connectionID, chainID)
+	}
+	cs, err := src.ChainProvider.QueryClientState(ctx, height, conn.Connection.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	tmcs, ok := cs.(*tmclient.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("client %s of connection %s is not a tendermint client", conn.Connection.ClientId, connectionID)
+	}
+	if _, err := chains.Get(tmcs.ChainId); err != nil {
+		return nil, fmt.Errorf("counterparty chain %s is not configured", tmcs.ChainId)
+	}
+
+	return &relayer.Path{
+		Src: &relayer.PathEnd{
+			ChainID:      chainID,
+			ClientID:     conn.Connection.ClientId,
+			ConnectionID: connectionID,
+		},
+		Dst: &relayer.PathEnd{
+			ChainID:      tmcs.ChainId,
+			ClientID:     conn.Connection.Counterparty.ClientId,
+			ConnectionID: conn.Connection.Counterparty.ConnectionId,
+		},
+	}, nil
+}
+
+func (imp *hermesImport) printReport(w io.Writer, chains, paths []string) {
+	fmt.Fprintf(w, "Imported %d chains and %d paths\n", len(chains), len(paths))
+	for _, c := range chains {
+		fmt.Fprintf(w, "  chain %s\n", c)
+	}
+	for _, p := range paths {
+		fmt.Fprintf(w, "  path %s\n", p)
+	}
+	if len(imp.Report) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Settings not imported:")
+	for _, r := range imp.Report {
+		fmt.Fprintf(w, "  %s\n", r)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+)
+
+const testHermesConfig = `
+[global]
+log_level = 'info'
+
+[mode.packets]
+enabled = true
+
+[[chains]]
+id = 'cosmoshub-4'
+type = 'CosmosSdk'
+rpc_addr = 'https://rpc.cosmos.network:443'
+grpc_addr = 'https://grpc.cosmos.network:443'
+rpc_timeout = '15s'
+account_prefix = 'cosmos'
+key_name = 'hub-relayer'
+gas_price = { price = 0.0025, denom = 'uatom' }
+gas_multiplier = 1.3
+max_msg_num = 30
+clock_drift = '5s'
+
+[chains.packet_filter]
+policy = 'allow'
+list = [
+  ['transfer', 'channel-141'],
+  ['ica*', 'channel-*'],
+]
+
+[[chains]]
+id = 'injective-1'
+rpc_addr = 'https://rpc.injective.network:443'
+account_prefix = 'inj'
+key_name = 'inj-relayer'
+gas_price = { price = 500000000, denom = 'inj' }
+gas_adjustment = 0.2
+address_type = { derivation = 'ethermint', proto_type = { pk_type = '/injective.crypto.v1beta1.ethsecp256k1.PubKey' } }
+
+[chains.packet_filter]
+policy = 'deny'
+list = [['transfer', 'channel-1']]
+
+[[chains]]
+id = 'penumbra-1'
+type = 'Penumbra'
+`
+
+func TestConvertHermesConfig(t *testing.T) {
+	imp, err := convertHermesConfig([]byte(testHermesConfig))
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]*cosmos.CosmosProviderConfig{
+		"cosmoshub-4": {
+			Key:            "hub-relayer",
+			ChainID:        "cosmoshub-4",
+			RPCAddr:        "https://rpc.cosmos.network:443",
+			AccountPrefix:  "cosmos",
+			KeyringBackend: "test",
+			GasAdjustment:  1.3,
+			GasPrices:      "0.0025uatom",
+			Timeout:        "15s",
+			OutputFormat:   "json",
+			SignModeStr:    "direct",
+		},
+		"injective-1": {
+			Key:            "inj-relayer",
+			ChainID:        "injective-1",
+			RPCAddr:        "https://rpc.injective.network:443",
+			AccountPrefix:  "inj",
+			KeyringBackend: "test",
+			GasAdjustment:  1.2,
+			GasPrices:      "500000000inj",
+			Timeout:        "10s",
+			OutputFormat:   "json",
+			SignModeStr:    "direct",
+			ExtraCodecs:    []string{"injective"},
+			KeyAlgorithm:   cosmos.KeyAlgorithmEthSecp256k1,
+		},
+	}, imp.Chains)
+
+	require.Equal(t, map[string]relayer.ChannelFilter{
+		"cosmoshub-4": {Rule: processor.RuleAllowList, ChannelList: []string{"channel-141"}},
+		"injective-1": {Rule: processor.RuleDenyList, ChannelList: []string{"channel-1"}},
+	}, imp.Filters)
+
+	require.Equal(t, []string{
+		"[global]: not supported",
+		"[mode]: not supported",
+		"chains[cosmoshub-4].packet_filter: allow entry ica*/channel-* skipped, wildcard channels are not supported",
+		"chains[cosmoshub-4].clock_drift: not supported",
+		"chains[cosmoshub-4].grpc_addr: the relayer queries over rpc-addr",
+		"chains[cosmoshub-4].max_msg_num: set with `rly start --max-msgs`",
+		"chains[penumbra-1]: skipped, chain type Penumbra is not supported",
+	}, imp.Report)
+}
+
+func TestConvertHermesPacketFilterWildcardsOnly(t *testing.T) {
+	imp, err := convertHermesConfig([]byte(`
+[[chains]]
+id = 'osmosis-1'
+
+[chains.packet_filter]
+policy = 'allow'
+list = [['transfer', 'channel-*']]
+`))
+	require.NoError(t, err)
+
+	// An allowlist without channels would stop relaying altogether.
+	require.Empty(t, imp.Filters)
+	require.Contains(t, imp.Report, "chains[osmosis-1].packet_filter: no channels left to allow, the filter is not imported")
+}
@@ -17,19 +17,41 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"time"
 
+	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/term"
 )
 
 const (
 	flagCoinType           = "coin-type"
+	flagPassphraseFile     = "passphrase-file"
+	flagAddress            = "address"
+	flagMnemonic           = "mnemonic"
+	flagReserve            = "reserve"
+	flagSignal             = "signal"
 	defaultCoinType uint32 = sdk.CoinType
+	ethCoinType     uint32 = 60
+
+	armorHeader = "-----BEGIN"
 )
 
 // keysCmd represents the keys command
@@ -47,6 +69,8 @@ func keysCmd(a *appState) *cobra.Command {
 		keysListCmd(a),
 		keysShowCmd(a),
 		keysExportCmd(a),
+		keysImportCmd(a),
+		keysRotateCmd(a),
 	)
 
 	return cmd
@@ -74,7 +98,7 @@ $ %s k a cosmoshub testkey`, appName, appName, appName)),
 				return errKeyExists(keyName)
 			}
 
-			coinType, err := cmd.Flags().GetUint32(flagCoinType)
+			coinType, err := coinTypeFlagValue(cmd, chain.ChainProvider)
 			if err != nil {
 				return err
 			}
@@ -120,7 +144,7 @@ $ %s k r cosmoshub faucet-key "[mnemonic-words]"`, appName, appName)),
 				return errKeyExists(keyName)
 			}
 
-			coinType, err := cmd.Flags().GetUint32(flagCoinType)
+			coinType, err := coinTypeFlagValue(cmd, chain.ChainProvider)
 			if err != nil {
 				return err
 			}
@@ -160,6 +184,9 @@ $ %s k d cosmoshub default`, appName, appName, appName)),
 			if !chain.ChainProvider.KeyExists(keyName) {
 				return errKeyDoesntExist(keyName)
 			}
+			if isRemoteKey(chain.ChainProvider, keyName) {
+				return errKeyReadOnly(keyName)
+			}
 
 			if skip, _ := cmd.Flags().GetBool(flagSkip); !skip {
 				fmt.Fprintf(cmd.ErrOrStderr(), "Are you sure you want to delete key(%s) from chain(%s)? (Y/n)\n", keyName, args[0])
@@ -228,6 +255,10 @@ $ %s k l ibc-1`, appName, appName)),
 			}
 
 			for key, val := range info {
+				if isRemoteKey(chain.ChainProvider, key) {
+					fmt.Fprintf(cmd.OutOrStdout(), "key(%s) -> %s (remote, read-only)\n", key, val)
+					continue
+				}
 				fmt.Fprintf(cmd.OutOrStdout(), "key(%s) -> %s\n", key, val)
 			}
 
@@ -270,6 +301,9 @@ $ %s k s ibc-2 testkey`, appName, appName, appName)),
 			if err != nil {
 				return err
 			}
+			if isRemoteKey(chain.ChainProvider, keyName) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "key %s is held by a remote signer and is read-only\n", keyName)
+			}
 
 			fmt.Fprintln(cmd.OutOrStdout(), address)
 			return nil
@@ -299,6 +333,9 @@ $ %s k e cosmoshub testkey`, appName, appName)),
 			if !chain.ChainProvider.KeyExists(keyName) {
 				return errKeyDoesntExist(keyName)
 			}
+			if isRemoteKey(chain.ChainProvider, keyName) {
+				return errKeyReadOnly(keyName)
+			}
 
 			info, err := chain.ChainProvider.ExportPrivKeyArmor(keyName)
 			if err != nil {
@@ -312,3 +349,435 @@ $ %s k e cosmoshub testkey`, appName, appName)),
 
 	return cmd
 }
+
+// keysImportCmd respresents the `keys import` command
+func keysImportCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import chain_name key_name [file]",
+		Aliases: []string{"i"},
+		Short:   "Imports an armored or hex encoded privkey to the keychain associated with a particular chain",
+		Long: strings.TrimSpace(`Imports a privkey to the keychain associated with a particular chain.
+
+The privkey is read from file, or from stdin if file is omitted or "-".
+It is either an armored privkey as printed by 'keys export', or a hex encoded secp256k1 privkey.
+
+The passphrase of an armored privkey is read from --passphrase-file, or prompted for if stdin is a terminal.
+Otherwise the passphrase of 'keys export' is used.
+
+The address of the imported key is derived with the account-prefix of the chain config and printed.
+It is only verified with --address, which fails the import unless the key has that address;
+without --address, a wrong account-prefix in the config goes unnoticed.`),
+		Args: withUsage(cobra.RangeArgs(2, 3)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys import ibc-0 testkey key.armor
+$ %s keys export ibc-0 testkey | %s keys import ibc-1 testkey
+$ %s keys import cosmoshub testkey key.armor --passphrase-file passphrase.txt
+$ %s k i cosmoshub testkey --address cosmos1...`, appName, appName, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyName := args[1]
+			chain, ok := a.Config.Chains[args[0]]
+			if !ok {
+				return errChainNotFound(args[0])
+			}
+
+			if chain.ChainProvider.KeyExists(keyName) {
+				return errKeyExists(keyName)
+			}
+
+			file := "-"
+			if len(args) == 3 {
+				file = args[2]
+			}
+			key, err := readPrivKey(cmd, file)
+			if err != nil {
+				return err
+			}
+
+			var address string
+			if strings.HasPrefix(key, armorHeader) {
+				passphrase, err := readPassphrase(cmd, file)
+				if err != nil {
+					return err
+				}
+				address, err = chain.ChainProvider.ImportPrivKeyArmor(keyName, key, passphrase)
+				if err != nil {
+					return err
+				}
+			} else {
+				address, err = chain.ChainProvider.ImportPrivKeyHex(keyName, key)
+				if err != nil {
+					return err
+				}
+			}
+
+			expected, err := cmd.Flags().GetString(flagAddress)
+			if err != nil {
+				return err
+			}
+			if expected != "" {
+				if err := checkImportedAddress(address, expected); err != nil {
+					if err := chain.ChainProvider.DeleteKey(keyName); err != nil {
+						a.Log.Warn("Failed to delete imported key", zap.String("key_name", keyName), zap.Error(err))
+					}
+					return err
+				}
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), address)
+			return nil
+		},
+	}
+	cmd.Flags().String(flagPassphraseFile, "", "file containing the passphrase of an armored privkey")
+	cmd.Flags().String(flagAddress, "", "expected address of the imported key; the import fails if it does not match")
+
+	return cmd
+}
+
+// keysRotateCmd respresents the `keys rotate` command
+func keysRotateCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rotate chain_name [new_key_name]",
+		Aliases: []string{"rot"},
+		Short:   "Rotates the relayer key of a particular chain to a new key",
+		Long: strings.TrimSpace(`Rotates the relayer key of a particular chain to a new key, generated or restored from --mnemonic.
+
+The balance of the old key, minus --reserve to pay for the rotation, is sent to the new key.
+Fee allowances granted by the old key are granted again by the new key and revoked from the old key,
+and the ICS-29 payees registered for the old key are registered for the new key.
+The key of the chain is then updated in the config.
+
+A relayer running with the debug server on --debug-addr must be switched to the new key with --signal,
+which happens before any funds are moved. The key of a chain relayed by such a relayer is not rotated without it.
+
+The new key is named after the old key and the current time, unless new_key_name is given.`),
+		Args: withUsage(cobra.RangeArgs(1, 2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys rotate cosmoshub
+$ %s keys rotate cosmoshub new-key --reserve 10000uatom --signal
+$ %s k rot osmosis new-key --mnemonic "[mnemonic-words]" -y`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainName := args[0]
+			chain, ok := a.Config.Chains[chainName]
+			if !ok {
+				return errChainNotFound(chainName)
+			}
+			ccp, ok := chain.ChainProvider.(*cosmos.CosmosProvider)
+			if !ok {
+				return fmt.Errorf("key rotation is not supported for chain %s of type %s", chainName, chain.ChainProvider.Type())
+			}
+
+			debugAddr, err := cmd.Flags().GetString(flagDebugAddr)
+			if err != nil {
+				return err
+			}
+			signal, err := cmd.Flags().GetBool(flagSignal)
+			if err != nil {
+				return err
+			}
+			var token string
+			if signal {
+				if token, err = keySwitchToken(cmd); err != nil {
+					return err
+				}
+				if token == "" {
+					return fmt.Errorf("--%s or %s is required to signal the relayer", flagKeySwitchToken, keySwitchTokenEnv)
+				}
+			} else if debugAddr != "" && relayedByRunningRelayer(cmd, debugAddr, ccp.ChainId()) {
+				// The running relayer would keep signing with the old key after its funds are moved.
+				return fmt.Errorf("chain %s is relayed by the relayer running with the debug server on %s, "+
+					"rotate its key with --%s to switch the running relayer to the new key", chainName, debugAddr, flagSignal)
+			}
+
+			oldKey := ccp.Key()
+			if !ccp.KeyExists(oldKey) {
+				return errKeyDoesntExist(oldKey)
+			}
+			if ccp.IsRemoteKey(oldKey) {
+				return errKeyReadOnly(oldKey)
+			}
+
+			newKey := fmt.Sprintf("%s-%d", oldKey, time.Now().Unix())
+			if len(args) == 2 {
+				newKey = args[1]
+			}
+			if ccp.KeyExists(newKey) {
+				return errKeyExists(newKey)
+			}
+
+			reserve, err := reserveFlagValue(cmd, ccp)
+			if err != nil {
+				return err
+			}
+			coinType, err := coinTypeFlagValue(cmd, chain.ChainProvider)
+			if err != nil {
+				return err
+			}
+			mnemonic, err := cmd.Flags().GetString(flagMnemonic)
+			if err != nil {
+				return err
+			}
+
+			var out string
+			if mnemonic != "" {
+				out, err = ccp.RestoreKey(newKey, mnemonic, coinType)
+				if err != nil {
+					return fmt.Errorf("failed to restore key: %w", err)
+				}
+			} else {
+				ko, err := ccp.AddKey(newKey, coinType)
+				if err != nil {
+					return fmt.Errorf("failed to add key: %w", err)
+				}
+				bz, err := json.Marshal(ko)
+				if err != nil {
+					return err
+				}
+				out = string(bz)
+			}
+
+			rotation, err := ccp.PlanKeyRotation(cmd.Context(), newKey, reserve)
+			if err != nil {
+				return multierr.Append(err, ccp.DeleteKey(newKey))
+			}
+			printKeyRotation(cmd.ErrOrStderr(), chainName, rotation)
+
+			if skip, _ := cmd.Flags().GetBool(flagSkip); !skip {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Are you sure you want to rotate the key? (Y/n)")
+				if !askForConfirmation(a, cmd.InOrStdin(), cmd.ErrOrStderr()) {
+					return ccp.DeleteKey(newKey)
+				}
+			}
+
+			if signal {
+				// Switch the running relayer first, so that it does not sign with the old key once its funds are moved.
+				if err := signalKeySwitch(cmd, debugAddr, token, ccp.ChainId(), newKey); err != nil {
+					return multierr.Append(err, ccp.DeleteKey(newKey))
+				}
+			}
+
+			// The new key is kept from here on, it may hold funds after a partial rotation.
+			fmt.Fprintln(cmd.OutOrStdout(), out)
+
+			if err := ccp.RotateKey(cmd.Context(), rotation); err != nil {
+				return err
+			}
+
+			if err := a.UpdateConfigOnTheFly(cmd, func(cfg *Config) error {
+				chain, ok := cfg.Chains[chainName]
+				if !ok {
+					return errChainNotFound(chainName)
+				}
+				ks, ok := chain.ChainProvider.(provider.KeySwitcher)
+				if !ok {
+					return fmt.Errorf("chain %s cannot switch keys", chainName)
+				}
+				return ks.UseKey(newKey)
+			}); err != nil {
+				return fmt.Errorf("failed to update key of chain %s in config: %w", chainName, err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "key of chain %s rotated from %s to %s\n", chainName, oldKey, newKey)
+			return nil
+		},
+	}
+	cmd.Flags().Uint32(flagCoinType, defaultCoinType, "coin type number for HD derivation")
+	cmd.Flags().String(flagMnemonic, "", "mnemonic to restore the new key from, instead of generating it")
+	cmd.Flags().String(flagReserve, "", "coins left to the old key to pay for the rotation; defaults to 500000 gas at the gas prices of the chain")
+	cmd.Flags().Bool(flagSignal, false, "switch the key of a relayer running with the debug server on --"+flagDebugAddr+" before moving funds")
+
+	cmd = keySwitchTokenFlag(a.Viper, cmd, "token of the debug server of the running relayer to switch keys with --"+flagSignal)
+	return debugAddrFlag(a.Viper, skipConfirm(a.Viper, cmd))
+}
+
+// reserveFlagValue returns the coins of the --reserve flag, or the default reserve of the chain.
+func reserveFlagValue(cmd *cobra.Command, ccp *cosmos.CosmosProvider) (sdk.Coins, error) {
+	reserve, err := cmd.Flags().GetString(flagReserve)
+	if err != nil {
+		return nil, err
+	}
+	if reserve == "" {
+		return ccp.DefaultRotationReserve()
+	}
+	coins, err := sdk.ParseCoinsNormalized(reserve)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flagReserve, err)
+	}
+	return coins, nil
+}
+
+// printKeyRotation writes what the key rotation r of a chain will move to w.
+func printKeyRotation(w io.Writer, chainName string, r *cosmos.KeyRotation) {
+	fmt.Fprintf(w, "Rotating key of chain %s from %s (%s) to %s (%s):\n", chainName, r.OldKey, r.OldAddress, r.NewKey, r.NewAddress)
+	if r.Transfer.IsZero() {
+		fmt.Fprintln(w, "  no balance to transfer")
+	} else {
+		fmt.Fprintf(w, "  transfer %s\n", r.Transfer)
+	}
+	for _, g := range r.FeeGrants {
+		fmt.Fprintf(w, "  move fee allowance granted to %s\n", g.Grantee)
+	}
+	for _, p := range r.Payees {
+		fmt.Fprintf(w, "  move ICS-29 payee registration on %s/%s\n", p.PortID, p.ChannelID)
+	}
+	for _, g := range r.ReceivedFeeGrants {
+		fmt.Fprintf(w, "  warning: the fee allowance granted by %s to the old key must be granted to the new key by its granter\n", g.Granter)
+	}
+}
+
+// relayedByRunningRelayer reports whether the relayer running with the debug server on debugAddr, if any, relays chainID.
+func relayedByRunningRelayer(cmd *cobra.Command, debugAddr, chainID string) bool {
+	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Second)
+	defer cancel()
+	u := url.URL{Scheme: "http", Host: debugAddr, Path: "/healthz"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// No relayer is running with the debug server.
+		return false
+	}
+	defer res.Body.Close()
+
+	// The status is reported whether or not the relayer is live.
+	var status processor.HealthStatus
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		return false
+	}
+	for _, c := range status.Chains {
+		if c.ChainID == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// signalKeySwitch switches the key of a chain in the relayer running with the debug server on debugAddr,
+// which authenticates the request with token.
+func signalKeySwitch(cmd *cobra.Command, debugAddr, token, chainID, key string) error {
+	u := url.URL{
+		Scheme:   "http",
+		Host:     debugAddr,
+		Path:     "/relayer/keys",
+		RawQuery: url.Values{"chain": []string{chainID}, "key": []string{key}}.Encode(),
+	}
+
+	req, err := http.NewRequestWithContext(cmd.Context(), http.MethodPost, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to signal relayer, is the relayer running with --%s %s? %w", flagDebugAddr, debugAddr, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("relayer key switch failed with status %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "running relayer switched to key %s\n", key)
+	return nil
+}
+
+// readPrivKey reads the privkey to import from file, or from stdin if file is "-".
+// A privkey typed into a terminal is not echoed.
+func readPrivKey(cmd *cobra.Command, file string) (string, error) {
+	var bz []byte
+	var err error
+	switch {
+	case file != "-":
+		bz, err = os.ReadFile(file)
+	case isTerminal(cmd.InOrStdin()):
+		fmt.Fprint(cmd.ErrOrStderr(), "Enter hex encoded privkey: ")
+		bz, err = term.ReadPassword(int(cmd.InOrStdin().(*os.File).Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+	default:
+		bz, err = io.ReadAll(cmd.InOrStdin())
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read privkey: %w", err)
+	}
+
+	key := strings.TrimSpace(string(bz))
+	if key == "" {
+		return "", errors.New("no privkey to import")
+	}
+	return key, nil
+}
+
+// readPassphrase returns the passphrase of an armored privkey read from file,
+// from --passphrase-file, a terminal prompt, or the passphrase of `keys export`.
+func readPassphrase(cmd *cobra.Command, file string) (string, error) {
+	passphraseFile, err := cmd.Flags().GetString(flagPassphraseFile)
+	if err != nil {
+		return "", err
+	}
+	if passphraseFile != "" {
+		bz, err := os.ReadFile(passphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return strings.TrimRight(string(bz), "\r\n"), nil
+	}
+
+	// A privkey piped to stdin leaves no terminal to prompt on.
+	if file == "-" || !isTerminal(cmd.InOrStdin()) {
+		return ckeys.DefaultKeyPass, nil
+	}
+
+	fmt.Fprint(cmd.ErrOrStderr(), "Enter passphrase to decrypt the privkey (empty for the passphrase of 'keys export'): ")
+	bz, err := term.ReadPassword(int(cmd.InOrStdin().(*os.File).Fd()))
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(bz) == 0 {
+		return ckeys.DefaultKeyPass, nil
+	}
+	return string(bz), nil
+}
+
+// checkImportedAddress checks that the address of an imported key, derived with the account prefix of the chain config,
+// matches the expected address, so that a wrong account prefix in the config is reported as such.
+func checkImportedAddress(address, expected string) error {
+	prefix, _, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return err
+	}
+	expectedPrefix, _, err := bech32.DecodeAndConvert(expected)
+	if err != nil {
+		return fmt.Errorf("invalid expected address %s: %w", expected, err)
+	}
+	if expectedPrefix != prefix {
+		return fmt.Errorf("expected address %s does not have the account prefix %s of the chain", expected, prefix)
+	}
+	if address != expected {
+		return fmt.Errorf("imported key has address %s, expected %s", address, expected)
+	}
+	return nil
+}
+
+// isTerminal returns true if r is a terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// coinTypeFlagValue returns the coin type of the --coin-type flag.
+// If the flag is not set, chains with eth_secp256k1 keys default to the Ethereum coin type.
+func coinTypeFlagValue(cmd *cobra.Command, p provider.ChainProvider) (uint32, error) {
+	if !cmd.Flags().Changed(flagCoinType) {
+		if pc, ok := p.ProviderConfig().(cosmos.CosmosProviderConfig); ok && pc.KeyAlgorithm == cosmos.KeyAlgorithmEthSecp256k1 {
+			return ethCoinType, nil
+		}
+	}
+	return cmd.Flags().GetUint32(flagCoinType)
+}
+
+// isRemoteKey returns true if the key is held by a remote signer of the chain provider.
+func isRemoteKey(p provider.ChainProvider, name string) bool {
+	rkp, ok := p.(provider.RemoteKeyProvider)
+	return ok && rkp.IsRemoteKey(name)
+}
@@ -1,17 +1,30 @@
 package cmd_test
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/client/keys"
 	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/cosmos/relayer/v2/cmd"
 	"github.com/cosmos/relayer/v2/internal/relayertest"
 	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestKeysList_Empty(t *testing.T) {
@@ -111,3 +124,114 @@ func TestKeysExport(t *testing.T) {
 
 	// TODO: confirm the imported address matches?
 }
+
+func TestKeysImport(t *testing.T) {
+	t.Parallel()
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+
+	sys.MustAddChain(t, "testChain", cmd.ProviderConfigWrapper{
+		Type: "cosmos",
+		Value: cosmos.CosmosProviderConfig{
+			AccountPrefix:  "cosmos",
+			ChainID:        "testcosmos",
+			KeyringBackend: "test",
+			Timeout:        "10s",
+		},
+	})
+
+	// Export a restored key and import it again from stdin.
+	_ = sys.MustRun(t, "keys", "restore", "testChain", "default", relayertest.ZeroMnemonic)
+	res := sys.MustRun(t, "keys", "export", "testChain", "default")
+	armorOut := res.Stdout.String()
+
+	res = sys.MustRunWithInput(t, strings.NewReader(armorOut), "keys", "import", "testChain", "imported")
+	require.Equal(t, relayertest.ZeroCosmosAddr+"\n", res.Stdout.String())
+	require.Empty(t, res.Stderr.String())
+
+	res = sys.MustRun(t, "keys", "show", "testChain", "imported")
+	require.Equal(t, relayertest.ZeroCosmosAddr+"\n", res.Stdout.String())
+
+	// Importing to an existing key fails.
+	res = sys.RunWithInput(zaptest.NewLogger(t), strings.NewReader(armorOut), "keys", "import", "testChain", "imported")
+	require.Error(t, res.Err)
+
+	// Import an armored key encrypted with a custom passphrase from a file.
+	priv := secp256k1.GenPrivKey()
+	addr, err := bech32.ConvertAndEncode("cosmos", priv.PubKey().Address())
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.armor")
+	passFile := filepath.Join(dir, "passphrase")
+	require.NoError(t, os.WriteFile(keyFile, []byte(crypto.EncryptArmorPrivKey(priv, "hunter22", "secp256k1")), 0600))
+	require.NoError(t, os.WriteFile(passFile, []byte("wrong\n"), 0600))
+
+	res = sys.Run(zaptest.NewLogger(t), "keys", "import", "testChain", "armored", keyFile, "--passphrase-file", passFile)
+	require.Error(t, res.Err)
+
+	require.NoError(t, os.WriteFile(passFile, []byte("hunter22\n"), 0600))
+	res = sys.MustRun(t, "keys", "import", "testChain", "armored", keyFile, "--passphrase-file", passFile, "--address", addr)
+	require.Equal(t, addr+"\n", res.Stdout.String())
+
+	// Import a hex key, checking its address.
+	hexKey := hex.EncodeToString(secp256k1.GenPrivKey().Key)
+	res = sys.RunWithInput(zaptest.NewLogger(t), strings.NewReader(hexKey), "keys", "import", "testChain", "hex", "--address", addr)
+	require.ErrorContains(t, res.Err, "expected "+addr)
+	res = sys.MustRun(t, "keys", "list", "testChain")
+	require.NotContains(t, res.Stdout.String(), "key(hex)")
+
+	osmoAddr, err := bech32.ConvertAndEncode("osmo", priv.PubKey().Address())
+	require.NoError(t, err)
+	res = sys.RunWithInput(zaptest.NewLogger(t), strings.NewReader(hex.EncodeToString(priv.Key)), "keys", "import", "testChain", "hex", "--address", osmoAddr)
+	require.ErrorContains(t, res.Err, "account prefix cosmos")
+
+	res = sys.MustRunWithInput(t, strings.NewReader("0x"+hex.EncodeToString(priv.Key)+"\n"), "keys", "import", "testChain", "hex")
+	require.Equal(t, addr+"\n", res.Stdout.String())
+}
+
+func TestKeysRotate_RunningRelayerRequiresSignal(t *testing.T) {
+	t.Parallel()
+
+	// The debug server of a relayer relaying the chain.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/healthz", r.URL.Path)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		require.NoError(t, json.NewEncoder(w).Encode(processor.HealthStatus{
+			Chains: []processor.ChainHealth{{ChainID: "testcosmos"}},
+		}))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	sys := relayertest.NewSystem(t)
+
+	_ = sys.MustRun(t, "config", "init")
+
+	sys.MustAddChain(t, "testChain", cmd.ProviderConfigWrapper{
+		Type: "cosmos",
+		Value: cosmos.CosmosProviderConfig{
+			AccountPrefix:  "cosmos",
+			ChainID:        "testcosmos",
+			KeyringBackend: "test",
+			Timeout:        "10s",
+		},
+	})
+	_ = sys.MustRun(t, "keys", "restore", "testChain", "default", relayertest.ZeroMnemonic)
+
+	res := sys.Run(zaptest.NewLogger(t), "keys", "rotate", "testChain", "new-key", "--debug-addr", u.Host, "-y")
+	require.Error(t, res.Err)
+	require.Contains(t, res.Err.Error(), "chain testChain is relayed by the relayer running with the debug server on "+u.Host)
+
+	// Signalling the running relayer requires the token of its debug server.
+	res = sys.Run(zaptest.NewLogger(t), "keys", "rotate", "testChain", "new-key", "--debug-addr", u.Host, "--signal", "-y")
+	require.Error(t, res.Err)
+	require.Contains(t, res.Err.Error(), "--key-switch-token")
+
+	// No funds were moved and no key was created.
+	res = sys.MustRun(t, "keys", "list", "testChain")
+	require.Equal(t, "key(default) -> "+relayertest.ZeroCosmosAddr+"\n", res.Stdout.String())
+}
@@ -2,15 +2,17 @@ package cmd
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/cosmos/relayer/v2/relayer"
 	"github.com/cosmos/relayer/v2/relayer/processor"
-	"github.com/google/go-github/v43/github"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -33,6 +35,9 @@ This includes the client, connection, and channel ids from both the source and d
 		pathsNewCmd(a),
 		pathsUpdateCmd(a),
 		pathsFetchCmd(a),
+		pathsDiscoverCmd(a),
+		pathsAuditCmd(a),
+		pathsExportRegistryCmd(a),
 		pathsDeleteCmd(a),
 	)
 
@@ -317,9 +322,19 @@ func pathsFetchCmd(a *appState) *cobra.Command {
 		Args:    withUsage(cobra.NoArgs),
 		Example: strings.TrimSpace(fmt.Sprintf(`
 $ %s paths fetch --home %s
-$ %s pth fch`, appName, defaultHome, appName)),
+$ %s paths fetch --registry-dir ~/chain-registry --registry-commit 3f1a2b4
+$ %s paths fetch --channel-filter preferred --overwrite
+$ %s pth fch`, appName, defaultHome, appName, appName, appName)),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			overwrite, _ := cmd.Flags().GetBool(flagOverwriteConfig)
+			channelFilter, err := cmd.Flags().GetString(flagChannelFilter)
+			if err != nil {
+				return err
+			}
+			includeChannel, err := registryChannelSelector(channelFilter)
+			if err != nil {
+				return err
+			}
 
 			chains := []string{}
 			for chainName := range a.Config.Chains {
@@ -342,32 +357,29 @@ $ %s pth fch`, appName, defaultHome, appName)),
 				}
 			}
 
-			client := github.NewClient(nil)
+			pthNames := make([]string, 0, len(chainCombinations))
 			for pthName := range chainCombinations {
-				_, exist := a.Config.Paths[pthName]
+				pthNames = append(pthNames, pthName)
+			}
+			sort.Strings(pthNames)
+
+			chainRegistry, err := a.chainRegistry(cmd)
+			if err != nil {
+				return err
+			}
+			for _, pthName := range pthNames {
+				existing, exist := a.Config.Paths[pthName]
 				if exist && !overwrite {
-					fmt.Fprintf(cmd.ErrOrStderr(), "skipping:  %s already exists in config, use -o to overwrite (clears filters)\n", pthName)
+					fmt.Fprintf(cmd.ErrOrStderr(), "skipping:  %s already exists in config, use -o to overwrite\n", pthName)
 					continue
 				}
 
-				// TODO: Don't use github api. Potentially use: https://github.com/eco-stake/cosmos-directory once they integrate IBC data into restAPI. This will avoid rate limits.
 				fileName := pthName + ".json"
-				regPath := path.Join("_IBC", fileName)
-				client, _, err := client.Repositories.DownloadContents(cmd.Context(), "cosmos", "chain-registry", regPath, nil)
+				b, err := chainRegistry.readFile(cmd.Context(), path.Join("_IBC", fileName))
 				if err != nil {
-					if errors.As(err, new(*github.RateLimitError)) {
-						fmt.Println("some paths failed: ", err)
-						break
-					}
 					fmt.Fprintf(cmd.ErrOrStderr(), "failure retrieving: %s: consider adding to cosmos/chain-registry: ERR: %v\n", pthName, err)
 					continue
 				}
-				defer client.Close()
-
-				b, err := io.ReadAll(client)
-				if err != nil {
-					return fmt.Errorf("error reading response body: %w", err)
-				}
 
 				ibc := &relayer.IBCdata{}
 				if err = json.Unmarshal(b, &ibc); err != nil {
@@ -387,11 +399,32 @@ $ %s pth fch`, appName, defaultHome, appName)),
 					ClientID:     ibc.Chain2.ClientID,
 					ConnectionID: ibc.Chain2.ConnectionID,
 				}
+
+				// An existing path may have chain_2 of the registry as its source.
+				srcIsChain1 := !exist || existing.Src.ChainID != dstPathEnd.ChainID
+				if !srcIsChain1 {
+					srcPathEnd, dstPathEnd = dstPathEnd, srcPathEnd
+				}
 				newPath := &relayer.Path{
 					Src: srcPathEnd,
 					Dst: dstPathEnd,
 				}
-				client.Close()
+
+				if includeChannel != nil {
+					if channels := ibc.ChannelIDs(srcIsChain1, includeChannel); len(channels) > 0 {
+						newPath.Filter = relayer.ChannelFilter{Rule: processor.RuleAllowList, ChannelList: channels}
+					} else {
+						fmt.Fprintf(cmd.ErrOrStderr(), "no %s channels of %s in chain registry, channels are not filtered\n", channelFilter, pthName)
+					}
+				}
+				if exist {
+					filter, err := existing.Filter.Merge(newPath.Filter)
+					if err != nil {
+						fmt.Fprintf(cmd.ErrOrStderr(), "cannot merge channel filters of %s, keeping the existing filter: %v\n", pthName, err)
+						filter = existing.Filter
+					}
+					newPath.Filter = filter
+				}
 
 				if err = a.Config.AddPath(pthName, newPath); err != nil {
 					return fmt.Errorf("failed to add path %s: %w", pthName, err)
@@ -407,5 +440,230 @@ $ %s pth fch`, appName, defaultHome, appName)),
 
 		},
 	}
-	return OverwriteConfigFlag(a.Viper, cmd)
+	return channelFilterFlag(a.Viper, registryFlags(a.Viper, OverwriteConfigFlag(a.Viper, cmd)))
+}
+
+const (
+	registryChannelsPreferred = "preferred"
+	registryChannelsLive      = "live"
+	registryChannelsAll       = "all"
+)
+
+// registryChannelSelector returns which channels of a chain-registry path are allowlisted by the --channel-filter value,
+// or nil when channels are not filtered.
+func registryChannelSelector(channelFilter string) (func(relayer.IBCChannel) bool, error) {
+	switch channelFilter {
+	case "":
+		return nil, nil
+	case registryChannelsPreferred:
+		return func(ch relayer.IBCChannel) bool {
+			return ch.Tags != nil && ch.Tags.Preferred
+		}, nil
+	case registryChannelsLive:
+		return func(ch relayer.IBCChannel) bool {
+			return ch.Tags != nil && ch.Tags.Status == "live"
+		}, nil
+	case registryChannelsAll:
+		return func(relayer.IBCChannel) bool { return true }, nil
+	default:
+		return nil, fmt.Errorf("invalid channel filter %q, must be %q, %q or %q",
+			channelFilter, registryChannelsPreferred, registryChannelsLive, registryChannelsAll)
+	}
+}
+
+func pathsDiscoverCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "discover chain_name_a chain_name_b [path_name]",
+		Aliases: []string{"disc"},
+		Short:   "Discovers the existing clients and connections between two chains and adds them as a path",
+		Long: `Queries the clients and connections of both chains for open connections on clients that track each other's chain.
+The candidate paths are listed from best to worst: paths with clients that are neither expired nor frozen first,
+then the ones with the most open channels, then the most recently updated. The first candidate is added,
+or the one chosen with --candidate. The path is named chain_name_a-chain_name_b unless path_name is given.`,
+		Args: withUsage(cobra.RangeArgs(2, 3)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths discover cosmoshub osmosis --list
+$ %s paths discover cosmoshub osmosis hub-osmo --candidate 2
+$ %s pth disc cosmoshub osmosis`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, ok := a.Config.Chains[args[0]]
+			if !ok {
+				return errChainNotFound(args[0])
+			}
+			dst, ok := a.Config.Chains[args[1]]
+			if !ok {
+				return errChainNotFound(args[1])
+			}
+			name := args[0] + "-" + args[1]
+			if len(args) == 3 {
+				name = args[2]
+			}
+
+			list, err := cmd.Flags().GetBool(flagList)
+			if err != nil {
+				return err
+			}
+			candidate, err := cmd.Flags().GetInt(flagCandidate)
+			if err != nil {
+				return err
+			}
+
+			candidates, err := relayer.DiscoverPaths(cmd.Context(), src, dst)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return fmt.Errorf("no open connections found between chains %s and %s", src.ChainID(), dst.ChainID())
+			}
+
+			now := time.Now()
+			for i, c := range candidates {
+				printPathCandidate(cmd.OutOrStdout(), i+1, c, now)
+			}
+			if list {
+				return nil
+			}
+
+			if candidate < 1 || candidate > len(candidates) {
+				return fmt.Errorf("invalid candidate %d, must be between 1 and %d", candidate, len(candidates))
+			}
+			c := candidates[candidate-1]
+			if c.Expired(now) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: a client of candidate %d is expired or frozen\n", candidate)
+			}
+			if err := a.Config.Paths.Add(name, c.Path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "added:  %s\n", name)
+			return a.OverwriteConfig(a.Config)
+		},
+	}
+	return discoverFlags(a.Viper, cmd)
+}
+
+func printPathCandidate(stdout io.Writer, i int, c *relayer.PathCandidate, now time.Time) {
+	expiry := "expires in " + c.Expiration.Sub(now).Round(time.Second).String()
+	switch {
+	case c.Frozen:
+		expiry = "frozen"
+	case c.Expired(now):
+		expiry = "expired"
+	}
+	fmt.Fprintf(stdout, "%2d: %s %s %s <> %s %s %s -> channels(%d) updated(%s) %s\n",
+		i, c.Path.Src.ChainID, c.Path.Src.ClientID, c.Path.Src.ConnectionID,
+		c.Path.Dst.ChainID, c.Path.Dst.ClientID, c.Path.Dst.ConnectionID,
+		c.OpenChannels, c.LastUpdated().UTC().Format(time.RFC3339), expiry)
+}
+
+func pathsAuditCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "audit path_name",
+		Aliases: []string{"aud"},
+		Short:   "Audits the clients, connections and channels of a path",
+		Long: `Checks the integrity of a path on both chains:
+- the clients track the counterparty chain, are neither frozen nor expired, and have a trusting period
+  shorter than the unbonding period of the counterparty chain
+- the latest heights of the clients are on the revision of the counterparty chain and not ahead of it
+- the connections and the channels relayed by the path are open and their counterparties point back at them
+- the backlog of unrelayed packets and acknowledgements of each open channel, a problem above --max-backlog
+Exits with an error if any problem is found.`,
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths audit demo-path
+$ %s paths audit demo-path --max-backlog 100 --json
+$ %s pth aud demo-path`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			c, src, dst, err := a.Config.ChainsFromPath(name)
+			if err != nil {
+				return err
+			}
+			maxBacklog, err := cmd.Flags().GetInt(flagMaxBacklog)
+			if err != nil {
+				return err
+			}
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			audit, err := relayer.AuditPath(cmd.Context(), c[src], c[dst], a.Config.Paths.MustGet(name).Filter, maxBacklog)
+			if err != nil {
+				return err
+			}
+
+			if jsn {
+				out, err := json.Marshal(audit)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			} else {
+				for _, f := range audit.Findings {
+					icon := check
+					if f.Problem {
+						icon = xIcon
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s: %s\n", icon, f.ChainID, f.Subject, f.Message)
+				}
+			}
+
+			if n := audit.Problems(); n > 0 {
+				return fmt.Errorf("path %s has %d problem(s)", name, n)
+			}
+			return nil
+		},
+	}
+	return jsonFlag(a.Viper, maxBacklogFlag(a.Viper, cmd))
+}
+
+func pathsExportRegistryCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export-registry path_name",
+		Aliases: []string{"exp"},
+		Short:   "Exports a path in the chain-registry _IBC format",
+		Long: `Queries both chains of a path for its clients, connections and open channels,
+and prints the path in the chain-registry _IBC format, ready to be added to cosmos/chain-registry.
+The chains are named after their names in the config, which should be their chain-registry names.`,
+		Args: withUsage(cobra.ExactArgs(1)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s paths export-registry demo-path
+$ %s paths export-registry demo-path --output-dir ~/chain-registry/_IBC
+$ %s pth exp demo-path`, appName, appName, appName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chains, src, dst, err := a.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			srcName, dstName := chains[src].ChainProvider.ChainName(), chains[dst].ChainProvider.ChainName()
+			data, err := relayer.QueryIBCData(cmd.Context(), chains[src], chains[dst], srcName, dstName)
+			if err != nil {
+				return err
+			}
+
+			out, err := json.MarshalIndent(data, "", "  ")
+			if err != nil {
+				return err
+			}
+			out = append(out, '\n')
+
+			dir, err := cmd.Flags().GetString(flagOutputDir)
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				_, err := cmd.OutOrStdout().Write(out)
+				return err
+			}
+
+			file := filepath.Join(dir, relayer.IBCDataFileName(srcName, dstName))
+			if err := os.WriteFile(file, out, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "wrote:  %s\n", file)
+			return nil
+		},
+	}
+	return outputDirFlag(a.Viper, cmd)
 }
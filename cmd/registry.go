@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v43/github"
+	"github.com/spf13/cobra"
+	lens "github.com/strangelove-ventures/lens/client"
+	registry "github.com/strangelove-ventures/lens/client/chain_registry"
+	"go.uber.org/zap"
+)
+
+const (
+	registryOwner = "cosmos"
+	registryRepo  = "chain-registry"
+
+	// registryDefaultRef is the branch of cosmos/chain-registry that is read unless a commit is pinned.
+	registryDefaultRef = "master"
+)
+
+// errRegistryFileNotFound is returned by chainRegistry.readFile for files that are not in the registry.
+var errRegistryFileNotFound = errors.New("file not found in chain registry")
+
+// chainRegistry reads chains and paths from the chain-registry: the cosmos/chain-registry repository on GitHub,
+// or a local checkout of it in dir, at its current state or at the pinned commit.
+type chainRegistry struct {
+	log    *zap.Logger
+	dir    string
+	commit string
+}
+
+var _ registry.ChainRegistry = &chainRegistry{}
+
+// chainRegistry returns the chain-registry configured by the --registry-dir and --registry-commit flags of cmd,
+// or else by the global config.
+func (a *appState) chainRegistry(cmd *cobra.Command) (*chainRegistry, error) {
+	r := &chainRegistry{log: a.Log.With(zap.String("registry", "cosmos_github"))}
+	if a.Config != nil {
+		r.dir, r.commit = a.Config.Global.RegistryDir, a.Config.Global.RegistryCommit
+	}
+
+	if cmd.Flags().Changed(flagRegistryDir) {
+		dir, err := cmd.Flags().GetString(flagRegistryDir)
+		if err != nil {
+			return nil, err
+		}
+		r.dir = dir
+	}
+	if cmd.Flags().Changed(flagRegistryCommit) {
+		commit, err := cmd.Flags().GetString(flagRegistryCommit)
+		if err != nil {
+			return nil, err
+		}
+		r.commit = commit
+	}
+
+	if r.dir != "" {
+		if _, err := os.Stat(r.dir); err != nil {
+			return nil, fmt.Errorf("invalid chain registry directory: %w", err)
+		}
+		r.log = a.Log.With(zap.String("registry", "local"), zap.String("registry_dir", r.dir))
+	}
+	if r.commit != "" {
+		r.log = r.log.With(zap.String("registry_commit", r.commit))
+	}
+	return r, nil
+}
+
+// deterministic reports whether r always reads the same files, so chain configs are generated
+// without picking RPC endpoints at random or by their health.
+func (r *chainRegistry) deterministic() bool {
+	return r.dir != "" || r.commit != ""
+}
+
+// readFile reads the file at the slash separated path name from the root of the registry.
+func (r *chainRegistry) readFile(ctx context.Context, name string) ([]byte, error) {
+	switch {
+	case r.dir != "" && r.commit != "":
+		out, err := r.git(ctx, "show", r.commit+":"+name)
+		if err != nil {
+			if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "exists on disk, but not in") {
+				return nil, fmt.Errorf("%w: %s at commit %s", errRegistryFileNotFound, name, r.commit)
+			}
+			return nil, err
+		}
+		return out, nil
+
+	case r.dir != "":
+		bz, err := os.ReadFile(filepath.Join(r.dir, filepath.FromSlash(name)))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", errRegistryFileNotFound, name)
+		}
+		return bz, err
+
+	default:
+		ref := r.commit
+		if ref == "" {
+			ref = registryDefaultRef
+		}
+		url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", registryOwner, registryRepo, ref, name)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		switch res.StatusCode {
+		case http.StatusOK:
+			return io.ReadAll(res.Body)
+		case http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %s", errRegistryFileNotFound, name)
+		default:
+			return nil, fmt.Errorf("response code: %d: GET failed: %s", res.StatusCode, url)
+		}
+	}
+}
+
+// git runs git in the local registry directory and returns its output.
+func (r *chainRegistry) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", r.dir}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// ListChains returns the names of the chains in the registry.
+func (r *chainRegistry) ListChains(ctx context.Context) ([]string, error) {
+	var names []string
+	switch {
+	case r.dir != "" && r.commit != "":
+		out, err := r.git(ctx, "ls-tree", "-d", "--name-only", r.commit)
+		if err != nil {
+			return nil, err
+		}
+		names = strings.Fields(string(out))
+
+	case r.dir != "":
+		entries, err := os.ReadDir(r.dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				names = append(names, e.Name())
+			}
+		}
+
+	case r.commit != "":
+		tree, _, err := github.NewClient(http.DefaultClient).Git.GetTree(ctx, registryOwner, registryRepo, r.commit, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range tree.Entries {
+			if entry.GetType() == "tree" {
+				names = append(names, entry.GetPath())
+			}
+		}
+
+	default:
+		return registry.DefaultChainRegistry(r.log).ListChains(ctx)
+	}
+
+	// Directories such as _IBC and .github are not chains.
+	chains := make([]string, 0, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "_") {
+			chains = append(chains, name)
+		}
+	}
+	sort.Strings(chains)
+	return chains, nil
+}
+
+// GetChain returns the chain.json of the chain name.
+func (r *chainRegistry) GetChain(ctx context.Context, name string) (registry.ChainInfo, error) {
+	bz, err := r.readFile(ctx, path.Join(name, "chain.json"))
+	if err != nil {
+		return registry.ChainInfo{}, err
+	}
+	info := registry.NewChainInfo(r.log.With(zap.String("chain_name", name)))
+	if err := json.Unmarshal(bz, &info); err != nil {
+		return registry.ChainInfo{}, fmt.Errorf("failed to unmarshal chain.json of %s: %w", name, err)
+	}
+	return info, nil
+}
+
+// SourceLink returns where the registry is read from.
+func (r *chainRegistry) SourceLink() string {
+	link := fmt.Sprintf("https://github.com/%s/%s", registryOwner, registryRepo)
+	if r.dir != "" {
+		link = r.dir
+	}
+	if r.commit != "" {
+		link += "@" + r.commit
+	}
+	return link
+}
+
+// ChainConfig returns the client config of the chain info.
+// Unlike info.GetChainConfig, a deterministic registry generates it from the registry alone:
+// the first RPC endpoint is used without checking its health, and gas prices are based on the assetlist.json of the registry.
+func (r *chainRegistry) ChainConfig(ctx context.Context, info registry.ChainInfo) (*lens.ChainClientConfig, error) {
+	if !r.deterministic() {
+		return info.GetChainConfig(ctx)
+	}
+
+	rpcs, err := info.GetAllRPCEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	if len(rpcs) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints found for chain %s", info.ChainName)
+	}
+
+	bz, err := r.readFile(ctx, path.Join(info.ChainName, "assetlist.json"))
+	if err != nil {
+		return nil, err
+	}
+	var assetList registry.AssetList
+	if err := json.Unmarshal(bz, &assetList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal assetlist.json of %s: %w", info.ChainName, err)
+	}
+	var gasPrices string
+	if len(assetList.Assets) > 0 {
+		gasPrices = fmt.Sprintf("%.2f%s", 0.01, assetList.Assets[0].Base)
+	}
+
+	// The defaults match the ones of info.GetChainConfig.
+	return &lens.ChainClientConfig{
+		Key:            "default",
+		ChainID:        info.ChainID,
+		RPCAddr:        rpcs[0],
+		AccountPrefix:  info.Bech32Prefix,
+		KeyringBackend: "test",
+		GasAdjustment:  1.2,
+		GasPrices:      gasPrices,
+		Timeout:        "20s",
+		OutputFormat:   "json",
+		SignModeStr:    "direct",
+	}, nil
+}
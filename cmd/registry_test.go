@@ -0,0 +1,192 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/internal/relayertest"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// writeRegistryFile writes v as JSON to the file name of the chain-registry checkout dir.
+func writeRegistryFile(t *testing.T, dir, name string, v any) {
+	t.Helper()
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+	bz, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, bz, 0644))
+}
+
+func writeRegistryChain(t *testing.T, dir, name, chainID, prefix, denom string, rpcs ...string) {
+	t.Helper()
+	var rpcList []map[string]string
+	for _, rpc := range rpcs {
+		rpcList = append(rpcList, map[string]string{"address": rpc})
+	}
+	writeRegistryFile(t, dir, name+"/chain.json", map[string]any{
+		"chain_name":    name,
+		"chain_id":      chainID,
+		"bech32_prefix": prefix,
+		"slip44":        118,
+		"apis":          map[string]any{"rpc": rpcList},
+	})
+	writeRegistryFile(t, dir, name+"/assetlist.json", map[string]any{
+		"chain_id": chainID,
+		"assets":   []map[string]string{{"base": denom}},
+	})
+}
+
+// newTestRegistry writes a chain-registry checkout with two chains and the path between them.
+func newTestRegistry(t *testing.T) string {
+	dir := t.TempDir()
+	writeRegistryChain(t, dir, "cosmoshub", "cosmoshub-4", "cosmos", "uatom", "https://rpc.cosmos.example:443", "https://rpc2.cosmos.example:443")
+	writeRegistryChain(t, dir, "osmosis", "osmosis-1", "osmo", "uosmo", "https://rpc.osmosis.example:443")
+	writeRegistryFile(t, dir, "_IBC/cosmoshub-osmosis.json", map[string]any{
+		"chain_1": map[string]string{"chain_name": "cosmoshub", "client_id": "07-tendermint-259", "connection_id": "connection-257"},
+		"chain_2": map[string]string{"chain_name": "osmosis", "client_id": "07-tendermint-1", "connection_id": "connection-1"},
+		"channels": []map[string]any{
+			{
+				"chain_1": map[string]string{"channel_id": "channel-141", "port_id": "transfer"},
+				"chain_2": map[string]string{"channel_id": "channel-0", "port_id": "transfer"},
+				"tags":    map[string]any{"status": "live", "preferred": true},
+			},
+			{
+				"chain_1": map[string]string{"channel_id": "channel-9", "port_id": "icahost"},
+				"chain_2": map[string]string{"channel_id": "channel-2", "port_id": "icahost"},
+				"tags":    map[string]any{"status": "live"},
+			},
+			{
+				"chain_1": map[string]string{"channel_id": "channel-5", "port_id": "transfer"},
+				"chain_2": map[string]string{"channel_id": "channel-7", "port_id": "transfer"},
+				"tags":    map[string]any{"status": "killed"},
+			},
+		},
+	})
+	return dir
+}
+
+// readConfig returns the config file of sys as generic YAML.
+func readConfig(t *testing.T, sys *relayertest.System) map[string]any {
+	t.Helper()
+	bz, err := os.ReadFile(filepath.Join(sys.HomeDir, "config", "config.yaml"))
+	require.NoError(t, err)
+	var cfg map[string]any
+	require.NoError(t, yaml.Unmarshal(bz, &cfg))
+	return cfg
+}
+
+func chainValue(cfg map[string]any, name string) map[string]any {
+	return cfg["chains"].(map[string]any)[name].(map[string]any)["value"].(map[string]any)
+}
+
+func TestLocalRegistry(t *testing.T) {
+	t.Parallel()
+
+	dir := newTestRegistry(t)
+	sys := relayertest.NewSystem(t)
+	_ = sys.MustRun(t, "config", "init")
+
+	res := sys.MustRun(t, "chains", "registry-list", "--registry-dir", dir)
+	require.Equal(t, "cosmoshub\nosmosis\n", res.Stdout.String())
+
+	_ = sys.MustRun(t, "chains", "add", "--registry-dir", dir, "cosmoshub", "osmosis")
+	hub := chainValue(readConfig(t, sys), "cosmoshub")
+	require.Equal(t, "cosmoshub-4", hub["chain-id"])
+	// The first RPC endpoint is used, without checking its health.
+	require.Equal(t, "https://rpc.cosmos.example:443", hub["rpc-addr"])
+	require.Equal(t, "0.01uatom", hub["gas-prices"])
+
+	res = sys.MustRun(t, "paths", "fetch", "--registry-dir", dir)
+	require.Contains(t, res.Stderr.String(), "added:  cosmoshub-osmosis")
+	res = sys.MustRun(t, "paths", "show", "cosmoshub-osmosis", "--json")
+	require.Contains(t, res.Stdout.String(), `"connection-id":"connection-257"`)
+
+	require.Error(t, sys.Run(nil, "chains", "add", "--registry-dir", filepath.Join(dir, "missing"), "juno").Err)
+}
+
+func TestFetchPathChannelFilter(t *testing.T) {
+	t.Parallel()
+
+	dir := newTestRegistry(t)
+	sys := relayertest.NewSystem(t)
+	_ = sys.MustRun(t, "config", "init")
+	_ = sys.MustRun(t, "chains", "add", "--registry-dir", dir, "cosmoshub", "osmosis")
+
+	filter := func() map[string]any {
+		return readConfig(t, sys)["paths"].(map[string]any)["cosmoshub-osmosis"].(map[string]any)["src-channel-filter"].(map[string]any)
+	}
+
+	_ = sys.MustRun(t, "paths", "fetch", "--registry-dir", dir, "--channel-filter", "preferred")
+	require.Equal(t, map[string]any{"rule": "allowlist", "channel-list": []any{"channel-141"}}, filter())
+
+	// Overwriting merges the allowlists.
+	_ = sys.MustRun(t, "paths", "fetch", "--registry-dir", dir, "--channel-filter", "all", "-o")
+	require.Equal(t, map[string]any{"rule": "allowlist", "channel-list": []any{"channel-141", "channel-9", "channel-5"}}, filter())
+
+	// The channels are those of the source chain of an existing path, here osmosis, and denied channels stay denied.
+	_ = sys.MustRun(t, "paths", "delete", "cosmoshub-osmosis")
+	_ = sys.MustRun(t, "paths", "new", "osmosis-1", "cosmoshub-4", "cosmoshub-osmosis")
+	_ = sys.MustRun(t, "paths", "update", "cosmoshub-osmosis", "--filter-rule", "denylist", "--filter-channels", "channel-2")
+	_ = sys.MustRun(t, "paths", "fetch", "--registry-dir", dir, "--channel-filter", "live", "-o")
+	require.Equal(t, map[string]any{"rule": "allowlist", "channel-list": []any{"channel-0"}}, filter())
+	res := sys.MustRun(t, "paths", "show", "cosmoshub-osmosis", "--json")
+	require.Contains(t, res.Stdout.String(), `"src":{"chain-id":"osmosis-1","client-id":"07-tendermint-1","connection-id":"connection-1"}`)
+
+	// Without --channel-filter, overwriting keeps the filter.
+	_ = sys.MustRun(t, "paths", "fetch", "--registry-dir", dir, "-o")
+	require.Equal(t, map[string]any{"rule": "allowlist", "channel-list": []any{"channel-0"}}, filter())
+
+	require.Error(t, sys.Run(nil, "paths", "fetch", "--registry-dir", dir, "--channel-filter", "open").Err)
+}
+
+func TestLocalRegistryPinnedCommit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := newTestRegistry(t)
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "registry")
+	commit := git("rev-parse", "HEAD")
+
+	// Changes after the pinned commit are not read.
+	writeRegistryChain(t, dir, "cosmoshub", "cosmoshub-4", "cosmos", "uatom", "https://rpc.changed.example:443")
+	writeRegistryChain(t, dir, "juno", "juno-1", "juno", "ujuno", "https://rpc.juno.example:443")
+
+	sys := relayertest.NewSystem(t)
+	_ = sys.MustRun(t, "config", "init")
+
+	// The registry is configured in the global config.
+	cfgPath := filepath.Join(sys.HomeDir, "config", "config.yaml")
+	bz, err := os.ReadFile(cfgPath)
+	require.NoError(t, err)
+	bz = []byte(strings.Replace(string(bz), "global:\n", "global:\n    registry-dir: "+dir+"\n    registry-commit: "+commit+"\n", 1))
+	require.NoError(t, os.WriteFile(cfgPath, bz, 0600))
+
+	res := sys.MustRun(t, "chains", "registry-list")
+	require.Equal(t, "cosmoshub\nosmosis\n", res.Stdout.String())
+
+	_ = sys.MustRun(t, "chains", "add", "cosmoshub")
+	require.Equal(t, "https://rpc.cosmos.example:443", chainValue(readConfig(t, sys), "cosmoshub")["rpc-addr"])
+	_ = sys.MustRun(t, "chains", "add", "juno")
+	require.NotContains(t, readConfig(t, sys)["chains"], "juno")
+
+	// Flags override the global config.
+	_ = sys.MustRun(t, "chains", "add", "--registry-commit", "", "juno")
+	require.Equal(t, "https://rpc.juno.example:443", chainValue(readConfig(t, sys), "juno")["rpc-addr"])
+}
@@ -122,6 +122,8 @@ func NewRootCmd(log *zap.Logger) *cobra.Command {
 		transactionCmd(a),
 		queryCmd(a),
 		startCmd(a),
+		auditCmd(a),
+		debugCmd(a),
 		lineBreakCommand(),
 		getVersionCmd(a),
 	)
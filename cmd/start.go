@@ -21,13 +21,18 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/cosmos/relayer/v2/internal/relaydebug"
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/alert"
+	"github.com/cosmos/relayer/v2/relayer/audit"
 	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/chaos"
 	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -94,6 +99,8 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 			}
 
 			var prometheusMetrics *processor.PrometheusMetrics
+			var health *processor.Health
+			var states *processor.StateRegistry
 
 			debugAddr, err := cmd.Flags().GetString(flagDebugAddr)
 			if err != nil {
@@ -110,7 +117,22 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 				log := a.Log.With(zap.String("sys", "debughttp"))
 				log.Info("Debug server listening", zap.String("addr", debugAddr))
 				prometheusMetrics = processor.NewPrometheusMetrics()
-				relaydebug.StartDebugServer(cmd.Context(), log, ln, prometheusMetrics.Registry)
+				health = processor.NewHealth()
+				states = processor.NewStateRegistry()
+				keySwitch, err := keySwitchToken(cmd)
+				if err != nil {
+					return err
+				}
+				keys := make(map[string]provider.KeySwitcher)
+				for chainID, chain := range chains {
+					if ks, ok := chain.ChainProvider.(provider.KeySwitcher); ok {
+						keys[chainID] = ks
+					}
+				}
+				relaydebug.StartDebugServer(cmd.Context(), log, ln, prometheusMetrics.Registry, health, states, relaydebug.KeySwitch{
+					Token: keySwitch,
+					Keys:  keys,
+				})
 				for _, chain := range chains {
 					if ccp, ok := chain.ChainProvider.(*cosmos.CosmosProvider); ok {
 						ccp.SetMetrics(prometheusMetrics)
@@ -118,6 +140,65 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 				}
 			}
 
+			auditLogPath, err := cmd.Flags().GetString(flagAuditLog)
+			if err != nil {
+				return err
+			}
+			if auditLogPath != "" {
+				auditLog, err := audit.Open(auditLogPath)
+				if err != nil {
+					return err
+				}
+				defer auditLog.Close()
+				a.Log.Info("Recording transactions to audit log", zap.String("file", auditLogPath))
+				for _, chain := range chains {
+					if ccp, ok := chain.ChainProvider.(*cosmos.CosmosProvider); ok {
+						ccp.SetAuditLog(auditLog)
+					}
+				}
+			}
+
+			fixturesDir, err := cmd.Flags().GetString(flagRecordFixtures)
+			if err != nil {
+				return err
+			}
+			if fixturesDir != "" {
+				a.Log.Info("Recording chain queries to fixtures", zap.String("dir", fixturesDir))
+				for _, chain := range chains {
+					if ccp, ok := chain.ChainProvider.(*cosmos.CosmosProvider); ok {
+						if err := ccp.RecordFixtures(filepath.Join(fixturesDir, chain.ChainID())); err != nil {
+							return err
+						}
+					}
+				}
+			}
+
+			chaosConfigPath, err := cmd.Flags().GetString(flagChaosConfig)
+			if err != nil {
+				return err
+			}
+			if chaosConfigPath != "" {
+				chaosConfig, err := chaos.LoadConfig(chaosConfigPath)
+				if err != nil {
+					return err
+				}
+				a.Log.Warn("Injecting faults into chain providers", zap.String("chaos_config", chaosConfigPath))
+				for _, chain := range chains {
+					if chain.ChainProvider, err = chaosConfig.Wrap(a.Log, chain.ChainProvider); err != nil {
+						return err
+					}
+				}
+			}
+
+			var notifier *alert.Notifier
+			if a.Config.Global.Alerts != nil {
+				notifier, err = alert.NewNotifier(a.Log.With(zap.String("sys", "alert")), *a.Config.Global.Alerts)
+				if err != nil {
+					return err
+				}
+				defer notifier.Wait()
+			}
+
 			processorType, err := cmd.Flags().GetString(flagProcessor)
 			if err != nil {
 				return err
@@ -132,6 +213,25 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 				return err
 			}
 
+			upgradeClients, err := cmd.Flags().GetBool(flagUpgradeClients)
+			if err != nil {
+				return err
+			}
+			var clientUpgrades *relayer.ClientUpgradeConfig
+			if upgradeClients {
+				clientUpgrades = &relayer.ClientUpgradeConfig{
+					OnChainIDChange: func(oldChainID, newChainID string) error {
+						a.Log.Info("Updating chain ID in config after upgrade",
+							zap.String("old_chain_id", oldChainID),
+							zap.String("new_chain_id", newChainID),
+						)
+						return a.UpdateConfigOnTheFly(cmd, func(cfg *Config) error {
+							return cfg.ChangeChainID(oldChainID, newChainID)
+						})
+					},
+				}
+			}
+
 			rlyErrCh := relayer.StartRelayer(
 				cmd.Context(),
 				a.Log,
@@ -142,6 +242,10 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 				clientUpdateThresholdTime,
 				processorType, initialBlockHistory,
 				prometheusMetrics,
+				notifier,
+				health,
+				states,
+				clientUpgrades,
 			)
 
 			// Block until the error channel sends a message.
@@ -161,9 +265,14 @@ $ %s start demo-path2 --max-tx-size 10`, appName, appName, appName, appName)),
 	cmd = updateTimeFlags(a.Viper, cmd)
 	cmd = strategyFlag(a.Viper, cmd)
 	cmd = debugServerFlags(a.Viper, cmd)
+	cmd = keySwitchTokenFlag(a.Viper, cmd, "enable switching keys with `rly keys rotate --signal` through the debug server, authenticated with this token")
 	cmd = processorFlag(a.Viper, cmd)
 	cmd = initBlockFlag(a.Viper, cmd)
 	cmd = memoFlag(a.Viper, cmd)
+	cmd = auditLogFlag(a.Viper, cmd)
+	cmd = recordFixturesFlag(a.Viper, cmd)
+	cmd = chaosConfigFlag(a.Viper, cmd)
+	cmd = upgradeClientsFlag(a.Viper, cmd)
 	return cmd
 }
 
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
 	"github.com/cosmos/relayer/v2/relayer"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -39,6 +42,7 @@ Most of these commands take a [path] argument. Make sure:
 		createClientCmd(a),
 		updateClientsCmd(a),
 		upgradeClientsCmd(a),
+		recoverClientCmd(a),
 		createConnectionCmd(a),
 		createChannelCmd(a),
 		closeChannelCmd(a),
@@ -301,6 +305,171 @@ func upgradeClientsCmd(a *appState) *cobra.Command {
 	return cmd
 }
 
+// recoveryPollInterval is how often the clients are queried while waiting for a client update proposal to pass.
+const recoveryPollInterval = time.Minute
+
+func recoverClientCmd(a *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover-client path_name chain_id",
+		Short: "recover the expired or frozen client of a configured path on chain_id through governance",
+		Long: `Recovers the expired or frozen client of the path on chain_id with a governance client update proposal.
+A substitute client with the parameters of the expired client is created, unless --substitute is set,
+and the proposal file and the command to submit it are written out. The substitute client is then kept
+updated until the proposal passes and the recovered client is updated, so the path can be relayed again.`,
+		Args: withUsage(cobra.ExactArgs(2)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s transact recover-client demo-path ibc-0
+$ %s tx recover-client demo-path ibc-0 --substitute 07-tendermint-9 --daemon gaiad`,
+			appName, appName,
+		)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := a.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+
+			chainID := args[1]
+			var counterpartyChainID string
+			switch chainID {
+			case src:
+				counterpartyChainID = dst
+			case dst:
+				counterpartyChainID = src
+			default:
+				return fmt.Errorf("chain %s is not on path %s", chainID, args[0])
+			}
+			host, counterparty := c[chainID], c[counterpartyChainID]
+
+			if exists := host.ChainProvider.KeyExists(host.ChainProvider.Key()); !exists {
+				return fmt.Errorf("key %s not found on chain %s", host.ChainProvider.Key(), host.ChainID())
+			}
+			cp, ok := host.ChainProvider.(*cosmos.CosmosProvider)
+			if !ok {
+				return fmt.Errorf("cannot recover clients on %s chain %s", host.ChainProvider.Type(), host.ChainID())
+			}
+
+			substituteID, err := cmd.Flags().GetString(flagSubstitute)
+			if err != nil {
+				return err
+			}
+			trustingPeriod, err := cmd.Flags().GetDuration(flagClientTrustingPeriod)
+			if err != nil {
+				return err
+			}
+			depositStr, err := cmd.Flags().GetString(flagDeposit)
+			if err != nil {
+				return err
+			}
+			daemon, err := cmd.Flags().GetString(flagDaemon)
+			if err != nil {
+				return err
+			}
+			watch, err := cmd.Flags().GetBool(flagWatch)
+			if err != nil {
+				return err
+			}
+
+			subjectID := host.ClientID()
+			subject, err := relayer.QueryClientHealth(cmd.Context(), host, subjectID, time.Now())
+			if err != nil {
+				return err
+			}
+			if subject.Active() {
+				return fmt.Errorf("client %s on chain %s is active, it does not need to be recovered", subjectID, host.ChainID())
+			}
+
+			memo := a.Config.memo(cmd)
+			if substituteID == "" {
+				substituteID, err = relayer.CreateSubstituteClient(cmd.Context(), host, counterparty, subject.ClientState, trustingPeriod, memo)
+				if err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "substitute client: %s\n", substituteID)
+
+			var deposit sdk.Coins
+			if depositStr != "" {
+				if deposit, err = sdk.ParseCoinsNormalized(depositStr); err != nil {
+					return fmt.Errorf("invalid deposit %q: %w", depositStr, err)
+				}
+			} else if deposit, err = cp.QueryMinDeposit(cmd.Context()); err != nil {
+				return err
+			}
+
+			proposal, err := cp.ClientUpdateProposal(
+				fmt.Sprintf("Recover IBC client %s", subjectID),
+				fmt.Sprintf("Replace the state of the %s client %s tracking %s with the state of the active client %s, so that its connections and channels can be relayed again.",
+					subjectStatus(subject), subjectID, counterparty.ChainID(), substituteID),
+				subjectID, substituteID, deposit,
+			)
+			if err != nil {
+				return err
+			}
+			dir := filepath.Join(a.HomePath, "proposals")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			file := filepath.Join(dir, fmt.Sprintf("recover-%s-%s.json", host.ChainID(), subjectID))
+			if err := os.WriteFile(file, proposal, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "proposal:          %s\n", file)
+			fmt.Fprintf(cmd.OutOrStdout(), "submit it with:\n  %s\n", submitProposalCommand(daemon, file, cp.PCfg))
+
+			if !watch {
+				return nil
+			}
+			recovery := &relayer.ClientRecovery{
+				Src:                host,
+				Dst:                counterparty,
+				SubjectClientID:    subjectID,
+				SubstituteClientID: substituteID,
+				Memo:               memo,
+				PollInterval:       recoveryPollInterval,
+			}
+			a.Log.Info(
+				"Waiting for the client update proposal to pass",
+				zap.String("chain_id", host.ChainID()),
+				zap.String("subject_client_id", subjectID),
+				zap.String("substitute_client_id", substituteID),
+			)
+			if err := recovery.Watch(cmd.Context()); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "client %s on chain %s recovered, path %s can be relayed again\n", subjectID, host.ChainID(), args[0])
+			return nil
+		},
+	}
+
+	cmd = recoverClientFlags(a.Viper, cmd)
+	cmd = memoFlag(a.Viper, cmd)
+	return cmd
+}
+
+// subjectStatus describes why the subject client of a client update proposal must be recovered.
+func subjectStatus(subject *relayer.ClientHealth) string {
+	if subject.Frozen {
+		return "frozen"
+	}
+	return "expired"
+}
+
+// submitProposalCommand returns the command submitting the proposal file with the binary daemon of the chain of pcfg.
+func submitProposalCommand(daemon, file string, pcfg cosmos.CosmosProviderConfig) string {
+	if daemon == "" {
+		daemon = "<daemon>"
+	}
+	command := fmt.Sprintf("%s tx gov submit-proposal %s --from <proposer> --chain-id %s --node %s --gas auto",
+		daemon, file, pcfg.ChainID, pcfg.RPCAddr)
+	if pcfg.GasAdjustment != 0 {
+		command += fmt.Sprintf(" --gas-adjustment %g", pcfg.GasAdjustment)
+	}
+	if pcfg.GasPrices != "" {
+		command += " --gas-prices " + pcfg.GasPrices
+	}
+	return command
+}
+
 func createConnectionCmd(a *appState) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "connection path_name",
@@ -368,6 +537,11 @@ $ %s tx conn demo-path --timeout 5s`,
 				return err
 			}
 
+			delayPeriod, err := cmd.Flags().GetDuration(flagDelayPeriod)
+			if err != nil {
+				return err
+			}
+
 			// ensure that the clients exist
 			clientSrc, clientDst, err := c[src].CreateClients(cmd.Context(), c[dst], allowUpdateAfterExpiry, allowUpdateAfterMisbehaviour, override, customClientTrustingPeriod, memo)
 			if err != nil {
@@ -379,7 +553,7 @@ $ %s tx conn demo-path --timeout 5s`,
 				}
 			}
 
-			connectionSrc, connectionDst, err := c[src].CreateOpenConnections(cmd.Context(), c[dst], retries, to, memo, initialBlockHistory, pathName)
+			connectionSrc, connectionDst, err := c[src].CreateOpenConnections(cmd.Context(), c[dst], retries, to, memo, initialBlockHistory, pathName, delayPeriod)
 			if err != nil {
 				return err
 			}
@@ -399,6 +573,7 @@ $ %s tx conn demo-path --timeout 5s`,
 	cmd = overrideFlag(a.Viper, cmd)
 	cmd = memoFlag(a.Viper, cmd)
 	cmd = initBlockFlag(a.Viper, cmd)
+	cmd = delayPeriodFlag(a.Viper, cmd)
 	return cmd
 }
 
@@ -639,6 +814,11 @@ $ %s tx connect demo-path --src-port transfer --dst-port transfer --order unorde
 				return err
 			}
 
+			delayPeriod, err := cmd.Flags().GetDuration(flagDelayPeriod)
+			if err != nil {
+				return err
+			}
+
 			// create clients if they aren't already created
 			clientSrc, clientDst, err := c[src].CreateClients(cmd.Context(), c[dst], allowUpdateAfterExpiry, allowUpdateAfterMisbehaviour, override, customClientTrustingPeriod, memo)
 			if err != nil {
@@ -651,7 +831,7 @@ $ %s tx connect demo-path --src-port transfer --dst-port transfer --order unorde
 			}
 
 			// create connection if it isn't already created
-			connectionSrc, connectionDst, err := c[src].CreateOpenConnections(cmd.Context(), c[dst], retries, to, memo, initialBlockHistory, pathName)
+			connectionSrc, connectionDst, err := c[src].CreateOpenConnections(cmd.Context(), c[dst], retries, to, memo, initialBlockHistory, pathName, delayPeriod)
 			if err != nil {
 				return fmt.Errorf("error creating connections: %w", err)
 			}
@@ -672,6 +852,7 @@ $ %s tx connect demo-path --src-port transfer --dst-port transfer --order unorde
 	cmd = overrideFlag(a.Viper, cmd)
 	cmd = memoFlag(a.Viper, cmd)
 	cmd = initBlockFlag(a.Viper, cmd)
+	cmd = delayPeriodFlag(a.Viper, cmd)
 	return cmd
 }
 
@@ -714,6 +895,7 @@ $ %s tx link-then-start demo-path --timeout 5s`, appName, appName)),
 	cmd = memoFlag(a.Viper, cmd)
 	cmd = debugServerFlags(a.Viper, cmd)
 	cmd = initBlockFlag(a.Viper, cmd)
+	cmd = delayPeriodFlag(a.Viper, cmd)
 	cmd = processorFlag(a.Viper, cmd)
 	cmd = updateTimeFlags(a.Viper, cmd)
 	return cmd
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitProposalCommand(t *testing.T) {
+	pcfg := cosmos.CosmosProviderConfig{
+		ChainID:       "cosmoshub-4",
+		RPCAddr:       "https://rpc.cosmos.network:443",
+		GasAdjustment: 1.3,
+		GasPrices:     "0.0025uatom",
+	}
+	require.Equal(t,
+		"gaiad tx gov submit-proposal recover.json --from <proposer> --chain-id cosmoshub-4 --node https://rpc.cosmos.network:443 --gas auto --gas-adjustment 1.3 --gas-prices 0.0025uatom",
+		submitProposalCommand("gaiad", "recover.json", pcfg),
+	)
+
+	pcfg.GasAdjustment, pcfg.GasPrices = 0, ""
+	require.Equal(t,
+		"<daemon> tx gov submit-proposal recover.json --from <proposer> --chain-id cosmoshub-4 --node https://rpc.cosmos.network:443 --gas auto",
+		submitProposalCommand("", "recover.json", pcfg),
+	)
+}
@@ -2,20 +2,48 @@ package relaydebug
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"strings"
+	"time"
 
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
+// stateRequestTimeout is how long to wait for the path processors to respond to a state request.
+const stateRequestTimeout = 10 * time.Second
+
+// KeySwitch enables the /relayer/keys endpoint, which switches the key of the chains in Keys, indexed by chain ID.
+// Requests must be authenticated with Token as a bearer token, the endpoint is disabled if it is empty.
+type KeySwitch struct {
+	Token string
+	Keys  map[string]provider.KeySwitcher
+}
+
 // StartDebugServer starts a debug server in a background goroutine,
 // accepting connections on the given listener.
 // Any HTTP logging will be written at info level to the given logger.
 // The server will be forcefully shut down when ctx finishes.
-func StartDebugServer(ctx context.Context, log *zap.Logger, ln net.Listener, registry *prometheus.Registry) {
+// The /healthz and /readyz endpoints report the liveness and readiness tracked by health,
+// /relayer/state reports the runtime state of the path processors registered with states,
+// and /relayer/keys switches keys if enabled by keySwitch.
+func StartDebugServer(
+	ctx context.Context,
+	log *zap.Logger,
+	ln net.Listener,
+	registry *prometheus.Registry,
+	health *processor.Health,
+	states *processor.StateRegistry,
+	keySwitch KeySwitch,
+) {
 	// Although we could just import net/http/pprof and rely on the default global server,
 	// we may want many instances of this in test,
 	// and we will probably want more endpoints as time goes on,
@@ -39,6 +67,61 @@ func StartDebugServer(ctx context.Context, log *zap.Logger, ln net.Listener, reg
 	// Serve relayer metrics
 	mux.Handle("/relayer/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
+	// Serve path processor state, optionally filtered with ?path=
+	mux.HandleFunc("/relayer/state", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), stateRequestTimeout)
+		defer cancel()
+		s, err := states.Snapshot(ctx, r.URL.Query().Get("path"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s); err != nil {
+			log.Info("Failed to write relayer state", zap.Error(err))
+		}
+	})
+
+	// Switch the key of a chain with POST ?chain=&key=, as signalled by `rly keys rotate`.
+	// The debug server may be reachable from other hosts, e.g. for probes, so this requires the token.
+	mux.HandleFunc("/relayer/keys", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if keySwitch.Token == "" {
+			http.Error(w, "key switching is not enabled", http.StatusForbidden)
+			return
+		}
+		if !keySwitch.authorized(r) {
+			log.Warn("Rejected unauthorized key switch request", zap.String("remote_addr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		chainID, key := r.URL.Query().Get("chain"), r.URL.Query().Get("key")
+		ks, ok := keySwitch.Keys[chainID]
+		if !ok {
+			http.Error(w, fmt.Sprintf("chain %s is not relayed or cannot switch keys", chainID), http.StatusNotFound)
+			return
+		}
+		if err := ks.UseKey(key); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Info("Switched relayer key", zap.String("chain_id", chainID), zap.String("key", key))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// Serve liveness and readiness probes
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		status := health.Status(processor.DefaultLivenessTimeout)
+		writeHealthStatus(w, log, status, status.Live)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := health.Status(processor.DefaultLivenessTimeout)
+		writeHealthStatus(w, log, status, status.Ready)
+	})
+
 	srv := &http.Server{
 		Handler:  mux,
 		ErrorLog: zap.NewStdLog(log),
@@ -54,3 +137,23 @@ func StartDebugServer(ctx context.Context, log *zap.Logger, ln net.Listener, reg
 		srv.Close()
 	}()
 }
+
+// authorized reports whether the request carries the token of the key switch.
+func (k KeySwitch) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(k.Token)) == 1
+}
+
+// writeHealthStatus writes the status as JSON, with a 503 status code if ok is false.
+func writeHealthStatus(w http.ResponseWriter, log *zap.Logger, status processor.HealthStatus, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Info("Failed to write health status", zap.Error(err))
+	}
+}
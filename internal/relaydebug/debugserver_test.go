@@ -0,0 +1,68 @@
+package relaydebug_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/internal/relaydebug"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingKeySwitcher struct {
+	key string
+}
+
+func (ks *recordingKeySwitcher) UseKey(key string) error {
+	ks.key = key
+	return nil
+}
+
+func startTestDebugServer(t *testing.T, keySwitch relaydebug.KeySwitch) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	relaydebug.StartDebugServer(ctx, zap.NewNop(), ln, prometheus.NewRegistry(), processor.NewHealth(), processor.NewStateRegistry(), keySwitch)
+	return "http://" + ln.Addr().String()
+}
+
+func postKeySwitch(t *testing.T, addr, token string) int {
+	req, err := http.NewRequest(http.MethodPost, addr+"/relayer/keys?chain=chain-a&key=new-key", nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestKeySwitchDisabledWithoutToken(t *testing.T) {
+	ks := &recordingKeySwitcher{}
+	addr := startTestDebugServer(t, relaydebug.KeySwitch{Keys: map[string]provider.KeySwitcher{"chain-a": ks}})
+
+	require.Equal(t, http.StatusForbidden, postKeySwitch(t, addr, "secret"))
+	require.Empty(t, ks.key)
+}
+
+func TestKeySwitchRequiresToken(t *testing.T) {
+	ks := &recordingKeySwitcher{}
+	addr := startTestDebugServer(t, relaydebug.KeySwitch{
+		Token: "secret",
+		Keys:  map[string]provider.KeySwitcher{"chain-a": ks},
+	})
+
+	require.Equal(t, http.StatusUnauthorized, postKeySwitch(t, addr, ""))
+	require.Equal(t, http.StatusUnauthorized, postKeySwitch(t, addr, "wrong"))
+	require.Empty(t, ks.key)
+
+	require.Equal(t, http.StatusNoContent, postKeySwitch(t, addr, "secret"))
+	require.Equal(t, "new-key", ks.key)
+}
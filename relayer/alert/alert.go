@@ -0,0 +1,272 @@
+// Package alert delivers notifications about operational events detected by the relayer,
+// such as clients approaching expiration or a low wallet balance, to webhooks and commands.
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sendTimeout is how long delivery of a single alert to a single sink may take.
+const sendTimeout = 10 * time.Second
+
+// Alert is a single notification sent to the configured sinks.
+type Alert struct {
+	Type    Type              `json:"type"`
+	Time    time.Time         `json:"time"`
+	ChainID string            `json:"chain_id"`
+	Path    string            `json:"path,omitempty"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type configuredSink struct {
+	sink
+	types map[Type]bool
+}
+
+// Notifier evaluates operational events against the configured rules and delivers alerts to sinks.
+// All methods are safe for concurrent use and are no-ops on a nil *Notifier,
+// so callers do not need to check whether alerting is enabled.
+type Notifier struct {
+	log   *zap.Logger
+	sinks []configuredSink
+	rules map[Type]rule
+
+	mu sync.Mutex
+	// lastFired tracks when each de-duplication key last fired.
+	lastFired map[string]time.Time
+	// sendFailures tracks consecutive send failures by path and chain.
+	sendFailures map[string]int
+
+	// wg tracks in-flight deliveries.
+	wg sync.WaitGroup
+}
+
+// NewNotifier returns a Notifier for the config.
+func NewNotifier(log *zap.Logger, cfg Config) (*Notifier, error) {
+	rules, err := cfg.rules()
+	if err != nil {
+		return nil, err
+	}
+	n := &Notifier{
+		log:          log,
+		rules:        rules,
+		lastFired:    make(map[string]time.Time),
+		sendFailures: make(map[string]int),
+	}
+	for i, sc := range cfg.Sinks {
+		s, err := newSink(sc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert sink %d: %w", i, err)
+		}
+		cs := configuredSink{sink: s}
+		if len(sc.Types) > 0 {
+			cs.types = make(map[Type]bool, len(sc.Types))
+			for _, t := range sc.Types {
+				cs.types[t] = true
+			}
+		}
+		n.sinks = append(n.sinks, cs)
+	}
+	return n, nil
+}
+
+// ClientCloseToExpiration is called with the time since the last update of a client on chainID.
+// It fires when that exceeds the threshold fraction of the trusting period.
+func (n *Notifier) ClientCloseToExpiration(path, chainID, clientID string, trustingPeriod, sinceUpdate time.Duration) {
+	if n == nil || trustingPeriod <= 0 {
+		return
+	}
+	r := n.rules[ClientExpiration]
+	if r.disabled {
+		return
+	}
+	elapsed := float64(sinceUpdate) / float64(trustingPeriod)
+	if elapsed < r.thresholdFor(chainID) {
+		return
+	}
+	n.fire(Alert{
+		Type:    ClientExpiration,
+		ChainID: chainID,
+		Path:    path,
+		Message: fmt.Sprintf("Client %s on %s is close to expiration, %.0f%% of its trusting period has elapsed since the last update", clientID, chainID, elapsed*100),
+		Details: map[string]string{
+			"client_id":         clientID,
+			"trusting_period":   trustingPeriod.String(),
+			"time_since_update": sinceUpdate.Round(time.Second).String(),
+		},
+	}, clientID)
+}
+
+// MessagesSent resets the consecutive send failure count for the chain on the path.
+func (n *Notifier) MessagesSent(path, chainID string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.sendFailures, path+"/"+chainID)
+}
+
+// SendFailed records a failure to send messages to the chain on the path.
+// It fires once the number of consecutive failures reaches the threshold.
+func (n *Notifier) SendFailed(path, chainID string, err error) {
+	if n == nil {
+		return
+	}
+	r := n.rules[SendFailures]
+	if r.disabled {
+		return
+	}
+
+	key := path + "/" + chainID
+	n.mu.Lock()
+	n.sendFailures[key]++
+	failures := n.sendFailures[key]
+	n.mu.Unlock()
+
+	if float64(failures) < r.thresholdFor(chainID) {
+		return
+	}
+	n.fire(Alert{
+		Type:    SendFailures,
+		ChainID: chainID,
+		Path:    path,
+		Message: fmt.Sprintf("Sending messages to %s on path %s has failed %d times in a row", chainID, path, failures),
+		Details: map[string]string{
+			"consecutive_failures": strconv.Itoa(failures),
+			"error":                err.Error(),
+		},
+	}, "")
+}
+
+// ChainBehind is called after each query cycle of a chain processor with the latest
+// height of the chain and the latest height that has been processed.
+func (n *Notifier) ChainBehind(chainID string, latestHeight, latestQueriedHeight int64) {
+	if n == nil {
+		return
+	}
+	r := n.rules[ChainOutOfSync]
+	if r.disabled {
+		return
+	}
+	behind := latestHeight - latestQueriedHeight
+	if float64(behind) < r.thresholdFor(chainID) {
+		return
+	}
+	n.fire(Alert{
+		Type:    ChainOutOfSync,
+		ChainID: chainID,
+		Message: fmt.Sprintf("Chain %s is out of sync, %d blocks behind the latest height", chainID, behind),
+		Details: map[string]string{
+			"latest_height":         strconv.FormatInt(latestHeight, 10),
+			"latest_queried_height": strconv.FormatInt(latestQueriedHeight, 10),
+		},
+	}, "")
+}
+
+// WalletBalance is called with the relayer wallet balance of a gas denom on a chain.
+// It fires when the balance is below the threshold.
+func (n *Notifier) WalletBalance(chainID, key, denom string, balance float64) {
+	if n == nil {
+		return
+	}
+	r := n.rules[LowBalance]
+	if r.disabled {
+		return
+	}
+	threshold := r.thresholdFor(chainID)
+	if threshold <= 0 || balance >= threshold {
+		return
+	}
+	n.fire(Alert{
+		Type:    LowBalance,
+		ChainID: chainID,
+		Message: fmt.Sprintf("Relayer wallet %s on %s is low on funds: %.0f%s", key, chainID, balance, denom),
+		Details: map[string]string{
+			"key":       key,
+			"denom":     denom,
+			"balance":   strconv.FormatFloat(balance, 'f', -1, 64),
+			"threshold": strconv.FormatFloat(threshold, 'f', -1, 64),
+		},
+	}, key+"/"+denom)
+}
+
+// MaxRetriesExceeded is called when the relayer gives up on sending a message to the chain on the path.
+// Alerts are de-duplicated by path, chain and event type, so subject is only informational.
+func (n *Notifier) MaxRetriesExceeded(path, chainID, eventType, subject string, maxRetries int) {
+	if n == nil {
+		return
+	}
+	if n.rules[MaxRetriesExceeded].disabled {
+		return
+	}
+	n.fire(Alert{
+		Type:    MaxRetriesExceeded,
+		ChainID: chainID,
+		Path:    path,
+		Message: fmt.Sprintf("Gave up on sending %s message to %s on path %s after %d retries", eventType, chainID, path, maxRetries),
+		Details: map[string]string{
+			"event_type":  eventType,
+			"subject":     subject,
+			"max_retries": strconv.Itoa(maxRetries),
+		},
+	}, eventType)
+}
+
+// Wait blocks until all in-flight alert deliveries have completed.
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// fire delivers the alert to all sinks accepting its type, unless an alert with
+// the same type, chain, path and subject fired within the de-duplication window.
+func (n *Notifier) fire(a Alert, subject string) {
+	a.Time = time.Now().UTC()
+
+	key := string(a.Type) + "/" + a.ChainID + "/" + a.Path + "/" + subject
+	window := n.rules[a.Type].dedupWindow
+
+	n.mu.Lock()
+	if last, ok := n.lastFired[key]; ok && a.Time.Sub(last) < window {
+		n.mu.Unlock()
+		return
+	}
+	n.lastFired[key] = a.Time
+	n.mu.Unlock()
+
+	n.log.Warn("Firing alert",
+		zap.String("type", string(a.Type)),
+		zap.String("chain_id", a.ChainID),
+		zap.String("path", a.Path),
+		zap.String("message", a.Message),
+	)
+
+	for _, s := range n.sinks {
+		if s.types != nil && !s.types[a.Type] {
+			continue
+		}
+		n.wg.Add(1)
+		go func(s sink) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+			if err := s.send(ctx, a); err != nil {
+				n.log.Error("Failed to deliver alert",
+					zap.String("type", string(a.Type)),
+					zap.String("chain_id", a.ChainID),
+					zap.Error(err),
+				)
+			}
+		}(s.sink)
+	}
+}
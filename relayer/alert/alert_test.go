@@ -0,0 +1,144 @@
+package alert_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/alert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recorder is an http handler that records the JSON bodies posted to it.
+type recorder struct {
+	mu     sync.Mutex
+	bodies []map[string]any
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]any
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	r.mu.Unlock()
+}
+
+func (r *recorder) received() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.bodies...)
+}
+
+func newNotifier(t *testing.T, cfg alert.Config) *alert.Notifier {
+	t.Helper()
+	n, err := alert.NewNotifier(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return n
+}
+
+func TestNotifierHTTPSinkAndDedup(t *testing.T) {
+	var rec recorder
+	srv := httptest.NewServer(&rec)
+	defer srv.Close()
+
+	n := newNotifier(t, alert.Config{
+		Sinks: []alert.SinkConfig{{Type: alert.SinkHTTP, URL: srv.URL}},
+	})
+
+	n.ChainBehind("chain-a", 100, 95)
+	n.Wait()
+	require.Empty(t, rec.received(), "below default threshold")
+
+	n.ChainBehind("chain-a", 100, 50)
+	n.ChainBehind("chain-a", 101, 50)
+	n.Wait()
+
+	got := rec.received()
+	require.Len(t, got, 1, "second alert should be de-duplicated")
+	require.Equal(t, string(alert.ChainOutOfSync), got[0]["type"])
+	require.Equal(t, "chain-a", got[0]["chain_id"])
+}
+
+func TestNotifierSendFailuresThreshold(t *testing.T) {
+	var rec recorder
+	srv := httptest.NewServer(&rec)
+	defer srv.Close()
+
+	n := newNotifier(t, alert.Config{
+		Sinks: []alert.SinkConfig{{Type: alert.SinkSlack, URL: srv.URL}},
+		Rules: map[alert.Type]alert.Rule{
+			alert.SendFailures: {Threshold: 2, DedupWindow: "0s"},
+		},
+	})
+
+	sendErr := errors.New("account sequence mismatch")
+
+	n.SendFailed("a-b", "chain-b", sendErr)
+	n.MessagesSent("a-b", "chain-b")
+	n.SendFailed("a-b", "chain-b", sendErr)
+	n.Wait()
+	require.Empty(t, rec.received(), "success should reset consecutive failures")
+
+	n.SendFailed("a-b", "chain-b", sendErr)
+	n.SendFailed("a-b", "chain-b", sendErr)
+	n.Wait()
+
+	got := rec.received()
+	require.Len(t, got, 2)
+	require.Contains(t, got[0]["text"], "send-failures")
+}
+
+func TestNotifierLowBalanceChainThresholds(t *testing.T) {
+	var rec recorder
+	srv := httptest.NewServer(&rec)
+	defer srv.Close()
+
+	n := newNotifier(t, alert.Config{
+		Sinks: []alert.SinkConfig{{Type: alert.SinkHTTP, URL: srv.URL, Types: []alert.Type{alert.LowBalance}}},
+		Rules: map[alert.Type]alert.Rule{
+			alert.LowBalance: {ChainThresholds: map[string]float64{"chain-a": 1000}},
+		},
+	})
+
+	n.WalletBalance("chain-a", "default", "uatom", 5000)
+	n.WalletBalance("chain-b", "default", "uosmo", 1)
+	// filtered out by the sink alert types.
+	n.ClientCloseToExpiration("a-b", "chain-a", "07-tendermint-0", time.Hour, 50*time.Minute)
+	n.Wait()
+	require.Empty(t, rec.received())
+
+	n.WalletBalance("chain-a", "default", "uatom", 999)
+	n.Wait()
+
+	got := rec.received()
+	require.Len(t, got, 1)
+	require.Equal(t, string(alert.LowBalance), got[0]["type"])
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, (&alert.Config{}).Validate())
+
+	require.Error(t, (&alert.Config{
+		Sinks: []alert.SinkConfig{{Type: "pager"}},
+	}).Validate())
+
+	require.Error(t, (&alert.Config{
+		Sinks: []alert.SinkConfig{{Type: alert.SinkExec}},
+	}).Validate())
+
+	require.Error(t, (&alert.Config{
+		Rules: map[alert.Type]alert.Rule{"unknown": {}},
+	}).Validate())
+
+	require.Error(t, (&alert.Config{
+		Rules: map[alert.Type]alert.Rule{alert.LowBalance: {DedupWindow: "soon"}},
+	}).Validate())
+}
@@ -0,0 +1,181 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Type identifies the kind of operational event an alert is raised for.
+type Type string
+
+const (
+	// ClientExpiration fires when the time since the last client update exceeds
+	// the threshold fraction of the client trusting period.
+	ClientExpiration Type = "client-expiration"
+
+	// SendFailures fires when sending messages to a chain on a path has failed
+	// at least threshold times in a row.
+	SendFailures Type = "send-failures"
+
+	// ChainOutOfSync fires when a chain processor has fallen at least threshold
+	// blocks behind the latest height of the chain.
+	ChainOutOfSync Type = "chain-out-of-sync"
+
+	// LowBalance fires when the relayer wallet balance of a gas denom is below threshold.
+	LowBalance Type = "low-balance"
+
+	// MaxRetriesExceeded fires when the relayer gives up on a message after it
+	// could not be sent within the maximum number of retries.
+	MaxRetriesExceeded Type = "max-retries-exceeded"
+)
+
+// Types is the list of all supported alert types.
+var Types = []Type{ClientExpiration, SendFailures, ChainOutOfSync, LowBalance, MaxRetriesExceeded}
+
+// defaultThresholds are used when a rule does not set a threshold.
+// A zero threshold for LowBalance means the rule only applies to chains listed in chain-thresholds.
+var defaultThresholds = map[Type]float64{
+	ClientExpiration:   0.75,
+	SendFailures:       3,
+	ChainOutOfSync:     20,
+	LowBalance:         0,
+	MaxRetriesExceeded: 0,
+}
+
+// defaultDedupWindow is how long an identical alert is suppressed for after it fires.
+const defaultDedupWindow = 15 * time.Minute
+
+// Sink types
+const (
+	SinkSlack = "slack"
+	SinkHTTP  = "http"
+	SinkExec  = "exec"
+)
+
+// Config describes where alerts are delivered to and when they fire.
+type Config struct {
+	Sinks []SinkConfig  `yaml:"sinks" json:"sinks"`
+	Rules map[Type]Rule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// SinkConfig describes a single alert destination.
+type SinkConfig struct {
+	// Type is one of slack, http or exec.
+	Type string `yaml:"type" json:"type"`
+
+	// URL is the webhook URL for slack and http sinks.
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Headers are added to the webhook request for http sinks.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// Command is the program and arguments to run for exec sinks.
+	// The alert is written to its stdin as JSON.
+	Command []string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// Types restricts the alert types delivered to this sink. Empty means all types.
+	Types []Type `yaml:"types,omitempty" json:"types,omitempty"`
+}
+
+// Rule holds the thresholds and de-duplication window for a single alert type.
+type Rule struct {
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// Threshold is the default threshold for all chains. Its unit depends on the alert type.
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty"`
+
+	// ChainThresholds overrides Threshold for specific chain IDs.
+	ChainThresholds map[string]float64 `yaml:"chain-thresholds,omitempty" json:"chain-thresholds,omitempty"`
+
+	// DedupWindow is how long identical alerts are suppressed for after firing, e.g. "15m".
+	DedupWindow string `yaml:"dedup-window,omitempty" json:"dedup-window,omitempty"`
+}
+
+// rule is the parsed form of Rule with defaults applied.
+type rule struct {
+	disabled        bool
+	threshold       float64
+	chainThresholds map[string]float64
+	dedupWindow     time.Duration
+}
+
+// thresholdFor returns the threshold for the chain ID.
+func (r rule) thresholdFor(chainID string) float64 {
+	if t, ok := r.chainThresholds[chainID]; ok {
+		return t
+	}
+	return r.threshold
+}
+
+// Validate returns an error if the config contains unknown sinks, alert types or invalid durations.
+func (c *Config) Validate() error {
+	_, err := c.rules()
+	if err != nil {
+		return err
+	}
+	for i, s := range c.Sinks {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("invalid alert sink %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s SinkConfig) validate() error {
+	switch s.Type {
+	case SinkSlack, SinkHTTP:
+		if s.URL == "" {
+			return fmt.Errorf("%s sink requires a url", s.Type)
+		}
+	case SinkExec:
+		if len(s.Command) == 0 {
+			return errors.New("exec sink requires a command")
+		}
+	default:
+		return fmt.Errorf("unknown sink type %q, expected one of: [%s, %s, %s]", s.Type, SinkSlack, SinkHTTP, SinkExec)
+	}
+	for _, t := range s.Types {
+		if !validType(t) {
+			return fmt.Errorf("unknown alert type %q", t)
+		}
+	}
+	return nil
+}
+
+// rules parses the configured rules, applying defaults for any alert types not configured.
+func (c *Config) rules() (map[Type]rule, error) {
+	rules := make(map[Type]rule, len(Types))
+	for _, t := range Types {
+		rules[t] = rule{threshold: defaultThresholds[t], dedupWindow: defaultDedupWindow}
+	}
+	for t, r := range c.Rules {
+		if !validType(t) {
+			return nil, fmt.Errorf("unknown alert type %q", t)
+		}
+		parsed := rules[t]
+		parsed.disabled = r.Disabled
+		if r.Threshold != 0 {
+			parsed.threshold = r.Threshold
+		}
+		parsed.chainThresholds = r.ChainThresholds
+		if r.DedupWindow != "" {
+			d, err := time.ParseDuration(r.DedupWindow)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dedup-window for alert type %q: %w", t, err)
+			}
+			parsed.dedupWindow = d
+		}
+		rules[t] = parsed
+	}
+	return rules, nil
+}
+
+func validType(t Type) bool {
+	for _, v := range Types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
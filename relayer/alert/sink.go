@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// sink delivers alerts to a single destination.
+type sink interface {
+	send(ctx context.Context, a Alert) error
+}
+
+// newSink returns the sink implementation for the config.
+func newSink(cfg SinkConfig) (sink, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case SinkSlack:
+		return slackSink{url: cfg.URL}, nil
+	case SinkHTTP:
+		return httpSink{url: cfg.URL, headers: cfg.Headers}, nil
+	default:
+		return execSink{command: cfg.Command}, nil
+	}
+}
+
+// slackSink posts alerts to a Slack compatible incoming webhook.
+type slackSink struct {
+	url string
+}
+
+func (s slackSink) send(ctx context.Context, a Alert) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*[%s]* %s", a.Type, a.Message)
+
+	keys := make([]string, 0, len(a.Details))
+	for k := range a.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&text, "\n• %s: `%s`", k, a.Details[k])
+	}
+
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: text.String()})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.url, nil, body)
+}
+
+// httpSink posts alerts as JSON to an arbitrary endpoint.
+type httpSink struct {
+	url     string
+	headers map[string]string
+}
+
+func (s httpSink) send(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.url, s.headers, body)
+}
+
+func postJSON(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", res.StatusCode, msg)
+	}
+	return nil
+}
+
+// execSink runs a command for each alert, writing the alert JSON to its stdin.
+// The alert type, chain ID, path and message are also available as RLY_ALERT_* environment variables.
+type execSink struct {
+	command []string
+}
+
+func (s execSink) send(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"RLY_ALERT_TYPE="+string(a.Type),
+		"RLY_ALERT_CHAIN_ID="+a.ChainID,
+		"RLY_ALERT_PATH="+a.Path,
+		"RLY_ALERT_MESSAGE="+a.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("alert command failed: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package audit provides an append-only, JSON lines encoded record of every transaction
+// broadcast by the relayer, along with tools for summarizing those records.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single audit record for a broadcast transaction.
+type Entry struct {
+	Time      time.Time   `json:"time"`
+	ChainID   string      `json:"chain_id"`
+	Key       string      `json:"key,omitempty"`
+	TxHash    string      `json:"tx_hash"`
+	Height    int64       `json:"height"`
+	Code      uint32      `json:"code"`
+	Fee       string      `json:"fee"`
+	GasWanted int64       `json:"gas_wanted"`
+	GasUsed   int64       `json:"gas_used"`
+	Memo      string      `json:"memo,omitempty"`
+	MsgTypes  []string    `json:"msg_types"`
+	ClientIDs []string    `json:"client_ids,omitempty"`
+	Packets   []PacketRef `json:"packets,omitempty"`
+
+	// Error is the reason a broadcast failed before the transaction was executed,
+	// e.g. a CheckTx rejection. Such entries may have no tx hash or height.
+	Error string `json:"error,omitempty"`
+}
+
+// Success returns true if the transaction was broadcast and executed successfully.
+func (e Entry) Success() bool {
+	return e.Code == 0 && e.Error == ""
+}
+
+// PacketRef identifies a packet that a message in the transaction operated on.
+// Channel and port identifiers are from the perspective of the packet's source chain.
+type PacketRef struct {
+	MsgType    string `json:"msg_type"`
+	Sequence   uint64 `json:"sequence,omitempty"`
+	SrcChannel string `json:"src_channel"`
+	SrcPort    string `json:"src_port"`
+	DstChannel string `json:"dst_channel,omitempty"`
+	DstPort    string `json:"dst_port,omitempty"`
+}
+
+// Channel returns the channel on the broadcasting chain that this packet message was relayed on.
+// MsgRecvPacket is executed on the destination chain, every other packet message on the source chain.
+func (p PacketRef) Channel() string {
+	if p.DstChannel != "" && isRecvPacket(p.MsgType) {
+		return p.DstChannel
+	}
+	return p.SrcChannel
+}
+
+// Port returns the port on the broadcasting chain of the channel returned by Channel.
+func (p PacketRef) Port() string {
+	if p.DstChannel != "" && isRecvPacket(p.MsgType) {
+		return p.DstPort
+	}
+	return p.SrcPort
+}
+
+// Log is a concurrency safe, append-only writer of audit entries to a file.
+type Log struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open opens the audit log at the given file path for appending, creating it if necessary.
+func Open(filePath string) (*Log, error) {
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", filePath, err)
+	}
+	return &Log{f: f}, nil
+}
+
+// Record appends the entry as a single JSON line.
+func (l *Log) Record(e Entry) error {
+	out, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A single write call per entry keeps lines intact with O_APPEND.
+	_, err = l.f.Write(out)
+	return err
+}
+
+// Close closes the underlying file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+func isRecvPacket(msgType string) bool {
+	return msgType == "/ibc.core.channel.v1.MsgRecvPacket"
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// dayFormat is the layout used for grouping entries by UTC day.
+const dayFormat = "2006-01-02"
+
+// PathResolver returns the configured path name for a client on a chain,
+// or an empty string if the client does not belong to a known path.
+type PathResolver func(chainID, clientID string) string
+
+// ChannelPathResolver returns the configured path name for a channel on a chain,
+// or an empty string if the channel does not belong to a known path.
+type ChannelPathResolver func(chainID, portID, channelID string) string
+
+// SummaryKey is the grouping key for summarized audit entries.
+type SummaryKey struct {
+	Day     string `json:"day"`
+	Path    string `json:"path"`
+	ChainID string `json:"chain_id"`
+	Channel string `json:"channel"`
+}
+
+// SummaryRow holds the aggregated totals for a single SummaryKey.
+type SummaryRow struct {
+	SummaryKey
+	Txs       int       `json:"txs"`
+	FailedTxs int       `json:"failed_txs"`
+	Packets   int       `json:"packets"`
+	GasWanted int64     `json:"gas_wanted"`
+	GasUsed   int64     `json:"gas_used"`
+	Fees      sdk.Coins `json:"fees"`
+}
+
+// ReadEntries decodes all audit entries from r.
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := scanner.Bytes()
+		if len(b) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(b, &e); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry on line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Summarize aggregates entries by day, path, chain and channel.
+//
+// Transactions relaying packets on multiple channels have their fees and gas
+// apportioned by the number of packet messages on each channel.
+// Transactions without packet messages, e.g. client updates and handshakes,
+// are grouped under an empty channel.
+//
+// The path of an entry is resolved from its updated client IDs with resolveClient,
+// falling back to the channels of its packets with resolveChannel, e.g. for packet-only transactions.
+func Summarize(entries []Entry, resolveClient PathResolver, resolveChannel ChannelPathResolver) []SummaryRow {
+	rows := make(map[SummaryKey]*SummaryRow)
+
+	for _, e := range entries {
+		path := entryPath(e, resolveClient, resolveChannel)
+		day := e.Time.UTC().Format(dayFormat)
+
+		fees, err := sdk.ParseCoinsNormalized(e.Fee)
+		if err != nil {
+			fees = sdk.NewCoins()
+		}
+
+		perChannel := make(map[string]int)
+		var channels []string
+		for _, p := range e.Packets {
+			ch := p.Channel()
+			if _, ok := perChannel[ch]; !ok {
+				channels = append(channels, ch)
+			}
+			perChannel[ch]++
+		}
+		if len(channels) == 0 {
+			channels = []string{""}
+			perChannel[""] = 0
+		}
+		sort.Strings(channels)
+
+		totalPackets := int64(len(e.Packets))
+		remainingFees := fees
+		remainingGasWanted, remainingGasUsed := e.GasWanted, e.GasUsed
+
+		for i, ch := range channels {
+			k := SummaryKey{Day: day, Path: path, ChainID: e.ChainID, Channel: ch}
+			row, ok := rows[k]
+			if !ok {
+				row = &SummaryRow{SummaryKey: k, Fees: sdk.NewCoins()}
+				rows[k] = row
+			}
+
+			count := int64(perChannel[ch])
+
+			var chFees sdk.Coins
+			var chGasWanted, chGasUsed int64
+			if i == len(channels)-1 {
+				// last channel takes the remainder so that totals are preserved.
+				chFees, chGasWanted, chGasUsed = remainingFees, remainingGasWanted, remainingGasUsed
+			} else {
+				chFees = apportion(fees, count, totalPackets)
+				chGasWanted = e.GasWanted * count / totalPackets
+				chGasUsed = e.GasUsed * count / totalPackets
+				remainingFees = remainingFees.Sub(chFees...)
+				remainingGasWanted -= chGasWanted
+				remainingGasUsed -= chGasUsed
+			}
+
+			row.Txs++
+			if !e.Success() {
+				row.FailedTxs++
+			}
+			row.Packets += int(count)
+			row.GasWanted += chGasWanted
+			row.GasUsed += chGasUsed
+			row.Fees = row.Fees.Add(chFees...)
+		}
+	}
+
+	out := make([]SummaryRow, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].SummaryKey, out[j].SummaryKey
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		return a.Channel < b.Channel
+	})
+	return out
+}
+
+// entryPath resolves the path for an entry using the client IDs of the MsgUpdateClient messages in the transaction,
+// or otherwise the channels of the packets relayed in the transaction.
+func entryPath(e Entry, resolveClient PathResolver, resolveChannel ChannelPathResolver) string {
+	if resolveClient != nil {
+		for _, clientID := range e.ClientIDs {
+			if p := resolveClient(e.ChainID, clientID); p != "" {
+				return p
+			}
+		}
+	}
+	if resolveChannel != nil {
+		for _, pkt := range e.Packets {
+			if p := resolveChannel(e.ChainID, pkt.Port(), pkt.Channel()); p != "" {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// apportion returns the share of coins for count out of total.
+func apportion(coins sdk.Coins, count, total int64) sdk.Coins {
+	if total == 0 {
+		return sdk.NewCoins()
+	}
+	share := sdk.NewCoins()
+	for _, c := range coins {
+		amt := c.Amount.MulRaw(count).QuoRaw(total)
+		share = share.Add(sdk.NewCoin(c.Denom, amt))
+	}
+	return share
+}
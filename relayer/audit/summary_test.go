@@ -0,0 +1,140 @@
+package audit_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/audit"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	recvPacket = "/ibc.core.channel.v1.MsgRecvPacket"
+	ack        = "/ibc.core.channel.v1.MsgAcknowledgement"
+)
+
+func TestLogRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	l, err := audit.Open(file)
+	require.NoError(t, err)
+
+	e := audit.Entry{
+		Time:     time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC),
+		ChainID:  "chain-a",
+		TxHash:   "ABCD",
+		Height:   10,
+		Fee:      "100uatom",
+		MsgTypes: []string{recvPacket},
+		Packets:  []audit.PacketRef{{MsgType: recvPacket, Sequence: 1, SrcChannel: "channel-1", DstChannel: "channel-0"}},
+	}
+	require.NoError(t, l.Record(e))
+	require.NoError(t, l.Record(e))
+	require.NoError(t, l.Close())
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	entries, err := audit.ReadEntries(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, e, entries[0])
+}
+
+func TestSummarize(t *testing.T) {
+	day1 := time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	entries := []audit.Entry{
+		{
+			Time:      day1,
+			ChainID:   "chain-a",
+			Fee:       "300uatom",
+			GasWanted: 3000,
+			GasUsed:   2700,
+			ClientIDs: []string{"07-tendermint-0"},
+			Packets: []audit.PacketRef{
+				{MsgType: recvPacket, Sequence: 1, SrcChannel: "channel-9", DstChannel: "channel-0"},
+				{MsgType: recvPacket, Sequence: 2, SrcChannel: "channel-9", DstChannel: "channel-0"},
+				{MsgType: ack, Sequence: 5, SrcChannel: "channel-1", DstChannel: "channel-8"},
+			},
+		},
+		{
+			Time:      day1.Add(time.Hour),
+			ChainID:   "chain-a",
+			Code:      5,
+			Fee:       "50uatom",
+			GasWanted: 500,
+			GasUsed:   400,
+			ClientIDs: []string{"07-tendermint-0"},
+		},
+		{
+			Time:      day2,
+			ChainID:   "chain-a",
+			Fee:       "10uatom",
+			ClientIDs: []string{"07-tendermint-7"},
+		},
+		{
+			// packet-only transaction, resolved by channel.
+			Time:    day2,
+			ChainID: "chain-a",
+			Fee:     "20uatom",
+			Packets: []audit.PacketRef{
+				{MsgType: recvPacket, Sequence: 3, SrcChannel: "channel-9", SrcPort: "transfer", DstChannel: "channel-0", DstPort: "transfer"},
+			},
+		},
+		{
+			// rejected by CheckTx, never executed.
+			Time:    day2,
+			ChainID: "chain-a",
+			Error:   "insufficient fees",
+			Packets: []audit.PacketRef{
+				{MsgType: recvPacket, Sequence: 4, SrcChannel: "channel-9", SrcPort: "transfer", DstChannel: "channel-0", DstPort: "transfer"},
+			},
+		},
+	}
+
+	resolve := func(chainID, clientID string) string {
+		if chainID == "chain-a" && clientID == "07-tendermint-0" {
+			return "a-b"
+		}
+		return ""
+	}
+
+	resolveChannel := func(chainID, portID, channelID string) string {
+		if chainID == "chain-a" && portID == "transfer" && channelID == "channel-0" {
+			return "a-b"
+		}
+		return ""
+	}
+
+	rows := audit.Summarize(entries, resolve, resolveChannel)
+	require.Len(t, rows, 5)
+
+	require.Equal(t, audit.SummaryKey{Day: "2022-11-01", Path: "a-b", ChainID: "chain-a", Channel: ""}, rows[0].SummaryKey)
+	require.Equal(t, 1, rows[0].Txs)
+	require.Equal(t, 1, rows[0].FailedTxs)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 50)), rows[0].Fees)
+
+	require.Equal(t, "channel-0", rows[1].Channel)
+	require.Equal(t, 2, rows[1].Packets)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 200)), rows[1].Fees)
+	require.Equal(t, int64(1800), rows[1].GasUsed)
+
+	require.Equal(t, "channel-1", rows[2].Channel)
+	require.Equal(t, 1, rows[2].Packets)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 100)), rows[2].Fees)
+	require.Equal(t, int64(900), rows[2].GasUsed)
+
+	require.Equal(t, audit.SummaryKey{Day: "2022-11-02", Path: "", ChainID: "chain-a", Channel: ""}, rows[3].SummaryKey)
+
+	require.Equal(t, audit.SummaryKey{Day: "2022-11-02", Path: "a-b", ChainID: "chain-a", Channel: "channel-0"}, rows[4].SummaryKey)
+	require.Equal(t, 2, rows[4].Txs)
+	require.Equal(t, 1, rows[4].FailedTxs)
+	require.Equal(t, 2, rows[4].Packets)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 20)), rows[4].Fees)
+}
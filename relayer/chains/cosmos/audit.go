@@ -0,0 +1,117 @@
+package cosmos
+
+import (
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v5/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/audit"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// SetAuditLog sets the audit log that every broadcast transaction will be recorded to.
+func (cc *CosmosProvider) SetAuditLog(l *audit.Log) {
+	cc.auditLog = l
+}
+
+// recordAudit appends an entry for a broadcast transaction to the audit log, if one is configured.
+// A non-nil broadcastErr records a broadcast that failed before the transaction was executed,
+// e.g. a CheckTx rejection, in which case resp may be nil and no fee was paid.
+func (cc *CosmosProvider) recordAudit(resp *sdk.TxResponse, fees sdk.Coins, memo string, msgs []provider.RelayerMessage, broadcastErr error) {
+	if cc.auditLog == nil {
+		return
+	}
+	if resp == nil {
+		resp = &sdk.TxResponse{}
+	}
+
+	entry := audit.Entry{
+		Time:      time.Now().UTC(),
+		ChainID:   cc.ChainId(),
+		Key:       cc.Key(),
+		TxHash:    resp.TxHash,
+		Height:    resp.Height,
+		Code:      resp.Code,
+		GasWanted: resp.GasWanted,
+		GasUsed:   resp.GasUsed,
+		Memo:      memo,
+		MsgTypes:  make([]string, len(msgs)),
+	}
+	if broadcastErr != nil {
+		entry.Error = broadcastErr.Error()
+	} else {
+		entry.Fee = fees.String()
+	}
+
+	for i, m := range msgs {
+		entry.MsgTypes[i] = m.Type()
+
+		cm, ok := m.(CosmosMessage)
+		if !ok {
+			continue
+		}
+		switch msg := cm.Msg.(type) {
+		case *clienttypes.MsgUpdateClient:
+			entry.ClientIDs = append(entry.ClientIDs, msg.ClientId)
+		case *chantypes.MsgRecvPacket:
+			entry.Packets = append(entry.Packets, auditPacketRef(entry.MsgTypes[i], msg.Packet))
+		case *chantypes.MsgAcknowledgement:
+			entry.Packets = append(entry.Packets, auditPacketRef(entry.MsgTypes[i], msg.Packet))
+		case *chantypes.MsgTimeout:
+			entry.Packets = append(entry.Packets, auditPacketRef(entry.MsgTypes[i], msg.Packet))
+		case *chantypes.MsgTimeoutOnClose:
+			entry.Packets = append(entry.Packets, auditPacketRef(entry.MsgTypes[i], msg.Packet))
+		case *transfertypes.MsgTransfer:
+			// The sequence is only assigned once the transfer is executed.
+			ref := audit.PacketRef{
+				MsgType:    entry.MsgTypes[i],
+				SrcChannel: msg.SourceChannel,
+				SrcPort:    msg.SourcePort,
+			}
+			if broadcastErr == nil && resp.Code == 0 {
+				ref.Sequence = sendPacketSequence(resp, msg.SourceChannel, msg.SourcePort)
+			}
+			entry.Packets = append(entry.Packets, ref)
+		}
+	}
+
+	if err := cc.auditLog.Record(entry); err != nil {
+		cc.log.Error(
+			"Failed to record transaction in audit log",
+			zap.String("tx_hash", resp.TxHash),
+			zap.Error(err),
+		)
+	}
+}
+
+func auditPacketRef(msgType string, p chantypes.Packet) audit.PacketRef {
+	return audit.PacketRef{
+		MsgType:    msgType,
+		Sequence:   p.Sequence,
+		SrcChannel: p.SourceChannel,
+		SrcPort:    p.SourcePort,
+		DstChannel: p.DestinationChannel,
+		DstPort:    p.DestinationPort,
+	}
+}
+
+// sendPacketSequence finds the packet sequence of the send_packet event for the channel and port in the response.
+func sendPacketSequence(resp *sdk.TxResponse, channelID, portID string) uint64 {
+	for _, e := range parseEventsFromTxResponse(resp) {
+		if e.EventType != spTag {
+			continue
+		}
+		if e.Attributes[srcChanTag] != channelID || e.Attributes[srcPortTag] != portID {
+			continue
+		}
+		seq, err := strconv.ParseUint(e.Attributes[seqTag], 10, 64)
+		if err == nil {
+			return seq
+		}
+	}
+	return 0
+}
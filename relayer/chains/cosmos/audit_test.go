@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/audit"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRecordAuditBroadcastError(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := audit.Open(file)
+	require.NoError(t, err)
+
+	cc := &CosmosProvider{log: zap.NewNop(), PCfg: CosmosProviderConfig{ChainID: "chain-a", Key: "relayer"}}
+	cc.SetAuditLog(l)
+
+	msgs := []provider.RelayerMessage{NewCosmosMessage(&chantypes.MsgRecvPacket{
+		Packet: chantypes.Packet{
+			Sequence:           1,
+			SourcePort:         "transfer",
+			SourceChannel:      "channel-1",
+			DestinationPort:    "transfer",
+			DestinationChannel: "channel-0",
+		},
+	})}
+
+	cc.recordAudit(nil, sdk.NewCoins(sdk.NewInt64Coin("uatom", 100)), "", msgs, errors.New("insufficient fees"))
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	entries, err := audit.ReadEntries(f)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	e := entries[0]
+	require.False(t, e.Success())
+	require.Equal(t, "insufficient fees", e.Error)
+	require.Empty(t, e.TxHash)
+	require.Zero(t, e.Height)
+	require.Empty(t, e.Fee, "no fee is paid for a transaction that was not executed")
+	require.Len(t, e.Packets, 1)
+	require.Equal(t, "channel-0", e.Packets[0].Channel())
+	require.Equal(t, "transfer", e.Packets[0].Port())
+}
@@ -0,0 +1,65 @@
+package cosmos
+
+import (
+	"context"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// blockResults is the outcome of the query of the block results at a height.
+type blockResults struct {
+	height int64
+	res    *ctypes.ResultBlockResults
+	err    error
+}
+
+// blockQueryConcurrency returns the number of blocks to query at a time configured for the chain.
+func (ccp *CosmosChainProcessor) blockQueryConcurrency() int {
+	if c := ccp.chainProvider.PCfg.BlockQueryConcurrency; c > 0 {
+		return c
+	}
+	return defaultBlockQueryConcurrency
+}
+
+// queryBlockResults queries the block results of the heights from to to, up to concurrency at a time,
+// and returns them in height order. Results are queried at most concurrency heights ahead of the receiver,
+// which bounds the results held in memory. The channel is closed after the last height, or once ctx is done,
+// which the receiver must ensure if it stops receiving early.
+func (ccp *CosmosChainProcessor) queryBlockResults(ctx context.Context, from, to int64, concurrency int) <-chan blockResults {
+	// Each query holds a slot of the pending queue until its result is reordered,
+	// the one being awaited included.
+	pending := make(chan chan blockResults, concurrency-1)
+	ordered := make(chan blockResults)
+
+	go func() {
+		defer close(pending)
+		for h := from; h <= to; h++ {
+			result := make(chan blockResults, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+			go func(h int64) {
+				queryCtx, cancelQueryCtx := context.WithTimeout(ctx, blockResultsQueryTimeout)
+				defer cancelQueryCtx()
+				res, err := ccp.blockQuerier.QueryBlockResults(queryCtx, h)
+				result <- blockResults{height: h, res: res, err: err}
+			}(h)
+		}
+	}()
+
+	go func() {
+		defer close(ordered)
+		for result := range pending {
+			r := <-result
+			select {
+			case ordered <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ordered
+}
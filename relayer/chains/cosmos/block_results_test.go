@@ -0,0 +1,141 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap"
+)
+
+// slowRPCClient serves block results slower for lower heights, so that concurrent queries complete out of order,
+// and fails at failHeight if set. Its latest height is latest.
+type slowRPCClient struct {
+	rpcclient.Client
+	failHeight int64
+	latest     int64
+
+	mu                  sync.Mutex
+	inFlight, maxFlight int
+}
+
+func (c *slowRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.latest}}, nil
+}
+
+func (c *slowRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxFlight {
+		c.maxFlight = c.inFlight
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(time.Duration(20-*height%20) * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if *height == c.failHeight {
+		return nil, fmt.Errorf("block results unavailable at height %d", *height)
+	}
+	return &ctypes.ResultBlockResults{Height: *height}, nil
+}
+
+func TestQueryBlockResultsInOrder(t *testing.T) {
+	client := &slowRPCClient{}
+	p := &CosmosProvider{}
+	p.RPCClient = client
+	ccp := NewCosmosChainProcessor(zap.NewNop(), p, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	want := int64(1)
+	for block := range ccp.queryBlockResults(ctx, 1, 40, 4) {
+		require.NoError(t, block.err)
+		require.Equal(t, want, block.height)
+		require.Equal(t, want, block.res.Height)
+		want++
+	}
+	require.Equal(t, int64(41), want)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	require.LessOrEqual(t, client.maxFlight, 4)
+	require.Greater(t, client.maxFlight, 1, "blocks should be queried concurrently")
+}
+
+func TestQueryBlockResultsStopsAfterError(t *testing.T) {
+	client := &slowRPCClient{failHeight: 5}
+	p := &CosmosProvider{}
+	p.RPCClient = client
+	ccp := NewCosmosChainProcessor(zap.NewNop(), p, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blocks := ccp.queryBlockResults(ctx, 1, 1000, 8)
+	var heights []int64
+	for block := range blocks {
+		if block.err != nil {
+			require.Equal(t, int64(5), block.height)
+			break
+		}
+		heights = append(heights, block.height)
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, heights)
+
+	// The queries ahead stop once the receiver cancels.
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		for range blocks {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("block results channel was not closed after cancel")
+	}
+}
+
+func TestBlockQueryConcurrency(t *testing.T) {
+	ccp := NewCosmosChainProcessor(zap.NewNop(), &CosmosProvider{}, nil)
+	require.Equal(t, defaultBlockQueryConcurrency, ccp.blockQueryConcurrency())
+
+	ccp.chainProvider.PCfg.BlockQueryConcurrency = 16
+	require.Equal(t, 16, ccp.blockQueryConcurrency())
+}
+
+func TestQueryCycleHealthRequiresProcessedBlocks(t *testing.T) {
+	ctx := context.Background()
+	p := &CosmosProvider{PCfg: CosmosProviderConfig{ChainID: "health-1"}}
+	p.RPCClient = &slowRPCClient{failHeight: 6, latest: 10}
+	ccp := NewCosmosChainProcessor(zap.NewNop(), p, nil)
+	health := processor.NewHealth()
+	ccp.SetHealth(health)
+
+	// Querying the latest height alone is not progress.
+	persistence := &queryCyclePersistence{latestQueriedBlock: 5}
+	require.NoError(t, ccp.queryCycle(ctx, persistence))
+	require.Equal(t, int64(5), persistence.latestQueriedBlock)
+	require.False(t, health.Status(time.Minute).Ready)
+
+	// A processor caught up to the latest height is.
+	persistence = &queryCyclePersistence{latestQueriedBlock: 10}
+	require.NoError(t, ccp.queryCycle(ctx, persistence))
+	require.True(t, health.Status(time.Minute).Ready)
+}
@@ -12,6 +12,7 @@ import (
 	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
 	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/alert"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
@@ -50,8 +51,23 @@ type CosmosChainProcessor struct {
 	// metrics to monitor lifetime of processor
 	metrics *processor.PrometheusMetrics
 
-	// parsed gas prices accepted by the chain (only used for metrics)
+	// parsed gas prices accepted by the chain (only used for metrics and alerts)
 	parsedGasPrices *sdk.DecCoins
+
+	// optional notifier for alerting on low balance and falling out of sync
+	notifier *alert.Notifier
+
+	// optional tracker for reporting query loop progress
+	health *processor.Health
+
+	// queries the latest heights, blocks and headers, chainProvider unless overridden
+	blockQuerier BlockQuerier
+
+	// optional handler upgrading the counterparty clients after scheduled upgrades of the chain
+	upgradeHandler UpgradeHandler
+
+	// scheduled upgrade of the chain with an upgraded client state, if any
+	pendingUpgrade *pendingUpgrade
 }
 
 func NewCosmosChainProcessor(log *zap.Logger, provider *CosmosProvider, metrics *processor.PrometheusMetrics) *CosmosChainProcessor {
@@ -64,9 +80,21 @@ func NewCosmosChainProcessor(log *zap.Logger, provider *CosmosProvider, metrics
 		connectionClients:    make(map[string]string),
 		channelConnections:   make(map[string]string),
 		metrics:              metrics,
+		blockQuerier:         provider,
 	}
 }
 
+// SetNotifier sets the notifier used to raise alerts for this chain.
+func (ccp *CosmosChainProcessor) SetNotifier(n *alert.Notifier) {
+	ccp.notifier = n
+}
+
+// SetHealth sets the tracker that query loop progress is reported to.
+func (ccp *CosmosChainProcessor) SetHealth(h *processor.Health) {
+	ccp.health = h
+	h.RegisterChainProcessor(ccp.chainProvider.ChainId())
+}
+
 const (
 	queryTimeout                = 5 * time.Second
 	blockResultsQueryTimeout    = 2 * time.Minute
@@ -76,6 +104,7 @@ const (
 	defaultMinQueryLoopDuration      = 1 * time.Second
 	defaultBalanceUpdateWaitDuration = 60 * time.Second
 	inSyncNumBlocksThreshold         = 2
+	defaultBlockQueryConcurrency     = 4
 )
 
 // latestClientState is a map of clientID to the latest clientInfo for that client.
@@ -104,6 +133,19 @@ func (l latestClientState) update(ctx context.Context, clientInfo clientInfo, cc
 	l[clientInfo.clientID] = clientState
 }
 
+// BlockQuerier queries the latest heights, block results and headers that the CosmosChainProcessor reads.
+type BlockQuerier interface {
+	QueryLatestHeight(ctx context.Context) (int64, error)
+	QueryBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error)
+	QueryIBCHeader(ctx context.Context, h int64) (provider.IBCHeader, error)
+}
+
+// SetBlockQuerier sets the querier of latest heights, block results and headers instead of the CosmosProvider,
+// e.g. a wrapper of it injecting faults.
+func (ccp *CosmosChainProcessor) SetBlockQuerier(q BlockQuerier) {
+	ccp.blockQuerier = q
+}
+
 // Provider returns the ChainProvider, which provides the methods for querying, assembling IBC messages, and sending transactions.
 func (ccp *CosmosChainProcessor) Provider() provider.ChainProvider {
 	return ccp.chainProvider
@@ -122,7 +164,7 @@ func (ccp *CosmosChainProcessor) latestHeightWithRetry(ctx context.Context) (lat
 		latestHeightQueryCtx, cancelLatestHeightQueryCtx := context.WithTimeout(ctx, queryTimeout)
 		defer cancelLatestHeightQueryCtx()
 		var err error
-		latestHeight, err = ccp.chainProvider.QueryLatestHeight(latestHeightQueryCtx)
+		latestHeight, err = ccp.blockQuerier.QueryLatestHeight(latestHeightQueryCtx)
 		return err
 	}, retry.Context(ctx), retry.Attempts(latestHeightQueryRetries), retry.Delay(latestHeightQueryRetryDelay), retry.LastErrorOnly(true), retry.OnRetry(func(n uint, err error) {
 		ccp.log.Info(
@@ -160,6 +202,7 @@ type queryCyclePersistence struct {
 	minQueryLoopDuration      time.Duration
 	lastBalanceUpdate         time.Time
 	balanceUpdateWaitDuration time.Duration
+	lastUpgradePlanQuery      time.Time
 }
 
 // Run starts the query loop for the chain which will gather applicable ibc messages and push events out to the relevant PathProcessors.
@@ -294,10 +337,14 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 		zap.Int64("latest_height", persistence.latestHeight),
 	)
 
-	if ccp.metrics != nil {
+	if ccp.metrics != nil || ccp.notifier != nil {
 		ccp.CollectMetrics(ctx, persistence)
 	}
 
+	if err := ccp.watchUpgrade(ctx, persistence); err != nil {
+		return err
+	}
+
 	// used at the end of the cycle to send signal to path processors to start processing if both chains are in sync and no new messages came in this cycle
 	firstTimeInSync := false
 
@@ -316,49 +363,22 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 
 	ibcMessagesCache := processor.NewIBCMessagesCache()
 
-	ibcHeaderCache := make(processor.IBCHeaderCache)
-
 	ppChanged := false
 
-	var latestHeader CosmosIBCHeader
-
 	newLatestQueriedBlock := persistence.latestQueriedBlock
 
 	chainID := ccp.chainProvider.ChainId()
 
-	for i := persistence.latestQueriedBlock + 1; i <= persistence.latestHeight; i++ {
-		var eg errgroup.Group
-		var blockRes *ctypes.ResultBlockResults
-		var ibcHeader provider.IBCHeader
-		i := i
-		eg.Go(func() (err error) {
-			queryCtx, cancelQueryCtx := context.WithTimeout(ctx, blockResultsQueryTimeout)
-			defer cancelQueryCtx()
-			blockRes, err = ccp.chainProvider.RPCClient.BlockResults(queryCtx, &i)
-			return err
-		})
-		eg.Go(func() (err error) {
-			queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
-			defer cancelQueryCtx()
-			ibcHeader, err = ccp.chainProvider.QueryIBCHeader(queryCtx, i)
-			return err
-		})
-
-		if err := eg.Wait(); err != nil {
-			ccp.log.Warn("Error querying block data", zap.Error(err))
+	blocksCtx, cancelBlocks := context.WithCancel(ctx)
+	blocks := ccp.queryBlockResults(blocksCtx, persistence.latestQueriedBlock+1, persistence.latestHeight, ccp.blockQueryConcurrency())
+	for block := range blocks {
+		if block.err != nil {
+			ccp.log.Warn("Error querying block data", zap.Int64("height", block.height), zap.Error(block.err))
 			break
 		}
 
-		latestHeader = ibcHeader.(CosmosIBCHeader)
-
-		heightUint64 := uint64(i)
-
-		ccp.latestBlock = provider.LatestBlock{
-			Height: heightUint64,
-			Time:   latestHeader.SignedHeader.Time,
-		}
-
-		ibcHeaderCache[heightUint64] = latestHeader
+		blockRes := block.res
+		heightUint64 := uint64(block.height)
 		ppChanged = true
 
 		blockMsgs := ccp.ibcMessagesFromBlockEvents(blockRes.BeginBlockEvents, blockRes.EndBlockEvents, heightUint64)
@@ -377,10 +397,24 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 				ccp.handleMessage(ctx, m, ibcMessagesCache)
 			}
 		}
-		newLatestQueriedBlock = i
+		newLatestQueriedBlock = block.height
+		ccp.health.ChainProcessorAdvanced(chainID, persistence.latestHeight, newLatestQueriedBlock)
+		if ccp.metrics != nil {
+			ccp.metrics.SetBlocksBehind(chainID, persistence.latestHeight-newLatestQueriedBlock)
+		}
+	}
+	// stops the block queries ahead of a failed one.
+	cancelBlocks()
+
+	if ccp.inSync {
+		ccp.notifier.ChainBehind(chainID, persistence.latestHeight, newLatestQueriedBlock)
 	}
 
 	if newLatestQueriedBlock == persistence.latestQueriedBlock {
+		// Without new blocks, only a processor caught up to the latest height is making progress.
+		if newLatestQueriedBlock >= persistence.latestHeight {
+			ccp.health.ChainProcessorAdvanced(chainID, persistence.latestHeight, newLatestQueriedBlock)
+		}
 		return nil
 	}
 
@@ -394,6 +428,28 @@ func (ccp *CosmosChainProcessor) queryCycle(ctx context.Context, persistence *qu
 		return nil
 	}
 
+	// Only the header of the latest queried block is needed to update clients,
+	// the PathProcessors query the headers of other heights they need for trusted validators.
+	queryCtx, cancelQueryCtx := context.WithTimeout(ctx, queryTimeout)
+	ibcHeader, err := ccp.blockQuerier.QueryIBCHeader(queryCtx, newLatestQueriedBlock)
+	cancelQueryCtx()
+	if err != nil {
+		// the queried blocks are queried again next cycle.
+		ccp.log.Warn("Error querying latest block header",
+			zap.Int64("height", newLatestQueriedBlock),
+			zap.Error(err),
+		)
+		return nil
+	}
+	latestHeader := ibcHeader.(CosmosIBCHeader)
+
+	ccp.latestBlock = provider.LatestBlock{
+		Height: uint64(newLatestQueriedBlock),
+		Time:   latestHeader.SignedHeader.Time,
+	}
+
+	ibcHeaderCache := processor.IBCHeaderCache{ccp.latestBlock.Height: latestHeader}
+
 	for _, pp := range ccp.pathProcessors {
 		clientID := pp.RelevantClientID(chainID)
 		clientState, err := ccp.clientState(ctx, clientID)
@@ -433,7 +489,11 @@ func (ccp *CosmosChainProcessor) CollectMetrics(ctx context.Context, persistence
 }
 
 func (ccp *CosmosChainProcessor) CurrentBlockHeight(ctx context.Context, persistence *queryCyclePersistence) {
+	if ccp.metrics == nil {
+		return
+	}
 	ccp.metrics.SetLatestHeight(ccp.chainProvider.ChainId(), persistence.latestHeight)
+	ccp.metrics.SetBlocksBehind(ccp.chainProvider.ChainId(), persistence.latestHeight-persistence.latestQueriedBlock)
 }
 
 func (ccp *CosmosChainProcessor) CurrentRelayerBalance(ctx context.Context) {
@@ -464,7 +524,10 @@ func (ccp *CosmosChainProcessor) CurrentRelayerBalance(ctx context.Context) {
 			if balance.Denom == gasDenom.Denom {
 				// Convert to a big float to get a float64 for metrics
 				f, _ := big.NewFloat(0.0).SetInt(balance.Amount.BigInt()).Float64()
-				ccp.metrics.SetWalletBalance(ccp.chainProvider.ChainId(), ccp.chainProvider.Key(), balance.Denom, f)
+				if ccp.metrics != nil {
+					ccp.metrics.SetWalletBalance(ccp.chainProvider.ChainId(), ccp.chainProvider.Key(), balance.Denom, f)
+				}
+				ccp.notifier.WalletBalance(ccp.chainProvider.ChainId(), ccp.chainProvider.Key(), balance.Denom, f)
 			}
 		}
 	}
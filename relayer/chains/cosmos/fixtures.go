@@ -0,0 +1,253 @@
+package cosmos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmjson "github.com/tendermint/tendermint/libs/json"
+	lightprovider "github.com/tendermint/tendermint/light/provider"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// Fixture directory layout. Block results and light blocks are stored per height,
+// ABCI query responses per hash of the query.
+const (
+	fixtureBlockResultsDir = "block_results"
+	fixtureLightBlocksDir  = "light_blocks"
+	fixtureABCIQueriesDir  = "abci_queries"
+)
+
+// fixtureStore reads and writes recorded RPC responses as JSON files in a directory.
+type fixtureStore struct {
+	dir string
+}
+
+func heightFixtureName(height int64) string {
+	return strconv.FormatInt(height, 10) + ".json"
+}
+
+// abciQueryFixtureName identifies an ABCI query by its path, data, height and prove option.
+func abciQueryFixtureName(path string, data tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n%t\n", path, opts.Height, opts.Prove)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)) + ".json"
+}
+
+func (s fixtureStore) write(subdir, name string, v any) error {
+	bz, err := tmjson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(s.dir, subdir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(bz); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+func (s fixtureStore) read(subdir, name string, v any) error {
+	bz, err := os.ReadFile(filepath.Join(s.dir, subdir, name))
+	if err != nil {
+		return err
+	}
+	return tmjson.Unmarshal(bz, v)
+}
+
+func (s fixtureStore) has(subdir, name string) bool {
+	_, err := os.Stat(filepath.Join(s.dir, subdir, name))
+	return err == nil
+}
+
+// blockHeights returns the heights with recorded block results in ascending order.
+func (s fixtureStore) blockHeights() ([]int64, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, fixtureBlockResultsDir))
+	if err != nil {
+		return nil, err
+	}
+	var heights []int64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		h, err := strconv.ParseInt(strings.TrimSuffix(name, ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// recordingRPCClient records the block results and ABCI query responses returned by the wrapped client.
+type recordingRPCClient struct {
+	rpcclient.Client
+	store fixtureStore
+	log   func(msg string, err error)
+}
+
+func (c recordingRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	res, err := c.Client.BlockResults(ctx, height)
+	if err == nil {
+		if err := c.store.write(fixtureBlockResultsDir, heightFixtureName(res.Height), res); err != nil {
+			c.log("Failed to record block results", err)
+		}
+	}
+	return res, err
+}
+
+func (c recordingRPCClient) ABCIQueryWithOptions(ctx context.Context, path string, data tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res, err := c.Client.ABCIQueryWithOptions(ctx, path, data, opts)
+	if err == nil {
+		if err := c.store.write(fixtureABCIQueriesDir, abciQueryFixtureName(path, data, opts), res); err != nil {
+			c.log("Failed to record ABCI query", err)
+		}
+	}
+	return res, err
+}
+
+// recordingLightProvider records the light blocks returned by the wrapped provider,
+// which are used to build IBC headers.
+type recordingLightProvider struct {
+	lightprovider.Provider
+	store fixtureStore
+	log   func(msg string, err error)
+}
+
+func (p recordingLightProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	lb, err := p.Provider.LightBlock(ctx, height)
+	if err == nil {
+		if err := p.store.write(fixtureLightBlocksDir, heightFixtureName(lb.Height), lb); err != nil {
+			p.log("Failed to record light block", err)
+		}
+	}
+	return lb, err
+}
+
+// fixtureReplay serves recorded responses. latestHeight is reported as the latest block height of the chain,
+// so that the replay can be advanced one recorded block at a time.
+type fixtureReplay struct {
+	store        fixtureStore
+	heights      []int64
+	latestHeight atomic.Int64
+}
+
+// replayRPCClient serves block results, ABCI queries and status from a fixture directory.
+// Any other call is passed through to the wrapped client.
+type replayRPCClient struct {
+	rpcclient.Client
+	replay *fixtureReplay
+}
+
+func (c replayRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{
+		SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.replay.latestHeight.Load()},
+	}, nil
+}
+
+func (c replayRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	h := c.replay.latestHeight.Load()
+	if height != nil {
+		h = *height
+	}
+	res := new(ctypes.ResultBlockResults)
+	if err := c.replay.store.read(fixtureBlockResultsDir, heightFixtureName(h), res); err != nil {
+		return nil, fmt.Errorf("no recorded block results at height %d: %w", h, err)
+	}
+	return res, nil
+}
+
+func (c replayRPCClient) ABCIQueryWithOptions(ctx context.Context, path string, data tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res := new(ctypes.ResultABCIQuery)
+	if err := c.replay.store.read(fixtureABCIQueriesDir, abciQueryFixtureName(path, data, opts), res); err != nil {
+		return nil, fmt.Errorf("no recorded ABCI query for %s at height %d: %w", path, opts.Height, err)
+	}
+	return res, nil
+}
+
+// replayLightProvider serves light blocks from a fixture directory.
+type replayLightProvider struct {
+	lightprovider.Provider
+	replay *fixtureReplay
+}
+
+func (p replayLightProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	if height == 0 {
+		height = p.replay.latestHeight.Load()
+	}
+	lb := new(tmtypes.LightBlock)
+	if err := p.replay.store.read(fixtureLightBlocksDir, heightFixtureName(height), lb); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, lightprovider.ErrLightBlockNotFound
+		}
+		return nil, err
+	}
+	return lb, nil
+}
+
+// RecordFixtures wraps the RPC client and light provider of cc so that block results, light blocks and
+// ABCI query responses are written to dir as they are queried. The fixtures can be replayed with
+// ReplayFixtures or NewReplayChainProcessor to reproduce the behavior of the relayer in a test.
+func (cc *CosmosProvider) RecordFixtures(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	store := fixtureStore{dir: dir}
+	log := func(msg string, err error) {
+		cc.log.Warn(msg, zap.String("chain_id", cc.ChainId()), zap.String("dir", dir), zap.Error(err))
+	}
+	cc.RPCClient = recordingRPCClient{Client: cc.RPCClient, store: store, log: log}
+	cc.LightProvider = recordingLightProvider{Provider: cc.LightProvider, store: store, log: log}
+	return nil
+}
+
+// ReplayFixtures replaces the RPC client and light provider of cc with ones serving the fixtures
+// recorded in dir by RecordFixtures. The latest height reported is the highest recorded height.
+func (cc *CosmosProvider) ReplayFixtures(dir string) error {
+	_, err := cc.replayFixtures(dir)
+	return err
+}
+
+func (cc *CosmosProvider) replayFixtures(dir string) (*fixtureReplay, error) {
+	store := fixtureStore{dir: dir}
+	heights, err := store.blockHeights()
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixtures: %w", err)
+	}
+	if len(heights) == 0 {
+		return nil, fmt.Errorf("no block results recorded in %s", dir)
+	}
+	replay := &fixtureReplay{store: store, heights: heights}
+	replay.latestHeight.Store(heights[len(heights)-1])
+	cc.RPCClient = replayRPCClient{Client: cc.RPCClient, replay: replay}
+	cc.LightProvider = replayLightProvider{Provider: cc.LightProvider, replay: replay}
+	return replay, nil
+}
@@ -0,0 +1,240 @@
+package cosmos
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	lightprovider "github.com/tendermint/tendermint/light/provider"
+	"github.com/tendermint/tendermint/proto/tendermint/crypto"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	fixtureChainID            = "fixture-1"
+	fixtureCounterpartyID     = "fixture-2"
+	fixtureClientID           = "07-tendermint-0"
+	fixtureCounterpartyClient = "07-tendermint-1"
+	fixtureSendPacketHeight   = 11
+)
+
+// fakeRPCClient serves blocks 10 to latest, with a send_packet event at fixtureSendPacketHeight.
+type fakeRPCClient struct {
+	rpcclient.Client
+	latest      atomic.Int64
+	clientState []byte
+}
+
+func (c *fakeRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.latest.Load()}}, nil
+}
+
+func (c *fakeRPCClient) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	res := &ctypes.ResultBlockResults{Height: *height}
+	if *height == fixtureSendPacketHeight {
+		res.TxsResults = []*abci.ResponseDeliverTx{{
+			Events: []abci.Event{{
+				Type: chantypes.EventTypeSendPacket,
+				Attributes: []abci.EventAttribute{
+					{Key: []byte(chantypes.AttributeKeySequence), Value: []byte("1")},
+					{Key: []byte(chantypes.AttributeKeyDataHex), Value: []byte("0123456789abcdef")},
+					{Key: []byte(chantypes.AttributeKeyTimeoutHeight), Value: []byte("2-1000")},
+					{Key: []byte(chantypes.AttributeKeyTimeoutTimestamp), Value: []byte("0")},
+					{Key: []byte(chantypes.AttributeKeySrcChannel), Value: []byte("channel-0")},
+					{Key: []byte(chantypes.AttributeKeySrcPort), Value: []byte("transfer")},
+					{Key: []byte(chantypes.AttributeKeyDstChannel), Value: []byte("channel-1")},
+					{Key: []byte(chantypes.AttributeKeyDstPort), Value: []byte("transfer")},
+				},
+			}},
+		}}
+	}
+	return res, nil
+}
+
+func (c *fakeRPCClient) ABCIQueryWithOptions(ctx context.Context, path string, data tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res := &ctypes.ResultABCIQuery{Response: abci.ResponseQuery{Height: c.latest.Load()}}
+	if path == "store/ibc/key" {
+		res.Response.Value = c.clientState
+		res.Response.ProofOps = &crypto.ProofOps{}
+	}
+	// gRPC queries, e.g. for connections and channels, get empty responses.
+	return res, nil
+}
+
+type fakeLightProvider struct {
+	lightprovider.Provider
+	valSet *tmtypes.ValidatorSet
+}
+
+func (p fakeLightProvider) LightBlock(ctx context.Context, height int64) (*tmtypes.LightBlock, error) {
+	return &tmtypes.LightBlock{
+		SignedHeader: &tmtypes.SignedHeader{
+			Header: &tmtypes.Header{
+				ChainID: fixtureChainID,
+				Height:  height,
+				Time:    time.Unix(1_600_000_000+height, 0).UTC(),
+			},
+			Commit: &tmtypes.Commit{Height: height},
+		},
+		ValidatorSet: p.valSet,
+	}, nil
+}
+
+func newFixtureProvider(t *testing.T) *CosmosProvider {
+	cfg := CosmosProviderConfig{
+		Key:            "fixture-key",
+		ChainID:        fixtureChainID,
+		AccountPrefix:  "cosmos",
+		KeyringBackend: "test",
+		Timeout:        "10s",
+	}
+	p, err := cfg.NewProvider(zap.NewNop(), t.TempDir(), true, "fixture-chain")
+	require.NoError(t, err)
+	return p.(*CosmosProvider)
+}
+
+func newFixturePathProcessor(log *zap.Logger) *processor.PathProcessor {
+	return processor.NewPathProcessor(
+		log,
+		processor.PathEnd{PathName: "fixture-path", ChainID: fixtureChainID, ClientID: fixtureClientID},
+		processor.PathEnd{PathName: "fixture-path", ChainID: fixtureCounterpartyID, ClientID: fixtureCounterpartyClient},
+		nil, "", time.Hour,
+	)
+}
+
+func TestRecordAndReplayFixtures(t *testing.T) {
+	ctx := context.Background()
+	log := zaptest.NewLogger(t)
+	dir := filepath.Join(t.TempDir(), fixtureChainID)
+
+	// Record the blocks queried by a chain processor from the fake upstream chain.
+	recorder := newFixtureProvider(t)
+	cdc := codec.NewProtoCodec(recorder.Codec.InterfaceRegistry)
+	clientState := tmclient.NewClientState(
+		fixtureCounterpartyID, tmclient.DefaultTrustLevel, time.Hour, 2*time.Hour, time.Minute,
+		clienttypes.NewHeight(2, 100), commitmenttypes.GetSDKSpecs(), nil, false, false,
+	)
+	clientStateBz, err := clienttypes.MarshalClientState(cdc, clientState)
+	require.NoError(t, err)
+
+	upstream := &fakeRPCClient{clientState: clientStateBz}
+	val := tmtypes.NewValidator(ed25519.GenPrivKey().PubKey(), 1)
+	recorder.RPCClient = upstream
+	recorder.LightProvider = fakeLightProvider{valSet: tmtypes.NewValidatorSet([]*tmtypes.Validator{val})}
+	require.NoError(t, recorder.RecordFixtures(dir))
+
+	ccp := NewCosmosChainProcessor(log, recorder, nil)
+	ccp.SetPathProcessors(processor.PathProcessors{newFixturePathProcessor(log)})
+	require.NoError(t, ccp.initializeConnectionState(ctx))
+	require.NoError(t, ccp.initializeChannelState(ctx))
+
+	persistence := queryCyclePersistence{latestQueriedBlock: 9}
+	for h := int64(10); h <= 12; h++ {
+		upstream.latest.Store(h)
+		require.NoError(t, ccp.queryCycle(ctx, &persistence))
+	}
+	require.Equal(t, int64(12), persistence.latestQueriedBlock)
+
+	heights, err := fixtureStore{dir: dir}.blockHeights()
+	require.NoError(t, err)
+	require.Equal(t, []int64{10, 11, 12}, heights)
+
+	// Replay the fixtures and check that the send_packet reaches the path processor.
+	replayer := newFixtureProvider(t)
+	rcp, err := NewReplayChainProcessor(log, replayer, dir)
+	require.NoError(t, err)
+
+	runCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err = processor.NewEventProcessor().
+		WithChainProcessors(rcp).
+		WithPathProcessors(newFixturePathProcessor(log)).
+		WithMessageLifecycle(&processor.PacketMessageLifecycle{
+			Termination: &processor.PacketMessage{
+				ChainID:   fixtureChainID,
+				EventType: chantypes.EventTypeSendPacket,
+				Info: provider.PacketInfo{
+					Sequence:      1,
+					SourceChannel: "channel-0",
+					SourcePort:    "transfer",
+					DestChannel:   "channel-1",
+					DestPort:      "transfer",
+				},
+			},
+		}).
+		Build().
+		Run(runCtx)
+	require.NoError(t, err)
+	require.NoError(t, runCtx.Err(), "replay did not reach the send_packet termination condition")
+}
+
+func TestRecordAndReplayFixturesCatchUp(t *testing.T) {
+	ctx := context.Background()
+	log := zaptest.NewLogger(t)
+	dir := filepath.Join(t.TempDir(), fixtureChainID)
+
+	recorder := newFixtureProvider(t)
+	upstream := &fakeRPCClient{}
+	val := tmtypes.NewValidator(ed25519.GenPrivKey().PubKey(), 1)
+	recorder.RPCClient = upstream
+	recorder.LightProvider = fakeLightProvider{valSet: tmtypes.NewValidatorSet([]*tmtypes.Validator{val})}
+	require.NoError(t, recorder.RecordFixtures(dir))
+
+	// Catch up on all blocks in a single query cycle.
+	ccp := NewCosmosChainProcessor(log, recorder, nil)
+	persistence := queryCyclePersistence{latestQueriedBlock: 9}
+	upstream.latest.Store(12)
+	require.NoError(t, ccp.queryCycle(ctx, &persistence))
+	require.Equal(t, int64(12), persistence.latestQueriedBlock)
+	require.Equal(t, uint64(12), ccp.latestBlock.Height)
+
+	// Only the header of the latest block is queried.
+	store := fixtureStore{dir: dir}
+	for h := int64(10); h <= 11; h++ {
+		require.False(t, store.has(fixtureLightBlocksDir, heightFixtureName(h)), "header queried at height %d", h)
+	}
+	require.True(t, store.has(fixtureLightBlocksDir, heightFixtureName(12)))
+
+	// The blocks without headers are replayed along with the latest one.
+	rcp, err := NewReplayChainProcessor(log, newFixtureProvider(t), dir)
+	require.NoError(t, err)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- rcp.Run(runCtx, 0) }()
+
+	select {
+	case <-rcp.Done():
+	case err := <-errCh:
+		t.Fatalf("replay stopped before replaying all blocks: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("replay did not finish")
+	}
+	require.Equal(t, uint64(12), rcp.latestBlock.Height)
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestReplayFixturesEmptyDir(t *testing.T) {
+	_, err := NewReplayChainProcessor(zap.NewNop(), newFixtureProvider(t), t.TempDir())
+	require.Error(t, err)
+}
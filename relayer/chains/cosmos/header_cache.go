@@ -0,0 +1,69 @@
+package cosmos
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// ibcHeadersToCache is the number of IBC headers kept by a CosmosProvider,
+// e.g. for the trusted validators of client updates by the PathProcessors of every path of the chain.
+const ibcHeadersToCache = 100
+
+// headerLRU is a bounded cache of the IBC headers of a chain by height, evicting the least recently used.
+// A nil headerLRU caches nothing.
+type headerLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of headerLRUEntry, most recently used first
+	headers map[int64]*list.Element
+}
+
+type headerLRUEntry struct {
+	height int64
+	header provider.IBCHeader
+}
+
+func newHeaderLRU(size int) *headerLRU {
+	return &headerLRU{
+		size:    size,
+		order:   list.New(),
+		headers: make(map[int64]*list.Element, size),
+	}
+}
+
+// get returns the cached header at height, if any.
+func (c *headerLRU) get(height int64) (provider.IBCHeader, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.headers[height]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(headerLRUEntry).header, true
+}
+
+// add caches the header at height, evicting the least recently used header if the cache is full.
+func (c *headerLRU) add(height int64, header provider.IBCHeader) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.headers[height]; ok {
+		e.Value = headerLRUEntry{height: height, header: header}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.headers[height] = c.order.PushFront(headerLRUEntry{height: height, header: header})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.headers, oldest.Value.(headerLRUEntry).height)
+	}
+}
@@ -0,0 +1,44 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func testIBCHeader(height int64) CosmosIBCHeader {
+	return CosmosIBCHeader{SignedHeader: &tmtypes.SignedHeader{Header: &tmtypes.Header{Height: height}}}
+}
+
+func TestHeaderLRU(t *testing.T) {
+	c := newHeaderLRU(2)
+	c.add(1, testIBCHeader(1))
+	c.add(2, testIBCHeader(2))
+
+	// Getting height 1 makes height 2 the least recently used.
+	h, ok := c.get(1)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), h.Height())
+
+	c.add(3, testIBCHeader(3))
+	_, ok = c.get(2)
+	require.False(t, ok, "least recently used header should be evicted")
+	_, ok = c.get(1)
+	require.True(t, ok)
+	_, ok = c.get(3)
+	require.True(t, ok)
+	require.Equal(t, 2, c.order.Len())
+	require.Len(t, c.headers, 2)
+
+	// Adding a cached height does not evict anything.
+	c.add(3, testIBCHeader(3))
+	require.Equal(t, 2, c.order.Len())
+}
+
+func TestHeaderLRUNil(t *testing.T) {
+	var c *headerLRU
+	c.add(1, testIBCHeader(1))
+	_, ok := c.get(1)
+	require.False(t, ok)
+}
@@ -0,0 +1,137 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/codec/legacy"
+	"github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
+	ethhd "github.com/evmos/ethermint/crypto/hd"
+	lens "github.com/strangelove-ventures/lens/client"
+)
+
+const (
+	// KeyAlgorithmSecp256k1 and KeyAlgorithmEthSecp256k1 are the supported values of CosmosProviderConfig.KeyAlgorithm.
+	KeyAlgorithmSecp256k1    = string(hd.Secp256k1Type)
+	KeyAlgorithmEthSecp256k1 = string(ethhd.EthSecp256k1Type)
+
+	// ethermintCodec and injectiveCodec are the extra codecs of lens for Ethermint and Injective chains.
+	ethermintCodec = "ethermint"
+	injectiveCodec = "injective"
+
+	// ethCoinType is the coin type of Ethereum, which lens derives eth_secp256k1 keys for.
+	ethCoinType = 60
+)
+
+var registerEthAminoOnce sync.Once
+
+// registerEthSecp256k1Amino registers the eth_secp256k1 keys with the global amino codec,
+// which the keyring armors private keys with.
+func registerEthSecp256k1Amino() {
+	registerEthAminoOnce.Do(func() {
+		legacy.Cdc.RegisterConcrete(&ethsecp256k1.PubKey{}, ethsecp256k1.PubKeyName, nil)
+		legacy.Cdc.RegisterConcrete(&ethsecp256k1.PrivKey{}, ethsecp256k1.PrivKeyName, nil)
+	})
+}
+
+// keyAlgo returns the signing algorithm of new keys: the configured KeyAlgorithm,
+// or like lens, eth_secp256k1 for the Ethereum coin type and secp256k1 for any other.
+func (cc *CosmosProvider) keyAlgo(coinType uint32) keyring.SignatureAlgo {
+	switch {
+	case cc.PCfg.KeyAlgorithm == KeyAlgorithmEthSecp256k1:
+		return ethhd.EthSecp256k1
+	case cc.PCfg.KeyAlgorithm == "" && coinType == ethCoinType:
+		return ethhd.EthSecp256k1
+	default:
+		return hd.Secp256k1
+	}
+}
+
+// RestoreKey restores the key derived from mnemonic with the signing algorithm of the chain.
+func (cc *CosmosProvider) RestoreKey(name, mnemonic string, coinType uint32) (string, error) {
+	ko, err := cc.keyAddOrRestore(name, coinType, mnemonic)
+	if err != nil {
+		return "", err
+	}
+	return ko.Address, nil
+}
+
+// keyAddOrRestore adds a key derived from mnemonic, or from a new mnemonic if none is given,
+// with the signing algorithm of the chain.
+func (cc *CosmosProvider) keyAddOrRestore(name string, coinType uint32, mnemonic ...string) (*lens.KeyOutput, error) {
+	var mnemonicStr string
+	if len(mnemonic) > 0 {
+		mnemonicStr = mnemonic[0]
+	} else {
+		var err error
+		if mnemonicStr, err = lens.CreateMnemonic(); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := cc.Keybase.NewAccount(name, mnemonicStr, "", hd.CreateHDPath(coinType, 0, 0).String(), cc.keyAlgo(coinType))
+	if err != nil {
+		return nil, err
+	}
+	acc, err := info.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	address, err := cc.EncodeBech32AccAddr(acc)
+	if err != nil {
+		return nil, err
+	}
+	return &lens.KeyOutput{Mnemonic: mnemonicStr, Address: address}, nil
+}
+
+// ImportPrivKeyArmor imports an ASCII armored private key, encrypted with passphrase as by ExportPrivKeyArmor,
+// to the keyring under name and returns its address.
+func (cc *CosmosProvider) ImportPrivKeyArmor(name, armor, passphrase string) (string, error) {
+	priv, _, err := crypto.UnarmorDecryptPrivKey(armor, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	return cc.importPrivKey(name, priv)
+}
+
+// ImportPrivKeyHex imports a hex encoded private key of the signing algorithm of the chain
+// to the keyring under name and returns its address.
+func (cc *CosmosProvider) ImportPrivKeyHex(name, hexKey string) (string, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode hex private key: %w", err)
+	}
+	if len(bz) != secp256k1.PrivKeySize {
+		return "", fmt.Errorf("invalid private key length: expected %d bytes, got %d", secp256k1.PrivKeySize, len(bz))
+	}
+	if cc.PCfg.KeyAlgorithm == KeyAlgorithmEthSecp256k1 {
+		return cc.importPrivKey(name, &ethsecp256k1.PrivKey{Key: bz})
+	}
+	return cc.importPrivKey(name, &secp256k1.PrivKey{Key: bz})
+}
+
+func (cc *CosmosProvider) importPrivKey(name string, priv cryptotypes.PrivKey) (string, error) {
+	if cc.PCfg.AccountPrefix == "" {
+		return "", fmt.Errorf("chain %s has no account prefix to derive the address of the imported key", cc.ChainName())
+	}
+	address, err := cc.EncodeBech32AccAddr(sdk.AccAddress(priv.PubKey().Address()))
+	if err != nil {
+		return "", err
+	}
+
+	// The keyring only imports armored keys, so the key is armored again with the default passphrase.
+	armor := crypto.EncryptArmorPrivKey(priv, ckeys.DefaultKeyPass, priv.Type())
+	if err := cc.Keybase.ImportPrivKey(name, armor, ckeys.DefaultKeyPass); err != nil {
+		return "", err
+	}
+	return address, nil
+}
@@ -0,0 +1,110 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"testing"
+
+	injectiveeth "github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	ckeys "github.com/cosmos/cosmos-sdk/client/keys"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
+	ethhd "github.com/evmos/ethermint/crypto/hd"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"
+
+func newEthProvider(t *testing.T, extraCodecs ...string) *CosmosProvider {
+	cfg := CosmosProviderConfig{
+		Key:            "eth-key",
+		ChainID:        "evmos_9001-2",
+		AccountPrefix:  "evmos",
+		KeyringBackend: "test",
+		Timeout:        "10s",
+		ExtraCodecs:    extraCodecs,
+		KeyAlgorithm:   KeyAlgorithmEthSecp256k1,
+	}
+	p, err := cfg.NewProvider(zap.NewNop(), t.TempDir(), true, "evmos")
+	require.NoError(t, err)
+	return p.(*CosmosProvider)
+}
+
+// signedPubKey returns the public key declared by the single signer of tx.
+func signedPubKey(t *testing.T, tx sdk.Tx) cryptotypes.PubKey {
+	pks, err := tx.(authsigning.SigVerifiableTx).GetPubKeys()
+	require.NoError(t, err)
+	require.Len(t, pks, 1)
+	return pks[0]
+}
+
+func TestEthSecp256k1Keys(t *testing.T) {
+	cc := newEthProvider(t)
+
+	derived, err := ethhd.EthSecp256k1.Derive()(testMnemonic, "", hd.CreateHDPath(ethCoinType, 0, 0).String())
+	require.NoError(t, err)
+	priv := ethhd.EthSecp256k1.Generate()(derived)
+	want := sdk.MustBech32ifyAddressBytes("evmos", priv.PubKey().Address())
+
+	addr, err := cc.RestoreKey("eth-key", testMnemonic, ethCoinType)
+	require.NoError(t, err)
+	require.Equal(t, want, addr)
+
+	addr, err = cc.Address()
+	require.NoError(t, err)
+	require.Equal(t, want, addr)
+
+	// Transactions declare and are signed with the eth_secp256k1 key.
+	pk := signedPubKey(t, signTestTx(t, cc))
+	require.IsType(t, &ethsecp256k1.PubKey{}, pk)
+	require.True(t, priv.PubKey().Equals(pk))
+
+	// Armored eth_secp256k1 keys round-trip.
+	armor, err := cc.ExportPrivKeyArmor("eth-key")
+	require.NoError(t, err)
+	addr, err = cc.ImportPrivKeyArmor("imported", armor, ckeys.DefaultKeyPass)
+	require.NoError(t, err)
+	require.Equal(t, want, addr)
+
+	addr, err = cc.ImportPrivKeyHex("hex", hex.EncodeToString(priv.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, want, addr)
+}
+
+func TestEthSecp256k1KeysInjective(t *testing.T) {
+	cc := newEthProvider(t, injectiveCodec)
+	_, err := cc.AddKey("eth-key", ethCoinType)
+	require.NoError(t, err)
+
+	// Injective declares its own public key type for the key in the keyring.
+	pk := signedPubKey(t, signTestTx(t, cc))
+	require.IsType(t, &injectiveeth.PubKey{}, pk)
+	addr, err := cc.Address()
+	require.NoError(t, err)
+	require.Equal(t, addr, sdk.MustBech32ifyAddressBytes("evmos", pk.Address()))
+}
+
+func TestKeyAlgorithm(t *testing.T) {
+	cc := newSignerProvider(t, nil)
+	require.Equal(t, hd.Secp256k1, cc.keyAlgo(sdk.CoinType))
+	// Like lens, the Ethereum coin type derives eth_secp256k1 keys unless an algorithm is configured.
+	require.Equal(t, ethhd.EthSecp256k1, cc.keyAlgo(ethCoinType))
+
+	cc.PCfg.KeyAlgorithm = KeyAlgorithmSecp256k1
+	require.Equal(t, hd.Secp256k1, cc.keyAlgo(ethCoinType))
+
+	addr, err := cc.ImportPrivKeyHex("hex", hex.EncodeToString(secp256k1.GenPrivKey().Key))
+	require.NoError(t, err)
+	k, err := cc.Keybase.Key("hex")
+	require.NoError(t, err)
+	pk, err := k.GetPubKey()
+	require.NoError(t, err)
+	require.IsType(t, &secp256k1.PubKey{}, pk)
+	require.Equal(t, addr, sdk.MustBech32ifyAddressBytes("cosmos", pk.Address()))
+
+	require.Error(t, CosmosProviderConfig{Timeout: "10s", KeyAlgorithm: "ed25519"}.Validate())
+}
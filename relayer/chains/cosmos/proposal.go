@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	govv1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+)
+
+// submitProposal is the proposal file format of `tx gov submit-proposal`.
+type submitProposal struct {
+	Messages []json.RawMessage `json:"messages"`
+	Metadata string            `json:"metadata"`
+	Deposit  string            `json:"deposit"`
+}
+
+// ClientUpdateProposal returns the proposal file, for `tx gov submit-proposal`, of a governance proposal
+// to replace the state of the expired or frozen subject client with the state of the substitute client.
+func (cc *CosmosProvider) ClientUpdateProposal(title, description, subjectClientID, substituteClientID string, deposit sdk.Coins) ([]byte, error) {
+	content, err := codectypes.NewAnyWithValue(&clienttypes.ClientUpdateProposal{
+		Title:              title,
+		Description:        description,
+		SubjectClientId:    subjectClientID,
+		SubstituteClientId: substituteClientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	authority, err := cc.EncodeBech32AccAddr(authtypes.NewModuleAddress(govtypes.ModuleName))
+	if err != nil {
+		return nil, err
+	}
+	msg, err := cc.Codec.Marshaler.MarshalInterfaceJSON(govv1.NewMsgExecLegacyContent(content, authority))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode proposal message: %w", err)
+	}
+
+	return json.MarshalIndent(submitProposal{
+		Messages: []json.RawMessage{msg},
+		Deposit:  deposit.String(),
+	}, "", "  ")
+}
+
+// QueryMinDeposit returns the minimum deposit for a governance proposal to enter its voting period.
+func (cc *CosmosProvider) QueryMinDeposit(ctx context.Context) (sdk.Coins, error) {
+	res, err := govv1beta1.NewQueryClient(cc).Params(ctx, &govv1beta1.QueryParamsRequest{ParamsType: govv1beta1.ParamDeposit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query governance deposit params: %w", err)
+	}
+	return res.DepositParams.MinDeposit, nil
+}
@@ -0,0 +1,51 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClientUpdateProposal(t *testing.T) {
+	cfg := CosmosProviderConfig{
+		Key:            "default",
+		ChainID:        "cosmoshub-4",
+		AccountPrefix:  "cosmos",
+		KeyringBackend: "test",
+		Timeout:        "10s",
+	}
+	p, err := cfg.NewProvider(zap.NewNop(), t.TempDir(), false, "cosmoshub")
+	require.NoError(t, err)
+
+	bz, err := p.(*CosmosProvider).ClientUpdateProposal("Recover client", "Replace the expired client",
+		"07-tendermint-5", "07-tendermint-9", sdk.NewCoins(sdk.NewInt64Coin("uatom", 250000000)))
+	require.NoError(t, err)
+
+	var proposal struct {
+		Messages []struct {
+			Type    string `json:"@type"`
+			Content struct {
+				Type               string `json:"@type"`
+				Title              string `json:"title"`
+				SubjectClientID    string `json:"subject_client_id"`
+				SubstituteClientID string `json:"substitute_client_id"`
+			} `json:"content"`
+			Authority string `json:"authority"`
+		} `json:"messages"`
+		Deposit string `json:"deposit"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &proposal))
+	require.Len(t, proposal.Messages, 1)
+
+	msg := proposal.Messages[0]
+	require.Equal(t, "/cosmos.gov.v1.MsgExecLegacyContent", msg.Type)
+	require.Equal(t, "cosmos10d07y265gmmuvt4z0w9aw880jnsr700j6zn9kn", msg.Authority)
+	require.Equal(t, "/ibc.core.client.v1.ClientUpdateProposal", msg.Content.Type)
+	require.Equal(t, "Recover client", msg.Content.Title)
+	require.Equal(t, "07-tendermint-5", msg.Content.SubjectClientID)
+	require.Equal(t, "07-tendermint-9", msg.Content.SubstituteClientID)
+	require.Equal(t, "250000000uatom", proposal.Deposit)
+}
@@ -12,6 +12,7 @@ import (
 	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
 	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
 	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/audit"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"github.com/gogo/protobuf/proto"
@@ -21,9 +22,10 @@ import (
 )
 
 var (
-	_ provider.ChainProvider  = &CosmosProvider{}
-	_ provider.KeyProvider    = &CosmosProvider{}
-	_ provider.ProviderConfig = &CosmosProviderConfig{}
+	_ provider.ChainProvider     = &CosmosProvider{}
+	_ provider.KeyProvider       = &CosmosProvider{}
+	_ provider.RemoteKeyProvider = &CosmosProvider{}
+	_ provider.ProviderConfig    = &CosmosProviderConfig{}
 )
 
 type CosmosProviderConfig struct {
@@ -41,12 +43,36 @@ type CosmosProviderConfig struct {
 	OutputFormat   string   `json:"output-format" yaml:"output-format"`
 	SignModeStr    string   `json:"sign-mode" yaml:"sign-mode"`
 	ExtraCodecs    []string `json:"extra-codecs" yaml:"extra-codecs"`
+
+	// KeyAlgorithm is the signing algorithm of the keys of the chain:
+	// secp256k1, the default, or eth_secp256k1 for Ethermint based chains.
+	KeyAlgorithm string `json:"key-algorithm,omitempty" yaml:"key-algorithm,omitempty"`
+
+	// Signer configures a remote signer holding Key, instead of the local keyring.
+	Signer *SignerConfig `json:"signer,omitempty" yaml:"signer,omitempty"`
+
+	// BlockQueryConcurrency is the number of blocks queried at a time while catching up to the latest height of the chain,
+	// defaultBlockQueryConcurrency if unset.
+	BlockQueryConcurrency int `json:"block-query-concurrency,omitempty" yaml:"block-query-concurrency,omitempty"`
 }
 
 func (pc CosmosProviderConfig) Validate() error {
 	if _, err := time.ParseDuration(pc.Timeout); err != nil {
 		return fmt.Errorf("invalid Timeout: %w", err)
 	}
+	switch pc.KeyAlgorithm {
+	case "", KeyAlgorithmSecp256k1, KeyAlgorithmEthSecp256k1:
+	default:
+		return fmt.Errorf("invalid KeyAlgorithm %q: expected %s or %s", pc.KeyAlgorithm, KeyAlgorithmSecp256k1, KeyAlgorithmEthSecp256k1)
+	}
+	if pc.BlockQueryConcurrency < 0 {
+		return fmt.Errorf("invalid BlockQueryConcurrency %d: must not be negative", pc.BlockQueryConcurrency)
+	}
+	if pc.Signer != nil {
+		if err := pc.Signer.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -66,12 +92,24 @@ func (pc CosmosProviderConfig) NewProvider(log *zap.Logger, homepath string, deb
 		return nil, err
 	}
 	pc.ChainName = chainName
+	for _, c := range extraCodecs(&pc) {
+		if c == ethermintCodec {
+			registerEthSecp256k1Amino()
+		}
+	}
 
-	return &CosmosProvider{
+	cp := &CosmosProvider{
 		log:         log,
 		ChainClient: *cc,
 		PCfg:        pc,
-	}, nil
+		headers:     newHeaderLRU(ibcHeadersToCache),
+	}
+	if pc.Signer != nil {
+		if err := cp.useRemoteSigner(); err != nil {
+			return nil, err
+		}
+	}
+	return cp, nil
 }
 
 // ChainClientConfig builds a ChainClientConfig struct from a CosmosProviderConfig, this is used
@@ -90,11 +128,26 @@ func ChainClientConfig(pcfg *CosmosProviderConfig) *lens.ChainClientConfig {
 		Timeout:        pcfg.Timeout,
 		OutputFormat:   pcfg.OutputFormat,
 		SignModeStr:    pcfg.SignModeStr,
-		ExtraCodecs:    pcfg.ExtraCodecs,
+		ExtraCodecs:    extraCodecs(pcfg),
 		Modules:        append([]module.AppModuleBasic{}, lens.ModuleBasics...),
 	}
 }
 
+// extraCodecs returns the extra codecs of the chain, including the Ethermint codec
+// that the keyring needs to store eth_secp256k1 keys.
+func extraCodecs(pcfg *CosmosProviderConfig) []string {
+	codecs := append([]string{}, pcfg.ExtraCodecs...)
+	if pcfg.KeyAlgorithm != KeyAlgorithmEthSecp256k1 {
+		return codecs
+	}
+	for _, c := range codecs {
+		if c == ethermintCodec {
+			return codecs
+		}
+	}
+	return append(codecs, ethermintCodec)
+}
+
 type CosmosProvider struct {
 	log *zap.Logger
 
@@ -109,6 +162,18 @@ type CosmosProvider struct {
 	totalFeesMu sync.Mutex
 
 	metrics *processor.PrometheusMetrics
+
+	// optional record of every broadcast transaction
+	auditLog *audit.Log
+
+	// signs transactions instead of the local keyring, if a remote signer is configured
+	signer Signer
+
+	// recently queried IBC headers, which do not change once committed
+	headers *headerLRU
+
+	// broadcasts signed transactions instead of BroadcastTx, if wrapped with WrapBroadcast
+	broadcast BroadcastFunc
 }
 
 type CosmosIBCHeader struct {
@@ -159,7 +224,7 @@ func (cc *CosmosProvider) AddKey(name string, coinType uint32) (*provider.KeyOut
 	//
 	// Translate the lens KeyOutput to a relayer KeyOutput here to satisfy the interface.
 
-	ko, err := cc.ChainClient.AddKey(name, coinType)
+	ko, err := cc.keyAddOrRestore(name, coinType)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,7 @@ import (
 	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	tmtypes "github.com/tendermint/tendermint/types"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -339,9 +340,10 @@ func (cc *CosmosProvider) QueryClientConsensusState(ctx context.Context, chainHe
 	}, nil
 }
 
-// QueryUpgradeProof performs an abci query with the given key and returns the proto encoded merkle proof
-// for the query and the height at which the proof will succeed on a tendermint verifier.
-func (cc *CosmosProvider) QueryUpgradeProof(ctx context.Context, key []byte, height uint64) ([]byte, clienttypes.Height, error) {
+// QueryUpgradeProof performs an abci query of the upgrade store at the last height before the upgrade at height
+// and returns the value of key and its proto encoded merkle proof.
+// The upgrade clears the upgraded client and consensus states once applied, so they are only found at that height.
+func (cc *CosmosProvider) QueryUpgradeProof(ctx context.Context, key []byte, height uint64) ([]byte, []byte, error) {
 	res, err := cc.QueryABCI(ctx, abci.RequestQuery{
 		Path:   "store/upgrade/key",
 		Height: int64(height - 1),
@@ -349,81 +351,99 @@ func (cc *CosmosProvider) QueryUpgradeProof(ctx context.Context, key []byte, hei
 		Prove:  true,
 	})
 	if err != nil {
-		return nil, clienttypes.Height{}, err
+		return nil, nil, err
 	}
 
 	merkleProof, err := commitmenttypes.ConvertProofs(res.ProofOps)
 	if err != nil {
-		return nil, clienttypes.Height{}, err
+		return nil, nil, err
 	}
 
 	proof, err := cc.Codec.Marshaler.Marshal(&merkleProof)
 	if err != nil {
-		return nil, clienttypes.Height{}, err
+		return nil, nil, err
 	}
 
-	revision := clienttypes.ParseChainID(cc.PCfg.ChainID)
+	return res.Value, proof, nil
+}
 
-	// proof height + 1 is returned as the proof created corresponds to the height the proof
-	// was created in the IAVL tree. Tendermint and subsequently the clients that rely on it
-	// have heights 1 above the IAVL tree. Thus we return proof height + 1
-	return proof, clienttypes.Height{
-		RevisionNumber: revision,
-		RevisionHeight: uint64(res.Height + 1),
-	}, nil
+// upgradeProofHeight returns the height at which the proofs of the upgrade at height succeed on a tendermint verifier,
+// under the revision of the chain ID the chain restarts with, which is set in the upgraded client state.
+// The proofs are created at the IAVL height before the upgrade, and tendermint heights are 1 above the IAVL tree.
+func (cc *CosmosProvider) upgradeProofHeight(clientState ibcexported.ClientState, height int64) clienttypes.Height {
+	chainID := cc.PCfg.ChainID
+	if tmClientState, ok := clientState.(*tmclient.ClientState); ok {
+		chainID = tmClientState.ChainId
+	}
+	return clienttypes.NewHeight(clienttypes.ParseChainID(chainID), uint64(height))
 }
 
-// QueryUpgradedClient returns upgraded client info
+// QueryUpgradedClient returns the upgraded client state of the upgrade at height, with its proof,
+// as committed at the last height before the upgrade.
 func (cc *CosmosProvider) QueryUpgradedClient(ctx context.Context, height int64) (*clienttypes.QueryClientStateResponse, error) {
-	req := clienttypes.QueryUpgradedClientStateRequest{}
-
-	queryClient := clienttypes.NewQueryClient(cc)
-
-	res, err := queryClient.UpgradedClientState(ctx, &req)
+	value, proof, err := cc.QueryUpgradeProof(ctx, upgradetypes.UpgradedClientKey(height), uint64(height))
 	if err != nil {
 		return nil, err
 	}
-
-	if res == nil || res.UpgradedClientState == nil {
+	if len(value) == 0 {
 		return nil, fmt.Errorf("upgraded client state plan does not exist at height %d", height)
 	}
 
-	proof, proofHeight, err := cc.QueryUpgradeProof(ctx, upgradetypes.UpgradedClientKey(height), uint64(height))
+	clientState, err := clienttypes.UnmarshalClientState(cc.Codec.Marshaler, value)
+	if err != nil {
+		return nil, err
+	}
+
+	anyClientState, err := clienttypes.PackClientState(clientState)
 	if err != nil {
 		return nil, err
 	}
 
 	return &clienttypes.QueryClientStateResponse{
-		ClientState: res.UpgradedClientState,
+		ClientState: anyClientState,
 		Proof:       proof,
-		ProofHeight: proofHeight,
+		ProofHeight: cc.upgradeProofHeight(clientState, height),
 	}, nil
 }
 
-// QueryUpgradedConsState returns upgraded consensus state and height of client
+// QueryUpgradedConsState returns the upgraded consensus state of the upgrade at height, with its proof,
+// as committed at the last height before the upgrade.
 func (cc *CosmosProvider) QueryUpgradedConsState(ctx context.Context, height int64) (*clienttypes.QueryConsensusStateResponse, error) {
-	req := clienttypes.QueryUpgradedConsensusStateRequest{}
-
-	queryClient := clienttypes.NewQueryClient(cc)
-
-	res, err := queryClient.UpgradedConsensusState(ctx, &req)
+	// The upgraded client state holds the chain ID of the proof height.
+	clientValue, _, err := cc.QueryUpgradeProof(ctx, upgradetypes.UpgradedClientKey(height), uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	if len(clientValue) == 0 {
+		return nil, fmt.Errorf("upgraded client state plan does not exist at height %d", height)
+	}
+	clientState, err := clienttypes.UnmarshalClientState(cc.Codec.Marshaler, clientValue)
 	if err != nil {
 		return nil, err
 	}
 
-	if res == nil || res.UpgradedConsensusState == nil {
+	value, proof, err := cc.QueryUpgradeProof(ctx, upgradetypes.UpgradedConsStateKey(height), uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
 		return nil, fmt.Errorf("upgraded consensus state plan does not exist at height %d", height)
 	}
 
-	proof, proofHeight, err := cc.QueryUpgradeProof(ctx, upgradetypes.UpgradedConsStateKey(height), uint64(height))
+	consensusState, err := clienttypes.UnmarshalConsensusState(cc.Codec.Marshaler, value)
+	if err != nil {
+		return nil, err
+	}
+
+	anyConsensusState, err := clienttypes.PackConsensusState(consensusState)
 	if err != nil {
 		return nil, err
 	}
 
 	return &clienttypes.QueryConsensusStateResponse{
-		ConsensusState: res.UpgradedConsensusState,
+		ConsensusState: anyConsensusState,
 		Proof:          proof,
-		ProofHeight:    proofHeight,
+		ProofHeight:    cc.upgradeProofHeight(clientState, height),
 	}, nil
 }
 
@@ -541,9 +561,39 @@ func (cc *CosmosProvider) QueryConnectionsUsingClient(ctx context.Context, heigh
 	return res, err
 }
 
+// QueryMaxExpectedTimePerBlock returns the max expected time per block of the connection params,
+// which are kept in the params subspace of the IBC module.
+func (cc *CosmosProvider) QueryMaxExpectedTimePerBlock(ctx context.Context) (time.Duration, error) {
+	res, err := proposal.NewQueryClient(cc).Params(ctx, &proposal.QueryParamsRequest{
+		Subspace: host.ModuleName,
+		Key:      string(conntypes.KeyMaxExpectedTimePerBlock),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query connection params: %w", err)
+	}
+
+	// The value is the amino JSON of a number of nanoseconds, i.e. a quoted string.
+	maxExpectedTimePerBlock, err := strconv.ParseUint(strings.ReplaceAll(res.Param.Value, `"`, ""), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse max expected time per block from connection params: %w", err)
+	}
+
+	return time.Duration(maxExpectedTimePerBlock), nil
+}
+
 // GenerateConnHandshakeProof generates all the proofs needed to prove the existence of the
 // connection state on this chain. A counterparty should use these generated proofs.
 func (cc *CosmosProvider) GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (clientState ibcexported.ClientState, clientStateProof []byte, consensusProof []byte, connectionProof []byte, connectionProofHeight ibcexported.Height, err error) {
+	proof, err := cc.connHandshakeProof(ctx, height, clientId, connId)
+	if err != nil {
+		return nil, nil, nil, nil, clienttypes.Height{}, err
+	}
+	return proof.ClientState, proof.ClientStateProof, proof.ConsensusStateProof, proof.ConnectionStateProof, proof.ProofHeight, nil
+}
+
+// connHandshakeProof generates the proofs of GenerateConnHandshakeProof,
+// along with the delay period of the connection.
+func (cc *CosmosProvider) connHandshakeProof(ctx context.Context, height int64, clientId, connId string) (provider.ConnectionProof, error) {
 	var (
 		clientStateRes     *clienttypes.QueryClientStateResponse
 		consensusStateRes  *clienttypes.QueryConsensusStateResponse
@@ -552,14 +602,14 @@ func (cc *CosmosProvider) GenerateConnHandshakeProof(ctx context.Context, height
 	)
 
 	// query for the client state for the proof and get the height to query the consensus state at.
-	clientStateRes, err = cc.QueryClientStateResponse(ctx, height, clientId)
+	clientStateRes, err := cc.QueryClientStateResponse(ctx, height, clientId)
 	if err != nil {
-		return nil, nil, nil, nil, clienttypes.Height{}, err
+		return provider.ConnectionProof{}, err
 	}
 
-	clientState, err = clienttypes.UnpackClientState(clientStateRes.ClientState)
+	clientState, err := clienttypes.UnpackClientState(clientStateRes.ClientState)
 	if err != nil {
-		return nil, nil, nil, nil, clienttypes.Height{}, err
+		return provider.ConnectionProof{}, err
 	}
 
 	eg.Go(func() error {
@@ -574,10 +624,17 @@ func (cc *CosmosProvider) GenerateConnHandshakeProof(ctx context.Context, height
 	})
 
 	if err := eg.Wait(); err != nil {
-		return nil, nil, nil, nil, clienttypes.Height{}, err
+		return provider.ConnectionProof{}, err
 	}
 
-	return clientState, clientStateRes.Proof, consensusStateRes.Proof, connectionStateRes.Proof, connectionStateRes.ProofHeight, nil
+	return provider.ConnectionProof{
+		ClientState:          clientState,
+		ClientStateProof:     clientStateRes.Proof,
+		ConsensusStateProof:  consensusStateRes.Proof,
+		ConnectionStateProof: connectionStateRes.Proof,
+		ProofHeight:          connectionStateRes.ProofHeight,
+		DelayPeriod:          connectionStateRes.Connection.DelayPeriod,
+	}, nil
 }
 
 // QueryChannel returns the channel associated with a channelID
@@ -674,34 +731,50 @@ func (cc *CosmosProvider) QueryChannels(ctx context.Context) ([]*chantypes.Ident
 	return res.Channels, nil
 }
 
-// QueryPacketCommitments returns an array of packet commitments
-// TODO add pagination support
+// QueryPacketCommitments returns an array of packet commitments, reading all pages of the query
 func (cc *CosmosProvider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (commitments *chantypes.QueryPacketCommitmentsResponse, err error) {
 	qc := chantypes.NewQueryClient(cc)
-	c, err := qc.PacketCommitments(ctx, &chantypes.QueryPacketCommitmentsRequest{
-		PortId:     portid,
-		ChannelId:  channelid,
-		Pagination: DefaultPageRequest(),
-	})
-	if err != nil {
-		return nil, err
+	for page := DefaultPageRequest(); ; {
+		c, err := qc.PacketCommitments(ctx, &chantypes.QueryPacketCommitmentsRequest{
+			PortId:     portid,
+			ChannelId:  channelid,
+			Pagination: page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if commitments == nil {
+			commitments = c
+		} else {
+			commitments.Commitments = append(commitments.Commitments, c.Commitments...)
+			commitments.Pagination = c.Pagination
+			commitments.Height = c.Height
+		}
+		if c.Pagination == nil || len(c.Pagination.NextKey) == 0 {
+			return commitments, nil
+		}
+		page = &querytypes.PageRequest{Key: c.Pagination.NextKey, Limit: page.Limit}
 	}
-	return c, nil
 }
 
-// QueryPacketAcknowledgements returns an array of packet acks
-// TODO add pagination support
+// QueryPacketAcknowledgements returns an array of packet acks, reading all pages of the query
 func (cc *CosmosProvider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string) (acknowledgements []*chantypes.PacketState, err error) {
 	qc := chantypes.NewQueryClient(cc)
-	acks, err := qc.PacketAcknowledgements(ctx, &chantypes.QueryPacketAcknowledgementsRequest{
-		PortId:     portid,
-		ChannelId:  channelid,
-		Pagination: DefaultPageRequest(),
-	})
-	if err != nil {
-		return nil, err
+	for page := DefaultPageRequest(); ; {
+		acks, err := qc.PacketAcknowledgements(ctx, &chantypes.QueryPacketAcknowledgementsRequest{
+			PortId:     portid,
+			ChannelId:  channelid,
+			Pagination: page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		acknowledgements = append(acknowledgements, acks.Acknowledgements...)
+		if acks.Pagination == nil || len(acks.Pagination.NextKey) == 0 {
+			return acknowledgements, nil
+		}
+		page = &querytypes.PageRequest{Key: acks.Pagination.NextKey, Limit: page.Limit}
 	}
-	return acks.Acknowledgements, nil
 }
 
 // QueryUnreceivedPackets returns a list of unrelayed packet commitments
@@ -821,6 +894,21 @@ func (cc *CosmosProvider) QueryNextSeqRecv(ctx context.Context, height int64, ch
 	}, nil
 }
 
+// QueryNextSeqAck returns the next sequence to acknowledge for a configured ordered channel
+func (cc *CosmosProvider) QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (uint64, error) {
+	value, _, _, err := cc.QueryTendermintProof(ctx, height, host.NextSequenceAckKey(portid, channelid))
+	if err != nil {
+		return 0, err
+	}
+
+	// check if next sequence ack exists
+	if len(value) == 0 {
+		return 0, sdkerrors.Wrapf(chantypes.ErrChannelNotFound, "portID (%s), channelID (%s)", portid, channelid)
+	}
+
+	return binary.BigEndian.Uint64(value), nil
+}
+
 // QueryPacketCommitment returns the packet commitment proof at a given height
 func (cc *CosmosProvider) QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (comRes *chantypes.QueryPacketCommitmentResponse, err error) {
 	key := host.PacketCommitmentKey(portid, channelid, seq)
@@ -888,6 +976,11 @@ func (cc *CosmosProvider) QueryLatestHeight(ctx context.Context) (int64, error)
 	return stat.SyncInfo.LatestBlockHeight, nil
 }
 
+// QueryBlockResults returns the results of the block at height.
+func (cc *CosmosProvider) QueryBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error) {
+	return cc.RPCClient.BlockResults(ctx, &height)
+}
+
 // QueryHeaderAtHeight returns the header at a given height
 func (cc *CosmosProvider) QueryHeaderAtHeight(ctx context.Context, height int64) (ibcexported.Header, error) {
 	var (
@@ -0,0 +1,94 @@
+package cosmos
+
+import (
+	"context"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+var _ processor.ChainProcessor = &ReplayChainProcessor{}
+
+// ReplayChainProcessor is a CosmosChainProcessor that replays block results, light blocks and ABCI queries
+// recorded with CosmosProvider.RecordFixtures, one recorded block per query cycle, so that the messages
+// parsed from a captured incident are fed to the PathProcessors exactly as they were when it happened.
+type ReplayChainProcessor struct {
+	*CosmosChainProcessor
+
+	replay *fixtureReplay
+
+	// done is closed once all recorded blocks have been replayed.
+	done chan struct{}
+}
+
+// NewReplayChainProcessor returns a ReplayChainProcessor replaying the fixtures recorded in dir.
+// The RPC client and light provider of provider are replaced with ones serving the fixtures.
+func NewReplayChainProcessor(log *zap.Logger, provider *CosmosProvider, dir string) (*ReplayChainProcessor, error) {
+	replay, err := provider.replayFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayChainProcessor{
+		CosmosChainProcessor: NewCosmosChainProcessor(log, provider, nil),
+		replay:               replay,
+		done:                 make(chan struct{}),
+	}, nil
+}
+
+// Done returns a channel which is closed once all recorded blocks have been replayed.
+func (rcp *ReplayChainProcessor) Done() <-chan struct{} {
+	return rcp.done
+}
+
+// Run replays all recorded blocks, starting from the first recorded height regardless of initialBlockHistory.
+// Gaps between recorded heights are skipped. After the last block, Run waits for the context to be done,
+// like a chain that stopped producing blocks, so that the PathProcessors can finish handling the replayed messages.
+func (rcp *ReplayChainProcessor) Run(ctx context.Context, initialBlockHistory uint64) error {
+	// Connections and channels are only queried at startup, so they may be missing
+	// if the recording was started with an already running relayer.
+	if err := rcp.initializeConnectionState(ctx); err != nil {
+		rcp.log.Warn("No recorded connection state, continuing without it", zap.Error(err))
+	}
+	if err := rcp.initializeChannelState(ctx); err != nil {
+		rcp.log.Warn("No recorded channel state, continuing without it", zap.Error(err))
+	}
+
+	heights := rcp.replay.heights
+	persistence := queryCyclePersistence{
+		latestQueriedBlock:        heights[0] - 1,
+		minQueryLoopDuration:      defaultMinQueryLoopDuration,
+		balanceUpdateWaitDuration: defaultBalanceUpdateWaitDuration,
+	}
+
+	for i, h := range heights {
+		if i > 0 && h > heights[i-1]+1 {
+			rcp.log.Info("Skipping heights missing from the recording",
+				zap.Int64("from", heights[i-1]+1),
+				zap.Int64("to", h-1),
+			)
+			persistence.latestQueriedBlock = h - 1
+		}
+		// Headers are only queried for the latest block of a query cycle, so blocks recorded without one
+		// are replayed along with the next block that has one, like the query cycle that recorded them.
+		if i < len(heights)-1 && heights[i+1] == h+1 &&
+			!rcp.replay.store.has(fixtureLightBlocksDir, heightFixtureName(h)) {
+			continue
+		}
+		rcp.replay.latestHeight.Store(h)
+		if err := rcp.queryCycle(ctx, &persistence); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	rcp.log.Info("Replayed all recorded blocks",
+		zap.Int64("first_height", heights[0]),
+		zap.Int64("last_height", heights[len(heights)-1]),
+	)
+	close(rcp.done)
+
+	<-ctx.Done()
+	return nil
+}
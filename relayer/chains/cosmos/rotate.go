@@ -0,0 +1,269 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/cosmos/cosmos-sdk/x/feegrant"
+	feetypes "github.com/cosmos/ibc-go/v5/modules/apps/29-fee/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// rotationReserveGas is the gas that the default reserve of a key rotation pays for at the configured gas prices.
+// The old key pays for the transfer of its balance and the revocation of its fee grants.
+const rotationReserveGas = 500_000
+
+var _ provider.KeySwitcher = &CosmosProvider{}
+
+// KeyRotation is the plan to move the funds and registrations of the relayer key of a chain to a new key.
+type KeyRotation struct {
+	OldKey     string
+	OldAddress string
+	NewKey     string
+	NewAddress string
+
+	// Transfer is the balance of the old key, minus the reserve, that is sent to the new key.
+	Transfer sdk.Coins
+
+	// FeeGrants are the fee allowances granted by the old key, which are granted again by the new key
+	// and revoked from the old key.
+	FeeGrants []*feegrant.Grant
+
+	// ReceivedFeeGrants are the fee allowances granted to the old key.
+	// Only their granters can grant them to the new key.
+	ReceivedFeeGrants []*feegrant.Grant
+
+	// Payees are the ICS-29 payee registrations of the old key, which are registered again for the new key.
+	Payees []FeePayee
+}
+
+// FeePayee is the ICS-29 payee registration of a relayer on a fee enabled channel.
+type FeePayee struct {
+	PortID    string
+	ChannelID string
+
+	// Payee receives the timeout and ack fees of the relayer, if set.
+	Payee string
+	// CounterpartyPayee receives the recv fees of the relayer on the counterparty chain, if set.
+	CounterpartyPayee string
+}
+
+// UseKey switches the key that the provider signs transactions with.
+func (cc *CosmosProvider) UseKey(name string) error {
+	if cc.PCfg.Signer != nil {
+		return fmt.Errorf("cannot switch keys of chain %s, its key is held by a remote signer", cc.ChainName())
+	}
+	if _, err := cc.Keybase.Key(name); err != nil {
+		return fmt.Errorf("failed to switch to key %s: %w", name, err)
+	}
+
+	// Hold the transaction lock so that no transaction is signed with a mix of both keys.
+	cc.txMu.Lock()
+	defer cc.txMu.Unlock()
+
+	cc.PCfg.Key = name
+	cc.Config.Key = name
+	cc.nextAccountSeq = 0
+	return nil
+}
+
+// DefaultRotationReserve returns the reserve that is left to the old key of a key rotation
+// to pay for its transactions, based on the configured gas prices.
+func (cc *CosmosProvider) DefaultRotationReserve() (sdk.Coins, error) {
+	if cc.PCfg.GasPrices == "" {
+		return sdk.NewCoins(), nil
+	}
+	prices, err := sdk.ParseDecCoins(cc.PCfg.GasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gas prices: %w", err)
+	}
+	reserve := sdk.NewCoins()
+	for _, p := range prices {
+		reserve = reserve.Add(sdk.NewCoin(p.Denom, p.Amount.MulInt64(rotationReserveGas).Ceil().TruncateInt()))
+	}
+	return reserve, nil
+}
+
+// PlanKeyRotation queries the balance, fee grants and ICS-29 payee registrations of the current key,
+// and plans moving them to newKey, leaving reserve with the current key.
+func (cc *CosmosProvider) PlanKeyRotation(ctx context.Context, newKey string, reserve sdk.Coins) (*KeyRotation, error) {
+	r := &KeyRotation{OldKey: cc.Key(), NewKey: newKey}
+
+	var err error
+	if r.OldAddress, err = cc.ShowAddress(r.OldKey); err != nil {
+		return nil, err
+	}
+	if r.NewAddress, err = cc.ShowAddress(r.NewKey); err != nil {
+		return nil, err
+	}
+
+	balance, err := cc.QueryBalanceWithAddress(ctx, r.OldAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query balance of %s: %w", r.OldAddress, err)
+	}
+	r.Transfer = sdk.NewCoins()
+	for _, c := range balance {
+		if amt := c.Amount.Sub(reserve.AmountOf(c.Denom)); amt.IsPositive() {
+			r.Transfer = r.Transfer.Add(sdk.NewCoin(c.Denom, amt))
+		}
+	}
+
+	if r.FeeGrants, err = cc.queryFeeGrantsByGranter(ctx, r.OldAddress); err != nil {
+		return nil, err
+	}
+	if r.ReceivedFeeGrants, err = cc.queryFeeGrantsByGrantee(ctx, r.OldAddress); err != nil {
+		return nil, err
+	}
+	if r.Payees, err = cc.queryFeePayees(ctx, r.OldAddress); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// RotateKey executes the key rotation r and switches the provider to the new key:
+// the old key sends its balance to the new key, the new key grants the fee allowances of the old key
+// and registers its payees, and finally the old key revokes its fee allowances.
+func (cc *CosmosProvider) RotateKey(ctx context.Context, r *KeyRotation) error {
+	oldAddr, err := cc.DecodeBech32AccAddr(r.OldAddress)
+	if err != nil {
+		return err
+	}
+	newAddr, err := cc.DecodeBech32AccAddr(r.NewAddress)
+	if err != nil {
+		return err
+	}
+
+	var transfer []provider.RelayerMessage
+	if !r.Transfer.IsZero() {
+		transfer = append(transfer, NewCosmosMessage(banktypes.NewMsgSend(oldAddr, newAddr, r.Transfer)))
+	}
+
+	var register, revoke []provider.RelayerMessage
+	for _, g := range r.FeeGrants {
+		if g.Grantee != r.NewAddress {
+			register = append(register, NewCosmosMessage(&feegrant.MsgGrantAllowance{
+				Granter:   r.NewAddress,
+				Grantee:   g.Grantee,
+				Allowance: g.Allowance,
+			}))
+		}
+		revoke = append(revoke, NewCosmosMessage(&feegrant.MsgRevokeAllowance{
+			Granter: r.OldAddress,
+			Grantee: g.Grantee,
+		}))
+	}
+	for _, p := range r.Payees {
+		if p.Payee != "" {
+			register = append(register, NewCosmosMessage(
+				feetypes.NewMsgRegisterPayee(p.PortID, p.ChannelID, r.NewAddress, rotatedPayee(p.Payee, r)),
+			))
+		}
+		if p.CounterpartyPayee != "" {
+			register = append(register, NewCosmosMessage(
+				feetypes.NewMsgRegisterCounterpartyPayee(p.PortID, p.ChannelID, r.NewAddress, p.CounterpartyPayee),
+			))
+		}
+	}
+
+	for _, step := range []struct {
+		key  string
+		msgs []provider.RelayerMessage
+	}{
+		{r.OldKey, transfer},
+		{r.NewKey, register},
+		{r.OldKey, revoke},
+	} {
+		if len(step.msgs) == 0 {
+			continue
+		}
+		if err := cc.UseKey(step.key); err != nil {
+			return err
+		}
+		if _, _, err := cc.SendMessages(ctx, step.msgs, ""); err != nil {
+			return fmt.Errorf("failed to rotate key %s to %s: %w", r.OldKey, r.NewKey, err)
+		}
+	}
+
+	return cc.UseKey(r.NewKey)
+}
+
+// rotatedPayee returns the payee to register for the new key of r:
+// a payee that was the old key itself becomes the new key.
+func rotatedPayee(payee string, r *KeyRotation) string {
+	if payee == r.OldAddress {
+		return r.NewAddress
+	}
+	return payee
+}
+
+func (cc *CosmosProvider) queryFeeGrantsByGranter(ctx context.Context, granter string) ([]*feegrant.Grant, error) {
+	qc := feegrant.NewQueryClient(cc)
+	var grants []*feegrant.Grant
+	for page := DefaultPageRequest(); ; {
+		res, err := qc.AllowancesByGranter(ctx, &feegrant.QueryAllowancesByGranterRequest{Granter: granter, Pagination: page})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query fee grants by %s: %w", granter, err)
+		}
+		grants = append(grants, res.Allowances...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return grants, nil
+		}
+		page = &querytypes.PageRequest{Key: res.Pagination.NextKey, Limit: page.Limit}
+	}
+}
+
+func (cc *CosmosProvider) queryFeeGrantsByGrantee(ctx context.Context, grantee string) ([]*feegrant.Grant, error) {
+	qc := feegrant.NewQueryClient(cc)
+	var grants []*feegrant.Grant
+	for page := DefaultPageRequest(); ; {
+		res, err := qc.Allowances(ctx, &feegrant.QueryAllowancesRequest{Grantee: grantee, Pagination: page})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query fee grants to %s: %w", grantee, err)
+		}
+		grants = append(grants, res.Allowances...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return grants, nil
+		}
+		page = &querytypes.PageRequest{Key: res.Pagination.NextKey, Limit: page.Limit}
+	}
+}
+
+// queryFeePayees returns the ICS-29 payee registrations of relayer on the fee enabled channels of the chain.
+// Chains without the fee middleware have no registrations.
+func (cc *CosmosProvider) queryFeePayees(ctx context.Context, relayer string) ([]FeePayee, error) {
+	qc := feetypes.NewQueryClient(cc)
+
+	// The response of ibc-go v5 is not paginated, so all channels are requested at once.
+	res, err := qc.FeeEnabledChannels(ctx, &feetypes.QueryFeeEnabledChannelsRequest{
+		Pagination: &querytypes.PageRequest{Limit: querytypes.MaxLimit},
+	})
+	if err != nil {
+		cc.log.Info(
+			"Skipping ICS-29 payee registrations, failed to query fee enabled channels",
+			zap.String("chain_id", cc.ChainId()),
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+
+	var payees []FeePayee
+	for _, ch := range res.FeeEnabledChannels {
+		p := FeePayee{PortID: ch.PortId, ChannelID: ch.ChannelId}
+		// Queries for relayers without a registration fail with a not found error.
+		if res, err := qc.Payee(ctx, &feetypes.QueryPayeeRequest{ChannelId: ch.ChannelId, Relayer: relayer}); err == nil {
+			p.Payee = res.PayeeAddress
+		}
+		if res, err := qc.CounterpartyPayee(ctx, &feetypes.QueryCounterpartyPayeeRequest{ChannelId: ch.ChannelId, Relayer: relayer}); err == nil {
+			p.CounterpartyPayee = res.CounterpartyPayee
+		}
+		if p.Payee != "" || p.CounterpartyPayee != "" {
+			payees = append(payees, p)
+		}
+	}
+	return payees, nil
+}
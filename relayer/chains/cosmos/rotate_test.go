@@ -0,0 +1,67 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseKey(t *testing.T) {
+	cc := newSignerProvider(t, nil)
+	_, err := cc.AddKey(signerKey, sdk.CoinType)
+	require.NoError(t, err)
+	ko, err := cc.AddKey("new-key", sdk.CoinType)
+	require.NoError(t, err)
+
+	require.Error(t, cc.UseKey("missing-key"))
+	require.Equal(t, signerKey, cc.Key())
+
+	require.NoError(t, cc.UseKey("new-key"))
+	require.Equal(t, "new-key", cc.Key())
+	require.Equal(t, "new-key", cc.ProviderConfig().(CosmosProviderConfig).Key)
+	addr, err := cc.Address()
+	require.NoError(t, err)
+	require.Equal(t, ko.Address, addr)
+
+	// Transactions are signed with the new key.
+	signed := signTestTx(t, cc)
+	// The signers are decoded with the process-global bech32 prefixes.
+	done := cc.SetSDKContext()
+	signers := signed.GetMsgs()[0].GetSigners()
+	done()
+	require.Equal(t, ko.Address, sdk.MustBech32ifyAddressBytes("cosmos", signers[0]))
+}
+
+func TestUseKeyRemoteSigner(t *testing.T) {
+	cc := newSignerProvider(t, &SignerConfig{URL: "http://127.0.0.1:1", PubKey: pubKeyJSON(t, secp256k1.GenPrivKey())})
+	_, err := cc.AddKey("local-key", sdk.CoinType)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, cc.UseKey("local-key"), "remote signer")
+	require.Equal(t, signerKey, cc.Key())
+}
+
+func TestDefaultRotationReserve(t *testing.T) {
+	cc := newSignerProvider(t, nil)
+
+	reserve, err := cc.DefaultRotationReserve()
+	require.NoError(t, err)
+	require.True(t, reserve.IsZero())
+
+	cc.PCfg.GasPrices = "0.0025uatom,0.1stake"
+	reserve, err = cc.DefaultRotationReserve()
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("uatom", 1250), sdk.NewInt64Coin("stake", 50_000)), reserve)
+
+	cc.PCfg.GasPrices = "cheap"
+	_, err = cc.DefaultRotationReserve()
+	require.Error(t, err)
+}
+
+func TestRotatedPayee(t *testing.T) {
+	r := &KeyRotation{OldAddress: "cosmos1old", NewAddress: "cosmos1new"}
+	require.Equal(t, "cosmos1new", rotatedPayee("cosmos1old", r))
+	require.Equal(t, "cosmos1payee", rotatedPayee("cosmos1payee", r))
+}
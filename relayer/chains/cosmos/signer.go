@@ -0,0 +1,469 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	injectiveeth "github.com/InjectiveLabs/sdk-go/chain/crypto/ethsecp256k1"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/evmos/ethermint/crypto/ethsecp256k1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	defaultSignerTimeout = 10 * time.Second
+
+	signerService    = "relayer.signer.v1.Signer"
+	signerSignMethod = "/" + signerService + "/Sign"
+)
+
+// ErrReadOnlyKey is returned when modifying, exporting or signing locally with a key held by a remote signer.
+var ErrReadOnlyKey = errors.New("key is held by a remote signer and is read-only")
+
+// SignerConfig configures a remote signer holding the key of the provider, instead of the local keyring.
+type SignerConfig struct {
+	// URL is the address of the remote signer, either http(s)://host[:port]/path or grpc(s)://host:port.
+	URL string `json:"url" yaml:"url"`
+
+	// PubKey is the public key of the key held by the remote signer, as printed by `<chain daemon> keys show -p`,
+	// e.g. {"@type":"/cosmos.crypto.secp256k1.PubKey","key":"A..."}.
+	PubKey string `json:"pub-key" yaml:"pub-key"`
+
+	// Headers are added to every request to an HTTP signer, e.g. for authentication.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Timeout bounds every sign request, 10s by default.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+func (sc SignerConfig) Validate() error {
+	u, err := url.Parse(sc.URL)
+	if err != nil {
+		return fmt.Errorf("invalid signer url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "grpc", "grpcs":
+	default:
+		return fmt.Errorf("invalid signer url %q: scheme must be one of http, https, grpc or grpcs", sc.URL)
+	}
+	if sc.PubKey == "" {
+		return fmt.Errorf("signer pub-key is required")
+	}
+	if sc.Timeout != "" {
+		if _, err := time.ParseDuration(sc.Timeout); err != nil {
+			return fmt.Errorf("invalid signer timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// SignRequest asks a Signer to sign the sign bytes of a transaction.
+type SignRequest struct {
+	KeyName       string `json:"key_name"`
+	Address       string `json:"address"`
+	ChainID       string `json:"chain_id"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+	SignMode      string `json:"sign_mode"`
+	SignBytes     []byte `json:"sign_bytes"`
+}
+
+// SignResponse is the response of a remote signer to a SignRequest.
+type SignResponse struct {
+	Signature []byte `json:"signature"`
+}
+
+// Signer signs the transactions of a CosmosProvider.
+type Signer interface {
+	// Sign returns the signature of req.SignBytes by the key named req.KeyName.
+	Sign(ctx context.Context, req SignRequest) ([]byte, error)
+}
+
+// keyringSigner signs with the keys in a local keyring.
+type keyringSigner struct {
+	kb keyring.Keyring
+}
+
+func (s keyringSigner) Sign(_ context.Context, req SignRequest) ([]byte, error) {
+	sig, _, err := s.kb.Sign(req.KeyName, req.SignBytes)
+	return sig, err
+}
+
+// RemoteSigner sends SignRequests to a remote signing service over HTTP or gRPC,
+// so that the private key of the relayer never has to be on the relayer host.
+//
+// HTTP signers receive a SignRequest as a JSON POST to the configured URL and respond with a SignResponse.
+// gRPC signers serve the unary method /relayer.signer.v1.Signer/Sign with the same messages,
+// using the JSON codec named "json".
+type RemoteSigner struct {
+	url     *url.URL
+	headers map[string]string
+	timeout time.Duration
+
+	httpClient *http.Client
+
+	connOnce sync.Once
+	conn     *grpc.ClientConn
+	connErr  error
+}
+
+// NewRemoteSigner returns a RemoteSigner for a validated SignerConfig.
+func NewRemoteSigner(sc SignerConfig) (*RemoteSigner, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(sc.URL)
+	timeout := defaultSignerTimeout
+	if sc.Timeout != "" {
+		timeout, _ = time.ParseDuration(sc.Timeout)
+	}
+	return &RemoteSigner{
+		url:        u,
+		headers:    sc.Headers,
+		timeout:    timeout,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *RemoteSigner) Sign(ctx context.Context, req SignRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var (
+		res SignResponse
+		err error
+	)
+	switch s.url.Scheme {
+	case "grpc", "grpcs":
+		err = s.signGRPC(ctx, req, &res)
+	default:
+		err = s.signHTTP(ctx, req, &res)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	if len(res.Signature) == 0 {
+		return nil, fmt.Errorf("remote signer returned an empty signature")
+	}
+	return res.Signature, nil
+}
+
+func (s *RemoteSigner) signHTTP(ctx context.Context, req SignRequest, res *SignResponse) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpRes, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close()
+
+	resBody, err := io.ReadAll(io.LimitReader(httpRes.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if httpRes.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s: %s", httpRes.Status, bytes.TrimSpace(resBody))
+	}
+	return json.Unmarshal(resBody, res)
+}
+
+func (s *RemoteSigner) signGRPC(ctx context.Context, req SignRequest, res *SignResponse) error {
+	s.connOnce.Do(func() {
+		creds := insecure.NewCredentials()
+		if s.url.Scheme == "grpcs" {
+			creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		}
+		s.conn, s.connErr = grpc.Dial(
+			s.url.Host,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(grpc.ForceCodec(signerCodec{})),
+		)
+	})
+	if s.connErr != nil {
+		return s.connErr
+	}
+	return s.conn.Invoke(ctx, signerSignMethod, &req, res)
+}
+
+// signerCodec encodes the messages of gRPC signers as JSON.
+type signerCodec struct{}
+
+func (signerCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (signerCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (signerCodec) Name() string {
+	return "json"
+}
+
+// remoteKeyring overlays a read-only record of the key held by a remote signer on the local keyring,
+// so that the key is listed and resolves to its address and public key like a local key.
+type remoteKeyring struct {
+	keyring.Keyring
+	record *keyring.Record
+}
+
+func (kr remoteKeyring) isRemote(uid string) bool {
+	return uid == kr.record.Name
+}
+
+func (kr remoteKeyring) isRemoteAddress(address sdk.Address) bool {
+	addr, err := kr.record.GetAddress()
+	return err == nil && addr.Equals(address)
+}
+
+func (kr remoteKeyring) List() ([]*keyring.Record, error) {
+	records, err := kr.Keyring.List()
+	if err != nil {
+		return nil, err
+	}
+	out := []*keyring.Record{kr.record}
+	for _, r := range records {
+		if !kr.isRemote(r.Name) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (kr remoteKeyring) Key(uid string) (*keyring.Record, error) {
+	if kr.isRemote(uid) {
+		return kr.record, nil
+	}
+	return kr.Keyring.Key(uid)
+}
+
+func (kr remoteKeyring) KeyByAddress(address sdk.Address) (*keyring.Record, error) {
+	if kr.isRemoteAddress(address) {
+		return kr.record, nil
+	}
+	return kr.Keyring.KeyByAddress(address)
+}
+
+func (kr remoteKeyring) Delete(uid string) error {
+	if kr.isRemote(uid) {
+		return ErrReadOnlyKey
+	}
+	return kr.Keyring.Delete(uid)
+}
+
+func (kr remoteKeyring) DeleteByAddress(address sdk.Address) error {
+	if kr.isRemoteAddress(address) {
+		return ErrReadOnlyKey
+	}
+	return kr.Keyring.DeleteByAddress(address)
+}
+
+func (kr remoteKeyring) Rename(from, to string) error {
+	if kr.isRemote(from) || kr.isRemote(to) {
+		return ErrReadOnlyKey
+	}
+	return kr.Keyring.Rename(from, to)
+}
+
+func (kr remoteKeyring) NewMnemonic(uid string, language keyring.Language, hdPath, bip39Passphrase string, algo keyring.SignatureAlgo) (*keyring.Record, string, error) {
+	if kr.isRemote(uid) {
+		return nil, "", ErrReadOnlyKey
+	}
+	return kr.Keyring.NewMnemonic(uid, language, hdPath, bip39Passphrase, algo)
+}
+
+func (kr remoteKeyring) NewAccount(uid, mnemonic, bip39Passphrase, hdPath string, algo keyring.SignatureAlgo) (*keyring.Record, error) {
+	if kr.isRemote(uid) {
+		return nil, ErrReadOnlyKey
+	}
+	return kr.Keyring.NewAccount(uid, mnemonic, bip39Passphrase, hdPath, algo)
+}
+
+func (kr remoteKeyring) ImportPrivKey(uid, armor, passphrase string) error {
+	if kr.isRemote(uid) {
+		return ErrReadOnlyKey
+	}
+	return kr.Keyring.ImportPrivKey(uid, armor, passphrase)
+}
+
+func (kr remoteKeyring) Sign(uid string, msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	if kr.isRemote(uid) {
+		return nil, nil, ErrReadOnlyKey
+	}
+	return kr.Keyring.Sign(uid, msg)
+}
+
+func (kr remoteKeyring) SignByAddress(address sdk.Address, msg []byte) ([]byte, cryptotypes.PubKey, error) {
+	if kr.isRemoteAddress(address) {
+		return nil, nil, ErrReadOnlyKey
+	}
+	return kr.Keyring.SignByAddress(address, msg)
+}
+
+func (kr remoteKeyring) ExportPrivKeyArmor(uid, encryptPassphrase string) (string, error) {
+	if kr.isRemote(uid) {
+		return "", ErrReadOnlyKey
+	}
+	return kr.Keyring.ExportPrivKeyArmor(uid, encryptPassphrase)
+}
+
+func (kr remoteKeyring) ExportPrivKeyArmorByAddress(address sdk.Address, encryptPassphrase string) (string, error) {
+	if kr.isRemoteAddress(address) {
+		return "", ErrReadOnlyKey
+	}
+	return kr.Keyring.ExportPrivKeyArmorByAddress(address, encryptPassphrase)
+}
+
+// useRemoteSigner makes the provider sign with the remote signer configured in PCfg.Signer.
+func (cc *CosmosProvider) useRemoteSigner() error {
+	s, err := NewRemoteSigner(*cc.PCfg.Signer)
+	if err != nil {
+		return err
+	}
+	var pk cryptotypes.PubKey
+	if err := cc.Codec.Marshaler.UnmarshalInterfaceJSON([]byte(cc.PCfg.Signer.PubKey), &pk); err != nil {
+		return fmt.Errorf("invalid signer pub-key: %w", err)
+	}
+	record, err := keyring.NewOfflineRecord(cc.PCfg.Key, pk)
+	if err != nil {
+		return err
+	}
+	cc.Keybase = remoteKeyring{Keyring: cc.Keybase, record: record}
+	cc.signer = s
+	return nil
+}
+
+// IsRemoteKey returns true if the key is held by a remote signer, and therefore read-only.
+func (cc *CosmosProvider) IsRemoteKey(name string) bool {
+	return cc.PCfg.Signer != nil && name == cc.PCfg.Key
+}
+
+// txSigner returns the Signer of the provider, which is the local keyring unless a remote signer is configured.
+func (cc *CosmosProvider) txSigner() Signer {
+	if cc.signer != nil {
+		return cc.signer
+	}
+	return keyringSigner{kb: cc.Keybase}
+}
+
+// signTx signs the transaction in txb with the key of the provider, like tx.Sign does with the local keyring.
+// The process-global SDK context is only held while the sign bytes are built,
+// so that a slow remote signer does not block the other chains.
+func (cc *CosmosProvider) signTx(ctx context.Context, txf tx.Factory, txb client.TxBuilder) error {
+	done := cc.SetSDKContext()
+	req, sig, err := cc.signRequest(txf, txb)
+	done()
+	if err != nil {
+		return err
+	}
+
+	sigBytes, err := cc.txSigner().Sign(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !sig.PubKey.VerifySignature(req.SignBytes, sigBytes) {
+		return fmt.Errorf("signature does not match the public key of %s", cc.PCfg.Key)
+	}
+
+	sig.Data.(*signing.SingleSignatureData).Signature = sigBytes
+	return txb.SetSignatures(sig)
+}
+
+// signRequest sets an empty signature for the key of the provider on txb,
+// and returns the request for the signature and the signature to complete.
+// It must be called with the SDK context of the provider set.
+func (cc *CosmosProvider) signRequest(txf tx.Factory, txb client.TxBuilder) (SignRequest, signing.SignatureV2, error) {
+	signMode := txf.SignMode()
+	if signMode == signing.SignMode_SIGN_MODE_UNSPECIFIED {
+		// use the SignModeHandler's default mode if unspecified
+		signMode = cc.Codec.TxConfig.SignModeHandler().DefaultMode()
+	}
+
+	k, err := cc.Keybase.Key(cc.PCfg.Key)
+	if err != nil {
+		return SignRequest{}, signing.SignatureV2{}, err
+	}
+	pubKey, err := k.GetPubKey()
+	if err != nil {
+		return SignRequest{}, signing.SignatureV2{}, err
+	}
+	pubKey = cc.txPubKey(pubKey)
+
+	signerData := authsigning.SignerData{
+		ChainID:       txf.ChainID(),
+		AccountNumber: txf.AccountNumber(),
+		Sequence:      txf.Sequence(),
+		PubKey:        pubKey,
+		Address:       sdk.AccAddress(pubKey.Address()).String(),
+	}
+
+	// The signer infos are part of the sign bytes of SIGN_MODE_DIRECT,
+	// so set them with an empty signature before generating the sign bytes.
+	sig := signing.SignatureV2{
+		PubKey:   pubKey,
+		Data:     &signing.SingleSignatureData{SignMode: signMode},
+		Sequence: txf.Sequence(),
+	}
+	if err := txb.SetSignatures(sig); err != nil {
+		return SignRequest{}, signing.SignatureV2{}, err
+	}
+
+	bytesToSign, err := cc.Codec.TxConfig.SignModeHandler().GetSignBytes(signMode, signerData, txb.GetTx())
+	if err != nil {
+		return SignRequest{}, signing.SignatureV2{}, err
+	}
+
+	return SignRequest{
+		KeyName:       cc.PCfg.Key,
+		Address:       signerData.Address,
+		ChainID:       signerData.ChainID,
+		AccountNumber: signerData.AccountNumber,
+		Sequence:      signerData.Sequence,
+		SignMode:      signMode.String(),
+		SignBytes:     bytesToSign,
+	}, sig, nil
+}
+
+// txPubKey returns the public key to declare in the signer info of a transaction signed by pubKey.
+// Injective declares its own eth_secp256k1 public key type, with the same addresses and signatures
+// as the Ethermint type that the keyring stores.
+func (cc *CosmosProvider) txPubKey(pubKey cryptotypes.PubKey) cryptotypes.PubKey {
+	ethPubKey, ok := pubKey.(*ethsecp256k1.PubKey)
+	if !ok {
+		return pubKey
+	}
+	for _, c := range cc.PCfg.ExtraCodecs {
+		if c == injectiveCodec {
+			return &injectiveeth.PubKey{Key: ethPubKey.Key}
+		}
+	}
+	return pubKey
+}
@@ -0,0 +1,236 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const signerKey = "remote-key"
+
+// stubSigner is a remote signer holding a single private key, recording the requests it receives.
+type stubSigner struct {
+	priv     *secp256k1.PrivKey
+	requests []SignRequest
+}
+
+func (s *stubSigner) sign(req SignRequest) SignResponse {
+	s.requests = append(s.requests, req)
+	sig, err := s.priv.Sign(req.SignBytes)
+	if err != nil {
+		panic(err)
+	}
+	return SignResponse{Signature: sig}
+}
+
+func (s *stubSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req SignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.sign(req))
+}
+
+// serveGRPC serves the signer over gRPC on a local port, returning its address.
+func (s *stubSigner) serveGRPC(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.ForceServerCodec(signerCodec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: signerService,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Sign",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				var req SignRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				res := s.sign(req)
+				return &res, nil
+			},
+		}},
+	}, s)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func newSignerProvider(t *testing.T, signer *SignerConfig) *CosmosProvider {
+	cfg := CosmosProviderConfig{
+		Key:            signerKey,
+		ChainID:        "signer-1",
+		AccountPrefix:  "cosmos",
+		KeyringBackend: "test",
+		Timeout:        "10s",
+		Signer:         signer,
+	}
+	p, err := cfg.NewProvider(zap.NewNop(), t.TempDir(), true, "signer-chain")
+	require.NoError(t, err)
+	return p.(*CosmosProvider)
+}
+
+func pubKeyJSON(t *testing.T, priv *secp256k1.PrivKey) string {
+	bz, err := lens.MakeCodec(lens.ModuleBasics, nil).Marshaler.MarshalInterfaceJSON(priv.PubKey())
+	require.NoError(t, err)
+	return string(bz)
+}
+
+// testTx builds an unsigned bank send from the key of cc with account number 1 and sequence 2.
+func testTx(t *testing.T, cc *CosmosProvider) (tx.Factory, client.TxBuilder) {
+	addr, err := cc.Address()
+	require.NoError(t, err)
+
+	// The addresses are set directly, since NewMsgSend encodes them with the process-global bech32 prefix.
+	txf := cc.TxFactory().WithAccountNumber(1).WithSequence(2).WithGas(100_000)
+	txb, err := txf.BuildUnsignedTx(&banktypes.MsgSend{FromAddress: addr, ToAddress: addr, Amount: sdk.NewCoins(sdk.NewInt64Coin("stake", 1))})
+	require.NoError(t, err)
+	return txf, txb
+}
+
+// signTestTx signs the transaction of testTx.
+func signTestTx(t *testing.T, cc *CosmosProvider) sdk.Tx {
+	txf, txb := testTx(t, cc)
+	require.NoError(t, cc.signTx(context.Background(), txf, txb))
+	return txb.GetTx()
+}
+
+func TestRemoteSigner(t *testing.T) {
+	stub := &stubSigner{priv: secp256k1.GenPrivKey()}
+	httpSrv := httptest.NewServer(stub)
+	defer httpSrv.Close()
+	grpcAddr := stub.serveGRPC(t)
+
+	for name, url := range map[string]string{
+		"http": httpSrv.URL,
+		"grpc": "grpc://" + grpcAddr,
+	} {
+		t.Run(name, func(t *testing.T) {
+			stub.requests = nil
+			cc := newSignerProvider(t, &SignerConfig{URL: url, PubKey: pubKeyJSON(t, stub.priv)})
+
+			signed := signTestTx(t, cc)
+			pks, err := signed.(authsigning.SigVerifiableTx).GetPubKeys()
+			require.NoError(t, err)
+			require.Len(t, pks, 1)
+			require.True(t, stub.priv.PubKey().Equals(pks[0]))
+
+			require.Len(t, stub.requests, 1)
+			req := stub.requests[0]
+			require.Equal(t, signerKey, req.KeyName)
+			require.Equal(t, "signer-1", req.ChainID)
+			require.Equal(t, uint64(1), req.AccountNumber)
+			require.Equal(t, uint64(2), req.Sequence)
+			require.Equal(t, sdk.AccAddress(stub.priv.PubKey().Address()).String(), req.Address)
+		})
+	}
+}
+
+func TestRemoteKeyIsReadOnly(t *testing.T) {
+	priv := secp256k1.GenPrivKey()
+	cc := newSignerProvider(t, &SignerConfig{URL: "http://127.0.0.1:1", PubKey: pubKeyJSON(t, priv)})
+
+	require.True(t, cc.IsRemoteKey(signerKey))
+	require.True(t, cc.KeyExists(signerKey))
+
+	addr, err := cc.Address()
+	require.NoError(t, err)
+	want, err := cc.EncodeBech32AccAddr(sdk.AccAddress(priv.PubKey().Address()))
+	require.NoError(t, err)
+	require.Equal(t, want, addr)
+
+	addrs, err := cc.ListAddresses()
+	require.NoError(t, err)
+	require.Equal(t, want, addrs[signerKey])
+
+	require.ErrorIs(t, cc.DeleteKey(signerKey), ErrReadOnlyKey)
+	_, err = cc.ExportPrivKeyArmor(signerKey)
+	require.ErrorIs(t, err, ErrReadOnlyKey)
+	_, err = cc.AddKey(signerKey, sdk.CoinType)
+	require.ErrorIs(t, err, ErrReadOnlyKey)
+
+	// Other keys are still held in the local keyring.
+	_, err = cc.AddKey("local-key", sdk.CoinType)
+	require.NoError(t, err)
+	require.False(t, cc.IsRemoteKey("local-key"))
+	require.NoError(t, cc.DeleteKey("local-key"))
+}
+
+func TestLocalKeyringSigner(t *testing.T) {
+	cc := newSignerProvider(t, nil)
+	_, err := cc.AddKey(signerKey, sdk.CoinType)
+	require.NoError(t, err)
+	require.False(t, cc.IsRemoteKey(signerKey))
+
+	signTestTx(t, cc)
+}
+
+// lockCheckingSigner fails to sign, recording whether the SDK context could be set while signing.
+type lockCheckingSigner struct {
+	cc       *CosmosProvider
+	unlocked chan bool
+}
+
+func (s lockCheckingSigner) Sign(ctx context.Context, req SignRequest) ([]byte, error) {
+	acquired := make(chan struct{})
+	go func() {
+		s.cc.SetSDKContext()()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		s.unlocked <- true
+	case <-time.After(time.Second):
+		s.unlocked <- false
+	}
+	return nil, errors.New("signer unavailable")
+}
+
+func TestSignTxReleasesSDKContext(t *testing.T) {
+	cc := newSignerProvider(t, nil)
+	_, err := cc.AddKey(signerKey, sdk.CoinType)
+	require.NoError(t, err)
+
+	s := lockCheckingSigner{cc: cc, unlocked: make(chan bool, 1)}
+	cc.signer = s
+
+	txf, txb := testTx(t, cc)
+	require.ErrorContains(t, cc.signTx(context.Background(), txf, txb), "signer unavailable")
+	require.True(t, <-s.unlocked, "SDK context was held while calling the signer")
+
+	// A failed signature leaves the SDK context free for the next transaction.
+	done := make(chan struct{})
+	go func() {
+		cc.SetSDKContext()()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SDK context was not released after a failed signature")
+	}
+}
+
+func TestSignerConfigValidate(t *testing.T) {
+	require.Error(t, SignerConfig{URL: "ftp://signer", PubKey: "{}"}.Validate())
+	require.Error(t, SignerConfig{URL: "https://signer"}.Validate())
+	require.Error(t, SignerConfig{URL: "https://signer", PubKey: "{}", Timeout: "soon"}.Validate())
+	require.NoError(t, SignerConfig{URL: "grpcs://signer:443", PubKey: "{}", Timeout: "5s"}.Validate())
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/tx"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
@@ -42,7 +41,6 @@ var (
 // Default IBC settings
 var (
 	defaultChainPrefix = commitmenttypes.NewMerklePrefix([]byte("ibc"))
-	defaultDelayPeriod = uint64(0)
 )
 
 // Strings for parsing events
@@ -158,8 +156,10 @@ func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.Rela
 			return err
 		}
 
-		resp, err = cc.BroadcastTx(ctx, txBytes)
+		resp, err = cc.broadcastTx(ctx, txBytes, sequence)
 		if err != nil {
+			cc.recordAudit(resp, fees, memo, msgs, err)
+
 			if strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error()) {
 				cc.handleAccountSequenceMismatchError(err)
 				return err
@@ -199,11 +199,13 @@ func (cc *CosmosProvider) SendMessages(ctx context.Context, msgs []provider.Rela
 	if rlyResp.Code != 0 {
 		cc.LogFailedTx(rlyResp, nil, msgs)
 		cc.UpdateFeesSpent(cc.ChainId(), cc.Key(), fees)
+		cc.recordAudit(resp, fees, memo, msgs, nil)
 		return rlyResp, false, fmt.Errorf("transaction failed with code: %d", resp.Code)
 	}
 
 	cc.LogSuccessTx(resp, msgs)
 	cc.UpdateFeesSpent(cc.ChainId(), cc.Key(), fees)
+	cc.recordAudit(resp, fees, memo, msgs, nil)
 
 	return rlyResp, true, nil
 }
@@ -265,10 +267,8 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 		return nil, 0, sdk.Coins{}, err
 	}
 
-	done := cc.SetSDKContext()
-
 	if err := retry.Do(func() error {
-		if err := tx.Sign(txf, cc.PCfg.Key, txb, false); err != nil {
+		if err := cc.signTx(ctx, txf, txb); err != nil {
 			return err
 		}
 		return nil
@@ -276,8 +276,6 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 		return nil, 0, sdk.Coins{}, err
 	}
 
-	done()
-
 	tx := txb.GetTx()
 	fees := tx.GetFee()
 
@@ -297,6 +295,29 @@ func (cc *CosmosProvider) buildMessages(ctx context.Context, msgs []provider.Rel
 	return txBytes, txf.Sequence(), fees, nil
 }
 
+// BroadcastFunc broadcasts the transaction txBytes, signed with the account sequence.
+type BroadcastFunc func(ctx context.Context, txBytes []byte, sequence uint64) (*sdk.TxResponse, error)
+
+// WrapBroadcast makes the provider broadcast signed transactions with the BroadcastFunc returned by wrap,
+// which is passed the current one, e.g. to inject faults into the broadcasts.
+func (cc *CosmosProvider) WrapBroadcast(wrap func(BroadcastFunc) BroadcastFunc) {
+	next := cc.broadcast
+	if next == nil {
+		next = func(ctx context.Context, txBytes []byte, _ uint64) (*sdk.TxResponse, error) {
+			return cc.BroadcastTx(ctx, txBytes)
+		}
+	}
+	cc.broadcast = wrap(next)
+}
+
+// broadcastTx broadcasts the transaction txBytes, signed with the account sequence.
+func (cc *CosmosProvider) broadcastTx(ctx context.Context, txBytes []byte, sequence uint64) (*sdk.TxResponse, error) {
+	if cc.broadcast != nil {
+		return cc.broadcast(ctx, txBytes, sequence)
+	}
+	return cc.BroadcastTx(ctx, txBytes)
+}
+
 // handleAccountSequenceMismatchError will parse the error string, e.g.:
 // "account sequence mismatch, expected 10, got 9: incorrect account sequence"
 // and update the next account sequence with the expected value.
@@ -596,7 +617,7 @@ func (cc *CosmosProvider) MsgConnectionOpenInit(info provider.ConnectionInfo, pr
 			Prefix:       info.CounterpartyCommitmentPrefix,
 		},
 		Version:     nil,
-		DelayPeriod: defaultDelayPeriod,
+		DelayPeriod: info.DelayPeriod,
 		Signer:      signer,
 	}
 
@@ -608,12 +629,12 @@ func (cc *CosmosProvider) ConnectionHandshakeProof(
 	msgOpenInit provider.ConnectionInfo,
 	height uint64,
 ) (provider.ConnectionProof, error) {
-	clientState, clientStateProof, consensusStateProof, connStateProof, proofHeight, err := cc.GenerateConnHandshakeProof(ctx, int64(height), msgOpenInit.ClientID, msgOpenInit.ConnID)
+	proof, err := cc.connHandshakeProof(ctx, int64(height), msgOpenInit.ClientID, msgOpenInit.ConnID)
 	if err != nil {
 		return provider.ConnectionProof{}, err
 	}
 
-	if len(connStateProof) == 0 {
+	if len(proof.ConnectionStateProof) == 0 {
 		// It is possible that we have asked for a proof too early.
 		// If the connection state proof is empty, there is no point in returning the next message.
 		// We are not using (*conntypes.MsgConnectionOpenTry).ValidateBasic here because
@@ -621,13 +642,7 @@ func (cc *CosmosProvider) ConnectionHandshakeProof(
 		return provider.ConnectionProof{}, fmt.Errorf("received invalid zero-length connection state proof")
 	}
 
-	return provider.ConnectionProof{
-		ClientState:          clientState,
-		ClientStateProof:     clientStateProof,
-		ConsensusStateProof:  consensusStateProof,
-		ConnectionStateProof: connStateProof,
-		ProofHeight:          proofHeight.(clienttypes.Height),
-	}, nil
+	return proof, nil
 }
 
 func (cc *CosmosProvider) MsgConnectionOpenTry(msgOpenInit provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
@@ -652,7 +667,7 @@ func (cc *CosmosProvider) MsgConnectionOpenTry(msgOpenInit provider.ConnectionIn
 		PreviousConnectionId: msgOpenInit.CounterpartyConnID,
 		ClientState:          csAny,
 		Counterparty:         counterparty,
-		DelayPeriod:          defaultDelayPeriod,
+		DelayPeriod:          proof.DelayPeriod,
 		CounterpartyVersions: conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions()),
 		ProofHeight:          proof.ProofHeight,
 		ProofInit:            proof.ConnectionStateProof,
@@ -978,20 +993,27 @@ func (cc *CosmosProvider) AcknowledgementFromSequence(ctx context.Context, dst p
 }
 
 // QueryIBCHeader returns the IBC compatible block header (CosmosIBCHeader) at a specific height.
+// Recently queried headers are served from a bounded cache.
 func (cc *CosmosProvider) QueryIBCHeader(ctx context.Context, h int64) (provider.IBCHeader, error) {
 	if h == 0 {
 		return nil, fmt.Errorf("height cannot be 0")
 	}
 
+	if header, ok := cc.headers.get(h); ok {
+		return header, nil
+	}
+
 	lightBlock, err := cc.LightProvider.LightBlock(ctx, h)
 	if err != nil {
 		return nil, err
 	}
 
-	return CosmosIBCHeader{
+	header := CosmosIBCHeader{
 		SignedHeader: lightBlock.SignedHeader,
 		ValidatorSet: lightBlock.ValidatorSet,
-	}, nil
+	}
+	cc.headers.add(h, header)
+	return header, nil
 }
 
 // InjectTrustedFields injects the necessary trusted fields for a header to update a light
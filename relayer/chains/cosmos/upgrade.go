@@ -0,0 +1,197 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/zap"
+)
+
+const (
+	// upgradePlanQueryInterval is how often the chain is queried for scheduled upgrades.
+	upgradePlanQueryInterval = 60 * time.Second
+
+	// upgradeRetryDelay is how long to wait before retrying to upgrade the counterparty clients after a failure.
+	upgradeRetryDelay = 30 * time.Second
+)
+
+// UpgradeHandler is called once the chain restarted after a scheduled upgrade with an upgraded client state,
+// to upgrade the clients tracking the chain on its counterparties. It is retried until it succeeds.
+type UpgradeHandler func(ctx context.Context, upgrade processor.ChainUpgrade) error
+
+// pendingUpgrade is a scheduled upgrade of the chain with an upgraded client state.
+type pendingUpgrade struct {
+	plan upgradetypes.Plan
+
+	// halted is set once the chain reached the last height before the upgrade.
+	halted bool
+
+	// upgradedClient and upgradedConsState are queried while the chain is halted, before the upgrade clears them.
+	upgradedClient    *clienttypes.QueryClientStateResponse
+	upgradedConsState *clienttypes.QueryConsensusStateResponse
+
+	// retryAt is when to retry the upgrade handler after it failed.
+	retryAt time.Time
+}
+
+// SetUpgradeHandler enables watching the chain for scheduled upgrades with an upgraded client state,
+// calling h once the chain restarted after the upgrade.
+func (ccp *CosmosChainProcessor) SetUpgradeHandler(h UpgradeHandler) {
+	ccp.upgradeHandler = h
+}
+
+// watchUpgrade follows scheduled upgrades of the chain, from the plan to the halt at the upgrade height
+// and the restart of the chain, at which point the upgrade handler is called.
+// An error is returned to stop the ChainProcessor once the chain restarted under a new chain ID,
+// which the PathProcessors are not relaying for.
+func (ccp *CosmosChainProcessor) watchUpgrade(ctx context.Context, persistence *queryCyclePersistence) error {
+	if ccp.upgradeHandler == nil {
+		return nil
+	}
+
+	latestHeight := persistence.latestHeight
+	u := ccp.pendingUpgrade
+	if u == nil || latestHeight < u.plan.Height-1 {
+		if time.Since(persistence.lastUpgradePlanQuery) < upgradePlanQueryInterval {
+			return nil
+		}
+		persistence.lastUpgradePlanQuery = time.Now()
+
+		queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+		defer cancel()
+		plan, err := ccp.chainProvider.queryUpgradePlan(queryCtx)
+		if err != nil {
+			ccp.log.Warn("Failed to query upgrade plan", zap.Error(err))
+			return nil
+		}
+		switch {
+		case plan == nil && u != nil:
+			ccp.log.Info("Upgrade plan was cancelled", zap.String("plan", u.plan.Name))
+			ccp.pendingUpgrade = nil
+		case plan != nil && (u == nil || u.plan.Name != plan.Name || u.plan.Height != plan.Height):
+			ccp.log.Info("Chain upgrade with upgraded client state is scheduled, counterparty clients will be upgraded",
+				zap.String("plan", plan.Name),
+				zap.Int64("plan_height", plan.Height),
+			)
+			ccp.pendingUpgrade = &pendingUpgrade{plan: *plan}
+		}
+		return nil
+	}
+
+	if latestHeight < u.plan.Height {
+		if !u.halted {
+			u.halted = true
+			ccp.log.Info("Chain reached the last height before the upgrade, waiting for it to restart",
+				zap.String("plan", u.plan.Name),
+				zap.Int64("plan_height", u.plan.Height),
+			)
+		}
+		if u.upgradedClient == nil {
+			if err := ccp.queryUpgradedStates(ctx, u); err != nil {
+				ccp.log.Warn("Failed to query upgraded client state while the chain is halted, will retry",
+					zap.String("plan", u.plan.Name),
+					zap.Error(err),
+				)
+			}
+		}
+		return nil
+	}
+
+	if time.Now().Before(u.retryAt) {
+		return nil
+	}
+
+	// The relayer was not running while the chain was halted, so the states are queried
+	// at the last height before the upgrade, as long as the node did not prune it.
+	if u.upgradedClient == nil {
+		if err := ccp.queryUpgradedStates(ctx, u); err != nil {
+			ccp.log.Error("Failed to query upgraded client state at the last height before the upgrade, will retry",
+				zap.String("plan", u.plan.Name),
+				zap.Duration("retry_delay", upgradeRetryDelay),
+				zap.Error(err),
+			)
+			u.retryAt = time.Now().Add(upgradeRetryDelay)
+			return nil
+		}
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	status, err := ccp.chainProvider.RPCClient.Status(queryCtx)
+	if err != nil {
+		ccp.log.Warn("Failed to query chain ID after upgrade", zap.Error(err))
+		return nil
+	}
+	upgrade := processor.ChainUpgrade{
+		PlanName:   u.plan.Name,
+		PlanHeight: u.plan.Height,
+		ChainID:    ccp.chainProvider.ChainId(),
+		NewChainID: status.NodeInfo.Network,
+
+		UpgradedClient:    u.upgradedClient,
+		UpgradedConsState: u.upgradedConsState,
+	}
+	ccp.log.Info("Chain restarted after upgrade, upgrading counterparty clients",
+		zap.String("plan", upgrade.PlanName),
+		zap.Int64("plan_height", upgrade.PlanHeight),
+		zap.String("new_chain_id", upgrade.NewChainID),
+	)
+	if err := ccp.upgradeHandler(ctx, upgrade); err != nil {
+		ccp.log.Error("Failed to upgrade counterparty clients, will retry",
+			zap.String("plan", upgrade.PlanName),
+			zap.Duration("retry_delay", upgradeRetryDelay),
+			zap.Error(err),
+		)
+		u.retryAt = time.Now().Add(upgradeRetryDelay)
+		return nil
+	}
+	ccp.pendingUpgrade = nil
+
+	if upgrade.RevisionChanged() {
+		return fmt.Errorf("chain %s restarted as %s after upgrade %s, restart the relayer to relay on it",
+			upgrade.ChainID, upgrade.NewChainID, upgrade.PlanName)
+	}
+	return nil
+}
+
+// queryUpgradedStates queries the upgraded client and consensus states of the upgrade, with their proofs,
+// at the last height before the upgrade.
+func (ccp *CosmosChainProcessor) queryUpgradedStates(ctx context.Context, u *pendingUpgrade) error {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	clientRes, err := ccp.chainProvider.QueryUpgradedClient(queryCtx, u.plan.Height)
+	if err != nil {
+		return err
+	}
+	consRes, err := ccp.chainProvider.QueryUpgradedConsState(queryCtx, u.plan.Height)
+	if err != nil {
+		return err
+	}
+	u.upgradedClient, u.upgradedConsState = clientRes, consRes
+	return nil
+}
+
+// queryUpgradePlan returns the scheduled upgrade plan of the chain if it has an upgraded client state, or nil.
+func (cc *CosmosProvider) queryUpgradePlan(ctx context.Context) (*upgradetypes.Plan, error) {
+	res, err := upgradetypes.NewQueryClient(cc).CurrentPlan(ctx, &upgradetypes.QueryCurrentPlanRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if res.Plan == nil {
+		return nil, nil
+	}
+
+	// Counterparty clients only need to be upgraded if the plan sets an upgraded client state.
+	if _, err := clienttypes.NewQueryClient(cc).UpgradedClientState(ctx, &clienttypes.QueryUpgradedClientStateRequest{}); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return res.Plan, nil
+}
@@ -0,0 +1,237 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	upgradetypes "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	lens "github.com/strangelove-ventures/lens/client"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	dbm "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+// restartedRPCClient reports the chain ID a chain restarted under after an upgrade.
+type restartedRPCClient struct {
+	rpcclient.Client
+	network string
+}
+
+func (c restartedRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: c.network}}, nil
+}
+
+// storeRPCClient serves the store queries of a chain from a multistore.
+type storeRPCClient struct {
+	rpcclient.Client
+	store   *rootmulti.Store
+	network string
+
+	// prunedBelow fails the queries of the heights below it.
+	prunedBelow int64
+}
+
+func newStoreRPCClient(network string) *storeRPCClient {
+	store := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	store.MountStoreWithDB(storetypes.NewKVStoreKey(upgradetypes.StoreKey), storetypes.StoreTypeIAVL, nil)
+	if err := store.LoadLatestVersion(); err != nil {
+		panic(err)
+	}
+	return &storeRPCClient{store: store, network: network}
+}
+
+func (c *storeRPCClient) upgradeStore() storetypes.KVStore {
+	return c.store.GetCommitKVStore(c.store.StoreKeysByName()[upgradetypes.StoreKey])
+}
+
+func (c *storeRPCClient) ABCIQueryWithOptions(
+	ctx context.Context,
+	path string,
+	data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	if opts.Height < c.prunedBelow {
+		return nil, fmt.Errorf("height %d is pruned", opts.Height)
+	}
+	res := c.store.Query(abci.RequestQuery{
+		Path:   strings.TrimPrefix(path, "store"),
+		Data:   data,
+		Height: opts.Height,
+		Prove:  opts.Prove,
+	})
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
+
+func (c *storeRPCClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{NodeInfo: p2p.DefaultNodeInfo{Network: c.network}}, nil
+}
+
+func TestWatchUpgradeWaitsForRestart(t *testing.T) {
+	ctx := context.Background()
+	cc := &CosmosProvider{PCfg: CosmosProviderConfig{ChainID: "osmosis-1"}}
+	cc.RPCClient = newStoreRPCClient("osmosis-1")
+	ccp := NewCosmosChainProcessor(zap.NewNop(), cc, nil)
+
+	called := false
+	ccp.SetUpgradeHandler(func(context.Context, processor.ChainUpgrade) error {
+		called = true
+		return nil
+	})
+	ccp.pendingUpgrade = &pendingUpgrade{plan: upgradetypes.Plan{Name: "v2", Height: 100}}
+
+	// The plan was queried recently, so nothing happens before the halt.
+	persistence := &queryCyclePersistence{latestHeight: 90, lastUpgradePlanQuery: time.Now()}
+	require.NoError(t, ccp.watchUpgrade(ctx, persistence))
+	require.False(t, ccp.pendingUpgrade.halted)
+
+	// The chain halts at the last height before the upgrade.
+	// The upgraded states are not found, so they are queried again on the next cycle.
+	persistence.latestHeight = 99
+	require.NoError(t, ccp.watchUpgrade(ctx, persistence))
+	require.True(t, ccp.pendingUpgrade.halted)
+	require.Nil(t, ccp.pendingUpgrade.upgradedClient)
+
+	// After a failure, the handler is not retried before the retry delay.
+	persistence.latestHeight = 100
+	ccp.pendingUpgrade.retryAt = time.Now().Add(time.Minute)
+	require.NoError(t, ccp.watchUpgrade(ctx, persistence))
+	require.False(t, called)
+	require.NotNil(t, ccp.pendingUpgrade)
+}
+
+func TestWatchUpgradeRestartUnderNewChainID(t *testing.T) {
+	ctx := context.Background()
+	cc := &CosmosProvider{PCfg: CosmosProviderConfig{ChainID: "osmosis-1"}}
+	cc.RPCClient = restartedRPCClient{network: "osmosis-2"}
+	ccp := NewCosmosChainProcessor(zap.NewNop(), cc, nil)
+
+	var upgrades []processor.ChainUpgrade
+	ccp.SetUpgradeHandler(func(_ context.Context, upgrade processor.ChainUpgrade) error {
+		upgrades = append(upgrades, upgrade)
+		return nil
+	})
+	upgradedClient := &clienttypes.QueryClientStateResponse{ProofHeight: clienttypes.NewHeight(2, 100)}
+	upgradedConsState := &clienttypes.QueryConsensusStateResponse{ProofHeight: clienttypes.NewHeight(2, 100)}
+	ccp.pendingUpgrade = &pendingUpgrade{
+		plan:              upgradetypes.Plan{Name: "v2", Height: 100},
+		halted:            true,
+		upgradedClient:    upgradedClient,
+		upgradedConsState: upgradedConsState,
+	}
+
+	// The chain produced the upgrade height, so the handler upgrades the counterparty clients
+	// and the processor stops, since it is not relaying for the new chain ID.
+	persistence := &queryCyclePersistence{latestHeight: 100, lastUpgradePlanQuery: time.Now()}
+	err := ccp.watchUpgrade(ctx, persistence)
+	require.ErrorContains(t, err, "restarted as osmosis-2")
+	require.Nil(t, ccp.pendingUpgrade)
+
+	require.Equal(t, []processor.ChainUpgrade{{
+		PlanName:   "v2",
+		PlanHeight: 100,
+		ChainID:    "osmosis-1",
+		NewChainID: "osmosis-2",
+
+		UpgradedClient:    upgradedClient,
+		UpgradedConsState: upgradedConsState,
+	}}, upgrades)
+}
+
+func TestWatchUpgradeQueriesUpgradedStatesWhileHalted(t *testing.T) {
+	ctx := context.Background()
+	client := newStoreRPCClient("osmosis-1")
+	cc := &CosmosProvider{PCfg: CosmosProviderConfig{ChainID: "osmosis-1"}}
+	cc.Codec = lens.MakeCodec(lens.ModuleBasics, nil)
+	cc.RPCClient = client
+	ccp := NewCosmosChainProcessor(zap.NewNop(), cc, nil)
+
+	var upgrades []processor.ChainUpgrade
+	ccp.SetUpgradeHandler(func(_ context.Context, upgrade processor.ChainUpgrade) error {
+		upgrades = append(upgrades, upgrade)
+		return nil
+	})
+	ccp.pendingUpgrade = &pendingUpgrade{plan: upgradetypes.Plan{Name: "v2", Height: 100}}
+
+	clientState := &tmclient.ClientState{ChainId: "osmosis-2", LatestHeight: clienttypes.NewHeight(2, 1)}
+	clientBz, err := clienttypes.MarshalClientState(cc.Codec.Marshaler, clientState)
+	require.NoError(t, err)
+	consState := &tmclient.ConsensusState{
+		Timestamp:          time.Unix(1_700_000_000, 0).UTC(),
+		Root:               commitmenttypes.NewMerkleRoot([]byte("app_hash")),
+		NextValidatorsHash: []byte("next_validators_hash_32_bytes___"),
+	}
+	consBz, err := clienttypes.MarshalConsensusState(cc.Codec.Marshaler, consState)
+	require.NoError(t, err)
+
+	// The upgraded client state is set with the plan, and the upgraded consensus state
+	// at the last height before the upgrade, at which the chain halts.
+	var haltCommit storetypes.CommitID
+	for h := int64(1); h < 100; h++ {
+		switch h {
+		case 50:
+			client.upgradeStore().Set(upgradetypes.UpgradedClientKey(100), clientBz)
+		case 99:
+			client.upgradeStore().Set(upgradetypes.UpgradedConsStateKey(100), consBz)
+		}
+		haltCommit = client.store.Commit()
+	}
+	require.Equal(t, int64(99), haltCommit.Version)
+
+	persistence := &queryCyclePersistence{latestHeight: 99, lastUpgradePlanQuery: time.Now()}
+	require.NoError(t, ccp.watchUpgrade(ctx, persistence))
+	require.True(t, ccp.pendingUpgrade.halted)
+	require.NotNil(t, ccp.pendingUpgrade.upgradedClient)
+
+	// The chain restarts under the new chain ID and applies the upgrade, which clears the upgraded states,
+	// and the node prunes the heights before the upgrade.
+	client.upgradeStore().Delete(upgradetypes.UpgradedClientKey(100))
+	client.upgradeStore().Delete(upgradetypes.UpgradedConsStateKey(100))
+	client.store.Commit()
+	client.network = "osmosis-2"
+	client.prunedBelow = 100
+
+	persistence.latestHeight = 100
+	require.ErrorContains(t, ccp.watchUpgrade(ctx, persistence), "restarted as osmosis-2")
+	require.Len(t, upgrades, 1)
+
+	upgrade := upgrades[0]
+	root := commitmenttypes.NewMerkleRoot(haltCommit.Hash)
+
+	require.Equal(t, clienttypes.NewHeight(2, 100), upgrade.UpgradedClient.ProofHeight)
+	upgradedClientState, err := clienttypes.UnpackClientState(upgrade.UpgradedClient.ClientState)
+	require.NoError(t, err)
+	require.Equal(t, clientState, upgradedClientState)
+	var clientProof commitmenttypes.MerkleProof
+	require.NoError(t, cc.Codec.Marshaler.Unmarshal(upgrade.UpgradedClient.Proof, &clientProof))
+	require.NoError(t, clientProof.VerifyMembership(commitmenttypes.GetSDKSpecs(), root,
+		commitmenttypes.NewMerklePath(upgradetypes.StoreKey, string(upgradetypes.UpgradedClientKey(100))), clientBz))
+
+	require.Equal(t, clienttypes.NewHeight(2, 100), upgrade.UpgradedConsState.ProofHeight)
+	upgradedConsState, err := clienttypes.UnpackConsensusState(upgrade.UpgradedConsState.ConsensusState)
+	require.NoError(t, err)
+	require.Equal(t, consState, upgradedConsState)
+	var consProof commitmenttypes.MerkleProof
+	require.NoError(t, cc.Codec.Marshaler.Unmarshal(upgrade.UpgradedConsState.Proof, &consProof))
+	require.NoError(t, consProof.VerifyMembership(commitmenttypes.GetSDKSpecs(), root,
+		commitmenttypes.NewMerklePath(upgradetypes.StoreKey, string(upgradetypes.UpgradedConsStateKey(100))), consBz))
+}
+
+func TestChainUpgradeRevisionChanged(t *testing.T) {
+	require.False(t, processor.ChainUpgrade{ChainID: "osmosis-1", NewChainID: "osmosis-1"}.RevisionChanged())
+	require.True(t, processor.ChainUpgrade{ChainID: "osmosis-1", NewChainID: "osmosis-2"}.RevisionChanged())
+}
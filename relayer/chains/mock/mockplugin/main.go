@@ -0,0 +1,17 @@
+// Command mockplugin serves the reference chain plugin, mock.Plugin, to the relayer.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmos/relayer/v2/relayer/chains/mock"
+	"github.com/cosmos/relayer/v2/relayer/chains/plugin"
+)
+
+func main() {
+	if err := plugin.Serve(mock.Plugin{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
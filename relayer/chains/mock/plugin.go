@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/plugin"
+	"github.com/cosmos/relayer/v2/relayer/chains/sim"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// PluginChannelKey is the channel that the ChainProcessor of Plugin observes transfers on.
+var PluginChannelKey = processor.ChannelKey{
+	ChannelID:             "channel-0",
+	PortID:                "transfer",
+	CounterpartyChannelID: "channel-1",
+	CounterpartyPortID:    "transfer",
+}
+
+var (
+	_ plugin.Plugin           = Plugin{}
+	_ provider.ProviderConfig = &PluginProviderConfig{}
+)
+
+// Plugin is the reference chain plugin. Its provider is a simulated chain configured by PluginProviderConfig,
+// and its ChainProcessor is a MockChainProcessor observing a MsgTransfer on PluginChannelKey in every block.
+type Plugin struct{}
+
+func (Plugin) ProviderConfig() provider.ProviderConfig {
+	return &PluginProviderConfig{}
+}
+
+// PluginProviderConfig is the provider config of Plugin. The provider is a sim.Provider
+// for an in-memory simulated chain, which produces blocks for as long as the plugin runs.
+type PluginProviderConfig struct {
+	ChainID   string `json:"chain-id"`
+	BlockTime string `json:"block-time,omitempty"`
+}
+
+func (pc *PluginProviderConfig) NewProvider(log *zap.Logger, homepath string, debug bool, chainName string) (provider.ChainProvider, error) {
+	if err := pc.Validate(); err != nil {
+		return nil, err
+	}
+	var blockTime time.Duration
+	if pc.BlockTime != "" {
+		blockTime, _ = time.ParseDuration(pc.BlockTime)
+	}
+	chain := sim.NewChain(pc.ChainID, blockTime)
+	go func() { _ = chain.Run(context.Background()) }()
+	return sim.NewProvider(chain), nil
+}
+
+func (pc *PluginProviderConfig) Validate() error {
+	if pc.ChainID == "" {
+		return errors.New("chain-id is required")
+	}
+	if pc.BlockTime != "" {
+		if _, err := time.ParseDuration(pc.BlockTime); err != nil {
+			return fmt.Errorf("invalid block-time %s: %w", pc.BlockTime, err)
+		}
+	}
+	return nil
+}
+
+func (Plugin) NewChainProcessor(log *zap.Logger, p provider.ChainProvider) processor.ChainProcessor {
+	var (
+		mu       sync.Mutex
+		sequence uint64
+	)
+	return NewMockChainProcessor(log, p.ChainId(), func() []TransactionMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		sequence++
+		return []TransactionMessage{{
+			EventType: chantypes.EventTypeSendPacket,
+			PacketInfo: &chantypes.Packet{
+				Sequence:           sequence,
+				SourceChannel:      PluginChannelKey.ChannelID,
+				SourcePort:         PluginChannelKey.PortID,
+				DestinationChannel: PluginChannelKey.CounterpartyChannelID,
+				DestinationPort:    PluginChannelKey.CounterpartyPortID,
+				Data:               []byte("mock"),
+			},
+		}}
+	})
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+var (
+	_ processor.ChainProcessor = &ChainProcessor{}
+
+	cacheDataType = reflect.TypeOf(processor.ChainProcessorCacheData{})
+)
+
+// ChainProcessor runs the ChainProcessor of a plugin and hands the data it produces to the PathProcessors
+// in the relayer process.
+type ChainProcessor struct {
+	log      *zap.Logger
+	provider *Provider
+
+	pathProcessors processor.PathProcessors
+
+	health *processor.Health
+}
+
+func NewChainProcessor(log *zap.Logger, p *Provider) *ChainProcessor {
+	return &ChainProcessor{
+		log:      log.With(zap.String("chain_name", p.ChainName()), zap.String("chain_id", p.ChainId())),
+		provider: p,
+	}
+}
+
+// SetHealth sets the Health that the progress of the plugin is reported to.
+func (cp *ChainProcessor) SetHealth(h *processor.Health) {
+	cp.health = h
+	h.RegisterChainProcessor(cp.provider.ChainId())
+}
+
+// SetPathProcessors sets the PathProcessors that the ChainProcessor hands data to.
+func (cp *ChainProcessor) SetPathProcessors(pathProcessors processor.PathProcessors) {
+	cp.pathProcessors = pathProcessors
+}
+
+// Provider returns the plugin Provider, which the linked PathProcessors query and send messages through.
+func (cp *ChainProcessor) Provider() provider.ChainProvider {
+	return cp.provider
+}
+
+// Run starts the ChainProcessor of the plugin and relays its data until ctx is done or the plugin fails.
+func (cp *ChainProcessor) Run(ctx context.Context, initialBlockHistory uint64) error {
+	conn, err := cp.provider.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := runRequest{InitialBlockHistory: initialBlockHistory}
+	for _, pp := range cp.pathProcessors {
+		pathEnd1, pathEnd2 := pp.PathEnds()
+		req.Paths = append(req.Paths, runPath{PathEnd1: pathEnd1, PathEnd2: pathEnd2})
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, runMethod)
+	if err != nil {
+		return fmt.Errorf("error running plugin chain processor: %w", err)
+	}
+	if err := stream.SendMsg(&req); err != nil {
+		return fmt.Errorf("error running plugin chain processor: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("error running plugin chain processor: %w", err)
+	}
+
+	cp.log.Info("Running plugin chain processor")
+
+	for {
+		var u runUpdate
+		if err := stream.RecvMsg(&u); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("plugin chain processor: %w", err)
+		}
+		if u.Path < 0 || u.Path >= len(cp.pathProcessors) {
+			return fmt.Errorf("plugin chain processor sent data for unknown path %d", u.Path)
+		}
+		pp := cp.pathProcessors[u.Path]
+
+		if u.BacklogReady {
+			pp.ProcessBacklogIfReady()
+			continue
+		}
+
+		v, err := cp.provider.codec.decode(u.Data, cacheDataType)
+		if err != nil {
+			return fmt.Errorf("error decoding plugin chain processor data: %w", err)
+		}
+		data := v.Interface().(processor.ChainProcessorCacheData)
+		if h := int64(data.LatestBlock.Height); h > 0 {
+			cp.health.ChainProcessorAdvanced(cp.provider.ChainId(), h, h)
+		}
+		pp.HandleNewData(cp.provider.ChainId(), data)
+	}
+}
@@ -0,0 +1,142 @@
+package plugin_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/mock"
+	"github.com/cosmos/relayer/v2/relayer/chains/plugin"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	pluginChainID = "plugin-1"
+
+	// servePluginEnv makes the test binary serve mock.Plugin instead of running the tests.
+	servePluginEnv = "RLY_TEST_SERVE_PLUGIN"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(servePluginEnv) == "1" {
+		if err := plugin.Serve(mock.Plugin{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newPluginProvider(t *testing.T) *plugin.Provider {
+	cfg := plugin.ProviderConfig{
+		Command: []string{os.Args[0]},
+		Env:     []string{servePluginEnv + "=1"},
+		ChainID: pluginChainID,
+		Config: map[string]any{
+			"chain-id":   pluginChainID,
+			"block-time": "50ms",
+		},
+	}
+	p, err := cfg.NewProvider(zaptest.NewLogger(t), t.TempDir(), true, "plugin-chain")
+	require.NoError(t, err)
+	pp := p.(*plugin.Provider)
+	t.Cleanup(func() { _ = pp.Close() })
+	return pp
+}
+
+func TestPluginProvider(t *testing.T) {
+	p := newPluginProvider(t)
+
+	require.Equal(t, pluginChainID, p.ChainId())
+	require.Equal(t, "plugin", p.Type())
+	require.Equal(t, "default", p.Key())
+
+	out, err := p.AddKey("plugin-key", sdk.CoinType)
+	require.NoError(t, err)
+	keyAddr, err := p.ShowAddress("plugin-key")
+	require.NoError(t, err)
+	require.Equal(t, out.Address, keyAddr)
+	require.True(t, p.KeyExists("plugin-key"))
+	addr, err := p.Address()
+	require.NoError(t, err)
+
+	msg, err := p.MsgTransfer(addr, sdk.NewInt64Coin("stake", 1), provider.PacketInfo{
+		SourcePort:    "transfer",
+		SourceChannel: "channel-0",
+		TimeoutHeight: clienttypes.NewHeight(1, 100),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "*sim.MsgSendPacket", msg.Type())
+	bz, err := msg.MsgBytes()
+	require.NoError(t, err)
+	require.NotEmpty(t, bz)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	h, err := p.QueryLatestHeight(ctx)
+	require.NoError(t, err)
+	require.Positive(t, h)
+	header, err := p.QueryIBCHeader(ctx, h)
+	require.NoError(t, err)
+	require.Equal(t, uint64(h), header.Height())
+
+	// Errors of the plugin provider are returned to the caller.
+	_, err = p.QueryBalance(ctx, "default")
+	require.ErrorContains(t, err, "not supported")
+}
+
+func TestPluginProviderConfig(t *testing.T) {
+	_, err := plugin.ProviderConfig{ChainID: pluginChainID}.NewProvider(zaptest.NewLogger(t), t.TempDir(), false, "")
+	require.Error(t, err)
+
+	p, err := plugin.ProviderConfig{Command: []string{"does-not-exist"}, ChainID: pluginChainID}.
+		NewProvider(zaptest.NewLogger(t), t.TempDir(), false, "")
+	require.NoError(t, err)
+	_, err = p.Address()
+	require.ErrorContains(t, err, "does-not-exist")
+}
+
+func TestPluginChainProcessor(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	p := newPluginProvider(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	pathProcessor := processor.NewPathProcessor(
+		log,
+		processor.PathEnd{PathName: "plugin-path", ChainID: pluginChainID, ClientID: "07-tendermint-0"},
+		processor.PathEnd{PathName: "plugin-path", ChainID: "plugin-2", ClientID: "07-tendermint-1"},
+		nil, "", time.Hour,
+	)
+
+	err := processor.NewEventProcessor().
+		WithChainProcessors(plugin.NewChainProcessor(log, p)).
+		WithPathProcessors(pathProcessor).
+		WithMessageLifecycle(&processor.PacketMessageLifecycle{
+			Termination: &processor.PacketMessage{
+				ChainID:   pluginChainID,
+				EventType: chantypes.EventTypeSendPacket,
+				Info: provider.PacketInfo{
+					Sequence:      2,
+					SourceChannel: mock.PluginChannelKey.ChannelID,
+					SourcePort:    mock.PluginChannelKey.PortID,
+					DestChannel:   mock.PluginChannelKey.CounterpartyChannelID,
+					DestPort:      mock.PluginChannelKey.CounterpartyPortID,
+				},
+			},
+		}).
+		Build().
+		Run(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Err(), "send_packet from the plugin chain processor was not observed")
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// handshakeTimeout is how long a plugin has to print its handshake line after being started.
+const handshakeTimeout = 30 * time.Second
+
+// process is a running plugin executable and the gRPC connection to it.
+type process struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  *grpc.ClientConn
+}
+
+// startProcess starts the plugin executable and connects to the address from its handshake line.
+func startProcess(log *zap.Logger, command, env []string) (*process, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("plugin command is empty")
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = append(append(os.Environ(), env...), protocolEnv+"="+protocolVersion)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting plugin %s: %w", command[0], err)
+	}
+
+	type handshake struct {
+		line string
+		err  error
+	}
+	handshakeCh := make(chan handshake, 1)
+	go func() {
+		r := bufio.NewReader(stdout)
+		line, err := r.ReadString('\n')
+		handshakeCh <- handshake{line: line, err: err}
+		// Anything else the plugin prints goes to stderr, to keep the relayer's stdout clean.
+		_, _ = io.Copy(os.Stderr, r)
+	}()
+
+	var addr string
+	select {
+	case h := <-handshakeCh:
+		if h.err != nil {
+			err = fmt.Errorf("error reading plugin handshake: %w", h.err)
+			break
+		}
+		fields := strings.Fields(h.line)
+		switch {
+		case len(fields) != 4 || fields[0] != handshakePrefix || fields[2] != "tcp":
+			err = fmt.Errorf("invalid plugin handshake: %q", h.line)
+		case fields[1] != protocolVersion:
+			err = fmt.Errorf("unsupported plugin protocol version %s, expected %s", fields[1], protocolVersion)
+		default:
+			addr = fields[3]
+		}
+	case <-time.After(handshakeTimeout):
+		err = fmt.Errorf("plugin did not complete the handshake within %s", handshakeTimeout)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("plugin %s: %w", command[0], err)
+	}
+
+	conn, err := grpc.Dial(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("error connecting to plugin %s: %w", command[0], err)
+	}
+
+	go func() {
+		err := cmd.Wait()
+		log.Info("Plugin exited", zap.String("command", command[0]), zap.Error(err))
+	}()
+
+	return &process{cmd: cmd, stdin: stdin, conn: conn}, nil
+}
+
+// close disconnects from the plugin and closes its stdin, which makes it exit.
+func (p *process) close() error {
+	err := p.conn.Close()
+	if cerr := p.stdin.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// The relayer starts a plugin executable with protocolEnv set to protocolVersion.
+// The plugin listens on a local port and prints a handshake line to stdout:
+//
+//	RLY_PLUGIN 1 tcp 127.0.0.1:<port>
+//
+// The relayer then connects to it over gRPC. The plugin exits when its stdin is closed.
+const (
+	protocolEnv     = "RLY_PLUGIN_PROTOCOL"
+	protocolVersion = "1"
+	handshakePrefix = "RLY_PLUGIN"
+
+	pluginService   = "relayer.plugin.v1.Plugin"
+	providerService = "relayer.plugin.v1.ChainProvider"
+
+	configureMethod = "/" + pluginService + "/Configure"
+	runMethod       = "/" + pluginService + "/RunChainProcessor"
+)
+
+var chainProviderType = reflect.TypeOf((*provider.ChainProvider)(nil)).Elem()
+
+// jsonCodec encodes the gRPC messages of the plugin services as JSON,
+// so that they can be plain Go types instead of generated protobuf code.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// configureRequest creates the provider of the plugin from the config of the chain in the relayer config.
+type configureRequest struct {
+	HomePath  string
+	Debug     bool
+	ChainName string
+	Config    json.RawMessage
+}
+
+type configureResponse struct {
+	ChainID string
+}
+
+// callRequest calls the ChainProvider method of the same name, with the arguments other than the context
+// encoded by valueCodec.
+type callRequest struct {
+	Args []json.RawMessage
+}
+
+// callResponse holds the results of a ChainProvider method other than the error, encoded by valueCodec,
+// and the message of the error, if any.
+type callResponse struct {
+	Results []json.RawMessage
+	Error   string `json:",omitempty"`
+}
+
+// runRequest runs the ChainProcessor of the plugin, linked with a PathProcessor per path.
+type runRequest struct {
+	InitialBlockHistory uint64
+	Paths               []runPath
+}
+
+type runPath struct {
+	PathEnd1 processor.PathEnd
+	PathEnd2 processor.PathEnd
+}
+
+// runUpdate is streamed to the relayer for every call the ChainProcessor makes to the PathProcessor of a path,
+// either HandleNewData with the encoded ChainProcessorCacheData or ProcessBacklogIfReady.
+type runUpdate struct {
+	Path         int
+	Data         json.RawMessage `json:",omitempty"`
+	BacklogReady bool            `json:",omitempty"`
+}
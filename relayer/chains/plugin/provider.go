@@ -0,0 +1,653 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v5/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// defaultCallTimeout bounds the calls to ChainProvider methods that do not take a context.
+const defaultCallTimeout = time.Minute
+
+var (
+	_ provider.ChainProvider  = &Provider{}
+	_ provider.ProviderConfig = &ProviderConfig{}
+)
+
+// ProviderConfig configures a chain whose ChainProvider and ChainProcessor run in a plugin executable.
+type ProviderConfig struct {
+	// Command is the plugin executable and its arguments.
+	Command []string `json:"command" yaml:"command"`
+
+	// Env holds additional KEY=value environment variables for the plugin.
+	Env []string `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// ChainID is the chain ID, so that it is known without starting the plugin.
+	ChainID string `json:"chain-id" yaml:"chain-id"`
+
+	// Config is passed to the plugin as JSON, which decodes it into its own provider config.
+	Config map[string]any `json:"config,omitempty" yaml:"config,omitempty"`
+}
+
+func (pc ProviderConfig) Validate() error {
+	if len(pc.Command) == 0 {
+		return fmt.Errorf("plugin command is required")
+	}
+	if pc.ChainID == "" {
+		return fmt.Errorf("chain-id is required")
+	}
+	return nil
+}
+
+// NewProvider validates the ProviderConfig and returns a Provider. The plugin is started on first use.
+func (pc ProviderConfig) NewProvider(log *zap.Logger, homepath string, debug bool, chainName string) (provider.ChainProvider, error) {
+	if err := pc.Validate(); err != nil {
+		return nil, err
+	}
+	return &Provider{
+		log:       log.With(zap.String("sys", "plugin"), zap.String("chain_id", pc.ChainID)),
+		PCfg:      pc,
+		homepath:  homepath,
+		debug:     debug,
+		chainName: chainName,
+		codec:     newValueCodec(nil),
+	}, nil
+}
+
+// Provider is a ChainProvider whose calls are made to a plugin process over gRPC.
+type Provider struct {
+	log *zap.Logger
+
+	PCfg ProviderConfig
+
+	homepath  string
+	debug     bool
+	chainName string
+	codec     *valueCodec
+
+	mu   sync.Mutex
+	proc *process
+}
+
+func (p *Provider) ChainName() string {
+	return p.chainName
+}
+
+func (p *Provider) ChainId() string {
+	return p.PCfg.ChainID
+}
+
+func (p *Provider) Type() string {
+	return "plugin"
+}
+
+func (p *Provider) ProviderConfig() provider.ProviderConfig {
+	return p.PCfg
+}
+
+// Close stops the plugin, if it was started.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc == nil {
+		return nil
+	}
+	err := p.proc.close()
+	p.proc = nil
+	return err
+}
+
+// connect starts and configures the plugin if it is not running yet, and returns the connection to it.
+func (p *Provider) connect(ctx context.Context) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc != nil {
+		return p.proc.conn, nil
+	}
+
+	proc, err := startProcess(p.log, p.PCfg.Command, p.PCfg.Env)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := json.Marshal(p.PCfg.Config)
+	if err != nil {
+		_ = proc.close()
+		return nil, fmt.Errorf("error encoding plugin config: %w", err)
+	}
+	req := configureRequest{
+		HomePath:  p.homepath,
+		Debug:     p.debug,
+		ChainName: p.chainName,
+		Config:    cfg,
+	}
+	var res configureResponse
+	if err := proc.conn.Invoke(ctx, configureMethod, &req, &res); err != nil {
+		_ = proc.close()
+		return nil, fmt.Errorf("error configuring plugin: %w", err)
+	}
+	if res.ChainID != p.PCfg.ChainID {
+		_ = proc.close()
+		return nil, fmt.Errorf("plugin provider is for chain %s, expected %s", res.ChainID, p.PCfg.ChainID)
+	}
+
+	p.proc = proc
+	return proc.conn, nil
+}
+
+// call calls method on the provider of the plugin. args are the arguments other than the context,
+// and results point to the results other than the error.
+func (p *Provider) call(ctx context.Context, method string, args []any, results ...any) error {
+	m, ok := chainProviderType.MethodByName(method)
+	if !ok {
+		return fmt.Errorf("unknown ChainProvider method %s", method)
+	}
+	conn, err := p.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req callRequest
+	for i := 0; i < m.Type.NumIn(); i++ {
+		t := m.Type.In(i)
+		if t == contextType {
+			continue
+		}
+		v := reflect.New(t).Elem()
+		if len(args) > 0 && args[0] != nil {
+			v.Set(reflect.ValueOf(args[0]))
+		}
+		if len(args) > 0 {
+			args = args[1:]
+		}
+		raw, err := p.codec.encode(v)
+		if err != nil {
+			return fmt.Errorf("%s: argument %d: %w", method, i, err)
+		}
+		req.Args = append(req.Args, raw)
+	}
+
+	var res callResponse
+	if err := conn.Invoke(ctx, "/"+providerService+"/"+method, &req, &res); err != nil {
+		return fmt.Errorf("plugin call %s: %w", method, err)
+	}
+	if len(res.Results) != len(results) {
+		return fmt.Errorf("plugin call %s: expected %d results, got %d", method, len(results), len(res.Results))
+	}
+	for i, r := range results {
+		rv := reflect.ValueOf(r).Elem()
+		v, err := p.codec.decode(res.Results[i], rv.Type())
+		if err != nil {
+			return fmt.Errorf("%s: result %d: %w", method, i, err)
+		}
+		rv.Set(v)
+	}
+	if res.Error != "" {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+// callDefault calls a method that does not take a context, with a timeout of defaultCallTimeout.
+func (p *Provider) callDefault(method string, args []any, results ...any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCallTimeout)
+	defer cancel()
+	return p.call(ctx, method, args, results...)
+}
+
+// callLogged calls a method that cannot return an error, logging the error instead.
+func (p *Provider) callLogged(method string, args []any, results ...any) {
+	if err := p.callDefault(method, args, results...); err != nil {
+		p.log.Error("Plugin call failed", zap.String("method", method), zap.Error(err))
+	}
+}
+
+func (p *Provider) Init() error {
+	return p.callDefault("Init", nil)
+}
+
+func (p *Provider) NewClientState(dstChainID string, dstIBCHeader provider.IBCHeader, dstTrustingPeriod, dstUbdPeriod time.Duration, allowUpdateAfterExpiry, allowUpdateAfterMisbehaviour bool) (r0 ibcexported.ClientState, err error) {
+	err = p.callDefault("NewClientState", []any{dstChainID, dstIBCHeader, dstTrustingPeriod, dstUbdPeriod, allowUpdateAfterExpiry, allowUpdateAfterMisbehaviour}, &r0)
+	return
+}
+
+func (p *Provider) MsgCreateClient(clientState ibcexported.ClientState, consensusState ibcexported.ConsensusState) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgCreateClient", []any{clientState, consensusState}, &r0)
+	return
+}
+
+func (p *Provider) MsgUpgradeClient(srcClientId string, consRes *clienttypes.QueryConsensusStateResponse, clientRes *clienttypes.QueryClientStateResponse) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgUpgradeClient", []any{srcClientId, consRes, clientRes}, &r0)
+	return
+}
+
+func (p *Provider) ValidatePacket(msgTransfer provider.PacketInfo, latestBlock provider.LatestBlock) error {
+	return p.callDefault("ValidatePacket", []any{msgTransfer, latestBlock})
+}
+
+func (p *Provider) PacketCommitment(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (r0 provider.PacketProof, err error) {
+	err = p.call(ctx, "PacketCommitment", []any{msgTransfer, height}, &r0)
+	return
+}
+
+func (p *Provider) PacketAcknowledgement(ctx context.Context, msgRecvPacket provider.PacketInfo, height uint64) (r0 provider.PacketProof, err error) {
+	err = p.call(ctx, "PacketAcknowledgement", []any{msgRecvPacket, height}, &r0)
+	return
+}
+
+func (p *Provider) PacketReceipt(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (r0 provider.PacketProof, err error) {
+	err = p.call(ctx, "PacketReceipt", []any{msgTransfer, height}, &r0)
+	return
+}
+
+func (p *Provider) NextSeqRecv(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (r0 provider.PacketProof, err error) {
+	err = p.call(ctx, "NextSeqRecv", []any{msgTransfer, height}, &r0)
+	return
+}
+
+func (p *Provider) MsgTransfer(dstAddr string, amount sdk.Coin, info provider.PacketInfo) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgTransfer", []any{dstAddr, amount, info}, &r0)
+	return
+}
+
+func (p *Provider) MsgRecvPacket(msgTransfer provider.PacketInfo, proof provider.PacketProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgRecvPacket", []any{msgTransfer, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgAcknowledgement(msgRecvPacket provider.PacketInfo, proofAcked provider.PacketProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgAcknowledgement", []any{msgRecvPacket, proofAcked}, &r0)
+	return
+}
+
+func (p *Provider) MsgTimeout(msgTransfer provider.PacketInfo, proofUnreceived provider.PacketProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgTimeout", []any{msgTransfer, proofUnreceived}, &r0)
+	return
+}
+
+func (p *Provider) MsgTimeoutOnClose(msgTransfer provider.PacketInfo, proofUnreceived provider.PacketProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgTimeoutOnClose", []any{msgTransfer, proofUnreceived}, &r0)
+	return
+}
+
+func (p *Provider) ConnectionHandshakeProof(ctx context.Context, msgOpenInit provider.ConnectionInfo, height uint64) (r0 provider.ConnectionProof, err error) {
+	err = p.call(ctx, "ConnectionHandshakeProof", []any{msgOpenInit, height}, &r0)
+	return
+}
+
+func (p *Provider) ConnectionProof(ctx context.Context, msgOpenAck provider.ConnectionInfo, height uint64) (r0 provider.ConnectionProof, err error) {
+	err = p.call(ctx, "ConnectionProof", []any{msgOpenAck, height}, &r0)
+	return
+}
+
+func (p *Provider) MsgConnectionOpenInit(info provider.ConnectionInfo, proof provider.ConnectionProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgConnectionOpenInit", []any{info, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgConnectionOpenTry(msgOpenInit provider.ConnectionInfo, proof provider.ConnectionProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgConnectionOpenTry", []any{msgOpenInit, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgConnectionOpenAck(msgOpenTry provider.ConnectionInfo, proof provider.ConnectionProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgConnectionOpenAck", []any{msgOpenTry, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgConnectionOpenConfirm(msgOpenAck provider.ConnectionInfo, proof provider.ConnectionProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgConnectionOpenConfirm", []any{msgOpenAck, proof}, &r0)
+	return
+}
+
+func (p *Provider) ChannelProof(ctx context.Context, msg provider.ChannelInfo, height uint64) (r0 provider.ChannelProof, err error) {
+	err = p.call(ctx, "ChannelProof", []any{msg, height}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelOpenInit(info provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelOpenInit", []any{info, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelOpenTry(msgOpenInit provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelOpenTry", []any{msgOpenInit, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelOpenAck(msgOpenTry provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelOpenAck", []any{msgOpenTry, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelOpenConfirm(msgOpenAck provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelOpenConfirm", []any{msgOpenAck, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelCloseInit(info provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelCloseInit", []any{info, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgChannelCloseConfirm(msgCloseInit provider.ChannelInfo, proof provider.ChannelProof) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgChannelCloseConfirm", []any{msgCloseInit, proof}, &r0)
+	return
+}
+
+func (p *Provider) MsgUpdateClientHeader(latestHeader provider.IBCHeader, trustedHeight clienttypes.Height, trustedHeader provider.IBCHeader) (r0 ibcexported.Header, err error) {
+	err = p.callDefault("MsgUpdateClientHeader", []any{latestHeader, trustedHeight, trustedHeader}, &r0)
+	return
+}
+
+func (p *Provider) MsgUpdateClient(clientId string, counterpartyHeader ibcexported.Header) (r0 provider.RelayerMessage, err error) {
+	err = p.callDefault("MsgUpdateClient", []any{clientId, counterpartyHeader}, &r0)
+	return
+}
+
+func (p *Provider) RelayPacketFromSequence(ctx context.Context, src provider.ChainProvider, srch, dsth, seq uint64, srcChanID, srcPortID string, order chantypes.Order) (r0 provider.RelayerMessage, r1 provider.RelayerMessage, err error) {
+	err = p.call(ctx, "RelayPacketFromSequence", []any{src, srch, dsth, seq, srcChanID, srcPortID, order}, &r0, &r1)
+	return
+}
+
+func (p *Provider) AcknowledgementFromSequence(ctx context.Context, dst provider.ChainProvider, dsth, seq uint64, dstChanID, dstPortID, srcChanID, srcPortID string) (r0 provider.RelayerMessage, err error) {
+	err = p.call(ctx, "AcknowledgementFromSequence", []any{dst, dsth, seq, dstChanID, dstPortID, srcChanID, srcPortID}, &r0)
+	return
+}
+
+func (p *Provider) SendMessage(ctx context.Context, msg provider.RelayerMessage, memo string) (r0 *provider.RelayerTxResponse, r1 bool, err error) {
+	err = p.call(ctx, "SendMessage", []any{msg, memo}, &r0, &r1)
+	return
+}
+
+func (p *Provider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (r0 *provider.RelayerTxResponse, r1 bool, err error) {
+	err = p.call(ctx, "SendMessages", []any{msgs, memo}, &r0, &r1)
+	return
+}
+
+func (p *Provider) CommitmentPrefix() (r0 commitmenttypes.MerklePrefix) {
+	p.callLogged("CommitmentPrefix", nil, &r0)
+	return
+}
+
+func (p *Provider) Key() (r0 string) {
+	p.callLogged("Key", nil, &r0)
+	return
+}
+
+func (p *Provider) Address() (r0 string, err error) {
+	err = p.callDefault("Address", nil, &r0)
+	return
+}
+
+func (p *Provider) Timeout() (r0 string) {
+	p.callLogged("Timeout", nil, &r0)
+	return
+}
+
+func (p *Provider) TrustingPeriod(ctx context.Context) (r0 time.Duration, err error) {
+	err = p.call(ctx, "TrustingPeriod", nil, &r0)
+	return
+}
+
+func (p *Provider) WaitForNBlocks(ctx context.Context, n int64) error {
+	return p.call(ctx, "WaitForNBlocks", []any{n})
+}
+
+func (p *Provider) Sprint(toPrint proto.Message) (r0 string, err error) {
+	err = p.callDefault("Sprint", []any{toPrint}, &r0)
+	return
+}
+
+func (p *Provider) BlockTime(ctx context.Context, height int64) (r0 time.Time, err error) {
+	err = p.call(ctx, "BlockTime", []any{height}, &r0)
+	return
+}
+
+func (p *Provider) QueryTx(ctx context.Context, hashHex string) (r0 *provider.RelayerTxResponse, err error) {
+	err = p.call(ctx, "QueryTx", []any{hashHex}, &r0)
+	return
+}
+
+func (p *Provider) QueryTxs(ctx context.Context, page, limit int, events []string) (r0 []*provider.RelayerTxResponse, err error) {
+	err = p.call(ctx, "QueryTxs", []any{page, limit, events}, &r0)
+	return
+}
+
+func (p *Provider) QueryLatestHeight(ctx context.Context) (r0 int64, err error) {
+	err = p.call(ctx, "QueryLatestHeight", nil, &r0)
+	return
+}
+
+func (p *Provider) QueryIBCHeader(ctx context.Context, h int64) (r0 provider.IBCHeader, err error) {
+	err = p.call(ctx, "QueryIBCHeader", []any{h}, &r0)
+	return
+}
+
+func (p *Provider) QuerySendPacket(ctx context.Context, srcChanID, srcPortID string, sequence uint64) (r0 provider.PacketInfo, err error) {
+	err = p.call(ctx, "QuerySendPacket", []any{srcChanID, srcPortID, sequence}, &r0)
+	return
+}
+
+func (p *Provider) QueryRecvPacket(ctx context.Context, dstChanID, dstPortID string, sequence uint64) (r0 provider.PacketInfo, err error) {
+	err = p.call(ctx, "QueryRecvPacket", []any{dstChanID, dstPortID, sequence}, &r0)
+	return
+}
+
+func (p *Provider) QueryBalance(ctx context.Context, keyName string) (r0 sdk.Coins, err error) {
+	err = p.call(ctx, "QueryBalance", []any{keyName}, &r0)
+	return
+}
+
+func (p *Provider) QueryBalanceWithAddress(ctx context.Context, addr string) (r0 sdk.Coins, err error) {
+	err = p.call(ctx, "QueryBalanceWithAddress", []any{addr}, &r0)
+	return
+}
+
+func (p *Provider) QueryUnbondingPeriod(ctx context.Context) (r0 time.Duration, err error) {
+	err = p.call(ctx, "QueryUnbondingPeriod", nil, &r0)
+	return
+}
+
+func (p *Provider) QueryClientState(ctx context.Context, height int64, clientid string) (r0 ibcexported.ClientState, err error) {
+	err = p.call(ctx, "QueryClientState", []any{height, clientid}, &r0)
+	return
+}
+
+func (p *Provider) QueryClientStateResponse(ctx context.Context, height int64, srcClientId string) (r0 *clienttypes.QueryClientStateResponse, err error) {
+	err = p.call(ctx, "QueryClientStateResponse", []any{height, srcClientId}, &r0)
+	return
+}
+
+func (p *Provider) QueryClientConsensusState(ctx context.Context, chainHeight int64, clientid string, clientHeight ibcexported.Height) (r0 *clienttypes.QueryConsensusStateResponse, err error) {
+	err = p.call(ctx, "QueryClientConsensusState", []any{chainHeight, clientid, clientHeight}, &r0)
+	return
+}
+
+func (p *Provider) QueryUpgradedClient(ctx context.Context, height int64) (r0 *clienttypes.QueryClientStateResponse, err error) {
+	err = p.call(ctx, "QueryUpgradedClient", []any{height}, &r0)
+	return
+}
+
+func (p *Provider) QueryUpgradedConsState(ctx context.Context, height int64) (r0 *clienttypes.QueryConsensusStateResponse, err error) {
+	err = p.call(ctx, "QueryUpgradedConsState", []any{height}, &r0)
+	return
+}
+
+func (p *Provider) QueryConsensusState(ctx context.Context, height int64) (r0 ibcexported.ConsensusState, r1 int64, err error) {
+	err = p.call(ctx, "QueryConsensusState", []any{height}, &r0, &r1)
+	return
+}
+
+func (p *Provider) QueryClients(ctx context.Context) (r0 clienttypes.IdentifiedClientStates, err error) {
+	err = p.call(ctx, "QueryClients", nil, &r0)
+	return
+}
+
+func (p *Provider) QueryConnection(ctx context.Context, height int64, connectionid string) (r0 *conntypes.QueryConnectionResponse, err error) {
+	err = p.call(ctx, "QueryConnection", []any{height, connectionid}, &r0)
+	return
+}
+
+func (p *Provider) QueryConnections(ctx context.Context) (conns []*conntypes.IdentifiedConnection, err error) {
+	err = p.call(ctx, "QueryConnections", nil, &conns)
+	return
+}
+
+func (p *Provider) QueryConnectionsUsingClient(ctx context.Context, height int64, clientid string) (r0 *conntypes.QueryConnectionsResponse, err error) {
+	err = p.call(ctx, "QueryConnectionsUsingClient", []any{height, clientid}, &r0)
+	return
+}
+
+func (p *Provider) QueryMaxExpectedTimePerBlock(ctx context.Context) (r0 time.Duration, err error) {
+	err = p.call(ctx, "QueryMaxExpectedTimePerBlock", nil, &r0)
+	return
+}
+
+func (p *Provider) GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (clientState ibcexported.ClientState, clientStateProof []byte, consensusProof []byte, connectionProof []byte, connectionProofHeight ibcexported.Height, err error) {
+	err = p.call(ctx, "GenerateConnHandshakeProof", []any{height, clientId, connId}, &clientState, &clientStateProof, &consensusProof, &connectionProof, &connectionProofHeight)
+	return
+}
+
+func (p *Provider) QueryChannel(ctx context.Context, height int64, channelid, portid string) (chanRes *chantypes.QueryChannelResponse, err error) {
+	err = p.call(ctx, "QueryChannel", []any{height, channelid, portid}, &chanRes)
+	return
+}
+
+func (p *Provider) QueryChannelClient(ctx context.Context, height int64, channelid, portid string) (r0 *clienttypes.IdentifiedClientState, err error) {
+	err = p.call(ctx, "QueryChannelClient", []any{height, channelid, portid}, &r0)
+	return
+}
+
+func (p *Provider) QueryConnectionChannels(ctx context.Context, height int64, connectionid string) (r0 []*chantypes.IdentifiedChannel, err error) {
+	err = p.call(ctx, "QueryConnectionChannels", []any{height, connectionid}, &r0)
+	return
+}
+
+func (p *Provider) QueryChannels(ctx context.Context) (r0 []*chantypes.IdentifiedChannel, err error) {
+	err = p.call(ctx, "QueryChannels", nil, &r0)
+	return
+}
+
+func (p *Provider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (commitments *chantypes.QueryPacketCommitmentsResponse, err error) {
+	err = p.call(ctx, "QueryPacketCommitments", []any{height, channelid, portid}, &commitments)
+	return
+}
+
+func (p *Provider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string) (acknowledgements []*chantypes.PacketState, err error) {
+	err = p.call(ctx, "QueryPacketAcknowledgements", []any{height, channelid, portid}, &acknowledgements)
+	return
+}
+
+func (p *Provider) QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) (r0 []uint64, err error) {
+	err = p.call(ctx, "QueryUnreceivedPackets", []any{height, channelid, portid, seqs}, &r0)
+	return
+}
+
+func (p *Provider) QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) (r0 []uint64, err error) {
+	err = p.call(ctx, "QueryUnreceivedAcknowledgements", []any{height, channelid, portid, seqs}, &r0)
+	return
+}
+
+func (p *Provider) QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (recvRes *chantypes.QueryNextSequenceReceiveResponse, err error) {
+	err = p.call(ctx, "QueryNextSeqRecv", []any{height, channelid, portid}, &recvRes)
+	return
+}
+
+func (p *Provider) QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (r0 uint64, err error) {
+	err = p.call(ctx, "QueryNextSeqAck", []any{height, channelid, portid}, &r0)
+	return
+}
+
+func (p *Provider) QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (comRes *chantypes.QueryPacketCommitmentResponse, err error) {
+	err = p.call(ctx, "QueryPacketCommitment", []any{height, channelid, portid, seq}, &comRes)
+	return
+}
+
+func (p *Provider) QueryPacketAcknowledgement(ctx context.Context, height int64, channelid, portid string, seq uint64) (ackRes *chantypes.QueryPacketAcknowledgementResponse, err error) {
+	err = p.call(ctx, "QueryPacketAcknowledgement", []any{height, channelid, portid, seq}, &ackRes)
+	return
+}
+
+func (p *Provider) QueryPacketReceipt(ctx context.Context, height int64, channelid, portid string, seq uint64) (recRes *chantypes.QueryPacketReceiptResponse, err error) {
+	err = p.call(ctx, "QueryPacketReceipt", []any{height, channelid, portid, seq}, &recRes)
+	return
+}
+
+func (p *Provider) QueryDenomTrace(ctx context.Context, denom string) (r0 *transfertypes.DenomTrace, err error) {
+	err = p.call(ctx, "QueryDenomTrace", []any{denom}, &r0)
+	return
+}
+
+func (p *Provider) QueryDenomTraces(ctx context.Context, offset, limit uint64, height int64) (r0 []transfertypes.DenomTrace, err error) {
+	err = p.call(ctx, "QueryDenomTraces", []any{offset, limit, height}, &r0)
+	return
+}
+
+func (p *Provider) CreateKeystore(path string) error {
+	return p.callDefault("CreateKeystore", []any{path})
+}
+
+func (p *Provider) KeystoreCreated(path string) (r0 bool) {
+	p.callLogged("KeystoreCreated", []any{path}, &r0)
+	return
+}
+
+func (p *Provider) AddKey(name string, coinType uint32) (output *provider.KeyOutput, err error) {
+	err = p.callDefault("AddKey", []any{name, coinType}, &output)
+	return
+}
+
+func (p *Provider) RestoreKey(name, mnemonic string, coinType uint32) (address string, err error) {
+	err = p.callDefault("RestoreKey", []any{name, mnemonic, coinType}, &address)
+	return
+}
+
+func (p *Provider) ShowAddress(name string) (address string, err error) {
+	err = p.callDefault("ShowAddress", []any{name}, &address)
+	return
+}
+
+func (p *Provider) ListAddresses() (r0 map[string]string, err error) {
+	err = p.callDefault("ListAddresses", nil, &r0)
+	return
+}
+
+func (p *Provider) DeleteKey(name string) error {
+	return p.callDefault("DeleteKey", []any{name})
+}
+
+func (p *Provider) KeyExists(name string) (r0 bool) {
+	p.callLogged("KeyExists", []any{name}, &r0)
+	return
+}
+
+func (p *Provider) ExportPrivKeyArmor(keyName string) (armor string, err error) {
+	err = p.callDefault("ExportPrivKeyArmor", []any{keyName}, &armor)
+	return
+}
+
+func (p *Provider) ImportPrivKeyArmor(name, armor, passphrase string) (address string, err error) {
+	err = p.callDefault("ImportPrivKeyArmor", []any{name, armor, passphrase}, &address)
+	return
+}
+
+func (p *Provider) ImportPrivKeyHex(name, hexKey string) (address string, err error) {
+	err = p.callDefault("ImportPrivKeyHex", []any{name, hexKey}, &address)
+	return
+}
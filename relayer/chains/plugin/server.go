@@ -0,0 +1,262 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Plugin is implemented by plugin executables to add support for a chain type to the relayer.
+type Plugin interface {
+	// ProviderConfig returns a pointer to an empty provider config, which the config
+	// of the chain in the relayer config is decoded into as JSON.
+	ProviderConfig() provider.ProviderConfig
+
+	// NewChainProcessor returns the ChainProcessor for a provider created from the provider config.
+	NewChainProcessor(log *zap.Logger, p provider.ChainProvider) processor.ChainProcessor
+}
+
+// Serve serves p to the relayer over gRPC until the relayer closes stdin.
+// It is meant to be called from the main function of a plugin executable, which is started by the relayer.
+func Serve(p Plugin) error {
+	if os.Getenv(protocolEnv) != protocolVersion {
+		return fmt.Errorf("plugins are started by the relayer, configure a chain of type plugin to run this plugin")
+	}
+
+	// Logs go to stderr, which is passed through to the relayer's stderr.
+	log, err := zap.NewProduction()
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	srv := newServer(log, p)
+	fmt.Printf("%s %s tcp %s\n", handshakePrefix, protocolVersion, lis.Addr())
+
+	go func() {
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		srv.Stop()
+	}()
+	return srv.Serve(lis)
+}
+
+// server serves the provider and ChainProcessor of a plugin.
+type server struct {
+	log    *zap.Logger
+	plugin Plugin
+	codec  *valueCodec
+
+	mu       sync.Mutex
+	provider provider.ChainProvider
+}
+
+// newServer returns a gRPC server serving p.
+func newServer(log *zap.Logger, p Plugin) *grpc.Server {
+	s := &server{
+		log:    log,
+		plugin: p,
+		codec:  newValueCodec(newHandles()),
+	}
+
+	srv := grpc.NewServer(grpc.ForceServerCodec(jsonCodec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: pluginService,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Configure",
+			Handler:    s.configure,
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "RunChainProcessor",
+			Handler:       s.runChainProcessor,
+			ServerStreams: true,
+		}},
+	}, s)
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: providerService,
+		HandlerType: (*any)(nil),
+	}
+	for i := 0; i < chainProviderType.NumMethod(); i++ {
+		m := chainProviderType.Method(i)
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: m.Name,
+			Handler:    s.callHandler(m),
+		})
+	}
+	srv.RegisterService(desc, s)
+
+	return srv
+}
+
+func (s *server) chainProvider() (provider.ChainProvider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		return nil, status.Error(codes.FailedPrecondition, "plugin is not configured")
+	}
+	return s.provider, nil
+}
+
+func (s *server) configure(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+	var req configureRequest
+	if err := dec(&req); err != nil {
+		return nil, err
+	}
+
+	cfg := s.plugin.ProviderConfig()
+	if err := json.Unmarshal(req.Config, cfg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error decoding provider config: %v", err)
+	}
+	p, err := cfg.NewProvider(s.log, req.HomePath, req.Debug, req.ChainName)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error creating provider: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = p
+	return &configureResponse{ChainID: p.ChainId()}, nil
+}
+
+// callHandler returns the handler calling method m of the provider.
+func (s *server) callHandler(m reflect.Method) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+		var req callRequest
+		if err := dec(&req); err != nil {
+			return nil, err
+		}
+		p, err := s.chainProvider()
+		if err != nil {
+			return nil, err
+		}
+
+		in := make([]reflect.Value, m.Type.NumIn())
+		args := req.Args
+		for i := range in {
+			t := m.Type.In(i)
+			if t == contextType {
+				in[i] = reflect.ValueOf(ctx)
+				continue
+			}
+			if len(args) == 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: missing arguments", m.Name)
+			}
+			if in[i], err = s.codec.decode(args[0], t); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%s: argument %d: %v", m.Name, i, err)
+			}
+			args = args[1:]
+		}
+
+		out := reflect.ValueOf(p).MethodByName(m.Name).Call(in)
+
+		var res callResponse
+		for i, v := range out {
+			if m.Type.Out(i) == errorType {
+				if !v.IsNil() {
+					res.Error = v.Interface().(error).Error()
+				}
+				continue
+			}
+			raw, err := s.codec.encode(v)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "%s: result %d: %v", m.Name, i, err)
+			}
+			res.Results = append(res.Results, raw)
+		}
+		return &res, nil
+	}
+}
+
+// runChainProcessor runs the ChainProcessor of the plugin, linked with a PathProcessor per requested path
+// that is never run itself. Instead, the data the ChainProcessor hands to it is streamed to the relayer.
+func (s *server) runChainProcessor(_ any, stream grpc.ServerStream) error {
+	var req runRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	p, err := s.chainProvider()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	type update struct {
+		path         int
+		data         processor.ChainProcessorCacheData
+		backlogReady bool
+	}
+	updates := make(chan update)
+
+	pathProcessors := make(processor.PathProcessors, len(req.Paths))
+	for i, path := range req.Paths {
+		pp := processor.NewPathProcessor(s.log, path.PathEnd1, path.PathEnd2, nil, "", 0)
+		pathProcessors[i] = pp
+
+		go func(i int) {
+			for {
+				var u update
+				select {
+				case <-ctx.Done():
+					return
+				case u.data = <-pp.NewData(p.ChainId()):
+				case <-pp.BacklogSignals():
+					u.backlogReady = true
+				}
+				u.path = i
+				select {
+				case <-ctx.Done():
+					return
+				case updates <- u:
+				}
+			}
+		}(i)
+	}
+
+	cp := s.plugin.NewChainProcessor(s.log, p)
+	cp.SetPathProcessors(pathProcessors)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cp.Run(ctx, req.InitialBlockHistory)
+	}()
+
+	for {
+		select {
+		case err := <-errCh:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return status.Errorf(codes.Unknown, "chain processor: %v", err)
+			}
+			return nil
+		case u := <-updates:
+			msg := runUpdate{Path: u.path, BacklogReady: u.backlogReady}
+			if !u.backlogReady {
+				if msg.Data, err = s.codec.encode(reflect.ValueOf(&u.data).Elem()); err != nil {
+					return status.Errorf(codes.Internal, "error encoding chain processor data: %v", err)
+				}
+			}
+			if err := stream.SendMsg(&msg); err != nil {
+				return err
+			}
+		}
+	}
+}
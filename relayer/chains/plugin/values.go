@@ -0,0 +1,469 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	lens "github.com/strangelove-ventures/lens/client"
+)
+
+// maxHandles is the number of RelayerMessages and IBCHeaders a plugin keeps for the relayer to refer back to.
+const maxHandles = 10_000
+
+var (
+	contextType        = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	protoMarshalerType = reflect.TypeOf((*codec.ProtoMarshaler)(nil)).Elem()
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	relayerMessageType = reflect.TypeOf((*provider.RelayerMessage)(nil)).Elem()
+	ibcHeaderType      = reflect.TypeOf((*provider.IBCHeader)(nil)).Elem()
+	heightType         = reflect.TypeOf((*ibcexported.Height)(nil)).Elem()
+	consensusStateType = reflect.TypeOf((*ibcexported.ConsensusState)(nil)).Elem()
+)
+
+// messageRef is the wire form of a RelayerMessage.
+type messageRef struct {
+	Handle uint64 `json:",omitempty"`
+	Type   string
+	Bytes  []byte
+}
+
+// headerRef is the wire form of an IBCHeader.
+type headerRef struct {
+	Handle         uint64 `json:",omitempty"`
+	Height         uint64
+	ConsensusState json.RawMessage
+}
+
+// message is a RelayerMessage received from the other end of the connection.
+// A non-zero handle refers to the original message in the plugin process.
+type message struct {
+	handle  uint64
+	msgType string
+	bytes   []byte
+}
+
+func (m *message) Type() string {
+	return m.msgType
+}
+
+func (m *message) MsgBytes() ([]byte, error) {
+	return m.bytes, nil
+}
+
+// header is an IBCHeader received from the other end of the connection.
+// A non-zero handle refers to the original header in the plugin process.
+type header struct {
+	handle         uint64
+	height         uint64
+	consensusState ibcexported.ConsensusState
+}
+
+func (h *header) Height() uint64 {
+	return h.height
+}
+
+func (h *header) ConsensusState() ibcexported.ConsensusState {
+	return h.consensusState
+}
+
+// handles keeps the RelayerMessages and IBCHeaders a plugin sent to the relayer, so that they resolve to the
+// original values when the relayer passes them back, e.g. to SendMessages or MsgUpdateClientHeader.
+type handles struct {
+	mu     sync.Mutex
+	last   uint64
+	values map[uint64]any
+}
+
+func newHandles() *handles {
+	return &handles{values: make(map[uint64]any)}
+}
+
+// add stores v and returns its handle, forgetting the value stored maxHandles handles ago.
+func (h *handles) add(v any) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last++
+	h.values[h.last] = v
+	if h.last > maxHandles {
+		delete(h.values, h.last-maxHandles)
+	}
+	return h.last
+}
+
+func (h *handles) get(handle uint64) (any, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, ok := h.values[handle]
+	return v, ok
+}
+
+// valueCodec encodes the arguments and results of ChainProvider methods as JSON, by their declared types.
+// Protobuf messages are encoded in binary, and the ClientState, ConsensusState and Header interfaces
+// as Any. RelayerMessages and IBCHeaders are passed by handle when sent by a plugin.
+type valueCodec struct {
+	cdc      *codec.ProtoCodec
+	registry codectypes.InterfaceRegistry
+
+	// handles is only set in the plugin process.
+	handles *handles
+}
+
+func newValueCodec(h *handles) *valueCodec {
+	registry := lens.MakeCodec(lens.ModuleBasics, nil).InterfaceRegistry
+	return &valueCodec{
+		cdc:      codec.NewProtoCodec(registry),
+		registry: registry,
+		handles:  h,
+	}
+}
+
+// encode encodes v according to its type, which is the declared type when v is obtained
+// from a struct field, container element or reflect.ValueOf(&x).Elem().
+func (c *valueCodec) encode(v reflect.Value) (json.RawMessage, error) {
+	t := v.Type()
+	switch {
+	case t == relayerMessageType:
+		return c.encodeMessage(v)
+	case t == ibcHeaderType:
+		return c.encodeHeader(v)
+	case t == heightType:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		switch h := v.Interface().(type) {
+		case clienttypes.Height:
+			return c.encode(reflect.ValueOf(h))
+		case *clienttypes.Height:
+			return c.encode(reflect.ValueOf(*h))
+		default:
+			return nil, fmt.Errorf("unsupported height type %T", h)
+		}
+	case t.Kind() == reflect.Interface:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		msg, ok := v.Interface().(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value of type %T for %s", v.Interface(), t)
+		}
+		return c.encodeAny(msg)
+	case t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(protoMarshalerType):
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return c.encodeProto(p.Interface().(codec.ProtoMarshaler))
+	case t.Kind() == reflect.Pointer && t.Implements(protoMarshalerType):
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return c.encodeProto(v.Interface().(codec.ProtoMarshaler))
+	case t.Implements(jsonMarshalerType):
+		return json.Marshal(v.Interface())
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		return c.encode(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]json.RawMessage, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			f, err := c.encode(v.Field(i))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", t, t.Field(i).Name, err)
+			}
+			fields[t.Field(i).Name] = f
+		}
+		return json.Marshal(fields)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return json.Marshal(v.Interface())
+		}
+		elems := make([]json.RawMessage, v.Len())
+		for i := range elems {
+			e, err := c.encode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = e
+		}
+		return json.Marshal(elems)
+	case reflect.Map:
+		// Maps are encoded as lists of key value pairs, since keys are not necessarily strings.
+		if v.IsNil() {
+			return json.RawMessage("null"), nil
+		}
+		pairs := make([][2]json.RawMessage, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := c.encode(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			e, err := c.encode(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, [2]json.RawMessage{k, e})
+		}
+		return json.Marshal(pairs)
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return json.Marshal(v.Interface())
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// decode decodes raw into a new value of type t.
+func (c *valueCodec) decode(raw json.RawMessage, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	if string(raw) == "null" {
+		return v, nil
+	}
+	switch {
+	case t == relayerMessageType:
+		return c.decodeMessage(raw)
+	case t == ibcHeaderType:
+		return c.decodeHeader(raw)
+	case t == heightType:
+		h, err := c.decode(raw, reflect.TypeOf(clienttypes.Height{}))
+		if err != nil {
+			return v, err
+		}
+		v.Set(h)
+		return v, nil
+	case t.Kind() == reflect.Interface:
+		msg, err := c.decodeAny(raw)
+		if err != nil {
+			return v, err
+		}
+		if !reflect.TypeOf(msg).Implements(t) {
+			return v, fmt.Errorf("%T does not implement %s", msg, t)
+		}
+		v.Set(reflect.ValueOf(msg))
+		return v, nil
+	case t.Kind() == reflect.Struct && reflect.PtrTo(t).Implements(protoMarshalerType):
+		err := c.decodeProto(raw, v.Addr().Interface().(codec.ProtoMarshaler))
+		return v, err
+	case t.Kind() == reflect.Pointer && t.Implements(protoMarshalerType):
+		v.Set(reflect.New(t.Elem()))
+		err := c.decodeProto(raw, v.Interface().(codec.ProtoMarshaler))
+		return v, err
+	case reflect.PtrTo(t).Implements(jsonMarshalerType) || t.Implements(jsonMarshalerType):
+		err := json.Unmarshal(raw, v.Addr().Interface())
+		return v, err
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		e, err := c.decode(raw, t.Elem())
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(e)
+		return v, nil
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return v, err
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f, ok := fields[t.Field(i).Name]
+			if !ok || !t.Field(i).IsExported() {
+				continue
+			}
+			fv, err := c.decode(f, t.Field(i).Type)
+			if err != nil {
+				return v, fmt.Errorf("%s.%s: %w", t, t.Field(i).Name, err)
+			}
+			v.Field(i).Set(fv)
+		}
+		return v, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			err := json.Unmarshal(raw, v.Addr().Interface())
+			return v, err
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return v, err
+		}
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		} else if len(elems) != t.Len() {
+			return v, fmt.Errorf("expected %d elements for %s, got %d", t.Len(), t, len(elems))
+		}
+		for i, raw := range elems {
+			e, err := c.decode(raw, t.Elem())
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(e)
+		}
+		return v, nil
+	case reflect.Map:
+		var pairs [][2]json.RawMessage
+		if err := json.Unmarshal(raw, &pairs); err != nil {
+			return v, err
+		}
+		v.Set(reflect.MakeMapWithSize(t, len(pairs)))
+		for _, pair := range pairs {
+			k, err := c.decode(pair[0], t.Key())
+			if err != nil {
+				return v, err
+			}
+			e, err := c.decode(pair[1], t.Elem())
+			if err != nil {
+				return v, err
+			}
+			v.SetMapIndex(k, e)
+		}
+		return v, nil
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		err := json.Unmarshal(raw, v.Addr().Interface())
+		return v, err
+	default:
+		return v, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func (c *valueCodec) encodeProto(msg codec.ProtoMarshaler) (json.RawMessage, error) {
+	bz, err := c.cdc.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(bz)
+}
+
+func (c *valueCodec) decodeProto(raw json.RawMessage, msg codec.ProtoMarshaler) error {
+	var bz []byte
+	if err := json.Unmarshal(raw, &bz); err != nil {
+		return err
+	}
+	return c.cdc.Unmarshal(bz, msg)
+}
+
+func (c *valueCodec) encodeAny(msg proto.Message) (json.RawMessage, error) {
+	a, err := codectypes.NewAnyWithValue(msg)
+	if err != nil {
+		return nil, err
+	}
+	return c.encodeProto(a)
+}
+
+// decodeAny decodes an Any into a message of the type registered for its type URL.
+func (c *valueCodec) decodeAny(raw json.RawMessage) (proto.Message, error) {
+	var a codectypes.Any
+	if err := c.decodeProto(raw, &a); err != nil {
+		return nil, err
+	}
+	msg, err := c.registry.Resolve(a.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	pm, ok := msg.(codec.ProtoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message type %s", a.TypeUrl)
+	}
+	if err := c.cdc.Unmarshal(a.Value, pm); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (c *valueCodec) encodeMessage(v reflect.Value) (json.RawMessage, error) {
+	if v.IsNil() {
+		return json.RawMessage("null"), nil
+	}
+	m := v.Interface().(provider.RelayerMessage)
+	bz, err := m.MsgBytes()
+	if err != nil {
+		return nil, err
+	}
+	ref := messageRef{Type: m.Type(), Bytes: bz}
+	if pm, ok := m.(*message); ok {
+		ref.Handle = pm.handle
+	} else if c.handles != nil {
+		ref.Handle = c.handles.add(m)
+	}
+	return json.Marshal(ref)
+}
+
+func (c *valueCodec) decodeMessage(raw json.RawMessage) (reflect.Value, error) {
+	var ref messageRef
+	if err := json.Unmarshal(raw, &ref); err != nil {
+		return reflect.Value{}, err
+	}
+	var m provider.RelayerMessage = &message{handle: ref.Handle, msgType: ref.Type, bytes: ref.Bytes}
+	if c.handles != nil && ref.Handle != 0 {
+		if orig, ok := c.handles.get(ref.Handle); ok {
+			m = orig.(provider.RelayerMessage)
+		}
+	}
+	return reflect.ValueOf(&m).Elem(), nil
+}
+
+func (c *valueCodec) encodeHeader(v reflect.Value) (json.RawMessage, error) {
+	if v.IsNil() {
+		return json.RawMessage("null"), nil
+	}
+	h := v.Interface().(provider.IBCHeader)
+	cs := h.ConsensusState()
+	csRaw, err := c.encode(reflect.ValueOf(&cs).Elem())
+	if err != nil {
+		return nil, err
+	}
+	ref := headerRef{Height: h.Height(), ConsensusState: csRaw}
+	if ph, ok := h.(*header); ok {
+		ref.Handle = ph.handle
+	} else if c.handles != nil {
+		ref.Handle = c.handles.add(h)
+	}
+	return json.Marshal(ref)
+}
+
+func (c *valueCodec) decodeHeader(raw json.RawMessage) (reflect.Value, error) {
+	var ref headerRef
+	if err := json.Unmarshal(raw, &ref); err != nil {
+		return reflect.Value{}, err
+	}
+	if c.handles != nil && ref.Handle != 0 {
+		if orig, ok := c.handles.get(ref.Handle); ok {
+			h := orig.(provider.IBCHeader)
+			return reflect.ValueOf(&h).Elem(), nil
+		}
+	}
+	cs, err := c.decode(ref.ConsensusState, consensusStateType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	var h provider.IBCHeader = &header{handle: ref.Handle, height: ref.Height}
+	if !cs.IsNil() {
+		h.(*header).consensusState = cs.Interface().(ibcexported.ConsensusState)
+	}
+	return reflect.ValueOf(&h).Elem(), nil
+}
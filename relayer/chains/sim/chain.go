@@ -0,0 +1,294 @@
+// Package sim implements an in-memory simulated chain with an IBC store, along with a
+// ChainProvider and ChainProcessor for it, so that PathProcessor flows such as handshakes,
+// packet relaying and timeouts can be tested end to end with go test, without running any
+// real chains.
+package sim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// DefaultBlockTime is the block time used by Run when none is configured.
+const DefaultBlockTime = 100 * time.Millisecond
+
+// event is an IBC event emitted while executing a message.
+// info is one of provider.PacketInfo, provider.ConnectionInfo, provider.ChannelInfo or clientEvent.
+type event struct {
+	eventType string
+	info      any
+}
+
+// clientEvent is the info of client creation and update events.
+type clientEvent struct {
+	clientID        string
+	consensusHeight clienttypes.Height
+}
+
+type txResult struct {
+	height uint64
+	hash   string
+	events []event
+}
+
+type block struct {
+	header Header
+	txs    []*txResult
+	// state is a snapshot of the IBC store after the block was committed.
+	state *state
+}
+
+// Chain is an in-memory chain with an IBC store. Messages are executed as soon as they are
+// delivered and committed with the next block. Blocks are produced by Run, or by calling
+// CommitBlock directly.
+type Chain struct {
+	chainID   string
+	revision  uint64
+	blockTime time.Duration
+
+	mu sync.Mutex
+
+	// blocks holds all committed blocks, blocks[i] has height i+1.
+	blocks []*block
+
+	// pending is the working state for the next block, and pendingTxs the transactions in it.
+	pending    *state
+	pendingTxs []*txResult
+
+	// committed is closed and replaced whenever a block is committed.
+	committed chan struct{}
+}
+
+// NewChain returns a new simulated chain with a committed genesis block at height 1.
+// Blocks are produced every blockTime by Run, DefaultBlockTime if zero.
+func NewChain(chainID string, blockTime time.Duration) *Chain {
+	if blockTime == 0 {
+		blockTime = DefaultBlockTime
+	}
+	c := &Chain{
+		chainID:   chainID,
+		revision:  clienttypes.ParseChainID(chainID),
+		blockTime: blockTime,
+		pending:   newState(),
+		committed: make(chan struct{}),
+	}
+	c.CommitBlock()
+	return c
+}
+
+// ChainID returns the chain ID of the chain.
+func (c *Chain) ChainID() string {
+	return c.chainID
+}
+
+// Run produces a block every block time until ctx is done.
+func (c *Chain) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.blockTime)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.CommitBlock()
+		}
+	}
+}
+
+// CommitBlock commits the pending transactions in a new block.
+func (c *Chain) CommitBlock() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	height := uint64(len(c.blocks) + 1)
+	c.blocks = append(c.blocks, &block{
+		header: Header{
+			ChainID:     c.chainID,
+			BlockHeight: height,
+			Time:        time.Now(),
+		},
+		txs:   c.pendingTxs,
+		state: c.pending.clone(),
+	})
+	c.pendingTxs = nil
+	close(c.committed)
+	c.committed = make(chan struct{})
+}
+
+// LatestHeight returns the height of the latest committed block.
+func (c *Chain) LatestHeight() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.blocks))
+}
+
+// height returns the clienttypes.Height of a block height on this chain.
+func (c *Chain) height(h uint64) clienttypes.Height {
+	return clienttypes.NewHeight(c.revision, h)
+}
+
+// block returns the committed block at height h, or the latest block if h is zero.
+func (c *Chain) block(h uint64) (*block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h == 0 {
+		h = uint64(len(c.blocks))
+	}
+	if h > uint64(len(c.blocks)) {
+		return nil, fmt.Errorf("height %d is greater than latest height %d of chain %s", h, len(c.blocks), c.chainID)
+	}
+	return c.blocks[h-1], nil
+}
+
+// stateAt returns the committed IBC store at height h, or at the latest height if h is zero.
+func (c *Chain) stateAt(h uint64) (*state, uint64, error) {
+	b, err := c.block(h)
+	if err != nil {
+		return nil, 0, err
+	}
+	return b.state, b.header.BlockHeight, nil
+}
+
+// proof returns a proof of the value stored under key at height h.
+func (c *Chain) proof(h uint64, key []byte) ([]byte, []byte, clienttypes.Height, error) {
+	s, h, err := c.stateAt(h)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+	value := s.get(key)
+	p := proof{ChainID: c.chainID, Height: h, Key: string(key), Value: value}
+	return value, p.bytes(), c.height(h), nil
+}
+
+// deliverTx executes msgs atomically against the pending state. If they all succeed,
+// it waits for the block including the transaction to be committed.
+func (c *Chain) deliverTx(ctx context.Context, msgs []any) (*txResult, error) {
+	c.mu.Lock()
+	height := uint64(len(c.blocks) + 1)
+	s := c.pending.clone()
+	x := &executor{chain: c, state: s, height: height, time: time.Now()}
+	events, err := x.execute(msgs)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.pending = s
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s/%d/%d", c.chainID, height, len(c.pendingTxs))
+	for _, msg := range msgs {
+		bz, err := msgBytes(msg)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		h.Write(bz)
+	}
+	hash := h.Sum(nil)
+	tx := &txResult{
+		height: height,
+		hash:   strings.ToUpper(hex.EncodeToString(hash)),
+		events: events,
+	}
+	c.pendingTxs = append(c.pendingTxs, tx)
+	committed := c.committed
+	c.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s was executed, but not committed before context done: %w", tx.hash, ctx.Err())
+		case <-committed:
+		}
+		c.mu.Lock()
+		done := uint64(len(c.blocks)) >= height
+		committed = c.committed
+		c.mu.Unlock()
+		if done {
+			return tx, nil
+		}
+	}
+}
+
+// CreateClient creates a client of the counterparty chain at its latest height,
+// and returns the client ID once the creation is committed.
+func (c *Chain) CreateClient(ctx context.Context, counterparty *Chain, trustingPeriod time.Duration) (string, error) {
+	b, err := counterparty.block(0)
+	if err != nil {
+		return "", err
+	}
+	tx, err := c.deliverTx(ctx, []any{&MsgCreateClient{
+		ClientState: &tmclient.ClientState{
+			ChainId:        counterparty.chainID,
+			TrustingPeriod: trustingPeriod,
+			LatestHeight:   counterparty.height(b.header.BlockHeight),
+		},
+		ConsensusState: b.header.ConsensusState().(*tmclient.ConsensusState),
+	}})
+	if err != nil {
+		return "", err
+	}
+	return tx.events[0].info.(clientEvent).clientID, nil
+}
+
+// SendPacket sends a packet over an open channel and returns its sequence once committed.
+func (c *Chain) SendPacket(
+	ctx context.Context,
+	portID, channelID string,
+	data []byte,
+	timeoutHeight clienttypes.Height,
+	timeoutTimestamp uint64,
+) (uint64, error) {
+	tx, err := c.deliverTx(ctx, []any{&MsgSendPacket{
+		SourcePort:       portID,
+		SourceChannel:    channelID,
+		Data:             data,
+		TimeoutHeight:    timeoutHeight,
+		TimeoutTimestamp: timeoutTimestamp,
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return tx.events[0].info.(provider.PacketInfo).Sequence, nil
+}
+
+// tx returns the committed transaction with the given hash.
+func (c *Chain) tx(hash string) (*txResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, b := range c.blocks {
+		for _, tx := range b.txs {
+			if strings.EqualFold(tx.hash, hash) {
+				return tx, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("transaction not found: %s", hash)
+}
+
+// findPacketEvent returns the info of the latest committed packet event matching the filter.
+func (c *Chain) findPacketEvent(eventType string, match func(provider.PacketInfo) bool) (provider.PacketInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.blocks) - 1; i >= 0; i-- {
+		for _, tx := range c.blocks[i].txs {
+			for _, e := range tx.events {
+				if e.eventType != eventType {
+					continue
+				}
+				if pi, ok := e.info.(provider.PacketInfo); ok && match(pi) {
+					return pi, nil
+				}
+			}
+		}
+	}
+	return provider.PacketInfo{}, fmt.Errorf("no %s event found", eventType)
+}
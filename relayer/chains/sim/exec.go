@@ -0,0 +1,737 @@
+package sim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v5/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// successAck is the acknowledgement written for every received packet.
+var successAck = chantypes.NewResultAcknowledgement([]byte{1}).Acknowledgement()
+
+// executor executes the messages of a transaction against the state of a pending block.
+type executor struct {
+	chain  *Chain
+	state  *state
+	height uint64
+	time   time.Time
+
+	events []event
+
+	// packetMsgs and redundantPacketMsgs count the packet messages and the ones which were no-ops,
+	// so that transactions that only contain redundant packet messages can be rejected like ibc-go does.
+	packetMsgs, redundantPacketMsgs int
+}
+
+func (x *executor) emit(eventType string, info any) {
+	x.events = append(x.events, event{eventType: eventType, info: info})
+}
+
+func (x *executor) execute(msgs []any) ([]event, error) {
+	for i, msg := range msgs {
+		if err := x.executeMsg(msg); err != nil {
+			return nil, fmt.Errorf("failed to execute message %d (%T): %w", i, msg, err)
+		}
+	}
+	if x.packetMsgs > 0 && x.packetMsgs == x.redundantPacketMsgs {
+		return nil, chantypes.ErrRedundantTx
+	}
+	return x.events, nil
+}
+
+func (x *executor) executeMsg(msg any) error {
+	switch m := msg.(type) {
+	case *MsgCreateClient:
+		return x.createClient(m)
+	case *MsgUpdateClient:
+		return x.updateClient(m)
+	case *MsgSendPacket:
+		return x.sendPacket(m)
+	case *conntypes.MsgConnectionOpenInit:
+		return x.connectionOpenInit(m)
+	case *conntypes.MsgConnectionOpenTry:
+		return x.connectionOpenTry(m)
+	case *conntypes.MsgConnectionOpenAck:
+		return x.connectionOpenAck(m)
+	case *conntypes.MsgConnectionOpenConfirm:
+		return x.connectionOpenConfirm(m)
+	case *chantypes.MsgChannelOpenInit:
+		return x.channelOpenInit(m)
+	case *chantypes.MsgChannelOpenTry:
+		return x.channelOpenTry(m)
+	case *chantypes.MsgChannelOpenAck:
+		return x.channelOpenAck(m)
+	case *chantypes.MsgChannelOpenConfirm:
+		return x.channelOpenConfirm(m)
+	case *chantypes.MsgChannelCloseInit:
+		return x.channelCloseInit(m)
+	case *chantypes.MsgChannelCloseConfirm:
+		return x.channelCloseConfirm(m)
+	case *chantypes.MsgRecvPacket:
+		x.packetMsgs++
+		return x.recvPacket(m)
+	case *chantypes.MsgAcknowledgement:
+		x.packetMsgs++
+		return x.acknowledgePacket(m)
+	case *chantypes.MsgTimeout:
+		x.packetMsgs++
+		return x.timeoutPacket(m)
+	default:
+		return fmt.Errorf("unsupported message type: %T", msg)
+	}
+}
+
+func (x *executor) selfHeight() clienttypes.Height {
+	return x.chain.height(x.height)
+}
+
+// [Begin] ICS-02 client
+
+func (x *executor) createClient(m *MsgCreateClient) error {
+	if m.ClientState == nil || m.ConsensusState == nil {
+		return errors.New("client state and consensus state are required")
+	}
+	if m.ClientState.ChainId == "" {
+		return errors.New("client state chain ID cannot be empty")
+	}
+	if m.ClientState.TrustingPeriod <= 0 {
+		return errors.New("client state trusting period must be positive")
+	}
+	clientID := clienttypes.FormatClientIdentifier(ibcexported.Tendermint, x.state.nextClientSeq)
+	x.state.nextClientSeq++
+	x.state.clients[clientID] = &client{
+		ChainID:        m.ClientState.ChainId,
+		TrustingPeriod: m.ClientState.TrustingPeriod,
+		LatestHeight:   m.ClientState.LatestHeight,
+		consensusTimes: map[uint64]time.Time{
+			m.ClientState.LatestHeight.RevisionHeight: m.ConsensusState.Timestamp,
+		},
+		processed: map[uint64]processedState{
+			m.ClientState.LatestHeight.RevisionHeight: {height: x.height, time: x.time},
+		},
+	}
+	x.emit(clienttypes.EventTypeCreateClient, clientEvent{clientID: clientID, consensusHeight: m.ClientState.LatestHeight})
+	return nil
+}
+
+func (x *executor) updateClient(m *MsgUpdateClient) error {
+	c, err := x.state.client(m.ClientID)
+	if err != nil {
+		return err
+	}
+	if m.Header == nil {
+		return errors.New("header is required")
+	}
+	if err := m.Header.ValidateBasic(); err != nil {
+		return err
+	}
+	if m.Header.ChainID != c.ChainID {
+		return fmt.Errorf("header is for chain %s, but client %s tracks chain %s", m.Header.ChainID, m.ClientID, c.ChainID)
+	}
+	if elapsed := x.time.Sub(c.latestConsensusTime()); elapsed > c.TrustingPeriod {
+		return fmt.Errorf("client %s is expired, %s elapsed since latest consensus state with trusting period %s", m.ClientID, elapsed, c.TrustingPeriod)
+	}
+	if _, ok := c.consensusTimes[m.Header.TrustedHeight.RevisionHeight]; !ok {
+		return fmt.Errorf("client %s has no consensus state at trusted height %s", m.ClientID, m.Header.TrustedHeight)
+	}
+	height := m.Header.GetHeight().(clienttypes.Height)
+	if _, ok := c.consensusTimes[height.RevisionHeight]; !ok {
+		c.consensusTimes[height.RevisionHeight] = m.Header.Time
+		c.processed[height.RevisionHeight] = processedState{height: x.height, time: x.time}
+		if height.GT(c.LatestHeight) {
+			c.LatestHeight = height
+		}
+	}
+	x.emit(clienttypes.EventTypeUpdateClient, clientEvent{clientID: m.ClientID, consensusHeight: height})
+	return nil
+}
+
+// [End] ICS-02 client
+
+// [Begin] ICS-03 connection handshake
+
+func (x *executor) connectionOpenInit(m *conntypes.MsgConnectionOpenInit) error {
+	if _, err := x.state.client(m.ClientId); err != nil {
+		return err
+	}
+	connectionID := conntypes.FormatConnectionIdentifier(x.state.nextConnectionSeq)
+	x.state.nextConnectionSeq++
+	x.state.setConnection(connectionID, conntypes.ConnectionEnd{
+		ClientId:     m.ClientId,
+		Versions:     conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions()),
+		State:        conntypes.INIT,
+		Counterparty: m.Counterparty,
+		DelayPeriod:  m.DelayPeriod,
+	})
+	x.emit(conntypes.EventTypeConnectionOpenInit, provider.ConnectionInfo{
+		Height:               x.height,
+		ConnID:               connectionID,
+		ClientID:             m.ClientId,
+		CounterpartyClientID: m.Counterparty.ClientId,
+	})
+	return nil
+}
+
+// verifyConnection checks the proof of the counterparty connection end, which must be in the given state
+// and have the client and counterparty of the connection end on this chain.
+func (x *executor) verifyConnection(
+	clientID string,
+	counterparty conntypes.Counterparty,
+	connectionID string,
+	expectedState conntypes.State,
+	proofBz []byte,
+	proofHeight clienttypes.Height,
+) error {
+	bz, err := x.state.verifyProof(clientID, proofBz, proofHeight, host.ConnectionKey(counterparty.ConnectionId))
+	if err != nil {
+		return err
+	}
+	if bz == nil {
+		return fmt.Errorf("counterparty connection %s does not exist", counterparty.ConnectionId)
+	}
+	var conn conntypes.ConnectionEnd
+	if err := conn.Unmarshal(bz); err != nil {
+		return fmt.Errorf("invalid counterparty connection end: %w", err)
+	}
+	if conn.State != expectedState {
+		return fmt.Errorf("counterparty connection %s is in state %s, expected %s", counterparty.ConnectionId, conn.State, expectedState)
+	}
+	if conn.ClientId != counterparty.ClientId {
+		return fmt.Errorf("counterparty connection client %s does not match %s", conn.ClientId, counterparty.ClientId)
+	}
+	if conn.Counterparty.ClientId != clientID {
+		return fmt.Errorf("counterparty connection has counterparty client %s, expected %s", conn.Counterparty.ClientId, clientID)
+	}
+	if expectedState != conntypes.INIT && conn.Counterparty.ConnectionId != connectionID {
+		return fmt.Errorf("counterparty connection has counterparty connection %s, expected %s", conn.Counterparty.ConnectionId, connectionID)
+	}
+	return nil
+}
+
+func (x *executor) connectionOpenTry(m *conntypes.MsgConnectionOpenTry) error {
+	if err := x.verifyConnection(m.ClientId, m.Counterparty, "", conntypes.INIT, m.ProofInit, m.ProofHeight); err != nil {
+		return err
+	}
+	connectionID := conntypes.FormatConnectionIdentifier(x.state.nextConnectionSeq)
+	x.state.nextConnectionSeq++
+	x.state.setConnection(connectionID, conntypes.ConnectionEnd{
+		ClientId:     m.ClientId,
+		Versions:     m.CounterpartyVersions,
+		State:        conntypes.TRYOPEN,
+		Counterparty: m.Counterparty,
+		DelayPeriod:  m.DelayPeriod,
+	})
+	x.emit(conntypes.EventTypeConnectionOpenTry, provider.ConnectionInfo{
+		Height:               x.height,
+		ConnID:               connectionID,
+		ClientID:             m.ClientId,
+		CounterpartyClientID: m.Counterparty.ClientId,
+		CounterpartyConnID:   m.Counterparty.ConnectionId,
+	})
+	return nil
+}
+
+func (x *executor) connectionOpenAck(m *conntypes.MsgConnectionOpenAck) error {
+	conn, ok := x.state.connection(m.ConnectionId)
+	if !ok {
+		return fmt.Errorf("connection not found: %s", m.ConnectionId)
+	}
+	if conn.State != conntypes.INIT {
+		return fmt.Errorf("connection %s is in state %s, expected %s", m.ConnectionId, conn.State, conntypes.INIT)
+	}
+	conn.Counterparty.ConnectionId = m.CounterpartyConnectionId
+	if err := x.verifyConnection(conn.ClientId, conn.Counterparty, m.ConnectionId, conntypes.TRYOPEN, m.ProofTry, m.ProofHeight); err != nil {
+		return err
+	}
+	conn.State = conntypes.OPEN
+	x.state.setConnection(m.ConnectionId, conn)
+	x.emit(conntypes.EventTypeConnectionOpenAck, provider.ConnectionInfo{
+		Height:               x.height,
+		ConnID:               m.ConnectionId,
+		ClientID:             conn.ClientId,
+		CounterpartyClientID: conn.Counterparty.ClientId,
+		CounterpartyConnID:   conn.Counterparty.ConnectionId,
+	})
+	return nil
+}
+
+func (x *executor) connectionOpenConfirm(m *conntypes.MsgConnectionOpenConfirm) error {
+	conn, ok := x.state.connection(m.ConnectionId)
+	if !ok {
+		return fmt.Errorf("connection not found: %s", m.ConnectionId)
+	}
+	if conn.State != conntypes.TRYOPEN {
+		return fmt.Errorf("connection %s is in state %s, expected %s", m.ConnectionId, conn.State, conntypes.TRYOPEN)
+	}
+	if err := x.verifyConnection(conn.ClientId, conn.Counterparty, m.ConnectionId, conntypes.OPEN, m.ProofAck, m.ProofHeight); err != nil {
+		return err
+	}
+	conn.State = conntypes.OPEN
+	x.state.setConnection(m.ConnectionId, conn)
+	x.emit(conntypes.EventTypeConnectionOpenConfirm, provider.ConnectionInfo{
+		Height:               x.height,
+		ConnID:               m.ConnectionId,
+		ClientID:             conn.ClientId,
+		CounterpartyClientID: conn.Counterparty.ClientId,
+		CounterpartyConnID:   conn.Counterparty.ConnectionId,
+	})
+	return nil
+}
+
+// [End] ICS-03 connection handshake
+
+// [Begin] ICS-04 channel handshake
+
+// channelConnection returns the connection of a channel, which must be open unless allowNotOpen.
+func (x *executor) channelConnection(connectionHops []string, allowNotOpen bool) (conntypes.ConnectionEnd, error) {
+	if len(connectionHops) != 1 {
+		return conntypes.ConnectionEnd{}, fmt.Errorf("channels must have exactly one connection hop, got %d", len(connectionHops))
+	}
+	conn, ok := x.state.connection(connectionHops[0])
+	if !ok {
+		return conn, fmt.Errorf("connection not found: %s", connectionHops[0])
+	}
+	if !allowNotOpen && conn.State != conntypes.OPEN {
+		return conn, fmt.Errorf("connection %s is in state %s, expected %s", connectionHops[0], conn.State, conntypes.OPEN)
+	}
+	return conn, nil
+}
+
+// verifyChannel checks the proof of the counterparty channel end, which must be in the given state
+// and have the ordering and counterparty of the channel end on this chain.
+func (x *executor) verifyChannel(
+	ch chantypes.Channel,
+	portID, channelID string,
+	expectedState chantypes.State,
+	proofBz []byte,
+	proofHeight clienttypes.Height,
+) error {
+	conn, err := x.channelConnection(ch.ConnectionHops, ch.State == chantypes.INIT || ch.State == chantypes.UNINITIALIZED)
+	if err != nil {
+		return err
+	}
+	bz, err := x.state.verifyProof(conn.ClientId, proofBz, proofHeight, host.ChannelKey(ch.Counterparty.PortId, ch.Counterparty.ChannelId))
+	if err != nil {
+		return err
+	}
+	if bz == nil {
+		return fmt.Errorf("counterparty channel %s/%s does not exist", ch.Counterparty.PortId, ch.Counterparty.ChannelId)
+	}
+	var counterparty chantypes.Channel
+	if err := counterparty.Unmarshal(bz); err != nil {
+		return fmt.Errorf("invalid counterparty channel end: %w", err)
+	}
+	if counterparty.State != expectedState {
+		return fmt.Errorf("counterparty channel is in state %s, expected %s", counterparty.State, expectedState)
+	}
+	if counterparty.Ordering != ch.Ordering {
+		return fmt.Errorf("counterparty channel ordering %s does not match %s", counterparty.Ordering, ch.Ordering)
+	}
+	if counterparty.Counterparty.PortId != portID {
+		return fmt.Errorf("counterparty channel has counterparty port %s, expected %s", counterparty.Counterparty.PortId, portID)
+	}
+	if expectedState != chantypes.INIT && counterparty.Counterparty.ChannelId != channelID {
+		return fmt.Errorf("counterparty channel has counterparty channel %s, expected %s", counterparty.Counterparty.ChannelId, channelID)
+	}
+	if len(counterparty.ConnectionHops) != 1 || counterparty.ConnectionHops[0] != conn.Counterparty.ConnectionId {
+		return fmt.Errorf("counterparty channel connection hops %v do not match counterparty connection %s", counterparty.ConnectionHops, conn.Counterparty.ConnectionId)
+	}
+	return nil
+}
+
+// initChannelSequences sets the packet sequences of a new channel.
+func (x *executor) initChannelSequences(portID, channelID string) {
+	x.state.setSequence(host.NextSequenceSendKey(portID, channelID), 1)
+	x.state.setSequence(host.NextSequenceRecvKey(portID, channelID), 1)
+	x.state.setSequence(host.NextSequenceAckKey(portID, channelID), 1)
+}
+
+func (x *executor) channelInfo(portID, channelID string, ch chantypes.Channel) provider.ChannelInfo {
+	return provider.ChannelInfo{
+		Height:                x.height,
+		PortID:                portID,
+		ChannelID:             channelID,
+		CounterpartyPortID:    ch.Counterparty.PortId,
+		CounterpartyChannelID: ch.Counterparty.ChannelId,
+		ConnID:                ch.ConnectionHops[0],
+		Version:               ch.Version,
+	}
+}
+
+func (x *executor) channelOpenInit(m *chantypes.MsgChannelOpenInit) error {
+	if _, err := x.channelConnection(m.Channel.ConnectionHops, true); err != nil {
+		return err
+	}
+	if m.PortId == "" {
+		return errors.New("port ID cannot be empty")
+	}
+	channelID := chantypes.FormatChannelIdentifier(x.state.nextChannelSeq)
+	x.state.nextChannelSeq++
+	ch := m.Channel
+	ch.State = chantypes.INIT
+	ch.Counterparty.ChannelId = ""
+	x.state.setChannel(m.PortId, channelID, ch)
+	x.initChannelSequences(m.PortId, channelID)
+	x.emit(chantypes.EventTypeChannelOpenInit, x.channelInfo(m.PortId, channelID, ch))
+	return nil
+}
+
+func (x *executor) channelOpenTry(m *chantypes.MsgChannelOpenTry) error {
+	ch := m.Channel
+	ch.State = chantypes.TRYOPEN
+	if _, err := x.channelConnection(ch.ConnectionHops, false); err != nil {
+		return err
+	}
+	if err := x.verifyChannel(ch, m.PortId, "", chantypes.INIT, m.ProofInit, m.ProofHeight); err != nil {
+		return err
+	}
+	channelID := chantypes.FormatChannelIdentifier(x.state.nextChannelSeq)
+	x.state.nextChannelSeq++
+	x.state.setChannel(m.PortId, channelID, ch)
+	x.initChannelSequences(m.PortId, channelID)
+	x.emit(chantypes.EventTypeChannelOpenTry, x.channelInfo(m.PortId, channelID, ch))
+	return nil
+}
+
+func (x *executor) channelOpenAck(m *chantypes.MsgChannelOpenAck) error {
+	ch, ok := x.state.channel(m.PortId, m.ChannelId)
+	if !ok {
+		return fmt.Errorf("channel not found: %s/%s", m.PortId, m.ChannelId)
+	}
+	if ch.State != chantypes.INIT {
+		return fmt.Errorf("channel %s/%s is in state %s, expected %s", m.PortId, m.ChannelId, ch.State, chantypes.INIT)
+	}
+	if _, err := x.channelConnection(ch.ConnectionHops, false); err != nil {
+		return err
+	}
+	ch.Counterparty.ChannelId = m.CounterpartyChannelId
+	ch.State = chantypes.OPEN
+	ch.Version = m.CounterpartyVersion
+	if err := x.verifyChannel(ch, m.PortId, m.ChannelId, chantypes.TRYOPEN, m.ProofTry, m.ProofHeight); err != nil {
+		return err
+	}
+	x.state.setChannel(m.PortId, m.ChannelId, ch)
+	x.emit(chantypes.EventTypeChannelOpenAck, x.channelInfo(m.PortId, m.ChannelId, ch))
+	return nil
+}
+
+func (x *executor) channelOpenConfirm(m *chantypes.MsgChannelOpenConfirm) error {
+	ch, ok := x.state.channel(m.PortId, m.ChannelId)
+	if !ok {
+		return fmt.Errorf("channel not found: %s/%s", m.PortId, m.ChannelId)
+	}
+	if ch.State != chantypes.TRYOPEN {
+		return fmt.Errorf("channel %s/%s is in state %s, expected %s", m.PortId, m.ChannelId, ch.State, chantypes.TRYOPEN)
+	}
+	if err := x.verifyChannel(ch, m.PortId, m.ChannelId, chantypes.OPEN, m.ProofAck, m.ProofHeight); err != nil {
+		return err
+	}
+	ch.State = chantypes.OPEN
+	x.state.setChannel(m.PortId, m.ChannelId, ch)
+	x.emit(chantypes.EventTypeChannelOpenConfirm, x.channelInfo(m.PortId, m.ChannelId, ch))
+	return nil
+}
+
+func (x *executor) channelCloseInit(m *chantypes.MsgChannelCloseInit) error {
+	ch, ok := x.state.channel(m.PortId, m.ChannelId)
+	if !ok {
+		return fmt.Errorf("channel not found: %s/%s", m.PortId, m.ChannelId)
+	}
+	if ch.State == chantypes.CLOSED {
+		return fmt.Errorf("channel %s/%s is already closed", m.PortId, m.ChannelId)
+	}
+	if _, err := x.channelConnection(ch.ConnectionHops, false); err != nil {
+		return err
+	}
+	ch.State = chantypes.CLOSED
+	x.state.setChannel(m.PortId, m.ChannelId, ch)
+	x.emit(chantypes.EventTypeChannelCloseInit, x.channelInfo(m.PortId, m.ChannelId, ch))
+	return nil
+}
+
+func (x *executor) channelCloseConfirm(m *chantypes.MsgChannelCloseConfirm) error {
+	ch, ok := x.state.channel(m.PortId, m.ChannelId)
+	if !ok {
+		return fmt.Errorf("channel not found: %s/%s", m.PortId, m.ChannelId)
+	}
+	if ch.State == chantypes.CLOSED {
+		return fmt.Errorf("channel %s/%s is already closed", m.PortId, m.ChannelId)
+	}
+	if err := x.verifyChannel(ch, m.PortId, m.ChannelId, chantypes.CLOSED, m.ProofInit, m.ProofHeight); err != nil {
+		return err
+	}
+	ch.State = chantypes.CLOSED
+	x.state.setChannel(m.PortId, m.ChannelId, ch)
+	x.emit(chantypes.EventTypeChannelCloseConfirm, x.channelInfo(m.PortId, m.ChannelId, ch))
+	return nil
+}
+
+// [End] ICS-04 channel handshake
+
+// verifyDelay checks that the delay period of the connection passed since the consensus state at proofHeight
+// was added to its client, both in time and in blocks of the block time, like ibc-go does for packet proofs.
+func (x *executor) verifyDelay(conn conntypes.ConnectionEnd, proofHeight clienttypes.Height) error {
+	if conn.DelayPeriod == 0 {
+		return nil
+	}
+	c, err := x.state.client(conn.ClientId)
+	if err != nil {
+		return err
+	}
+	processed, ok := c.processed[proofHeight.RevisionHeight]
+	if !ok {
+		return fmt.Errorf("client %s has no consensus state at proof height %s", conn.ClientId, proofHeight)
+	}
+	delay := time.Duration(conn.DelayPeriod)
+	if validTime := processed.time.Add(delay); x.time.Before(validTime) {
+		return fmt.Errorf("%w: cannot verify packet until time %s, current time %s",
+			tmclient.ErrDelayPeriodNotPassed, validTime, x.time)
+	}
+	blockDelay := uint64((delay + x.chain.blockTime - 1) / x.chain.blockTime)
+	if validHeight := processed.height + blockDelay; x.height < validHeight {
+		return fmt.Errorf("%w: cannot verify packet until height %d, current height %d",
+			tmclient.ErrDelayPeriodNotPassed, validHeight, x.height)
+	}
+	return nil
+}
+
+// [Begin] ICS-04 packet flow
+
+// openChannel returns a channel which must be open, along with its connection.
+func (x *executor) openChannel(portID, channelID string) (chantypes.Channel, conntypes.ConnectionEnd, error) {
+	ch, ok := x.state.channel(portID, channelID)
+	if !ok {
+		return ch, conntypes.ConnectionEnd{}, fmt.Errorf("channel not found: %s/%s", portID, channelID)
+	}
+	if ch.State != chantypes.OPEN {
+		return ch, conntypes.ConnectionEnd{}, fmt.Errorf("channel %s/%s is in state %s, expected %s", portID, channelID, ch.State, chantypes.OPEN)
+	}
+	conn, err := x.channelConnection(ch.ConnectionHops, false)
+	return ch, conn, err
+}
+
+func packetInfo(height uint64, packet chantypes.Packet, order chantypes.Order) provider.PacketInfo {
+	return provider.PacketInfo{
+		Height:           height,
+		Sequence:         packet.Sequence,
+		SourcePort:       packet.SourcePort,
+		SourceChannel:    packet.SourceChannel,
+		DestPort:         packet.DestinationPort,
+		DestChannel:      packet.DestinationChannel,
+		ChannelOrder:     order.String(),
+		Data:             packet.Data,
+		TimeoutHeight:    packet.TimeoutHeight,
+		TimeoutTimestamp: packet.TimeoutTimestamp,
+	}
+}
+
+func (x *executor) sendPacket(m *MsgSendPacket) error {
+	ch, _, err := x.openChannel(m.SourcePort, m.SourceChannel)
+	if err != nil {
+		return err
+	}
+	if len(m.Data) == 0 {
+		return errors.New("packet data cannot be empty")
+	}
+	if m.TimeoutHeight.IsZero() && m.TimeoutTimestamp == 0 {
+		return errors.New("packet timeout height and timestamp cannot both be zero")
+	}
+	seqKey := host.NextSequenceSendKey(m.SourcePort, m.SourceChannel)
+	packet := chantypes.Packet{
+		Sequence:           x.state.sequence(seqKey),
+		SourcePort:         m.SourcePort,
+		SourceChannel:      m.SourceChannel,
+		DestinationPort:    ch.Counterparty.PortId,
+		DestinationChannel: ch.Counterparty.ChannelId,
+		Data:               m.Data,
+		TimeoutHeight:      m.TimeoutHeight,
+		TimeoutTimestamp:   m.TimeoutTimestamp,
+	}
+	x.state.setSequence(seqKey, packet.Sequence+1)
+	x.state.set(host.PacketCommitmentKey(packet.SourcePort, packet.SourceChannel, packet.Sequence), chantypes.CommitPacket(nil, packet))
+	x.emit(chantypes.EventTypeSendPacket, packetInfo(x.height, packet, ch.Ordering))
+	return nil
+}
+
+func (x *executor) recvPacket(m *chantypes.MsgRecvPacket) error {
+	p := m.Packet
+	ch, conn, err := x.openChannel(p.DestinationPort, p.DestinationChannel)
+	if err != nil {
+		return err
+	}
+	if ch.Counterparty.PortId != p.SourcePort || ch.Counterparty.ChannelId != p.SourceChannel {
+		return fmt.Errorf("packet source %s/%s does not match channel counterparty %s/%s", p.SourcePort, p.SourceChannel, ch.Counterparty.PortId, ch.Counterparty.ChannelId)
+	}
+	if !p.TimeoutHeight.IsZero() && x.selfHeight().GTE(p.TimeoutHeight) {
+		return fmt.Errorf("%w: block height %s >= timeout height %s", chantypes.ErrPacketTimeout, x.selfHeight(), p.TimeoutHeight)
+	}
+	if p.TimeoutTimestamp != 0 && uint64(x.time.UnixNano()) >= p.TimeoutTimestamp {
+		return fmt.Errorf("%w: block time %d >= timeout timestamp %d", chantypes.ErrPacketTimeout, x.time.UnixNano(), p.TimeoutTimestamp)
+	}
+
+	seqKey := host.NextSequenceRecvKey(p.DestinationPort, p.DestinationChannel)
+	receiptKey := host.PacketReceiptKey(p.DestinationPort, p.DestinationChannel, p.Sequence)
+	switch ch.Ordering {
+	case chantypes.ORDERED:
+		nextSeq := x.state.sequence(seqKey)
+		if p.Sequence < nextSeq {
+			x.redundantPacketMsgs++
+			return nil
+		}
+		if p.Sequence != nextSeq {
+			return fmt.Errorf("packet sequence %d does not match next receive sequence %d", p.Sequence, nextSeq)
+		}
+	default:
+		if x.state.get(receiptKey) != nil {
+			x.redundantPacketMsgs++
+			return nil
+		}
+	}
+
+	if err := x.verifyDelay(conn, m.ProofHeight); err != nil {
+		return err
+	}
+
+	commitmentKey := host.PacketCommitmentKey(p.SourcePort, p.SourceChannel, p.Sequence)
+	if err := x.state.verifyValue(conn.ClientId, m.ProofCommitment, m.ProofHeight, commitmentKey, chantypes.CommitPacket(nil, p)); err != nil {
+		return err
+	}
+
+	if ch.Ordering == chantypes.ORDERED {
+		x.state.setSequence(seqKey, p.Sequence+1)
+	} else {
+		x.state.set(receiptKey, []byte{1})
+	}
+	x.state.set(host.PacketAcknowledgementKey(p.DestinationPort, p.DestinationChannel, p.Sequence), chantypes.CommitAcknowledgement(successAck))
+
+	x.emit(chantypes.EventTypeRecvPacket, packetInfo(x.height, p, ch.Ordering))
+	ackInfo := packetInfo(x.height, p, ch.Ordering)
+	ackInfo.Ack = successAck
+	x.emit(chantypes.EventTypeWriteAck, ackInfo)
+	return nil
+}
+
+// packetCommitment checks the commitment of a sent packet. It returns false if the commitment
+// no longer exists, i.e. the packet was already acknowledged or timed out.
+func (x *executor) packetCommitment(p chantypes.Packet) (bool, error) {
+	commitment := x.state.get(host.PacketCommitmentKey(p.SourcePort, p.SourceChannel, p.Sequence))
+	if commitment == nil {
+		return false, nil
+	}
+	if !bytes.Equal(commitment, chantypes.CommitPacket(nil, p)) {
+		return false, fmt.Errorf("packet does not match commitment for sequence %d", p.Sequence)
+	}
+	return true, nil
+}
+
+func (x *executor) acknowledgePacket(m *chantypes.MsgAcknowledgement) error {
+	p := m.Packet
+	ch, conn, err := x.openChannel(p.SourcePort, p.SourceChannel)
+	if err != nil {
+		return err
+	}
+	exists, err := x.packetCommitment(p)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		x.redundantPacketMsgs++
+		return nil
+	}
+
+	if err := x.verifyDelay(conn, m.ProofHeight); err != nil {
+		return err
+	}
+
+	ackKey := host.PacketAcknowledgementKey(p.DestinationPort, p.DestinationChannel, p.Sequence)
+	if err := x.state.verifyValue(conn.ClientId, m.ProofAcked, m.ProofHeight, ackKey, chantypes.CommitAcknowledgement(m.Acknowledgement)); err != nil {
+		return err
+	}
+
+	if ch.Ordering == chantypes.ORDERED {
+		seqKey := host.NextSequenceAckKey(p.SourcePort, p.SourceChannel)
+		if nextSeq := x.state.sequence(seqKey); p.Sequence != nextSeq {
+			return fmt.Errorf("packet sequence %d does not match next acknowledgement sequence %d", p.Sequence, nextSeq)
+		}
+		x.state.setSequence(seqKey, p.Sequence+1)
+	}
+	x.state.delete(host.PacketCommitmentKey(p.SourcePort, p.SourceChannel, p.Sequence))
+
+	info := packetInfo(x.height, p, ch.Ordering)
+	info.Data = nil
+	x.emit(chantypes.EventTypeAcknowledgePacket, info)
+	return nil
+}
+
+func (x *executor) timeoutPacket(m *chantypes.MsgTimeout) error {
+	p := m.Packet
+	ch, ok := x.state.channel(p.SourcePort, p.SourceChannel)
+	if !ok {
+		return fmt.Errorf("channel not found: %s/%s", p.SourcePort, p.SourceChannel)
+	}
+	conn, err := x.channelConnection(ch.ConnectionHops, false)
+	if err != nil {
+		return err
+	}
+	exists, err := x.packetCommitment(p)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		x.redundantPacketMsgs++
+		return nil
+	}
+
+	c, err := x.state.client(conn.ClientId)
+	if err != nil {
+		return err
+	}
+	proofTime, ok := c.consensusTimes[m.ProofHeight.RevisionHeight]
+	if !ok {
+		return fmt.Errorf("client %s has no consensus state at proof height %s", conn.ClientId, m.ProofHeight)
+	}
+	heightTimedOut := !p.TimeoutHeight.IsZero() && m.ProofHeight.GTE(p.TimeoutHeight)
+	timestampTimedOut := p.TimeoutTimestamp != 0 && uint64(proofTime.UnixNano()) >= p.TimeoutTimestamp
+	if !heightTimedOut && !timestampTimedOut {
+		return fmt.Errorf("packet has not timed out at proof height %s", m.ProofHeight)
+	}
+
+	if err := x.verifyDelay(conn, m.ProofHeight); err != nil {
+		return err
+	}
+
+	switch ch.Ordering {
+	case chantypes.ORDERED:
+		if m.NextSequenceRecv > p.Sequence {
+			return fmt.Errorf("next receive sequence %d is greater than packet sequence %d, packet was received", m.NextSequenceRecv, p.Sequence)
+		}
+		seqKey := host.NextSequenceRecvKey(p.DestinationPort, p.DestinationChannel)
+		if err := x.state.verifyValue(conn.ClientId, m.ProofUnreceived, m.ProofHeight, seqKey, sequenceBytes(m.NextSequenceRecv)); err != nil {
+			return err
+		}
+		ch.State = chantypes.CLOSED
+		x.state.setChannel(p.SourcePort, p.SourceChannel, ch)
+	default:
+		receiptKey := host.PacketReceiptKey(p.DestinationPort, p.DestinationChannel, p.Sequence)
+		if err := x.state.verifyValue(conn.ClientId, m.ProofUnreceived, m.ProofHeight, receiptKey, nil); err != nil {
+			return err
+		}
+	}
+	x.state.delete(host.PacketCommitmentKey(p.SourcePort, p.SourceChannel, p.Sequence))
+
+	info := packetInfo(x.height, p, ch.Ordering)
+	info.Data = nil
+	x.emit(chantypes.EventTypeTimeoutPacket, info)
+	return nil
+}
+
+// [End] ICS-04 packet flow
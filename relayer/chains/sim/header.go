@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+var (
+	_ provider.IBCHeader = Header{}
+	_ ibcexported.Header = &Header{}
+)
+
+// Header is the IBC header of a block of a simulated chain.
+// It is used both as the provider.IBCHeader of a block and as the header in a MsgUpdateClient,
+// in which case TrustedHeight is the height of the consensus state it is verified against.
+type Header struct {
+	ChainID       string             `json:"chain_id"`
+	BlockHeight   uint64             `json:"height"`
+	Time          time.Time          `json:"time"`
+	TrustedHeight clienttypes.Height `json:"trusted_height"`
+}
+
+// Height returns the block height of the header.
+func (h Header) Height() uint64 {
+	return h.BlockHeight
+}
+
+// ConsensusState returns a tendermint consensus state with the timestamp of the header,
+// so that client expiration can be computed the same way as for cosmos chains.
+func (h Header) ConsensusState() ibcexported.ConsensusState {
+	root := sha256.Sum256(binary.BigEndian.AppendUint64([]byte(h.ChainID), h.BlockHeight))
+	return &tmclient.ConsensusState{
+		Timestamp: h.Time,
+		Root:      commitmenttypes.NewMerkleRoot(root[:]),
+	}
+}
+
+func (h *Header) Reset()         { *h = Header{} }
+func (h *Header) String() string { return fmt.Sprintf("%s@%d", h.ChainID, h.BlockHeight) }
+func (*Header) ProtoMessage()    {}
+
+// ClientType reports the tendermint client type, since simulated clients are tracked
+// with tendermint client states.
+func (*Header) ClientType() string {
+	return ibcexported.Tendermint
+}
+
+func (h *Header) GetHeight() ibcexported.Height {
+	return clienttypes.NewHeight(clienttypes.ParseChainID(h.ChainID), h.BlockHeight)
+}
+
+func (h *Header) ValidateBasic() error {
+	if h.ChainID == "" {
+		return errors.New("header chain ID cannot be empty")
+	}
+	if h.BlockHeight == 0 {
+		return errors.New("header height cannot be zero")
+	}
+	if h.TrustedHeight.RevisionHeight >= h.BlockHeight {
+		return fmt.Errorf("header height %d must be greater than trusted height %d", h.BlockHeight, h.TrustedHeight.RevisionHeight)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package sim
+
+import (
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func (scp *SimChainProcessor) handleEvent(e event, c processor.IBCMessagesCache) {
+	switch info := e.info.(type) {
+	case provider.PacketInfo:
+		scp.handlePacketMessage(e.eventType, info, c)
+	case provider.ChannelInfo:
+		scp.handleChannelMessage(e.eventType, info, c)
+	case provider.ConnectionInfo:
+		scp.handleConnectionMessage(e.eventType, info, c)
+	case clientEvent:
+		scp.logObservedIBCMessage(e.eventType, zap.String("client_id", info.clientID))
+	}
+}
+
+func (scp *SimChainProcessor) handlePacketMessage(eventType string, pi provider.PacketInfo, c processor.IBCMessagesCache) {
+	k, err := processor.PacketInfoChannelKey(eventType, pi)
+	if err != nil {
+		scp.log.Error("Unexpected error handling packet message",
+			zap.String("event_type", eventType),
+			zap.Uint64("sequence", pi.Sequence),
+			zap.Inline(k),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if eventType == chantypes.EventTypeTimeoutPacket && pi.ChannelOrder == chantypes.ORDERED.String() {
+		scp.channelStateCache[k] = false
+	}
+
+	if !c.PacketFlow.ShouldRetainSequence(scp.pathProcessors, k, scp.chainProvider.ChainId(), eventType, pi.Sequence) {
+		scp.log.Debug("Not retaining packet message",
+			zap.String("event_type", eventType),
+			zap.Uint64("sequence", pi.Sequence),
+			zap.Inline(k),
+		)
+		return
+	}
+
+	c.PacketFlow.Retain(k, eventType, pi)
+	scp.logPacketMessage(eventType, pi)
+}
+
+func (scp *SimChainProcessor) handleChannelMessage(eventType string, ci provider.ChannelInfo, ibcMessagesCache processor.IBCMessagesCache) {
+	scp.channelConnections[ci.ChannelID] = ci.ConnID
+	channelKey := processor.ChannelInfoChannelKey(ci)
+
+	if eventType == chantypes.EventTypeChannelOpenInit {
+		found := false
+		for k := range scp.channelStateCache {
+			// Don't add a channelKey to the channelStateCache without counterparty channel ID
+			// since we already have the channelKey in the channelStateCache which includes the
+			// counterparty channel ID.
+			if k.MsgInitKey() == channelKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			scp.channelStateCache[channelKey] = false
+		}
+	} else {
+		switch eventType {
+		case chantypes.EventTypeChannelOpenTry:
+			scp.channelStateCache[channelKey] = false
+		case chantypes.EventTypeChannelOpenAck, chantypes.EventTypeChannelOpenConfirm:
+			scp.channelStateCache[channelKey] = true
+		case chantypes.EventTypeChannelCloseInit, chantypes.EventTypeChannelCloseConfirm:
+			for k := range scp.channelStateCache {
+				if k.PortID == ci.PortID && k.ChannelID == ci.ChannelID {
+					scp.channelStateCache[k] = false
+					break
+				}
+			}
+		}
+		// Clear out MsgInitKeys once we have the counterparty channel ID
+		delete(scp.channelStateCache, channelKey.MsgInitKey())
+	}
+
+	ibcMessagesCache.ChannelHandshake.Retain(channelKey, eventType, ci)
+
+	scp.logChannelMessage(eventType, ci)
+}
+
+func (scp *SimChainProcessor) handleConnectionMessage(eventType string, ci provider.ConnectionInfo, ibcMessagesCache processor.IBCMessagesCache) {
+	scp.connectionClients[ci.ConnID] = ci.ClientID
+	connectionKey := processor.ConnectionInfoConnectionKey(ci)
+	if eventType == conntypes.EventTypeConnectionOpenInit {
+		found := false
+		for k := range scp.connectionStateCache {
+			// Don't add a connectionKey to the connectionStateCache without counterparty connection ID
+			// since we already have the connectionKey in the connectionStateCache which includes the
+			// counterparty connection ID.
+			if k.MsgInitKey() == connectionKey {
+				found = true
+				break
+			}
+		}
+		if !found {
+			scp.connectionStateCache[connectionKey] = false
+		}
+	} else {
+		// Clear out MsgInitKeys once we have the counterparty connection ID
+		delete(scp.connectionStateCache, connectionKey.MsgInitKey())
+		open := (eventType == conntypes.EventTypeConnectionOpenAck || eventType == conntypes.EventTypeConnectionOpenConfirm)
+		scp.connectionStateCache[connectionKey] = open
+	}
+	ibcMessagesCache.ConnectionHandshake.Retain(connectionKey, eventType, ci)
+
+	scp.logConnectionMessage(eventType, ci)
+}
+
+func (scp *SimChainProcessor) logObservedIBCMessage(m string, fields ...zap.Field) {
+	scp.log.With(zap.String("event_type", m)).Debug("Observed IBC message", fields...)
+}
+
+func (scp *SimChainProcessor) logPacketMessage(message string, pi provider.PacketInfo) {
+	if !scp.log.Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+	scp.logObservedIBCMessage(message,
+		zap.Uint64("sequence", pi.Sequence),
+		zap.String("src_channel", pi.SourceChannel),
+		zap.String("src_port", pi.SourcePort),
+		zap.String("dst_channel", pi.DestChannel),
+		zap.String("dst_port", pi.DestPort),
+	)
+}
+
+func (scp *SimChainProcessor) logChannelMessage(message string, ci provider.ChannelInfo) {
+	scp.logObservedIBCMessage(message,
+		zap.String("channel_id", ci.ChannelID),
+		zap.String("port_id", ci.PortID),
+		zap.String("counterparty_channel_id", ci.CounterpartyChannelID),
+		zap.String("counterparty_port_id", ci.CounterpartyPortID),
+		zap.String("connection_id", ci.ConnID),
+	)
+}
+
+func (scp *SimChainProcessor) logConnectionMessage(message string, ci provider.ConnectionInfo) {
+	scp.logObservedIBCMessage(message,
+		zap.String("client_id", ci.ClientID),
+		zap.String("connection_id", ci.ConnID),
+		zap.String("counterparty_client_id", ci.CounterpartyClientID),
+		zap.String("counterparty_connection_id", ci.CounterpartyConnID),
+	)
+}
@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap/zapcore"
+)
+
+var _ provider.RelayerMessage = Message{}
+
+// Message wraps a message that can be executed by a simulated chain.
+// Msg is either one of the ibc-go core messages or one of the simulation specific
+// messages in this file, for the operations where the ibc-go message would need
+// a packed client state or header.
+type Message struct {
+	Msg any
+}
+
+func NewMessage(msg any) provider.RelayerMessage {
+	return Message{Msg: msg}
+}
+
+// Type returns the type URL for ibc-go messages, and the Go type otherwise.
+func (m Message) Type() string {
+	if pm, ok := m.Msg.(proto.Message); ok {
+		if name := proto.MessageName(pm); name != "" {
+			return "/" + name
+		}
+	}
+	return fmt.Sprintf("%T", m.Msg)
+}
+
+// MsgBytes returns the protobuf encoding of ibc-go messages, and the JSON encoding otherwise.
+func (m Message) MsgBytes() ([]byte, error) {
+	return msgBytes(m.Msg)
+}
+
+func msgBytes(msg any) ([]byte, error) {
+	if pm, ok := msg.(proto.Message); ok {
+		return proto.Marshal(pm)
+	}
+	return json.Marshal(msg)
+}
+
+// MarshalLogObject is used to encode m to a zap logger with the zap.Object field type.
+func (m Message) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	bz, err := m.MsgBytes()
+	if err != nil {
+		return err
+	}
+	enc.AddString("type", m.Type())
+	enc.AddBinary("msg_bytes", bz)
+	return nil
+}
+
+// MsgCreateClient creates a client of a counterparty chain.
+type MsgCreateClient struct {
+	ClientState    *tmclient.ClientState    `json:"client_state"`
+	ConsensusState *tmclient.ConsensusState `json:"consensus_state"`
+	Signer         string                   `json:"signer"`
+}
+
+// MsgUpdateClient updates a client with a header of the counterparty chain.
+type MsgUpdateClient struct {
+	ClientID string  `json:"client_id"`
+	Header   *Header `json:"header"`
+	Signer   string  `json:"signer"`
+}
+
+// MsgSendPacket sends a packet with arbitrary data over a channel,
+// standing in for an application message such as an ICS-20 MsgTransfer.
+type MsgSendPacket struct {
+	SourcePort       string             `json:"source_port"`
+	SourceChannel    string             `json:"source_channel"`
+	Data             []byte             `json:"data"`
+	TimeoutHeight    clienttypes.Height `json:"timeout_height"`
+	TimeoutTimestamp uint64             `json:"timeout_timestamp"`
+	Signer           string             `json:"signer"`
+}
@@ -0,0 +1,69 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+)
+
+// proof stands in for a merkle proof. It states the value, or absence, of a key in the
+// IBC store of a chain at a height. It is verified structurally against the client of
+// the chain on the counterparty, and no cryptographic verification is done.
+type proof struct {
+	ChainID string `json:"chain_id"`
+	Height  uint64 `json:"height"`
+	Key     string `json:"key"`
+	Value   []byte `json:"value,omitempty"`
+}
+
+func (p proof) bytes() []byte {
+	bz, err := json.Marshal(p)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// verifyProof checks that proofBz is a proof for key on the chain tracked by clientID,
+// at a height for which the client has a consensus state. It returns the proven value,
+// which is nil if the proof is of absence.
+func (s *state) verifyProof(clientID string, proofBz []byte, proofHeight clienttypes.Height, key []byte) ([]byte, error) {
+	c, err := s.client(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if len(proofBz) == 0 {
+		return nil, fmt.Errorf("empty proof for key %s", key)
+	}
+	var p proof
+	if err := json.Unmarshal(proofBz, &p); err != nil {
+		return nil, fmt.Errorf("invalid proof for key %s: %w", key, err)
+	}
+	if p.ChainID != c.ChainID {
+		return nil, fmt.Errorf("proof is for chain %s, but client %s tracks chain %s", p.ChainID, clientID, c.ChainID)
+	}
+	if p.Height != proofHeight.RevisionHeight {
+		return nil, fmt.Errorf("proof is for height %d, but proof height is %s", p.Height, proofHeight)
+	}
+	if _, ok := c.consensusTimes[proofHeight.RevisionHeight]; !ok {
+		return nil, fmt.Errorf("client %s has no consensus state at proof height %s", clientID, proofHeight)
+	}
+	if p.Key != string(key) {
+		return nil, fmt.Errorf("proof is for key %s, expected key %s", p.Key, key)
+	}
+	return p.Value, nil
+}
+
+// verifyValue checks that proofBz proves value is stored under key.
+func (s *state) verifyValue(clientID string, proofBz []byte, proofHeight clienttypes.Height, key, value []byte) error {
+	proven, err := s.verifyProof(clientID, proofBz, proofHeight, key)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(proven, value) {
+		return fmt.Errorf("proven value for key %s does not match expected value", key)
+	}
+	return nil
+}
@@ -0,0 +1,698 @@
+package sim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v5/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v5/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	// ProviderType is the provider type of simulated chains.
+	ProviderType = "sim"
+
+	// UnbondingPeriod is the unbonding period reported by simulated chains.
+	UnbondingPeriod = 21 * 24 * time.Hour
+
+	defaultKey = "default"
+)
+
+var (
+	_ provider.ChainProvider  = &Provider{}
+	_ provider.ProviderConfig = ProviderConfig{}
+
+	// ErrNotSupported is returned by Provider methods for functionality that simulated chains do not have,
+	// such as the bank and staking modules.
+	ErrNotSupported = errors.New("not supported by simulated chains")
+
+	defaultChainPrefix = commitmenttypes.NewMerklePrefix([]byte(host.StoreKey))
+)
+
+// ProviderConfig is the ProviderConfig of a simulated chain.
+// Simulated chains only exist in memory, so providers must be created with NewProvider.
+type ProviderConfig struct {
+	ChainID string `json:"chain-id" yaml:"chain-id"`
+}
+
+func (pc ProviderConfig) NewProvider(log *zap.Logger, homepath string, debug bool, chainName string) (provider.ChainProvider, error) {
+	return nil, fmt.Errorf("simulated chain %s cannot be created from config: %w", pc.ChainID, ErrNotSupported)
+}
+
+func (pc ProviderConfig) Validate() error {
+	return nil
+}
+
+// Provider is a ChainProvider for a simulated Chain.
+// It keeps an in-memory keyring, where addresses are derived from the key name.
+type Provider struct {
+	chain *Chain
+
+	mu   sync.Mutex
+	key  string
+	keys map[string]string
+}
+
+// NewProvider returns a Provider for chain, with a "default" key.
+func NewProvider(chain *Chain) *Provider {
+	p := &Provider{
+		chain: chain,
+		key:   defaultKey,
+		keys:  make(map[string]string),
+	}
+	_, _ = p.AddKey(defaultKey, 0)
+	return p
+}
+
+// Chain returns the simulated chain of the provider.
+func (p *Provider) Chain() *Chain {
+	return p.chain
+}
+
+func (p *Provider) Init() error {
+	return nil
+}
+
+func (p *Provider) ChainName() string {
+	return p.chain.chainID
+}
+
+func (p *Provider) ChainId() string {
+	return p.chain.chainID
+}
+
+func (p *Provider) Type() string {
+	return ProviderType
+}
+
+func (p *Provider) ProviderConfig() provider.ProviderConfig {
+	return ProviderConfig{ChainID: p.chain.chainID}
+}
+
+func (p *Provider) CommitmentPrefix() commitmenttypes.MerklePrefix {
+	return defaultChainPrefix
+}
+
+func (p *Provider) Key() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.key
+}
+
+// Address returns the address of the configured key.
+func (p *Provider) Address() (string, error) {
+	return p.ShowAddress(p.Key())
+}
+
+func (p *Provider) Timeout() string {
+	return "10s"
+}
+
+// TrustingPeriod returns 85% of the unbonding period, the same as cosmos chains.
+func (p *Provider) TrustingPeriod(ctx context.Context) (time.Duration, error) {
+	return UnbondingPeriod / 100 * 85, nil
+}
+
+func (p *Provider) WaitForNBlocks(ctx context.Context, n int64) error {
+	target := p.chain.LatestHeight() + uint64(n)
+	for {
+		if p.chain.LatestHeight() >= target {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.chain.blockTime / 2):
+		}
+	}
+}
+
+func (p *Provider) Sprint(toPrint proto.Message) (string, error) {
+	out, err := json.Marshal(toPrint)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// [Begin] KeyProvider
+
+func (p *Provider) CreateKeystore(path string) error {
+	return nil
+}
+
+func (p *Provider) KeystoreCreated(path string) bool {
+	return true
+}
+
+func (p *Provider) AddKey(name string, coinType uint32) (*provider.KeyOutput, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.keys[name]; ok {
+		return nil, fmt.Errorf("key already exists: %s", name)
+	}
+	hash := sha256.Sum256([]byte(p.chain.chainID + "/" + name))
+	address := fmt.Sprintf("sim1%x", hash[:20])
+	p.keys[name] = address
+	return &provider.KeyOutput{Address: address}, nil
+}
+
+func (p *Provider) RestoreKey(name, mnemonic string, coinType uint32) (string, error) {
+	ko, err := p.AddKey(name, coinType)
+	if err != nil {
+		return "", err
+	}
+	return ko.Address, nil
+}
+
+func (p *Provider) ShowAddress(name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	address, ok := p.keys[name]
+	if !ok {
+		return "", fmt.Errorf("key not found: %s", name)
+	}
+	return address, nil
+}
+
+func (p *Provider) ListAddresses() (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]string, len(p.keys))
+	for name, address := range p.keys {
+		out[name] = address
+	}
+	return out, nil
+}
+
+func (p *Provider) DeleteKey(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.keys[name]; !ok {
+		return fmt.Errorf("key not found: %s", name)
+	}
+	delete(p.keys, name)
+	return nil
+}
+
+func (p *Provider) KeyExists(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.keys[name]
+	return ok
+}
+
+func (p *Provider) ExportPrivKeyArmor(keyName string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (p *Provider) ImportPrivKeyArmor(name, armor, passphrase string) (string, error) {
+	return "", ErrNotSupported
+}
+
+func (p *Provider) ImportPrivKeyHex(name, hexKey string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// [End] KeyProvider
+
+// [Begin] QueryProvider
+
+func (p *Provider) BlockTime(ctx context.Context, height int64) (time.Time, error) {
+	b, err := p.chain.block(uint64(height))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return b.header.Time, nil
+}
+
+func (p *Provider) QueryTx(ctx context.Context, hashHex string) (*provider.RelayerTxResponse, error) {
+	tx, err := p.chain.tx(hashHex)
+	if err != nil {
+		return nil, err
+	}
+	return txResponse(tx), nil
+}
+
+func (p *Provider) QueryTxs(ctx context.Context, page, limit int, events []string) ([]*provider.RelayerTxResponse, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryLatestHeight(ctx context.Context) (int64, error) {
+	return int64(p.chain.LatestHeight()), nil
+}
+
+func (p *Provider) QueryIBCHeader(ctx context.Context, h int64) (provider.IBCHeader, error) {
+	if h <= 0 {
+		return nil, fmt.Errorf("invalid height: %d", h)
+	}
+	b, err := p.chain.block(uint64(h))
+	if err != nil {
+		return nil, err
+	}
+	return b.header, nil
+}
+
+func (p *Provider) QuerySendPacket(ctx context.Context, srcChanID, srcPortID string, sequence uint64) (provider.PacketInfo, error) {
+	return p.chain.findPacketEvent(chantypes.EventTypeSendPacket, func(pi provider.PacketInfo) bool {
+		return pi.SourceChannel == srcChanID && pi.SourcePort == srcPortID && pi.Sequence == sequence
+	})
+}
+
+func (p *Provider) QueryRecvPacket(ctx context.Context, dstChanID, dstPortID string, sequence uint64) (provider.PacketInfo, error) {
+	return p.chain.findPacketEvent(chantypes.EventTypeWriteAck, func(pi provider.PacketInfo) bool {
+		return pi.DestChannel == dstChanID && pi.DestPort == dstPortID && pi.Sequence == sequence
+	})
+}
+
+func (p *Provider) QueryBalance(ctx context.Context, keyName string) (sdk.Coins, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryBalanceWithAddress(ctx context.Context, addr string) (sdk.Coins, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryUnbondingPeriod(ctx context.Context) (time.Duration, error) {
+	return UnbondingPeriod, nil
+}
+
+// tmClientState returns the client state of a simulated client as a tendermint client state.
+func tmClientState(c *client) *tmclient.ClientState {
+	return &tmclient.ClientState{
+		ChainId:         c.ChainID,
+		TrustLevel:      tmclient.DefaultTrustLevel,
+		TrustingPeriod:  c.TrustingPeriod,
+		UnbondingPeriod: UnbondingPeriod,
+		LatestHeight:    c.LatestHeight,
+	}
+}
+
+func (p *Provider) QueryClientState(ctx context.Context, height int64, clientid string) (ibcexported.ClientState, error) {
+	s, _, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.client(clientid)
+	if err != nil {
+		return nil, err
+	}
+	return tmClientState(c), nil
+}
+
+func (p *Provider) QueryClientStateResponse(ctx context.Context, height int64, srcClientId string) (*clienttypes.QueryClientStateResponse, error) {
+	cs, err := p.QueryClientState(ctx, height, srcClientId)
+	if err != nil {
+		return nil, err
+	}
+	anyClientState, err := clienttypes.PackClientState(cs)
+	if err != nil {
+		return nil, err
+	}
+	_, proofBz, proofHeight, err := p.chain.proof(uint64(height), host.FullClientStateKey(srcClientId))
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryClientStateResponse{
+		ClientState: anyClientState,
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryClientConsensusState(ctx context.Context, chainHeight int64, clientid string, clientHeight ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	s, _, err := p.chain.stateAt(uint64(chainHeight))
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.client(clientid)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := c.consensusTimes[clientHeight.GetRevisionHeight()]
+	if !ok {
+		return nil, fmt.Errorf("client %s has no consensus state at height %s", clientid, clientHeight)
+	}
+	anyConsensusState, err := clienttypes.PackConsensusState(Header{
+		ChainID:     c.ChainID,
+		BlockHeight: clientHeight.GetRevisionHeight(),
+		Time:        t,
+	}.ConsensusState())
+	if err != nil {
+		return nil, err
+	}
+	_, proofBz, proofHeight, err := p.chain.proof(uint64(chainHeight), host.FullConsensusStateKey(clientid, clientHeight))
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryConsensusStateResponse{
+		ConsensusState: anyConsensusState,
+		Proof:          proofBz,
+		ProofHeight:    proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryUpgradedClient(ctx context.Context, height int64) (*clienttypes.QueryClientStateResponse, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryUpgradedConsState(ctx context.Context, height int64) (*clienttypes.QueryConsensusStateResponse, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryConsensusState(ctx context.Context, height int64) (ibcexported.ConsensusState, int64, error) {
+	b, err := p.chain.block(uint64(height))
+	if err != nil {
+		return nil, 0, err
+	}
+	return b.header.ConsensusState(), int64(b.header.BlockHeight), nil
+}
+
+func (p *Provider) QueryClients(ctx context.Context) (clienttypes.IdentifiedClientStates, error) {
+	s, _, err := p.chain.stateAt(0)
+	if err != nil {
+		return nil, err
+	}
+	var clients clienttypes.IdentifiedClientStates
+	for id, c := range s.clients {
+		clients = append(clients, clienttypes.NewIdentifiedClientState(id, tmClientState(c)))
+	}
+	sort.Sort(clients)
+	return clients, nil
+}
+
+func (p *Provider) QueryConnection(ctx context.Context, height int64, connectionid string) (*conntypes.QueryConnectionResponse, error) {
+	s, h, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	conn, ok := s.connection(connectionid)
+	if !ok {
+		return nil, fmt.Errorf("connection not found: %s", connectionid)
+	}
+	_, proofBz, proofHeight, err := p.chain.proof(h, host.ConnectionKey(connectionid))
+	if err != nil {
+		return nil, err
+	}
+	return &conntypes.QueryConnectionResponse{
+		Connection:  &conn,
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryConnections(ctx context.Context) ([]*conntypes.IdentifiedConnection, error) {
+	s, _, err := p.chain.stateAt(0)
+	if err != nil {
+		return nil, err
+	}
+	var conns []*conntypes.IdentifiedConnection
+	for _, id := range s.connectionIDs() {
+		conn, _ := s.connection(id)
+		ic := conntypes.NewIdentifiedConnection(id, conn)
+		conns = append(conns, &ic)
+	}
+	return conns, nil
+}
+
+func (p *Provider) QueryConnectionsUsingClient(ctx context.Context, height int64, clientid string) (*conntypes.QueryConnectionsResponse, error) {
+	s, h, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	res := &conntypes.QueryConnectionsResponse{Height: p.chain.height(h)}
+	for _, id := range s.connectionIDs() {
+		conn, _ := s.connection(id)
+		if conn.ClientId != clientid {
+			continue
+		}
+		ic := conntypes.NewIdentifiedConnection(id, conn)
+		res.Connections = append(res.Connections, &ic)
+	}
+	return res, nil
+}
+
+// QueryMaxExpectedTimePerBlock returns the block time of the simulated chain.
+func (p *Provider) QueryMaxExpectedTimePerBlock(ctx context.Context) (time.Duration, error) {
+	return p.chain.blockTime, nil
+}
+
+func (p *Provider) GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (
+	clientState ibcexported.ClientState,
+	clientStateProof []byte,
+	consensusProof []byte,
+	connectionProof []byte,
+	connectionProofHeight ibcexported.Height,
+	err error,
+) {
+	clientState, err = p.QueryClientState(ctx, height, clientId)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	value, connectionProof, proofHeight, err := p.chain.proof(uint64(height), host.ConnectionKey(connId))
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("connection not found: %s", connId)
+	}
+	// Client and consensus state proofs are not verified by simulated chains.
+	return clientState, nil, nil, connectionProof, proofHeight, nil
+}
+
+func (p *Provider) QueryChannel(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryChannelResponse, error) {
+	s, h, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	ch, ok := s.channel(portid, channelid)
+	if !ok {
+		return nil, fmt.Errorf("channel not found: %s/%s", portid, channelid)
+	}
+	_, proofBz, proofHeight, err := p.chain.proof(h, host.ChannelKey(portid, channelid))
+	if err != nil {
+		return nil, err
+	}
+	return &chantypes.QueryChannelResponse{
+		Channel:     &ch,
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryChannelClient(ctx context.Context, height int64, channelid, portid string) (*clienttypes.IdentifiedClientState, error) {
+	s, _, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	ch, ok := s.channel(portid, channelid)
+	if !ok {
+		return nil, fmt.Errorf("channel not found: %s/%s", portid, channelid)
+	}
+	if len(ch.ConnectionHops) != 1 {
+		return nil, fmt.Errorf("channel %s/%s has %d connection hops", portid, channelid, len(ch.ConnectionHops))
+	}
+	conn, ok := s.connection(ch.ConnectionHops[0])
+	if !ok {
+		return nil, fmt.Errorf("connection not found: %s", ch.ConnectionHops[0])
+	}
+	c, err := s.client(conn.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	ics := clienttypes.NewIdentifiedClientState(conn.ClientId, tmClientState(c))
+	return &ics, nil
+}
+
+func (p *Provider) queryChannels(height int64, filter func(chantypes.Channel) bool) ([]*chantypes.IdentifiedChannel, error) {
+	s, _, err := p.chain.stateAt(uint64(height))
+	if err != nil {
+		return nil, err
+	}
+	var channels []*chantypes.IdentifiedChannel
+	for _, id := range s.channelIDs() {
+		ch, _ := s.channel(id.portID, id.channelID)
+		if !filter(ch) {
+			continue
+		}
+		ic := chantypes.NewIdentifiedChannel(id.portID, id.channelID, ch)
+		channels = append(channels, &ic)
+	}
+	return channels, nil
+}
+
+func (p *Provider) QueryConnectionChannels(ctx context.Context, height int64, connectionid string) ([]*chantypes.IdentifiedChannel, error) {
+	return p.queryChannels(height, func(ch chantypes.Channel) bool {
+		return len(ch.ConnectionHops) > 0 && ch.ConnectionHops[0] == connectionid
+	})
+}
+
+func (p *Provider) QueryChannels(ctx context.Context) ([]*chantypes.IdentifiedChannel, error) {
+	return p.queryChannels(0, func(chantypes.Channel) bool { return true })
+}
+
+func (p *Provider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (*chantypes.QueryPacketCommitmentsResponse, error) {
+	s, h, err := p.chain.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	res := &chantypes.QueryPacketCommitmentsResponse{Height: p.chain.height(h)}
+	for _, seq := range s.packetSequences(host.PacketCommitmentKey, portid, channelid) {
+		ps := chantypes.NewPacketState(portid, channelid, seq, s.get(host.PacketCommitmentKey(portid, channelid, seq)))
+		res.Commitments = append(res.Commitments, &ps)
+	}
+	return res, nil
+}
+
+func (p *Provider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string) ([]*chantypes.PacketState, error) {
+	s, _, err := p.chain.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	var acks []*chantypes.PacketState
+	for _, seq := range s.packetSequences(host.PacketAcknowledgementKey, portid, channelid) {
+		ps := chantypes.NewPacketState(portid, channelid, seq, s.get(host.PacketAcknowledgementKey(portid, channelid, seq)))
+		acks = append(acks, &ps)
+	}
+	return acks, nil
+}
+
+// QueryUnreceivedPackets returns the sequences which have not been received on the given receiving channel.
+func (p *Provider) QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	s, _, err := p.chain.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	ch, ok := s.channel(portid, channelid)
+	if !ok {
+		return nil, fmt.Errorf("channel not found: %s/%s", portid, channelid)
+	}
+	nextSeqRecv := s.sequence(host.NextSequenceRecvKey(portid, channelid))
+	var unreceived []uint64
+	for _, seq := range seqs {
+		if ch.Ordering == chantypes.ORDERED {
+			if seq >= nextSeqRecv {
+				unreceived = append(unreceived, seq)
+			}
+			continue
+		}
+		if s.get(host.PacketReceiptKey(portid, channelid, seq)) == nil {
+			unreceived = append(unreceived, seq)
+		}
+	}
+	return unreceived, nil
+}
+
+// QueryUnreceivedAcknowledgements returns the sequences of packets sent on the given channel which still have a commitment,
+// i.e. which have not been acknowledged.
+func (p *Provider) QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	s, _, err := p.chain.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	var unreceived []uint64
+	for _, seq := range seqs {
+		if s.get(host.PacketCommitmentKey(portid, channelid, seq)) != nil {
+			unreceived = append(unreceived, seq)
+		}
+	}
+	return unreceived, nil
+}
+
+func (p *Provider) QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryNextSequenceReceiveResponse, error) {
+	key := host.NextSequenceRecvKey(portid, channelid)
+	value, proofBz, proofHeight, err := p.chain.proof(uint64(height), key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("channel not found: %s/%s", portid, channelid)
+	}
+	s, _, err := p.chain.stateAt(proofHeight.RevisionHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &chantypes.QueryNextSequenceReceiveResponse{
+		NextSequenceReceive: s.sequence(key),
+		Proof:               proofBz,
+		ProofHeight:         proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (uint64, error) {
+	key := host.NextSequenceAckKey(portid, channelid)
+	value, _, proofHeight, err := p.chain.proof(uint64(height), key)
+	if err != nil {
+		return 0, err
+	}
+	if value == nil {
+		return 0, fmt.Errorf("channel not found: %s/%s", portid, channelid)
+	}
+	s, _, err := p.chain.stateAt(proofHeight.RevisionHeight)
+	if err != nil {
+		return 0, err
+	}
+	return s.sequence(key), nil
+}
+
+func (p *Provider) QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketCommitmentResponse, error) {
+	value, proofBz, proofHeight, err := p.chain.proof(uint64(height), host.PacketCommitmentKey(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return &chantypes.QueryPacketCommitmentResponse{
+		Commitment:  value,
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryPacketAcknowledgement(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketAcknowledgementResponse, error) {
+	value, proofBz, proofHeight, err := p.chain.proof(uint64(height), host.PacketAcknowledgementKey(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return &chantypes.QueryPacketAcknowledgementResponse{
+		Acknowledgement: value,
+		Proof:           proofBz,
+		ProofHeight:     proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryPacketReceipt(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketReceiptResponse, error) {
+	value, proofBz, proofHeight, err := p.chain.proof(uint64(height), host.PacketReceiptKey(portid, channelid, seq))
+	if err != nil {
+		return nil, err
+	}
+	return &chantypes.QueryPacketReceiptResponse{
+		Received:    value != nil,
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) QueryDenomTrace(ctx context.Context, denom string) (*transfertypes.DenomTrace, error) {
+	return nil, ErrNotSupported
+}
+
+func (p *Provider) QueryDenomTraces(ctx context.Context, offset, limit uint64, height int64) ([]transfertypes.DenomTrace, error) {
+	return nil, ErrNotSupported
+}
+
+// [End] QueryProvider
@@ -0,0 +1,234 @@
+package sim
+
+import (
+	"context"
+	"time"
+
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+const inSyncNumBlocksThreshold = 2
+
+var _ processor.ChainProcessor = &SimChainProcessor{}
+
+// SimChainProcessor is the ChainProcessor of a simulated chain. It reads committed blocks
+// directly from the Chain, instead of querying an RPC endpoint, and otherwise tracks
+// state and publishes IBC messages to its PathProcessors the same way CosmosChainProcessor does.
+type SimChainProcessor struct {
+	log *zap.Logger
+
+	chainProvider *Provider
+
+	pathProcessors processor.PathProcessors
+
+	// indicates whether queries are in sync with latest height of the chain
+	inSync bool
+
+	// highest block
+	latestBlock provider.LatestBlock
+
+	// holds open state for known connections
+	connectionStateCache processor.ConnectionStateCache
+
+	// holds open state for known channels
+	channelStateCache processor.ChannelStateCache
+
+	// map of connection ID to client ID
+	connectionClients map[string]string
+
+	// map of channel ID to connection ID
+	channelConnections map[string]string
+
+	health *processor.Health
+}
+
+func NewSimChainProcessor(log *zap.Logger, provider *Provider) *SimChainProcessor {
+	return &SimChainProcessor{
+		log:                  log.With(zap.String("chain_name", provider.ChainName()), zap.String("chain_id", provider.ChainId())),
+		chainProvider:        provider,
+		connectionStateCache: make(processor.ConnectionStateCache),
+		channelStateCache:    make(processor.ChannelStateCache),
+		connectionClients:    make(map[string]string),
+		channelConnections:   make(map[string]string),
+	}
+}
+
+// Provider returns the ChainProvider, which provides the methods for querying, assembling IBC messages, and sending transactions.
+func (scp *SimChainProcessor) Provider() provider.ChainProvider {
+	return scp.chainProvider
+}
+
+// SetHealth sets the tracker that query loop progress is reported to.
+func (scp *SimChainProcessor) SetHealth(h *processor.Health) {
+	scp.health = h
+	h.RegisterChainProcessor(scp.chainProvider.ChainId())
+}
+
+// Set the PathProcessors that this ChainProcessor should publish relevant IBC events to.
+// ChainProcessors need reference to their PathProcessors and vice-versa, handled by EventProcessorBuilder.Build().
+func (scp *SimChainProcessor) SetPathProcessors(pathProcessors processor.PathProcessors) {
+	scp.pathProcessors = pathProcessors
+}
+
+// queryCyclePersistence hold the variables that should be retained across queryCycles.
+type queryCyclePersistence struct {
+	latestHeight       uint64
+	latestQueriedBlock uint64
+}
+
+// Run starts the query loop for the chain which will gather applicable ibc messages and push events out to the relevant PathProcessors.
+// The query loop runs once per block time of the simulated chain.
+func (scp *SimChainProcessor) Run(ctx context.Context, initialBlockHistory uint64) error {
+	persistence := queryCyclePersistence{
+		latestHeight: scp.chainProvider.chain.LatestHeight(),
+	}
+	if persistence.latestHeight > initialBlockHistory {
+		persistence.latestQueriedBlock = persistence.latestHeight - initialBlockHistory
+	}
+
+	if err := scp.initializeState(); err != nil {
+		return err
+	}
+
+	scp.log.Debug("Entering main query loop")
+
+	ticker := time.NewTicker(scp.chainProvider.chain.blockTime)
+	defer ticker.Stop()
+
+	for {
+		scp.queryCycle(&persistence)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// initializeState will bootstrap the connectionStateCache and channelStateCache with the open connection and channel state.
+func (scp *SimChainProcessor) initializeState() error {
+	s, _, err := scp.chainProvider.chain.stateAt(0)
+	if err != nil {
+		return err
+	}
+	for _, id := range s.connectionIDs() {
+		c, _ := s.connection(id)
+		scp.connectionClients[id] = c.ClientId
+		scp.connectionStateCache[processor.ConnectionKey{
+			ConnectionID:         id,
+			ClientID:             c.ClientId,
+			CounterpartyConnID:   c.Counterparty.ConnectionId,
+			CounterpartyClientID: c.Counterparty.ClientId,
+		}] = c.State == conntypes.OPEN
+	}
+	for _, id := range s.channelIDs() {
+		ch, _ := s.channel(id.portID, id.channelID)
+		scp.channelConnections[id.channelID] = ch.ConnectionHops[0]
+		scp.channelStateCache[processor.ChannelKey{
+			ChannelID:             id.channelID,
+			PortID:                id.portID,
+			CounterpartyChannelID: ch.Counterparty.ChannelId,
+			CounterpartyPortID:    ch.Counterparty.PortId,
+		}] = ch.State == chantypes.OPEN
+	}
+	return nil
+}
+
+func (scp *SimChainProcessor) queryCycle(persistence *queryCyclePersistence) {
+	chain := scp.chainProvider.chain
+	persistence.latestHeight = chain.LatestHeight()
+
+	// used at the end of the cycle to send signal to path processors to start processing if both chains are in sync and no new messages came in this cycle
+	firstTimeInSync := false
+
+	if !scp.inSync && persistence.latestHeight-persistence.latestQueriedBlock < inSyncNumBlocksThreshold {
+		scp.inSync = true
+		firstTimeInSync = true
+		scp.log.Info("Chain is in sync")
+	}
+
+	ibcMessagesCache := processor.NewIBCMessagesCache()
+
+	ibcHeaderCache := make(processor.IBCHeaderCache)
+
+	var latestHeader Header
+
+	chainID := chain.ChainID()
+
+	newLatestQueriedBlock := persistence.latestQueriedBlock
+
+	for h := persistence.latestQueriedBlock + 1; h <= persistence.latestHeight; h++ {
+		b, err := chain.block(h)
+		if err != nil {
+			scp.log.Warn("Error querying block data", zap.Error(err))
+			break
+		}
+		newLatestQueriedBlock = h
+		scp.health.ChainProcessorAdvanced(chainID, int64(persistence.latestHeight), int64(newLatestQueriedBlock))
+		latestHeader = b.header
+		scp.latestBlock = provider.LatestBlock{
+			Height: h,
+			Time:   b.header.Time,
+		}
+		ibcHeaderCache[h] = b.header
+
+		for _, tx := range b.txs {
+			for _, e := range tx.events {
+				scp.handleEvent(e, ibcMessagesCache)
+			}
+		}
+	}
+
+	if newLatestQueriedBlock == persistence.latestQueriedBlock {
+		// Without new blocks, only a processor caught up to the latest height is making progress.
+		if newLatestQueriedBlock >= persistence.latestHeight {
+			scp.health.ChainProcessorAdvanced(chainID, int64(persistence.latestHeight), int64(newLatestQueriedBlock))
+		}
+		if firstTimeInSync {
+			for _, pp := range scp.pathProcessors {
+				pp.ProcessBacklogIfReady()
+			}
+		}
+		return
+	}
+	// Heights after a failed block query are queried again in the next cycle.
+	persistence.latestQueriedBlock = newLatestQueriedBlock
+
+	s, _, err := chain.stateAt(newLatestQueriedBlock)
+	if err != nil {
+		scp.log.Error("Error fetching chain state", zap.Error(err))
+		return
+	}
+
+	for _, pp := range scp.pathProcessors {
+		clientID := pp.RelevantClientID(chainID)
+		c, err := s.client(clientID)
+		if err != nil {
+			scp.log.Error("Error fetching client state",
+				zap.String("client_id", clientID),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		pp.HandleNewData(chainID, processor.ChainProcessorCacheData{
+			LatestBlock:      scp.latestBlock,
+			LatestHeader:     latestHeader,
+			IBCMessagesCache: ibcMessagesCache.Clone(),
+			InSync:           scp.inSync,
+			ClientState: provider.ClientState{
+				ClientID:        clientID,
+				ConsensusHeight: c.LatestHeight,
+				TrustingPeriod:  c.TrustingPeriod,
+				ConsensusTime:   c.latestConsensusTime(),
+			},
+			ConnectionStateCache: scp.connectionStateCache.FilterForClient(clientID),
+			ChannelStateCache:    scp.channelStateCache.FilterForClient(clientID, scp.channelConnections, scp.connectionClients),
+			IBCHeaderCache:       ibcHeaderCache.Clone(),
+		})
+	}
+}
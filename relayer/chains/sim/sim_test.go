@@ -0,0 +1,395 @@
+package sim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/sim"
+	"github.com/cosmos/relayer/v2/relayer/chaos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const (
+	simPathName = "simpath"
+	simPortID   = "transfer"
+	blockTime   = 20 * time.Millisecond
+)
+
+// simPath is a pair of running simulated chains with clients of each other.
+type simPath struct {
+	t *testing.T
+
+	chain1, chain2 *sim.Chain
+	prov1, prov2   *sim.Provider
+	pathEnd1       processor.PathEnd
+	pathEnd2       processor.PathEnd
+
+	// delayPeriod is the delay period of the connection opened by openChannel.
+	delayPeriod time.Duration
+
+	// faults injected into the calls to the providers by chain ID, if any, and where they are logged.
+	faults   map[string]chaos.Faults
+	faultLog *zap.Logger
+
+	// health the processors report to, if any.
+	health *processor.Health
+}
+
+func newSimPath(t *testing.T, ctx context.Context) *simPath {
+	chain1 := sim.NewChain("sim-1", blockTime)
+	chain2 := sim.NewChain("sim-2", blockTime)
+	go func() { _ = chain1.Run(ctx) }()
+	go func() { _ = chain2.Run(ctx) }()
+
+	clientID1, err := chain1.CreateClient(ctx, chain2, time.Hour)
+	require.NoError(t, err)
+	clientID2, err := chain2.CreateClient(ctx, chain1, time.Hour)
+	require.NoError(t, err)
+
+	return &simPath{
+		t:        t,
+		chain1:   chain1,
+		chain2:   chain2,
+		prov1:    sim.NewProvider(chain1),
+		prov2:    sim.NewProvider(chain2),
+		pathEnd1: processor.PathEnd{PathName: simPathName, ChainID: chain1.ChainID(), ClientID: clientID1},
+		pathEnd2: processor.PathEnd{PathName: simPathName, ChainID: chain2.ChainID(), ClientID: clientID2},
+	}
+}
+
+// run runs an event processor for the path until the message lifecycle terminates or ctx is done,
+// starting initialBlockHistory blocks in the past.
+func (p *simPath) run(ctx context.Context, initialBlockHistory uint64, messageLifecycle processor.MessageLifecycle) *processor.PathProcessor {
+	log := zaptest.NewLogger(p.t)
+	pp := processor.NewPathProcessor(log, p.pathEnd1, p.pathEnd2, nil, "", 6*time.Hour)
+	if p.health != nil {
+		pp.SetHealth(p.health)
+	}
+	err := processor.NewEventProcessor().
+		WithChainProcessors(
+			p.chainProcessor(log, p.prov1),
+			p.chainProcessor(log, p.prov2),
+		).
+		WithInitialBlockHistory(initialBlockHistory).
+		WithPathProcessors(pp).
+		WithMessageLifecycle(messageLifecycle).
+		Build().
+		Run(ctx)
+	require.NoError(p.t, err)
+	return pp
+}
+
+// chainProcessor returns the chain processor for prov, injecting the faults configured for its chain.
+func (p *simPath) chainProcessor(log *zap.Logger, prov *sim.Provider) processor.ChainProcessor {
+	scp := sim.NewSimChainProcessor(log, prov)
+	scp.SetHealth(p.health)
+	f, ok := p.faults[prov.ChainId()]
+	if !ok {
+		return scp
+	}
+	// seed 2 drops the first broadcast to sim-2, fails the second with a sequence mismatch and duplicates the third.
+	cp, err := chaos.NewProvider(p.faultLog, prov, f, 2)
+	require.NoError(p.t, err)
+	return chaos.NewChainProcessor(scp, cp)
+}
+
+// openChannel runs the connection and channel handshakes, and returns the channel on chain1.
+func (p *simPath) openChannel(ctx context.Context, order chantypes.Order) processor.ChannelKey {
+	handshakeCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	p.run(handshakeCtx, 0, &processor.ConnectionMessageLifecycle{
+		Initial: &processor.ConnectionMessage{
+			ChainID:   p.pathEnd1.ChainID,
+			EventType: conntypes.EventTypeConnectionOpenInit,
+			Info: provider.ConnectionInfo{
+				ClientID:                     p.pathEnd1.ClientID,
+				CounterpartyClientID:         p.pathEnd2.ClientID,
+				CounterpartyCommitmentPrefix: p.prov2.CommitmentPrefix(),
+				DelayPeriod:                  uint64(p.delayPeriod),
+			},
+		},
+		Termination: &processor.ConnectionMessage{
+			ChainID:   p.pathEnd2.ChainID,
+			EventType: conntypes.EventTypeConnectionOpenConfirm,
+			Info: provider.ConnectionInfo{
+				ClientID:             p.pathEnd2.ClientID,
+				CounterpartyClientID: p.pathEnd1.ClientID,
+			},
+		},
+	})
+	require.NoError(p.t, handshakeCtx.Err(), "connection handshake did not complete")
+
+	conns, err := p.prov1.QueryConnections(ctx)
+	require.NoError(p.t, err)
+	require.Len(p.t, conns, 1)
+	require.Equal(p.t, conntypes.OPEN, conns[0].State)
+	require.Equal(p.t, uint64(p.delayPeriod), conns[0].DelayPeriod)
+
+	handshakeCtx, cancel = context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	p.run(handshakeCtx, 0, &processor.ChannelMessageLifecycle{
+		Initial: &processor.ChannelMessage{
+			ChainID:   p.pathEnd1.ChainID,
+			EventType: chantypes.EventTypeChannelOpenInit,
+			Info: provider.ChannelInfo{
+				PortID:             simPortID,
+				CounterpartyPortID: simPortID,
+				ConnID:             conns[0].Id,
+				Version:            "ics20-1",
+				Order:              order,
+			},
+		},
+		Termination: &processor.ChannelMessage{
+			ChainID:   p.pathEnd2.ChainID,
+			EventType: chantypes.EventTypeChannelOpenConfirm,
+			Info: provider.ChannelInfo{
+				PortID:             simPortID,
+				CounterpartyPortID: simPortID,
+			},
+		},
+	})
+	require.NoError(p.t, handshakeCtx.Err(), "channel handshake did not complete")
+
+	channels, err := p.prov1.QueryChannels(ctx)
+	require.NoError(p.t, err)
+	require.Len(p.t, channels, 1)
+	require.Equal(p.t, chantypes.OPEN, channels[0].State)
+	require.Equal(p.t, order, channels[0].Ordering)
+
+	return processor.ChannelKey{
+		ChannelID:             channels[0].ChannelId,
+		PortID:                channels[0].PortId,
+		CounterpartyChannelID: channels[0].Counterparty.ChannelId,
+		CounterpartyPortID:    channels[0].Counterparty.PortId,
+	}
+}
+
+// relayUntil relays packets until the given packet event is observed on chain1 for seq.
+// Processing starts from the first block, so that packets sent before are relayed as well.
+func (p *simPath) relayUntil(ctx context.Context, k processor.ChannelKey, eventType string, seq uint64) {
+	p.relayFromUntil(ctx, p.chain1.LatestHeight(), k, eventType, seq)
+}
+
+// relayFromUntil relays packets until the given packet event is observed on chain1 for seq,
+// starting initialBlockHistory blocks in the past.
+func (p *simPath) relayFromUntil(ctx context.Context, initialBlockHistory uint64, k processor.ChannelKey, eventType string, seq uint64) {
+	relayCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	p.run(relayCtx, initialBlockHistory, &processor.PacketMessageLifecycle{
+		Termination: &processor.PacketMessage{
+			ChainID:   p.pathEnd1.ChainID,
+			EventType: eventType,
+			Info: provider.PacketInfo{
+				Sequence:      seq,
+				SourcePort:    k.PortID,
+				SourceChannel: k.ChannelID,
+				DestPort:      k.CounterpartyPortID,
+				DestChannel:   k.CounterpartyChannelID,
+			},
+		},
+	})
+	require.NoError(p.t, relayCtx.Err(), "%s was not observed for sequence %d", eventType, seq)
+}
+
+func TestSimHealth(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	p.health = processor.NewHealth()
+	p.openChannel(ctx, chantypes.UNORDERED)
+
+	status := p.health.Status(time.Minute)
+	require.True(t, status.Live)
+	require.True(t, status.Ready)
+	require.Len(t, status.Chains, 2)
+	for _, c := range status.Chains {
+		require.True(t, c.Ready, "chain %s did not report progress", c.ChainID)
+		require.NotZero(t, c.LatestQueriedHeight)
+	}
+	require.Len(t, status.Paths, 1)
+}
+
+func TestSimRelayPacket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	k := p.openChannel(ctx, chantypes.UNORDERED)
+
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), 1_000_000), 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), seq)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeAcknowledgePacket, seq)
+
+	receipt, err := p.prov2.QueryPacketReceipt(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID, seq)
+	require.NoError(t, err)
+	require.True(t, receipt.Received)
+
+	commitments, err := p.prov1.QueryPacketCommitments(ctx, 0, k.ChannelID, k.PortID)
+	require.NoError(t, err)
+	require.Empty(t, commitments.Commitments)
+}
+
+func TestSimRelayPacketWithConnectionDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	// packets can only be proven at consensus states added at least 10 blocks and 200ms earlier,
+	// so they are only relayed if the path processor waits for the delay period to pass.
+	p.delayPeriod = 10 * blockTime
+	k := p.openChannel(ctx, chantypes.UNORDERED)
+
+	conns, err := p.prov2.QueryConnections(ctx)
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	require.Equal(t, uint64(p.delayPeriod), conns[0].DelayPeriod)
+
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), 1_000_000), 0)
+	require.NoError(t, err)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeAcknowledgePacket, seq)
+
+	receipt, err := p.prov2.QueryPacketReceipt(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID, seq)
+	require.NoError(t, err)
+	require.True(t, receipt.Received)
+}
+
+func TestSimTimeoutPacketWithConnectionDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	p.delayPeriod = 10 * blockTime
+	k := p.openChannel(ctx, chantypes.UNORDERED)
+
+	timeoutHeight := clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), p.chain2.LatestHeight()+5)
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), timeoutHeight, 0)
+	require.NoError(t, err)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeTimeoutPacket, seq)
+
+	receipt, err := p.prov2.QueryPacketReceipt(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID, seq)
+	require.NoError(t, err)
+	require.False(t, receipt.Received)
+}
+
+func TestSimRelayPacketWithFaults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	k := p.openChannel(ctx, chantypes.UNORDERED)
+
+	// broadcasts to chain2 are dropped, duplicated and rejected, so the packet is only
+	// relayed if the path processor retries messages that never made it to the chain.
+	core, logs := observer.New(zap.DebugLevel)
+	p.faultLog = zap.New(core)
+	p.faults = map[string]chaos.Faults{
+		p.chain2.ChainID(): {
+			Latency:                "5ms",
+			DropBroadcastRate:      0.5,
+			DuplicateBroadcastRate: 0.5,
+			SequenceMismatchRate:   0.1,
+		},
+	}
+
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), 1_000_000), 0)
+	require.NoError(t, err)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeAcknowledgePacket, seq)
+
+	receipt, err := p.prov2.QueryPacketReceipt(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID, seq)
+	require.NoError(t, err)
+	require.True(t, receipt.Received)
+
+	require.NotZero(t, logs.FilterMessage("Injected dropped broadcast").Len())
+	require.NotZero(t, logs.FilterMessage("Injected account sequence mismatch").Len())
+}
+
+func TestSimTimeoutPacket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	k := p.openChannel(ctx, chantypes.UNORDERED)
+
+	// a timeout height that chain2 has already reached, so the packet can only time out.
+	timeoutHeight := clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), p.chain2.LatestHeight())
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), timeoutHeight, 0)
+	require.NoError(t, err)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeTimeoutPacket, seq)
+
+	receipt, err := p.prov2.QueryPacketReceipt(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID, seq)
+	require.NoError(t, err)
+	require.False(t, receipt.Received)
+
+	channel, err := p.prov1.QueryChannel(ctx, 0, k.ChannelID, k.PortID)
+	require.NoError(t, err)
+	require.Equal(t, chantypes.OPEN, channel.Channel.State)
+}
+
+func TestSimOrderedRelayPacketsInSequence(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	k := p.openChannel(ctx, chantypes.ORDERED)
+	timeoutHeight := clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), 1_000_000)
+
+	// the first packet is sent before the block history the relayer starts from,
+	// so it is only relayed, before the others, if it is queried from the chain history.
+	_, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("first"), timeoutHeight, 0)
+	require.NoError(t, err)
+	startHeight := p.chain1.LatestHeight() + 1
+	for p.chain1.LatestHeight() < startHeight {
+		time.Sleep(blockTime)
+	}
+
+	var seq uint64
+	for i := 0; i < 3; i++ {
+		seq, err = p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), timeoutHeight, 0)
+		require.NoError(t, err)
+	}
+	require.Equal(t, uint64(4), seq)
+
+	p.relayFromUntil(ctx, p.chain1.LatestHeight()-startHeight, k, chantypes.EventTypeAcknowledgePacket, seq)
+
+	nextSeqRecv, err := p.prov2.QueryNextSeqRecv(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), nextSeqRecv.NextSequenceReceive)
+
+	commitments, err := p.prov1.QueryPacketCommitments(ctx, 0, k.ChannelID, k.PortID)
+	require.NoError(t, err)
+	require.Empty(t, commitments.Commitments)
+}
+
+func TestSimOrderedTimeoutClosesChannel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := newSimPath(t, ctx)
+	k := p.openChannel(ctx, chantypes.ORDERED)
+
+	timeoutHeight := clienttypes.NewHeight(clienttypes.ParseChainID(p.chain2.ChainID()), p.chain2.LatestHeight())
+	seq, err := p.chain1.SendPacket(ctx, k.PortID, k.ChannelID, []byte("hello"), timeoutHeight, 0)
+	require.NoError(t, err)
+
+	p.relayUntil(ctx, k, chantypes.EventTypeTimeoutPacket, seq)
+
+	channel, err := p.prov1.QueryChannel(ctx, 0, k.ChannelID, k.PortID)
+	require.NoError(t, err)
+	require.Equal(t, chantypes.CLOSED, channel.Channel.State)
+}
@@ -0,0 +1,218 @@
+package sim
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v5/modules/core/24-host"
+)
+
+// client is the state of a light client of a counterparty chain.
+// Headers are not verified cryptographically, only structurally.
+type client struct {
+	ChainID        string
+	TrustingPeriod time.Duration
+	LatestHeight   clienttypes.Height
+
+	// consensusTimes holds the timestamp of each consensus state, keyed by revision height.
+	consensusTimes map[uint64]time.Time
+
+	// processed holds the height and time of the block each consensus state was added in,
+	// keyed by revision height, to enforce the delay period of connections.
+	processed map[uint64]processedState
+}
+
+// processedState is the height and time of the block a consensus state was added in.
+type processedState struct {
+	height uint64
+	time   time.Time
+}
+
+func (c *client) clone() *client {
+	cc := *c
+	cc.consensusTimes = make(map[uint64]time.Time, len(c.consensusTimes))
+	for h, t := range c.consensusTimes {
+		cc.consensusTimes[h] = t
+	}
+	cc.processed = make(map[uint64]processedState, len(c.processed))
+	for h, p := range c.processed {
+		cc.processed[h] = p
+	}
+	return &cc
+}
+
+// latestConsensusTime returns the timestamp of the latest consensus state.
+func (c *client) latestConsensusTime() time.Time {
+	return c.consensusTimes[c.LatestHeight.RevisionHeight]
+}
+
+// state is the IBC store of a simulated chain.
+// Connections, channels, sequences, commitments, receipts and acknowledgements are kept
+// in a key value store under their ICS-24 paths so that proofs can be produced for any of them.
+type state struct {
+	clients map[string]*client
+	store   map[string][]byte
+
+	nextClientSeq     uint64
+	nextConnectionSeq uint64
+	nextChannelSeq    uint64
+}
+
+func newState() *state {
+	return &state{
+		clients: make(map[string]*client),
+		store:   make(map[string][]byte),
+	}
+}
+
+func (s *state) clone() *state {
+	cs := &state{
+		clients:           make(map[string]*client, len(s.clients)),
+		store:             make(map[string][]byte, len(s.store)),
+		nextClientSeq:     s.nextClientSeq,
+		nextConnectionSeq: s.nextConnectionSeq,
+		nextChannelSeq:    s.nextChannelSeq,
+	}
+	for id, c := range s.clients {
+		cs.clients[id] = c.clone()
+	}
+	for k, v := range s.store {
+		// values are never mutated in place, so they can be shared.
+		cs.store[k] = v
+	}
+	return cs
+}
+
+func (s *state) get(key []byte) []byte {
+	return s.store[string(key)]
+}
+
+func (s *state) set(key, value []byte) {
+	s.store[string(key)] = value
+}
+
+func (s *state) delete(key []byte) {
+	delete(s.store, string(key))
+}
+
+func (s *state) client(clientID string) (*client, error) {
+	c, ok := s.clients[clientID]
+	if !ok {
+		return nil, fmt.Errorf("client not found: %s", clientID)
+	}
+	return c, nil
+}
+
+func (s *state) connection(connectionID string) (conntypes.ConnectionEnd, bool) {
+	var conn conntypes.ConnectionEnd
+	bz := s.get(host.ConnectionKey(connectionID))
+	if bz == nil {
+		return conn, false
+	}
+	if err := conn.Unmarshal(bz); err != nil {
+		panic(fmt.Errorf("corrupt connection %s in store: %w", connectionID, err))
+	}
+	return conn, true
+}
+
+func (s *state) setConnection(connectionID string, conn conntypes.ConnectionEnd) {
+	bz, err := conn.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	s.set(host.ConnectionKey(connectionID), bz)
+}
+
+func (s *state) connectionIDs() []string {
+	return s.idsWithPrefix(host.KeyConnectionPrefix + "/")
+}
+
+func (s *state) channel(portID, channelID string) (chantypes.Channel, bool) {
+	var ch chantypes.Channel
+	bz := s.get(host.ChannelKey(portID, channelID))
+	if bz == nil {
+		return ch, false
+	}
+	if err := ch.Unmarshal(bz); err != nil {
+		panic(fmt.Errorf("corrupt channel %s/%s in store: %w", portID, channelID, err))
+	}
+	return ch, true
+}
+
+func (s *state) setChannel(portID, channelID string, ch chantypes.Channel) {
+	bz, err := ch.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	s.set(host.ChannelKey(portID, channelID), bz)
+}
+
+// portChannel identifies a channel end on this chain.
+type portChannel struct {
+	portID    string
+	channelID string
+}
+
+func (s *state) channelIDs() []portChannel {
+	var ids []portChannel
+	for _, path := range s.idsWithPrefix(host.KeyChannelEndPrefix + "/" + host.KeyPortPrefix + "/") {
+		// path is "<port>/channels/<channel>"
+		parts := strings.Split(path, "/")
+		if len(parts) != 3 {
+			continue
+		}
+		ids = append(ids, portChannel{portID: parts[0], channelID: parts[2]})
+	}
+	return ids
+}
+
+// idsWithPrefix returns the sorted remainder of all store keys with the given prefix.
+func (s *state) idsWithPrefix(prefix string) []string {
+	var ids []string
+	for k := range s.store {
+		if strings.HasPrefix(k, prefix) {
+			ids = append(ids, strings.TrimPrefix(k, prefix))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *state) sequence(key []byte) uint64 {
+	bz := s.get(key)
+	if bz == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(bz)
+}
+
+func (s *state) setSequence(key []byte, seq uint64) {
+	s.set(key, sequenceBytes(seq))
+}
+
+func sequenceBytes(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
+
+// packetSequences returns the sorted sequences of the packet state stored under the key returned by keyFn.
+func (s *state) packetSequences(keyFn func(portID, channelID string, sequence uint64) []byte, portID, channelID string) []uint64 {
+	prefix := strings.TrimSuffix(string(keyFn(portID, channelID, 0)), "0")
+	var seqs []uint64
+	for k := range s.store {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		var seq uint64
+		if _, err := fmt.Sscan(strings.TrimPrefix(k, prefix), &seq); err == nil {
+			seqs = append(seqs, seq)
+		}
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs
+}
@@ -0,0 +1,579 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v5/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v5/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+// SendMessage attempts to sign, encode & send a RelayerMessage
+// This is used extensively in the relayer as an extension of the Provider interface
+func (p *Provider) SendMessage(ctx context.Context, msg provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	return p.SendMessages(ctx, []provider.RelayerMessage{msg}, memo)
+}
+
+// SendMessages executes msgs in a single transaction on the simulated chain,
+// and returns once the block including it has been committed.
+func (p *Provider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	simMsgs := make([]any, len(msgs))
+	for i, msg := range msgs {
+		m, ok := msg.(Message)
+		if !ok {
+			return nil, false, fmt.Errorf("unsupported message type, expected: sim.Message, actual: %T", msg)
+		}
+		simMsgs[i] = m.Msg
+	}
+	tx, err := p.chain.deliverTx(ctx, simMsgs)
+	if err != nil {
+		return nil, false, err
+	}
+	return txResponse(tx), true, nil
+}
+
+// txResponse converts a transaction result to a RelayerTxResponse, with event attributes
+// named like the ones emitted by ibc-go.
+func txResponse(tx *txResult) *provider.RelayerTxResponse {
+	res := &provider.RelayerTxResponse{
+		Height: int64(tx.height),
+		TxHash: tx.hash,
+	}
+	for _, e := range tx.events {
+		res.Events = append(res.Events, provider.RelayerEvent{
+			EventType:  e.eventType,
+			Attributes: eventAttributes(e.info),
+		})
+	}
+	return res
+}
+
+func eventAttributes(info any) map[string]string {
+	switch info := info.(type) {
+	case clientEvent:
+		return map[string]string{
+			clienttypes.AttributeKeyClientID:        info.clientID,
+			clienttypes.AttributeKeyConsensusHeight: info.consensusHeight.String(),
+		}
+	case provider.ConnectionInfo:
+		return map[string]string{
+			conntypes.AttributeKeyConnectionID:             info.ConnID,
+			conntypes.AttributeKeyClientID:                 info.ClientID,
+			conntypes.AttributeKeyCounterpartyClientID:     info.CounterpartyClientID,
+			conntypes.AttributeKeyCounterpartyConnectionID: info.CounterpartyConnID,
+		}
+	case provider.ChannelInfo:
+		return map[string]string{
+			chantypes.AttributeKeyPortID:             info.PortID,
+			chantypes.AttributeKeyChannelID:          info.ChannelID,
+			chantypes.AttributeCounterpartyPortID:    info.CounterpartyPortID,
+			chantypes.AttributeCounterpartyChannelID: info.CounterpartyChannelID,
+			chantypes.AttributeKeyConnectionID:       info.ConnID,
+			chantypes.AttributeVersion:               info.Version,
+		}
+	case provider.PacketInfo:
+		attrs := map[string]string{
+			chantypes.AttributeKeySequence:         strconv.FormatUint(info.Sequence, 10),
+			chantypes.AttributeKeySrcPort:          info.SourcePort,
+			chantypes.AttributeKeySrcChannel:       info.SourceChannel,
+			chantypes.AttributeKeyDstPort:          info.DestPort,
+			chantypes.AttributeKeyDstChannel:       info.DestChannel,
+			chantypes.AttributeKeyChannelOrdering:  info.ChannelOrder,
+			chantypes.AttributeKeyTimeoutHeight:    info.TimeoutHeight.String(),
+			chantypes.AttributeKeyTimeoutTimestamp: strconv.FormatUint(info.TimeoutTimestamp, 10),
+		}
+		if info.Data != nil {
+			attrs[chantypes.AttributeKeyData] = string(info.Data)
+		}
+		if info.Ack != nil {
+			attrs[chantypes.AttributeKeyAck] = string(info.Ack)
+		}
+		return attrs
+	}
+	return nil
+}
+
+func (p *Provider) signer() (string, error) {
+	return p.Address()
+}
+
+// [Begin] Client IBC message assembly
+
+func (p *Provider) NewClientState(
+	dstChainID string,
+	dstUpdateHeader provider.IBCHeader,
+	dstTrustingPeriod,
+	dstUbdPeriod time.Duration,
+	allowUpdateAfterExpiry,
+	allowUpdateAfterMisbehaviour bool,
+) (ibcexported.ClientState, error) {
+	return &tmclient.ClientState{
+		ChainId:                      dstChainID,
+		TrustLevel:                   tmclient.DefaultTrustLevel,
+		TrustingPeriod:               dstTrustingPeriod,
+		UnbondingPeriod:              dstUbdPeriod,
+		LatestHeight:                 clienttypes.NewHeight(clienttypes.ParseChainID(dstChainID), dstUpdateHeader.Height()),
+		AllowUpdateAfterExpiry:       allowUpdateAfterExpiry,
+		AllowUpdateAfterMisbehaviour: allowUpdateAfterMisbehaviour,
+	}, nil
+}
+
+func (p *Provider) MsgCreateClient(clientState ibcexported.ClientState, consensusState ibcexported.ConsensusState) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := clientState.(*tmclient.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("unsupported client state type, expected: *tmclient.ClientState, actual: %T", clientState)
+	}
+	cons, ok := consensusState.(*tmclient.ConsensusState)
+	if !ok {
+		return nil, fmt.Errorf("unsupported consensus state type, expected: *tmclient.ConsensusState, actual: %T", consensusState)
+	}
+	return NewMessage(&MsgCreateClient{
+		ClientState:    cs,
+		ConsensusState: cons,
+		Signer:         signer,
+	}), nil
+}
+
+func (p *Provider) MsgUpgradeClient(srcClientId string, consRes *clienttypes.QueryConsensusStateResponse, clientRes *clienttypes.QueryClientStateResponse) (provider.RelayerMessage, error) {
+	return nil, ErrNotSupported
+}
+
+// MsgUpdateClientHeader assembles a Header of the latest block, trusted at trustedHeight.
+func (p *Provider) MsgUpdateClientHeader(latestHeader provider.IBCHeader, trustedHeight clienttypes.Height, trustedHeader provider.IBCHeader) (ibcexported.Header, error) {
+	latest, ok := latestHeader.(Header)
+	if !ok {
+		return nil, fmt.Errorf("unsupported IBC header type, expected: sim.Header, actual: %T", latestHeader)
+	}
+	if _, ok := trustedHeader.(Header); !ok {
+		return nil, fmt.Errorf("unsupported IBC trusted header type, expected: sim.Header, actual: %T", trustedHeader)
+	}
+	latest.TrustedHeight = trustedHeight
+	return &latest, nil
+}
+
+func (p *Provider) MsgUpdateClient(clientID string, counterpartyHeader ibcexported.Header) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	header, ok := counterpartyHeader.(*Header)
+	if !ok {
+		return nil, fmt.Errorf("unsupported header type, expected: *sim.Header, actual: %T", counterpartyHeader)
+	}
+	return NewMessage(&MsgUpdateClient{
+		ClientID: clientID,
+		Header:   header,
+		Signer:   signer,
+	}), nil
+}
+
+// [End] Client IBC message assembly
+
+// [Begin] Packet flow IBC message assembly
+
+// MsgTransfer assembles a MsgSendPacket with ICS-20 packet data for amount.
+func (p *Provider) MsgTransfer(dstAddr string, amount sdk.Coin, info provider.PacketInfo) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	data := transfertypes.NewFungibleTokenPacketData(amount.Denom, amount.Amount.String(), signer, dstAddr)
+	return NewMessage(&MsgSendPacket{
+		SourcePort:       info.SourcePort,
+		SourceChannel:    info.SourceChannel,
+		Data:             data.GetBytes(),
+		TimeoutHeight:    info.TimeoutHeight,
+		TimeoutTimestamp: info.TimeoutTimestamp,
+		Signer:           signer,
+	}), nil
+}
+
+func (p *Provider) ValidatePacket(msgTransfer provider.PacketInfo, latest provider.LatestBlock) error {
+	if msgTransfer.Sequence == 0 {
+		return errors.New("refusing to relay packet with sequence: 0")
+	}
+
+	if len(msgTransfer.Data) == 0 {
+		return errors.New("refusing to relay packet with empty data")
+	}
+
+	if msgTransfer.TimeoutHeight.IsZero() && msgTransfer.TimeoutTimestamp == 0 {
+		return errors.New("refusing to relay packet without a timeout (height or timestamp must be set)")
+	}
+
+	latestClientTypesHeight := p.chain.height(latest.Height)
+	if !msgTransfer.TimeoutHeight.IsZero() && latestClientTypesHeight.GTE(msgTransfer.TimeoutHeight) {
+		return provider.NewTimeoutHeightError(latest.Height, msgTransfer.TimeoutHeight.RevisionHeight)
+	}
+	latestTimestamp := uint64(latest.Time.UnixNano())
+	if msgTransfer.TimeoutTimestamp > 0 && latestTimestamp > msgTransfer.TimeoutTimestamp {
+		return provider.NewTimeoutTimestampError(latestTimestamp, msgTransfer.TimeoutTimestamp)
+	}
+
+	return nil
+}
+
+func (p *Provider) PacketCommitment(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	key := host.PacketCommitmentKey(msgTransfer.SourcePort, msgTransfer.SourceChannel, msgTransfer.Sequence)
+	commitment, proofBz, proofHeight, err := p.chain.proof(height, key)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet commitment: %w", err)
+	}
+	if len(commitment) == 0 {
+		return provider.PacketProof{}, chantypes.ErrPacketCommitmentNotFound
+	}
+	return provider.PacketProof{
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) PacketAcknowledgement(ctx context.Context, msgRecvPacket provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	key := host.PacketAcknowledgementKey(msgRecvPacket.DestPort, msgRecvPacket.DestChannel, msgRecvPacket.Sequence)
+	ack, proofBz, proofHeight, err := p.chain.proof(height, key)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet acknowledgement: %w", err)
+	}
+	if len(ack) == 0 {
+		return provider.PacketProof{}, chantypes.ErrInvalidAcknowledgement
+	}
+	return provider.PacketProof{
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) PacketReceipt(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	key := host.PacketReceiptKey(msgTransfer.DestPort, msgTransfer.DestChannel, msgTransfer.Sequence)
+	_, proofBz, proofHeight, err := p.chain.proof(height, key)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for packet receipt: %w", err)
+	}
+	return provider.PacketProof{
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) NextSeqRecv(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	key := host.NextSequenceRecvKey(msgTransfer.DestPort, msgTransfer.DestChannel)
+	_, proofBz, proofHeight, err := p.chain.proof(height, key)
+	if err != nil {
+		return provider.PacketProof{}, fmt.Errorf("error querying proof for next sequence receive: %w", err)
+	}
+	return provider.PacketProof{
+		Proof:       proofBz,
+		ProofHeight: proofHeight,
+	}, nil
+}
+
+func (p *Provider) MsgRecvPacket(msgTransfer provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgRecvPacket{
+		Packet:          msgTransfer.Packet(),
+		ProofCommitment: proof.Proof,
+		ProofHeight:     proof.ProofHeight,
+		Signer:          signer,
+	}), nil
+}
+
+func (p *Provider) MsgAcknowledgement(msgRecvPacket provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgAcknowledgement{
+		Packet:          msgRecvPacket.Packet(),
+		Acknowledgement: msgRecvPacket.Ack,
+		ProofAcked:      proof.Proof,
+		ProofHeight:     proof.ProofHeight,
+		Signer:          signer,
+	}), nil
+}
+
+func (p *Provider) MsgTimeout(msgTransfer provider.PacketInfo, proof provider.PacketProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgTimeout{
+		Packet:           msgTransfer.Packet(),
+		ProofUnreceived:  proof.Proof,
+		ProofHeight:      proof.ProofHeight,
+		NextSequenceRecv: msgTransfer.Sequence,
+		Signer:           signer,
+	}), nil
+}
+
+func (p *Provider) MsgTimeoutOnClose(msgTransfer provider.PacketInfo, proofUnreceived provider.PacketProof) (provider.RelayerMessage, error) {
+	return nil, ErrNotSupported
+}
+
+// [End] Packet flow IBC message assembly
+
+// [Begin] Connection handshake IBC message assembly
+
+func (p *Provider) ConnectionHandshakeProof(ctx context.Context, msgOpenInit provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	clientState, clientStateProof, consensusStateProof, connStateProof, proofHeight, err := p.GenerateConnHandshakeProof(ctx, int64(height), msgOpenInit.ClientID, msgOpenInit.ConnID)
+	if err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	connState, err := p.QueryConnection(ctx, int64(height), msgOpenInit.ConnID)
+	if err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return provider.ConnectionProof{
+		ClientState:          clientState,
+		ClientStateProof:     clientStateProof,
+		ConsensusStateProof:  consensusStateProof,
+		ConnectionStateProof: connStateProof,
+		ProofHeight:          proofHeight.(clienttypes.Height),
+		DelayPeriod:          connState.Connection.DelayPeriod,
+	}, nil
+}
+
+func (p *Provider) ConnectionProof(ctx context.Context, msgOpenAck provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	connState, err := p.QueryConnection(ctx, int64(height), msgOpenAck.ConnID)
+	if err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return provider.ConnectionProof{
+		ConnectionStateProof: connState.Proof,
+		ProofHeight:          connState.ProofHeight,
+	}, nil
+}
+
+func (p *Provider) MsgConnectionOpenInit(info provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&conntypes.MsgConnectionOpenInit{
+		ClientId: info.ClientID,
+		Counterparty: conntypes.Counterparty{
+			ClientId: info.CounterpartyClientID,
+			Prefix:   info.CounterpartyCommitmentPrefix,
+		},
+		Version:     conntypes.DefaultIBCVersion,
+		DelayPeriod: info.DelayPeriod,
+		Signer:      signer,
+	}), nil
+}
+
+func (p *Provider) MsgConnectionOpenTry(msgOpenInit provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	csAny, err := clienttypes.PackClientState(proof.ClientState)
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&conntypes.MsgConnectionOpenTry{
+		ClientId:             msgOpenInit.CounterpartyClientID,
+		PreviousConnectionId: msgOpenInit.CounterpartyConnID,
+		ClientState:          csAny,
+		Counterparty: conntypes.Counterparty{
+			ClientId:     msgOpenInit.ClientID,
+			ConnectionId: msgOpenInit.ConnID,
+			Prefix:       defaultChainPrefix,
+		},
+		CounterpartyVersions: conntypes.ExportedVersionsToProto(conntypes.GetCompatibleVersions()),
+		ProofHeight:          proof.ProofHeight,
+		ProofInit:            proof.ConnectionStateProof,
+		ProofClient:          proof.ClientStateProof,
+		ProofConsensus:       proof.ConsensusStateProof,
+		ConsensusHeight:      proof.ClientState.GetLatestHeight().(clienttypes.Height),
+		DelayPeriod:          proof.DelayPeriod,
+		Signer:               signer,
+	}), nil
+}
+
+func (p *Provider) MsgConnectionOpenAck(msgOpenTry provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	csAny, err := clienttypes.PackClientState(proof.ClientState)
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&conntypes.MsgConnectionOpenAck{
+		ConnectionId:             msgOpenTry.CounterpartyConnID,
+		CounterpartyConnectionId: msgOpenTry.ConnID,
+		Version:                  conntypes.DefaultIBCVersion,
+		ClientState:              csAny,
+		ProofHeight:              proof.ProofHeight,
+		ProofTry:                 proof.ConnectionStateProof,
+		ProofClient:              proof.ClientStateProof,
+		ProofConsensus:           proof.ConsensusStateProof,
+		ConsensusHeight:          proof.ClientState.GetLatestHeight().(clienttypes.Height),
+		Signer:                   signer,
+	}), nil
+}
+
+func (p *Provider) MsgConnectionOpenConfirm(msgOpenAck provider.ConnectionInfo, proof provider.ConnectionProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&conntypes.MsgConnectionOpenConfirm{
+		ConnectionId: msgOpenAck.CounterpartyConnID,
+		ProofAck:     proof.ConnectionStateProof,
+		ProofHeight:  proof.ProofHeight,
+		Signer:       signer,
+	}), nil
+}
+
+// [End] Connection handshake IBC message assembly
+
+// [Begin] Channel handshake IBC message assembly
+
+func (p *Provider) ChannelProof(ctx context.Context, msg provider.ChannelInfo, height uint64) (provider.ChannelProof, error) {
+	channelRes, err := p.QueryChannel(ctx, int64(height), msg.ChannelID, msg.PortID)
+	if err != nil {
+		return provider.ChannelProof{}, err
+	}
+	return provider.ChannelProof{
+		Proof:       channelRes.Proof,
+		ProofHeight: channelRes.ProofHeight,
+		Version:     channelRes.Channel.Version,
+		Ordering:    channelRes.Channel.Ordering,
+	}, nil
+}
+
+func (p *Provider) MsgChannelOpenInit(info provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelOpenInit{
+		PortId: info.PortID,
+		Channel: chantypes.Channel{
+			State:    chantypes.INIT,
+			Ordering: info.Order,
+			Counterparty: chantypes.Counterparty{
+				PortId: info.CounterpartyPortID,
+			},
+			ConnectionHops: []string{info.ConnID},
+			Version:        info.Version,
+		},
+		Signer: signer,
+	}), nil
+}
+
+func (p *Provider) MsgChannelOpenTry(msgOpenInit provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelOpenTry{
+		PortId:            msgOpenInit.CounterpartyPortID,
+		PreviousChannelId: msgOpenInit.CounterpartyChannelID,
+		Channel: chantypes.Channel{
+			State:    chantypes.TRYOPEN,
+			Ordering: proof.Ordering,
+			Counterparty: chantypes.Counterparty{
+				PortId:    msgOpenInit.PortID,
+				ChannelId: msgOpenInit.ChannelID,
+			},
+			ConnectionHops: []string{msgOpenInit.CounterpartyConnID},
+			Version:        proof.Version,
+		},
+		CounterpartyVersion: proof.Version,
+		ProofInit:           proof.Proof,
+		ProofHeight:         proof.ProofHeight,
+		Signer:              signer,
+	}), nil
+}
+
+func (p *Provider) MsgChannelOpenAck(msgOpenTry provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelOpenAck{
+		PortId:                msgOpenTry.CounterpartyPortID,
+		ChannelId:             msgOpenTry.CounterpartyChannelID,
+		CounterpartyChannelId: msgOpenTry.ChannelID,
+		CounterpartyVersion:   proof.Version,
+		ProofTry:              proof.Proof,
+		ProofHeight:           proof.ProofHeight,
+		Signer:                signer,
+	}), nil
+}
+
+func (p *Provider) MsgChannelOpenConfirm(msgOpenAck provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelOpenConfirm{
+		PortId:      msgOpenAck.CounterpartyPortID,
+		ChannelId:   msgOpenAck.CounterpartyChannelID,
+		ProofAck:    proof.Proof,
+		ProofHeight: proof.ProofHeight,
+		Signer:      signer,
+	}), nil
+}
+
+func (p *Provider) MsgChannelCloseInit(info provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelCloseInit{
+		PortId:    info.PortID,
+		ChannelId: info.ChannelID,
+		Signer:    signer,
+	}), nil
+}
+
+func (p *Provider) MsgChannelCloseConfirm(msgCloseInit provider.ChannelInfo, proof provider.ChannelProof) (provider.RelayerMessage, error) {
+	signer, err := p.signer()
+	if err != nil {
+		return nil, err
+	}
+	return NewMessage(&chantypes.MsgChannelCloseConfirm{
+		PortId:      msgCloseInit.CounterpartyPortID,
+		ChannelId:   msgCloseInit.CounterpartyChannelID,
+		ProofInit:   proof.Proof,
+		ProofHeight: proof.ProofHeight,
+		Signer:      signer,
+	}), nil
+}
+
+// [End] Channel handshake IBC message assembly
+
+// RelayPacketFromSequence is not supported, since simulated chains are only relayed by the event processor.
+func (p *Provider) RelayPacketFromSequence(
+	ctx context.Context,
+	src provider.ChainProvider,
+	srch, dsth, seq uint64,
+	srcChanID, srcPortID string,
+	order chantypes.Order,
+) (provider.RelayerMessage, provider.RelayerMessage, error) {
+	return nil, nil, ErrNotSupported
+}
+
+// AcknowledgementFromSequence is not supported, since simulated chains are only relayed by the event processor.
+func (p *Provider) AcknowledgementFromSequence(ctx context.Context, dst provider.ChainProvider, dsth, seq uint64, dstChanID, dstPortID, srcChanID, srcPortID string) (provider.RelayerMessage, error) {
+	return nil, ErrNotSupported
+}
@@ -69,8 +69,8 @@ func (c *Chain) CreateOpenChannels(
 
 	return processor.NewEventProcessor().
 		WithChainProcessors(
-			c.chainProcessor(c.log, nil),
-			dst.chainProcessor(c.log, nil),
+			c.chainProcessor(c.log, nil, nil, nil, nil),
+			dst.chainProcessor(c.log, nil, nil, nil, nil),
 		).
 		WithPathProcessors(pp).
 		WithInitialBlockHistory(0).
@@ -118,8 +118,8 @@ func (c *Chain) CloseChannel(
 
 	return processor.NewEventProcessor().
 		WithChainProcessors(
-			c.chainProcessor(c.log, nil),
-			dst.chainProcessor(c.log, nil),
+			c.chainProcessor(c.log, nil, nil, nil, nil),
+			dst.chainProcessor(c.log, nil, nil, nil, nil),
 		).
 		WithPathProcessors(processor.NewPathProcessor(
 			c.log,
@@ -0,0 +1,34 @@
+package chaos
+
+import (
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+)
+
+var _ processor.ChainProcessor = &ChainProcessor{}
+
+// ChainProcessor wraps the ChainProcessor of the provider wrapped by a Provider, so that the PathProcessors
+// it is linked with query proofs and send messages through the Provider.
+type ChainProcessor struct {
+	processor.ChainProcessor
+
+	provider *Provider
+}
+
+// NewChainProcessor returns a ChainProcessor linking cp with p. If cp supports it, latest heights,
+// block results and headers are queried through p as well, so that the chain processor sees query errors
+// and stale heights.
+func NewChainProcessor(cp processor.ChainProcessor, p *Provider) *ChainProcessor {
+	if s, ok := cp.(interface {
+		SetBlockQuerier(cosmos.BlockQuerier)
+	}); ok {
+		s.SetBlockQuerier(p)
+	}
+	return &ChainProcessor{ChainProcessor: cp, provider: p}
+}
+
+// Provider returns the fault injecting Provider.
+func (cp *ChainProcessor) Provider() provider.ChainProvider {
+	return cp.provider
+}
@@ -0,0 +1,263 @@
+package chaos_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/chaos"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap/zaptest"
+)
+
+// countingProvider counts broadcasts and reports a fixed latest height.
+type countingProvider struct {
+	provider.ChainProvider
+	broadcasts int
+}
+
+func (p *countingProvider) ChainId() string { return "chaos-1" }
+
+func (p *countingProvider) QueryLatestHeight(ctx context.Context) (int64, error) { return 100, nil }
+
+func (p *countingProvider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	p.broadcasts++
+	return &provider.RelayerTxResponse{Height: 100, TxHash: "ABCD"}, true, nil
+}
+
+func (p *countingProvider) QueryBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error) {
+	return &ctypes.ResultBlockResults{Height: height}, nil
+}
+
+// signingProvider signs a transaction with its account sequence and broadcasts it through a wrappable function
+// like the cosmos provider, retrying it with the sequence queried again after an account sequence mismatch.
+type signingProvider struct {
+	countingProvider
+	sequence  uint64
+	broadcast cosmos.BroadcastFunc
+
+	// attempts counts the broadcasts made by SendMessages, and sent the signed transactions that reached the chain.
+	attempts int
+	sent     []string
+}
+
+func (p *signingProvider) WrapBroadcast(wrap func(cosmos.BroadcastFunc) cosmos.BroadcastFunc) {
+	p.broadcast = wrap(func(ctx context.Context, txBytes []byte, sequence uint64) (*sdk.TxResponse, error) {
+		p.sent = append(p.sent, string(txBytes))
+		return &sdk.TxResponse{Height: 100, TxHash: "ABCD"}, nil
+	})
+}
+
+func (p *signingProvider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	var err error
+	for ; p.attempts < 3; p.attempts++ {
+		var res *sdk.TxResponse
+		res, err = p.broadcast(ctx, []byte(fmt.Sprintf("%s/%d", memo, p.sequence)), p.sequence)
+		if err == nil {
+			p.sequence++
+			return &provider.RelayerTxResponse{Height: res.Height, TxHash: res.TxHash}, true, nil
+		}
+		if !strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error()) {
+			break
+		}
+	}
+	return nil, false, err
+}
+
+func newChaosProvider(t *testing.T, f chaos.Faults) (*chaos.Provider, *countingProvider) {
+	inner := &countingProvider{}
+	p, err := chaos.NewProvider(zaptest.NewLogger(t), inner, f, 1)
+	require.NoError(t, err)
+	return p, inner
+}
+
+func TestQueryErrorRates(t *testing.T) {
+	ctx := context.Background()
+
+	p, _ := newChaosProvider(t, chaos.Faults{
+		QueryErrorRates: map[string]float64{"QueryLatestHeight": 1},
+	})
+	_, err := p.QueryLatestHeight(ctx)
+	require.ErrorIs(t, err, chaos.ErrInjected)
+
+	p, _ = newChaosProvider(t, chaos.Faults{
+		QueryErrorRates: map[string]float64{chaos.AllQueries: 1, "QueryLatestHeight": 0},
+	})
+	h, err := p.QueryLatestHeight(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), h)
+	_, err = p.QueryChannels(ctx)
+	require.ErrorIs(t, err, chaos.ErrInjected)
+}
+
+func TestStaleHeight(t *testing.T) {
+	p, _ := newChaosProvider(t, chaos.Faults{StaleHeightRate: 1, StaleHeightBlocks: 3})
+	h, err := p.QueryLatestHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(97), h)
+}
+
+func TestBroadcastFaults(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sequence mismatch", func(t *testing.T) {
+		p, inner := newChaosProvider(t, chaos.Faults{SequenceMismatchRate: 1})
+		_, success, err := p.SendMessages(ctx, nil, "")
+		require.False(t, success)
+		require.ErrorIs(t, err, chaos.ErrInjected)
+		require.ErrorContains(t, err, sdkerrors.ErrWrongSequence.Error())
+		require.Zero(t, inner.broadcasts)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		p, inner := newChaosProvider(t, chaos.Faults{DropBroadcastRate: 1})
+		res, success, err := p.SendMessage(ctx, nil, "")
+		require.NoError(t, err)
+		require.True(t, success)
+		require.NotEmpty(t, res.TxHash)
+		require.Zero(t, inner.broadcasts)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		p, inner := newChaosProvider(t, chaos.Faults{DuplicateBroadcastRate: 1})
+		res, success, err := p.SendMessages(ctx, nil, "")
+		require.NoError(t, err)
+		require.True(t, success)
+		require.Equal(t, "ABCD", res.TxHash)
+		require.Equal(t, 2, inner.broadcasts)
+	})
+}
+
+func TestBroadcastFaultsInWrappedProvider(t *testing.T) {
+	ctx := context.Background()
+	newProvider := func(t *testing.T, f chaos.Faults) (*chaos.Provider, *signingProvider) {
+		inner := &signingProvider{sequence: 5}
+		p, err := chaos.NewProvider(zaptest.NewLogger(t), inner, f, 1)
+		require.NoError(t, err)
+		return p, inner
+	}
+
+	t.Run("sequence mismatch", func(t *testing.T) {
+		p, inner := newProvider(t, chaos.Faults{SequenceMismatchRate: 1})
+		_, success, err := p.SendMessages(ctx, nil, "memo")
+		require.False(t, success)
+		require.ErrorIs(t, err, chaos.ErrInjected)
+		require.ErrorContains(t, err, "account sequence mismatch, expected 6, got 5")
+		// The mismatch reached the wrapped provider, which retried it.
+		require.Equal(t, 3, inner.attempts)
+		require.Empty(t, inner.sent)
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		p, inner := newProvider(t, chaos.Faults{DropBroadcastRate: 1})
+		res, success, err := p.SendMessages(ctx, nil, "memo")
+		require.NoError(t, err)
+		require.True(t, success)
+		require.NotEqual(t, "ABCD", res.TxHash)
+		require.Empty(t, inner.sent)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		p, inner := newProvider(t, chaos.Faults{DuplicateBroadcastRate: 1})
+		res, success, err := p.SendMessages(ctx, nil, "memo")
+		require.NoError(t, err)
+		require.True(t, success)
+		require.Equal(t, "ABCD", res.TxHash)
+		// The same signed transaction is broadcast twice, not signed again.
+		require.Equal(t, []string{"memo/5", "memo/5"}, inner.sent)
+		require.Equal(t, uint64(6), inner.sequence)
+	})
+}
+
+// blockQuerierProcessor is a chain processor whose block queries can be routed through a chaos provider.
+type blockQuerierProcessor struct {
+	processor.ChainProcessor
+	querier cosmos.BlockQuerier
+}
+
+func (cp *blockQuerierProcessor) SetBlockQuerier(q cosmos.BlockQuerier) { cp.querier = q }
+
+func TestChainProcessorBlockQueries(t *testing.T) {
+	ctx := context.Background()
+	p, _ := newChaosProvider(t, chaos.Faults{
+		QueryErrorRates:   map[string]float64{"QueryBlockResults": 1, "QueryIBCHeader": 1},
+		StaleHeightRate:   1,
+		StaleHeightBlocks: 2,
+	})
+	inner := &blockQuerierProcessor{}
+	chaos.NewChainProcessor(inner, p)
+	require.NotNil(t, inner.querier)
+
+	h, err := inner.querier.QueryLatestHeight(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(98), h)
+	_, err = inner.querier.QueryBlockResults(ctx, h)
+	require.ErrorIs(t, err, chaos.ErrInjected)
+	_, err = inner.querier.QueryIBCHeader(ctx, h)
+	require.ErrorIs(t, err, chaos.ErrInjected)
+}
+
+func TestLatency(t *testing.T) {
+	p, _ := newChaosProvider(t, chaos.Faults{Latency: "1h"})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.QueryLatestHeight(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSeedIsDeterministic(t *testing.T) {
+	ctx := context.Background()
+	f := chaos.Faults{QueryErrorRates: map[string]float64{chaos.AllQueries: 0.5}}
+	outcomes := func() (failed []bool) {
+		p, _ := newChaosProvider(t, f)
+		for i := 0; i < 20; i++ {
+			_, err := p.QueryLatestHeight(ctx)
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+	require.Equal(t, outcomes(), outcomes())
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+seed: 42
+chains:
+  chaos-1:
+    latency: 100ms
+    query-error-rates:
+      QueryLatestHeight: 0.2
+      "*": 0.01
+    drop-broadcast-rate: 0.1
+`), 0o600))
+	c, err := chaos.LoadConfig(valid)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), c.Seed)
+	require.Equal(t, 0.2, c.Chains["chaos-1"].QueryErrorRates["QueryLatestHeight"])
+
+	wrapped, err := c.Wrap(zaptest.NewLogger(t), &countingProvider{})
+	require.NoError(t, err)
+	require.IsType(t, &chaos.Provider{}, wrapped)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+chains:
+  chaos-1:
+    drop-broadcast-rate: 1.5
+`), 0o600))
+	_, err = chaos.LoadConfig(invalid)
+	require.ErrorContains(t, err, "drop-broadcast-rate")
+}
@@ -0,0 +1,132 @@
+package chaos
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllQueries is the query-error-rates key that applies to every query method without its own rate.
+const AllQueries = "*"
+
+// Config describes the faults to inject per chain ID.
+type Config struct {
+	// Seed seeds the random source deciding which calls fail, so that a run can be reproduced.
+	// Zero seeds from the current time.
+	Seed int64 `yaml:"seed,omitempty" json:"seed,omitempty"`
+
+	Chains map[string]Faults `yaml:"chains" json:"chains"`
+}
+
+// Faults describes the faults injected into the calls to a single chain provider.
+// Rates are probabilities between 0 and 1 that a single call is affected.
+type Faults struct {
+	// Latency is added to every call taking a context, e.g. "200ms".
+	Latency string `yaml:"latency,omitempty" json:"latency,omitempty"`
+
+	// LatencyJitter is the maximum random latency added on top of Latency, e.g. "100ms".
+	LatencyJitter string `yaml:"latency-jitter,omitempty" json:"latency-jitter,omitempty"`
+
+	// QueryErrorRates maps query method names, e.g. "QueryLatestHeight" or "PacketCommitment",
+	// to the rate at which they return an error. The "*" key applies to all other query methods.
+	QueryErrorRates map[string]float64 `yaml:"query-error-rates,omitempty" json:"query-error-rates,omitempty"`
+
+	// DropBroadcastRate is the rate at which broadcasts report success without reaching the chain.
+	DropBroadcastRate float64 `yaml:"drop-broadcast-rate,omitempty" json:"drop-broadcast-rate,omitempty"`
+
+	// DuplicateBroadcastRate is the rate at which broadcasts are sent to the chain twice.
+	DuplicateBroadcastRate float64 `yaml:"duplicate-broadcast-rate,omitempty" json:"duplicate-broadcast-rate,omitempty"`
+
+	// SequenceMismatchRate is the rate at which broadcasts fail with an account sequence mismatch error.
+	SequenceMismatchRate float64 `yaml:"sequence-mismatch-rate,omitempty" json:"sequence-mismatch-rate,omitempty"`
+
+	// StaleHeightRate is the rate at which QueryLatestHeight reports a height StaleHeightBlocks behind the latest.
+	StaleHeightRate float64 `yaml:"stale-height-rate,omitempty" json:"stale-height-rate,omitempty"`
+
+	// StaleHeightBlocks is how many blocks behind stale heights are, 1 by default.
+	StaleHeightBlocks int64 `yaml:"stale-height-blocks,omitempty" json:"stale-height-blocks,omitempty"`
+}
+
+// faults is the parsed form of Faults.
+type faults struct {
+	Faults
+	latency       time.Duration
+	latencyJitter time.Duration
+}
+
+// LoadConfig reads and validates a chaos config from a YAML file.
+func LoadConfig(path string) (*Config, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading chaos config: %w", err)
+	}
+	var c Config
+	if err := yaml.Unmarshal(bz, &c); err != nil {
+		return nil, fmt.Errorf("error parsing chaos config %s: %w", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chaos config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Validate returns an error if any chain has an invalid rate or duration.
+func (c *Config) Validate() error {
+	for chainID, f := range c.Chains {
+		if _, err := f.parse(); err != nil {
+			return fmt.Errorf("chain %s: %w", chainID, err)
+		}
+	}
+	return nil
+}
+
+func (f Faults) parse() (faults, error) {
+	parsed := faults{Faults: f}
+	var err error
+	if f.Latency != "" {
+		if parsed.latency, err = time.ParseDuration(f.Latency); err != nil {
+			return faults{}, fmt.Errorf("invalid latency: %w", err)
+		}
+	}
+	if f.LatencyJitter != "" {
+		if parsed.latencyJitter, err = time.ParseDuration(f.LatencyJitter); err != nil {
+			return faults{}, fmt.Errorf("invalid latency-jitter: %w", err)
+		}
+	}
+	if parsed.latency < 0 || parsed.latencyJitter < 0 {
+		return faults{}, fmt.Errorf("latency must not be negative")
+	}
+
+	rates := map[string]float64{
+		"drop-broadcast-rate":      f.DropBroadcastRate,
+		"duplicate-broadcast-rate": f.DuplicateBroadcastRate,
+		"sequence-mismatch-rate":   f.SequenceMismatchRate,
+		"stale-height-rate":        f.StaleHeightRate,
+	}
+	for method, rate := range f.QueryErrorRates {
+		rates["query-error-rates."+method] = rate
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return faults{}, fmt.Errorf("%s must be between 0 and 1, got %v", name, rate)
+		}
+	}
+
+	if f.StaleHeightBlocks < 0 {
+		return faults{}, fmt.Errorf("stale-height-blocks must not be negative")
+	}
+	if parsed.StaleHeightBlocks == 0 {
+		parsed.StaleHeightBlocks = 1
+	}
+	return parsed, nil
+}
+
+// queryErrorRate returns the error rate for the query method.
+func (f faults) queryErrorRate(method string) float64 {
+	if rate, ok := f.QueryErrorRates[method]; ok {
+		return rate
+	}
+	return f.QueryErrorRates[AllQueries]
+}
@@ -0,0 +1,574 @@
+package chaos
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	mrand "math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	transfertypes "github.com/cosmos/ibc-go/v5/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap"
+)
+
+// ErrInjected is returned, wrapped, by calls that were made to fail on purpose.
+var ErrInjected = errors.New("chaos: injected fault")
+
+var _ provider.ChainProvider = &Provider{}
+
+// Provider is a ChainProvider that wraps another ChainProvider and injects faults into its calls:
+// latency, query errors, dropped, duplicated and sequence mismatched broadcasts, and stale latest heights.
+// Calls that are not affected by a fault are passed through to the wrapped provider.
+type Provider struct {
+	provider.ChainProvider
+
+	log    *zap.Logger
+	faults faults
+
+	// wrapsBroadcast is set if broadcast faults are injected into the broadcasts of the wrapped provider.
+	wrapsBroadcast bool
+
+	mu  sync.Mutex
+	rng *mrand.Rand
+}
+
+// NewProvider returns a Provider injecting f into the calls to p.
+// Faults are decided by a random source seeded with seed and the chain ID of p,
+// so that every chain of a run with the same seed sees the same sequence of faults.
+// A zero seed seeds from the current time.
+func NewProvider(log *zap.Logger, p provider.ChainProvider, f Faults, seed int64) (*Provider, error) {
+	parsed, err := f.parse()
+	if err != nil {
+		return nil, err
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(p.ChainId()))
+	cp := &Provider{
+		ChainProvider: p,
+		log:           log.With(zap.String("sys", "chaos"), zap.String("chain_id", p.ChainId())),
+		faults:        parsed,
+		rng:           mrand.New(mrand.NewSource(seed ^ int64(h.Sum64()))),
+	}
+	if w, ok := p.(broadcastWrapper); ok {
+		w.WrapBroadcast(cp.wrapBroadcast)
+		cp.wrapsBroadcast = true
+	}
+	return cp, nil
+}
+
+// broadcastWrapper is implemented by providers that broadcast their signed transactions through a wrappable function.
+type broadcastWrapper interface {
+	WrapBroadcast(wrap func(cosmos.BroadcastFunc) cosmos.BroadcastFunc)
+}
+
+// Wrap returns p wrapped in a Provider injecting the faults configured for its chain ID,
+// or p itself if no faults are configured for it.
+func (c *Config) Wrap(log *zap.Logger, p provider.ChainProvider) (provider.ChainProvider, error) {
+	f, ok := c.Chains[p.ChainId()]
+	if !ok {
+		return p, nil
+	}
+	return NewProvider(log, p, f, c.Seed)
+}
+
+// Unwrap returns the wrapped ChainProvider.
+func (p *Provider) Unwrap() provider.ChainProvider {
+	return p.ChainProvider
+}
+
+// chance returns true with the given probability.
+func (p *Provider) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rng.Float64() < rate
+}
+
+// delay sleeps for the configured latency, returning early with an error if ctx is done first.
+func (p *Provider) delay(ctx context.Context) error {
+	d := p.faults.latency
+	if p.faults.latencyJitter > 0 {
+		p.mu.Lock()
+		d += time.Duration(p.rng.Int63n(int64(p.faults.latencyJitter)))
+		p.mu.Unlock()
+	}
+	if d == 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// query adds latency to a query and decides whether it fails.
+func (p *Provider) query(ctx context.Context, method string) error {
+	if err := p.delay(ctx); err != nil {
+		return err
+	}
+	if p.chance(p.faults.queryErrorRate(method)) {
+		p.log.Debug("Injected query error", zap.String("method", method))
+		return fmt.Errorf("%w: %s", ErrInjected, method)
+	}
+	return nil
+}
+
+// QueryLatestHeight returns the latest height of the wrapped provider,
+// or a height StaleHeightBlocks behind it at the stale height rate.
+func (p *Provider) QueryLatestHeight(ctx context.Context) (int64, error) {
+	if err := p.query(ctx, "QueryLatestHeight"); err != nil {
+		return 0, err
+	}
+	h, err := p.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return h, err
+	}
+	if p.chance(p.faults.StaleHeightRate) {
+		stale := h - p.faults.StaleHeightBlocks
+		if stale < 1 {
+			stale = 1
+		}
+		p.log.Debug("Injected stale height", zap.Int64("latest_height", h), zap.Int64("stale_height", stale))
+		return stale, nil
+	}
+	return h, nil
+}
+
+// SendMessage sends a single message as SendMessages does.
+func (p *Provider) SendMessage(ctx context.Context, msg provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	return p.SendMessages(ctx, []provider.RelayerMessage{msg}, memo)
+}
+
+// SendMessages sends msgs with the wrapped provider. If it broadcasts signed transactions through WrapBroadcast,
+// the broadcast faults are injected into those broadcasts, see wrapBroadcast.
+// Otherwise, e.g. for simulated chains which do not sign transactions, the faults are injected here:
+// the broadcast is made to fail with an account sequence mismatch error, or dropped, reporting success
+// with a random tx hash without the messages reaching the chain, or duplicated, sending the messages
+// twice and reporting the result of the first broadcast.
+func (p *Provider) SendMessages(ctx context.Context, msgs []provider.RelayerMessage, memo string) (*provider.RelayerTxResponse, bool, error) {
+	if p.wrapsBroadcast {
+		return p.ChainProvider.SendMessages(ctx, msgs, memo)
+	}
+
+	if err := p.delay(ctx); err != nil {
+		return nil, false, err
+	}
+
+	if p.chance(p.faults.SequenceMismatchRate) {
+		p.log.Debug("Injected account sequence mismatch", zap.Int("num_msgs", len(msgs)))
+		return nil, false, fmt.Errorf("%w: %v", ErrInjected, sdkerrors.Wrap(sdkerrors.ErrWrongSequence, "account sequence mismatch, expected 1, got 0"))
+	}
+
+	if p.chance(p.faults.DropBroadcastRate) {
+		p.log.Debug("Injected dropped broadcast", zap.Int("num_msgs", len(msgs)))
+		return &provider.RelayerTxResponse{TxHash: randomTxHash()}, true, nil
+	}
+
+	res, success, err := p.ChainProvider.SendMessages(ctx, msgs, memo)
+
+	if p.chance(p.faults.DuplicateBroadcastRate) {
+		_, _, dupErr := p.ChainProvider.SendMessages(ctx, msgs, memo)
+		p.log.Debug("Injected duplicate broadcast", zap.Int("num_msgs", len(msgs)), zap.NamedError("duplicate_error", dupErr))
+	}
+
+	return res, success, err
+}
+
+// wrapBroadcast injects the broadcast faults into the broadcasts of signed transactions by the wrapped provider,
+// so that they are handled by the wrapped provider like faults of the chain: an account sequence mismatch,
+// as if another transaction of the account was included first, is retried with the sequence queried again,
+// a dropped broadcast reports success with a random tx hash, and a duplicated broadcast sends the same signed
+// transaction twice, reporting the result of the first broadcast.
+func (p *Provider) wrapBroadcast(broadcast cosmos.BroadcastFunc) cosmos.BroadcastFunc {
+	return func(ctx context.Context, txBytes []byte, sequence uint64) (*sdk.TxResponse, error) {
+		if err := p.delay(ctx); err != nil {
+			return nil, err
+		}
+
+		if p.chance(p.faults.SequenceMismatchRate) {
+			p.log.Debug("Injected account sequence mismatch", zap.Uint64("sequence", sequence))
+			return nil, fmt.Errorf("%w: %v", ErrInjected, sdkerrors.Wrapf(sdkerrors.ErrWrongSequence, "account sequence mismatch, expected %d, got %d", sequence+1, sequence))
+		}
+
+		if p.chance(p.faults.DropBroadcastRate) {
+			p.log.Debug("Injected dropped broadcast", zap.Uint64("sequence", sequence))
+			return &sdk.TxResponse{TxHash: randomTxHash()}, nil
+		}
+
+		res, err := broadcast(ctx, txBytes, sequence)
+
+		if p.chance(p.faults.DuplicateBroadcastRate) {
+			_, dupErr := broadcast(ctx, txBytes, sequence)
+			p.log.Debug("Injected duplicate broadcast", zap.Uint64("sequence", sequence), zap.NamedError("duplicate_error", dupErr))
+		}
+
+		return res, err
+	}
+}
+
+func randomTxHash() string {
+	var bz [32]byte
+	_, _ = rand.Read(bz[:])
+	return strings.ToUpper(hex.EncodeToString(bz[:]))
+}
+
+// The methods below add latency and fail at the query error rate of their name before calling the wrapped provider.
+
+func (p *Provider) PacketCommitment(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	if err := p.query(ctx, "PacketCommitment"); err != nil {
+		return provider.PacketProof{}, err
+	}
+	return p.ChainProvider.PacketCommitment(ctx, msgTransfer, height)
+}
+
+func (p *Provider) PacketAcknowledgement(ctx context.Context, msgRecvPacket provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	if err := p.query(ctx, "PacketAcknowledgement"); err != nil {
+		return provider.PacketProof{}, err
+	}
+	return p.ChainProvider.PacketAcknowledgement(ctx, msgRecvPacket, height)
+}
+
+func (p *Provider) PacketReceipt(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	if err := p.query(ctx, "PacketReceipt"); err != nil {
+		return provider.PacketProof{}, err
+	}
+	return p.ChainProvider.PacketReceipt(ctx, msgTransfer, height)
+}
+
+func (p *Provider) NextSeqRecv(ctx context.Context, msgTransfer provider.PacketInfo, height uint64) (provider.PacketProof, error) {
+	if err := p.query(ctx, "NextSeqRecv"); err != nil {
+		return provider.PacketProof{}, err
+	}
+	return p.ChainProvider.NextSeqRecv(ctx, msgTransfer, height)
+}
+
+func (p *Provider) ConnectionHandshakeProof(ctx context.Context, msgOpenInit provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	if err := p.query(ctx, "ConnectionHandshakeProof"); err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return p.ChainProvider.ConnectionHandshakeProof(ctx, msgOpenInit, height)
+}
+
+func (p *Provider) ConnectionProof(ctx context.Context, msgOpenAck provider.ConnectionInfo, height uint64) (provider.ConnectionProof, error) {
+	if err := p.query(ctx, "ConnectionProof"); err != nil {
+		return provider.ConnectionProof{}, err
+	}
+	return p.ChainProvider.ConnectionProof(ctx, msgOpenAck, height)
+}
+
+func (p *Provider) ChannelProof(ctx context.Context, msg provider.ChannelInfo, height uint64) (provider.ChannelProof, error) {
+	if err := p.query(ctx, "ChannelProof"); err != nil {
+		return provider.ChannelProof{}, err
+	}
+	return p.ChainProvider.ChannelProof(ctx, msg, height)
+}
+
+func (p *Provider) RelayPacketFromSequence(ctx context.Context, src provider.ChainProvider, srch, dsth, seq uint64, srcChanID, srcPortID string, order chantypes.Order) (provider.RelayerMessage, provider.RelayerMessage, error) {
+	if err := p.query(ctx, "RelayPacketFromSequence"); err != nil {
+		return nil, nil, err
+	}
+	return p.ChainProvider.RelayPacketFromSequence(ctx, src, srch, dsth, seq, srcChanID, srcPortID, order)
+}
+
+func (p *Provider) AcknowledgementFromSequence(ctx context.Context, dst provider.ChainProvider, dsth, seq uint64, dstChanID, dstPortID, srcChanID, srcPortID string) (provider.RelayerMessage, error) {
+	if err := p.query(ctx, "AcknowledgementFromSequence"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.AcknowledgementFromSequence(ctx, dst, dsth, seq, dstChanID, dstPortID, srcChanID, srcPortID)
+}
+
+func (p *Provider) TrustingPeriod(ctx context.Context) (time.Duration, error) {
+	if err := p.query(ctx, "TrustingPeriod"); err != nil {
+		return 0, err
+	}
+	return p.ChainProvider.TrustingPeriod(ctx)
+}
+
+func (p *Provider) BlockTime(ctx context.Context, height int64) (time.Time, error) {
+	if err := p.query(ctx, "BlockTime"); err != nil {
+		return time.Time{}, err
+	}
+	return p.ChainProvider.BlockTime(ctx, height)
+}
+
+func (p *Provider) QueryTx(ctx context.Context, hashHex string) (*provider.RelayerTxResponse, error) {
+	if err := p.query(ctx, "QueryTx"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryTx(ctx, hashHex)
+}
+
+func (p *Provider) QueryTxs(ctx context.Context, page, limit int, events []string) ([]*provider.RelayerTxResponse, error) {
+	if err := p.query(ctx, "QueryTxs"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryTxs(ctx, page, limit, events)
+}
+
+// QueryBlockResults returns the block results of the wrapped provider, if it queries them, see cosmos.BlockQuerier.
+func (p *Provider) QueryBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error) {
+	q, ok := p.ChainProvider.(interface {
+		QueryBlockResults(ctx context.Context, height int64) (*ctypes.ResultBlockResults, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("chain %s of type %s does not query block results", p.ChainId(), p.Type())
+	}
+	if err := p.query(ctx, "QueryBlockResults"); err != nil {
+		return nil, err
+	}
+	return q.QueryBlockResults(ctx, height)
+}
+
+func (p *Provider) QueryIBCHeader(ctx context.Context, h int64) (provider.IBCHeader, error) {
+	if err := p.query(ctx, "QueryIBCHeader"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryIBCHeader(ctx, h)
+}
+
+func (p *Provider) QuerySendPacket(ctx context.Context, srcChanID, srcPortID string, sequence uint64) (provider.PacketInfo, error) {
+	if err := p.query(ctx, "QuerySendPacket"); err != nil {
+		return provider.PacketInfo{}, err
+	}
+	return p.ChainProvider.QuerySendPacket(ctx, srcChanID, srcPortID, sequence)
+}
+
+func (p *Provider) QueryRecvPacket(ctx context.Context, dstChanID, dstPortID string, sequence uint64) (provider.PacketInfo, error) {
+	if err := p.query(ctx, "QueryRecvPacket"); err != nil {
+		return provider.PacketInfo{}, err
+	}
+	return p.ChainProvider.QueryRecvPacket(ctx, dstChanID, dstPortID, sequence)
+}
+
+func (p *Provider) QueryBalance(ctx context.Context, keyName string) (sdk.Coins, error) {
+	if err := p.query(ctx, "QueryBalance"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryBalance(ctx, keyName)
+}
+
+func (p *Provider) QueryBalanceWithAddress(ctx context.Context, addr string) (sdk.Coins, error) {
+	if err := p.query(ctx, "QueryBalanceWithAddress"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryBalanceWithAddress(ctx, addr)
+}
+
+func (p *Provider) QueryUnbondingPeriod(ctx context.Context) (time.Duration, error) {
+	if err := p.query(ctx, "QueryUnbondingPeriod"); err != nil {
+		return 0, err
+	}
+	return p.ChainProvider.QueryUnbondingPeriod(ctx)
+}
+
+func (p *Provider) QueryClientState(ctx context.Context, height int64, clientid string) (ibcexported.ClientState, error) {
+	if err := p.query(ctx, "QueryClientState"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryClientState(ctx, height, clientid)
+}
+
+func (p *Provider) QueryClientStateResponse(ctx context.Context, height int64, srcClientId string) (*clienttypes.QueryClientStateResponse, error) {
+	if err := p.query(ctx, "QueryClientStateResponse"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryClientStateResponse(ctx, height, srcClientId)
+}
+
+func (p *Provider) QueryClientConsensusState(ctx context.Context, chainHeight int64, clientid string, clientHeight ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	if err := p.query(ctx, "QueryClientConsensusState"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryClientConsensusState(ctx, chainHeight, clientid, clientHeight)
+}
+
+func (p *Provider) QueryUpgradedClient(ctx context.Context, height int64) (*clienttypes.QueryClientStateResponse, error) {
+	if err := p.query(ctx, "QueryUpgradedClient"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryUpgradedClient(ctx, height)
+}
+
+func (p *Provider) QueryUpgradedConsState(ctx context.Context, height int64) (*clienttypes.QueryConsensusStateResponse, error) {
+	if err := p.query(ctx, "QueryUpgradedConsState"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryUpgradedConsState(ctx, height)
+}
+
+func (p *Provider) QueryConsensusState(ctx context.Context, height int64) (ibcexported.ConsensusState, int64, error) {
+	if err := p.query(ctx, "QueryConsensusState"); err != nil {
+		return nil, 0, err
+	}
+	return p.ChainProvider.QueryConsensusState(ctx, height)
+}
+
+func (p *Provider) QueryClients(ctx context.Context) (clienttypes.IdentifiedClientStates, error) {
+	if err := p.query(ctx, "QueryClients"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryClients(ctx)
+}
+
+func (p *Provider) QueryConnection(ctx context.Context, height int64, connectionid string) (*conntypes.QueryConnectionResponse, error) {
+	if err := p.query(ctx, "QueryConnection"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryConnection(ctx, height, connectionid)
+}
+
+func (p *Provider) QueryConnections(ctx context.Context) ([]*conntypes.IdentifiedConnection, error) {
+	if err := p.query(ctx, "QueryConnections"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryConnections(ctx)
+}
+
+func (p *Provider) QueryConnectionsUsingClient(ctx context.Context, height int64, clientid string) (*conntypes.QueryConnectionsResponse, error) {
+	if err := p.query(ctx, "QueryConnectionsUsingClient"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryConnectionsUsingClient(ctx, height, clientid)
+}
+
+func (p *Provider) QueryMaxExpectedTimePerBlock(ctx context.Context) (time.Duration, error) {
+	if err := p.query(ctx, "QueryMaxExpectedTimePerBlock"); err != nil {
+		return 0, err
+	}
+	return p.ChainProvider.QueryMaxExpectedTimePerBlock(ctx)
+}
+
+func (p *Provider) GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (ibcexported.ClientState, []byte, []byte, []byte, ibcexported.Height, error) {
+	if err := p.query(ctx, "GenerateConnHandshakeProof"); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	return p.ChainProvider.GenerateConnHandshakeProof(ctx, height, clientId, connId)
+}
+
+func (p *Provider) QueryChannel(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryChannelResponse, error) {
+	if err := p.query(ctx, "QueryChannel"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryChannel(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryChannelClient(ctx context.Context, height int64, channelid, portid string) (*clienttypes.IdentifiedClientState, error) {
+	if err := p.query(ctx, "QueryChannelClient"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryChannelClient(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryConnectionChannels(ctx context.Context, height int64, connectionid string) ([]*chantypes.IdentifiedChannel, error) {
+	if err := p.query(ctx, "QueryConnectionChannels"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryConnectionChannels(ctx, height, connectionid)
+}
+
+func (p *Provider) QueryChannels(ctx context.Context) ([]*chantypes.IdentifiedChannel, error) {
+	if err := p.query(ctx, "QueryChannels"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryChannels(ctx)
+}
+
+func (p *Provider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (*chantypes.QueryPacketCommitmentsResponse, error) {
+	if err := p.query(ctx, "QueryPacketCommitments"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryPacketCommitments(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string) ([]*chantypes.PacketState, error) {
+	if err := p.query(ctx, "QueryPacketAcknowledgements"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryPacketAcknowledgements(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	if err := p.query(ctx, "QueryUnreceivedPackets"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryUnreceivedPackets(ctx, height, channelid, portid, seqs)
+}
+
+func (p *Provider) QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	if err := p.query(ctx, "QueryUnreceivedAcknowledgements"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryUnreceivedAcknowledgements(ctx, height, channelid, portid, seqs)
+}
+
+func (p *Provider) QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (*chantypes.QueryNextSequenceReceiveResponse, error) {
+	if err := p.query(ctx, "QueryNextSeqRecv"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryNextSeqRecv(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (uint64, error) {
+	if err := p.query(ctx, "QueryNextSeqAck"); err != nil {
+		return 0, err
+	}
+	return p.ChainProvider.QueryNextSeqAck(ctx, height, channelid, portid)
+}
+
+func (p *Provider) QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketCommitmentResponse, error) {
+	if err := p.query(ctx, "QueryPacketCommitment"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryPacketCommitment(ctx, height, channelid, portid, seq)
+}
+
+func (p *Provider) QueryPacketAcknowledgement(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketAcknowledgementResponse, error) {
+	if err := p.query(ctx, "QueryPacketAcknowledgement"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryPacketAcknowledgement(ctx, height, channelid, portid, seq)
+}
+
+func (p *Provider) QueryPacketReceipt(ctx context.Context, height int64, channelid, portid string, seq uint64) (*chantypes.QueryPacketReceiptResponse, error) {
+	if err := p.query(ctx, "QueryPacketReceipt"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryPacketReceipt(ctx, height, channelid, portid, seq)
+}
+
+func (p *Provider) QueryDenomTrace(ctx context.Context, denom string) (*transfertypes.DenomTrace, error) {
+	if err := p.query(ctx, "QueryDenomTrace"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryDenomTrace(ctx, denom)
+}
+
+func (p *Provider) QueryDenomTraces(ctx context.Context, offset, limit uint64, height int64) ([]transfertypes.DenomTrace, error) {
+	if err := p.query(ctx, "QueryDenomTraces"); err != nil {
+		return nil, err
+	}
+	return p.ChainProvider.QueryDenomTraces(ctx, offset, limit, height)
+}
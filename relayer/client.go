@@ -418,14 +418,25 @@ func UpgradeClient(
 		return err
 	})
 
-	var updateMsg provider.RelayerMessage
-	eg.Go(func() error {
-		var err error
-		updateMsg, err = MsgUpdateClient(ctx, src, dst, srch, dsth)
+	if err := eg.Wait(); err != nil {
 		return err
-	})
+	}
 
-	if err := eg.Wait(); err != nil {
+	return sendUpgradeClient(ctx, src, dst, srch, dsth, clientRes, consRes, memo)
+}
+
+// sendUpgradeClient updates the client on dst to the height srch of the upgraded src chain
+// and upgrades it with the upgraded client and consensus states of src.
+func sendUpgradeClient(
+	ctx context.Context,
+	src, dst *Chain,
+	srch, dsth int64,
+	clientRes *clienttypes.QueryClientStateResponse,
+	consRes *clienttypes.QueryConsensusStateResponse,
+	memo string,
+) error {
+	updateMsg, err := MsgUpdateClient(ctx, src, dst, srch, dsth)
+	if err != nil {
 		return err
 	}
 
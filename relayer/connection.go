@@ -12,6 +12,7 @@ import (
 
 // CreateOpenConnections runs the connection creation messages on timeout until they pass.
 // The returned boolean indicates that the path end has been modified.
+// delayPeriod is the connection delay period, which must pass after a client update before packets can be proven with it.
 func (c *Chain) CreateOpenConnections(
 	ctx context.Context,
 	dst *Chain,
@@ -20,6 +21,7 @@ func (c *Chain) CreateOpenConnections(
 	memo string,
 	initialBlockHistory uint64,
 	pathName string,
+	delayPeriod time.Duration,
 ) (string, string, error) {
 	// client identifiers must be filled in
 	if err := ValidateClientPaths(c, dst); err != nil {
@@ -59,8 +61,8 @@ func (c *Chain) CreateOpenConnections(
 
 	return connectionSrc, connectionDst, processor.NewEventProcessor().
 		WithChainProcessors(
-			c.chainProcessor(c.log, nil),
-			dst.chainProcessor(c.log, nil),
+			c.chainProcessor(c.log, nil, nil, nil, nil),
+			dst.chainProcessor(c.log, nil, nil, nil, nil),
 		).
 		WithPathProcessors(pp).
 		WithInitialBlockHistory(initialBlockHistory).
@@ -72,6 +74,7 @@ func (c *Chain) CreateOpenConnections(
 					ClientID:                     c.PathEnd.ClientID,
 					CounterpartyClientID:         dst.PathEnd.ClientID,
 					CounterpartyCommitmentPrefix: dst.ChainProvider.CommitmentPrefix(),
+					DelayPeriod:                  uint64(delayPeriod),
 				},
 			},
 			Termination: &processor.ConnectionMessage{
@@ -0,0 +1,219 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// PathCandidate is a path between two chains made of clients and connections that already exist on both chains.
+type PathCandidate struct {
+	Path *Path
+
+	// OpenChannels is the number of open channels on the connection of the source.
+	OpenChannels int
+
+	// SrcUpdated and DstUpdated are the times of the latest consensus states of the clients on the source and destination.
+	SrcUpdated, DstUpdated time.Time
+
+	// Expiration is when the first of both clients expires, and Frozen whether either client is frozen.
+	Expiration time.Time
+	Frozen     bool
+
+	srcClient, dstClient *tmclient.ClientState
+}
+
+// LastUpdated returns the time of the latest update of the least recently updated client of the path.
+func (c *PathCandidate) LastUpdated() time.Time {
+	if c.DstUpdated.Before(c.SrcUpdated) {
+		return c.DstUpdated
+	}
+	return c.SrcUpdated
+}
+
+// Expired reports whether either client of the path is frozen or expired at now.
+func (c *PathCandidate) Expired(now time.Time) bool {
+	return c.Frozen || !now.Before(c.Expiration)
+}
+
+// DiscoverPaths finds the paths between src and dst, made of open connections on clients that track each other's chain.
+// The candidates are ranked by SortPathCandidates.
+func DiscoverPaths(ctx context.Context, src, dst *Chain) ([]*PathCandidate, error) {
+	var (
+		srcClients, dstClients clienttypes.IdentifiedClientStates
+		srcConns, dstConns     []*conntypes.IdentifiedConnection
+		srch, dsth             int64
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	for _, q := range []struct {
+		c       *Chain
+		clients *clienttypes.IdentifiedClientStates
+		conns   *[]*conntypes.IdentifiedConnection
+		height  *int64
+	}{
+		{src, &srcClients, &srcConns, &srch},
+		{dst, &dstClients, &dstConns, &dsth},
+	} {
+		q := q
+		eg.Go(func() (err error) {
+			*q.height, err = q.c.ChainProvider.QueryLatestHeight(egCtx)
+			if err != nil {
+				return err
+			}
+			*q.clients, err = q.c.ChainProvider.QueryClients(egCtx)
+			if err != nil {
+				return fmt.Errorf("failed to query clients on chain %s: %w", q.c.ChainID(), err)
+			}
+			*q.conns, err = q.c.ChainProvider.QueryConnections(egCtx)
+			if err != nil {
+				return fmt.Errorf("failed to query connections on chain %s: %w", q.c.ChainID(), err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	candidates := matchPathCandidates(
+		src.ChainID(), dst.ChainID(),
+		clientsTracking(srcClients, dst.ChainID()), clientsTracking(dstClients, src.ChainID()),
+		srcConns, dstConns,
+	)
+
+	eg, egCtx = errgroup.WithContext(ctx)
+	for _, c := range candidates {
+		c := c
+		eg.Go(func() error {
+			channels, err := src.ChainProvider.QueryConnectionChannels(egCtx, srch, c.Path.Src.ConnectionID)
+			if err != nil {
+				return fmt.Errorf("failed to query channels of connection %s on chain %s: %w", c.Path.Src.ConnectionID, src.ChainID(), err)
+			}
+			for _, ch := range channels {
+				if ch.State == chantypes.OPEN {
+					c.OpenChannels++
+				}
+			}
+			return nil
+		})
+		eg.Go(func() (err error) {
+			c.SrcUpdated, err = clientUpdateTime(egCtx, src, srch, c.Path.Src.ClientID, c.srcClient)
+			return err
+		})
+		eg.Go(func() (err error) {
+			c.DstUpdated, err = clientUpdateTime(egCtx, dst, dsth, c.Path.Dst.ClientID, c.dstClient)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		c.Expiration = c.SrcUpdated.Add(c.srcClient.TrustingPeriod)
+		if dstExpiration := c.DstUpdated.Add(c.dstClient.TrustingPeriod); dstExpiration.Before(c.Expiration) {
+			c.Expiration = dstExpiration
+		}
+		c.Frozen = !c.srcClient.FrozenHeight.IsZero() || !c.dstClient.FrozenHeight.IsZero()
+	}
+
+	SortPathCandidates(candidates, time.Now())
+	return candidates, nil
+}
+
+// clientUpdateTime returns the time of the latest consensus state of the client on c.
+func clientUpdateTime(ctx context.Context, c *Chain, height int64, clientID string, cs *tmclient.ClientState) (time.Time, error) {
+	res, err := c.ChainProvider.QueryClientConsensusState(ctx, height, clientID, cs.GetLatestHeight())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query consensus state of client %s on chain %s: %w", clientID, c.ChainID(), err)
+	}
+	consensusState, err := clienttypes.UnpackConsensusState(res.ConsensusState)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, int64(consensusState.GetTimestamp())), nil
+}
+
+// clientsTracking returns the tendermint clients of clients that track the chain chainID, by client ID.
+func clientsTracking(clients clienttypes.IdentifiedClientStates, chainID string) map[string]*tmclient.ClientState {
+	tracking := make(map[string]*tmclient.ClientState)
+	for _, c := range clients {
+		cs, err := clienttypes.UnpackClientState(c.ClientState)
+		if err != nil {
+			continue
+		}
+		if tmcs, ok := cs.(*tmclient.ClientState); ok && tmcs.ChainId == chainID {
+			tracking[c.ClientId] = tmcs
+		}
+	}
+	return tracking
+}
+
+// matchPathCandidates returns the paths made of an open connection on src and its open counterparty connection on dst,
+// both on clients that track the other chain. srcClients and dstClients are those clients, by client ID.
+func matchPathCandidates(
+	srcChainID, dstChainID string,
+	srcClients, dstClients map[string]*tmclient.ClientState,
+	srcConns, dstConns []*conntypes.IdentifiedConnection,
+) []*PathCandidate {
+	dstConnsByID := make(map[string]*conntypes.IdentifiedConnection, len(dstConns))
+	for _, conn := range dstConns {
+		dstConnsByID[conn.Id] = conn
+	}
+
+	var candidates []*PathCandidate
+	for _, srcConn := range srcConns {
+		if srcConn.State != conntypes.OPEN {
+			continue
+		}
+		srcClient, ok := srcClients[srcConn.ClientId]
+		if !ok {
+			continue
+		}
+		dstConn, ok := dstConnsByID[srcConn.Counterparty.ConnectionId]
+		if !ok || dstConn.State != conntypes.OPEN {
+			continue
+		}
+		dstClient, ok := dstClients[dstConn.ClientId]
+		if !ok {
+			continue
+		}
+		if dstConn.ClientId != srcConn.Counterparty.ClientId ||
+			dstConn.Counterparty.ClientId != srcConn.ClientId ||
+			dstConn.Counterparty.ConnectionId != srcConn.Id {
+			continue
+		}
+
+		candidates = append(candidates, &PathCandidate{
+			Path: &Path{
+				Src: &PathEnd{ChainID: srcChainID, ClientID: srcConn.ClientId, ConnectionID: srcConn.Id},
+				Dst: &PathEnd{ChainID: dstChainID, ClientID: dstConn.ClientId, ConnectionID: dstConn.Id},
+			},
+			srcClient: srcClient,
+			dstClient: dstClient,
+		})
+	}
+	return candidates
+}
+
+// SortPathCandidates sorts candidates from best to worst: paths with clients that are neither expired nor frozen at now first,
+// then the ones with the most open channels, then the most recently updated.
+func SortPathCandidates(candidates []*PathCandidate, now time.Time) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Expired(now) != b.Expired(now) {
+			return !a.Expired(now)
+		}
+		if a.OpenChannels != b.OpenChannels {
+			return a.OpenChannels > b.OpenChannels
+		}
+		return a.LastUpdated().After(b.LastUpdated())
+	})
+}
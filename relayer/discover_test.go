@@ -0,0 +1,102 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func identifiedConnection(id, clientID string, state conntypes.State, counterpartyClientID, counterpartyConnectionID string) *conntypes.IdentifiedConnection {
+	return &conntypes.IdentifiedConnection{
+		Id:           id,
+		ClientId:     clientID,
+		State:        state,
+		Counterparty: conntypes.Counterparty{ClientId: counterpartyClientID, ConnectionId: counterpartyConnectionID},
+	}
+}
+
+func TestClientsTracking(t *testing.T) {
+	clients := clienttypes.IdentifiedClientStates{
+		clienttypes.NewIdentifiedClientState("07-tendermint-0", &tmclient.ClientState{ChainId: "osmosis-1"}),
+		clienttypes.NewIdentifiedClientState("07-tendermint-1", &tmclient.ClientState{ChainId: "juno-1"}),
+		clienttypes.NewIdentifiedClientState("07-tendermint-2", &tmclient.ClientState{ChainId: "osmosis-1"}),
+	}
+	tracking := clientsTracking(clients, "osmosis-1")
+	require.Len(t, tracking, 2)
+	require.Contains(t, tracking, "07-tendermint-0")
+	require.Contains(t, tracking, "07-tendermint-2")
+}
+
+func TestMatchPathCandidates(t *testing.T) {
+	hubClients := map[string]*tmclient.ClientState{
+		"07-tendermint-1": {ChainId: "osmosis-1"},
+		"07-tendermint-2": {ChainId: "osmosis-1"},
+	}
+	osmoClients := map[string]*tmclient.ClientState{
+		"07-tendermint-5": {ChainId: "cosmoshub-4"},
+		"07-tendermint-6": {ChainId: "cosmoshub-4"},
+	}
+	hubConns := []*conntypes.IdentifiedConnection{
+		identifiedConnection("connection-0", "07-tendermint-1", conntypes.OPEN, "07-tendermint-5", "connection-10"),
+		// The counterparty connection is not open.
+		identifiedConnection("connection-1", "07-tendermint-2", conntypes.OPEN, "07-tendermint-6", "connection-11"),
+		// The client does not track osmosis.
+		identifiedConnection("connection-2", "07-tendermint-9", conntypes.OPEN, "07-tendermint-5", "connection-12"),
+		// The counterparty connection is on another client.
+		identifiedConnection("connection-3", "07-tendermint-2", conntypes.OPEN, "07-tendermint-5", "connection-13"),
+		identifiedConnection("connection-4", "07-tendermint-2", conntypes.OPEN, "07-tendermint-6", "connection-14"),
+		identifiedConnection("connection-5", "07-tendermint-1", conntypes.INIT, "07-tendermint-5", ""),
+	}
+	osmoConns := []*conntypes.IdentifiedConnection{
+		identifiedConnection("connection-10", "07-tendermint-5", conntypes.OPEN, "07-tendermint-1", "connection-0"),
+		identifiedConnection("connection-11", "07-tendermint-6", conntypes.TRYOPEN, "07-tendermint-2", "connection-1"),
+		identifiedConnection("connection-12", "07-tendermint-5", conntypes.OPEN, "07-tendermint-9", "connection-2"),
+		identifiedConnection("connection-13", "07-tendermint-6", conntypes.OPEN, "07-tendermint-2", "connection-3"),
+		identifiedConnection("connection-14", "07-tendermint-6", conntypes.OPEN, "07-tendermint-2", "connection-4"),
+	}
+
+	candidates := matchPathCandidates("cosmoshub-4", "osmosis-1", hubClients, osmoClients, hubConns, osmoConns)
+	require.Len(t, candidates, 2)
+	require.Equal(t, &Path{
+		Src: &PathEnd{ChainID: "cosmoshub-4", ClientID: "07-tendermint-1", ConnectionID: "connection-0"},
+		Dst: &PathEnd{ChainID: "osmosis-1", ClientID: "07-tendermint-5", ConnectionID: "connection-10"},
+	}, candidates[0].Path)
+	require.Equal(t, &Path{
+		Src: &PathEnd{ChainID: "cosmoshub-4", ClientID: "07-tendermint-2", ConnectionID: "connection-4"},
+		Dst: &PathEnd{ChainID: "osmosis-1", ClientID: "07-tendermint-6", ConnectionID: "connection-14"},
+	}, candidates[1].Path)
+}
+
+func TestSortPathCandidates(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	candidate := func(name string, channels int, updated time.Time, expiration time.Time, frozen bool) *PathCandidate {
+		return &PathCandidate{
+			Path:         &Path{Src: &PathEnd{ConnectionID: name}},
+			OpenChannels: channels,
+			SrcUpdated:   updated,
+			DstUpdated:   updated.Add(time.Minute),
+			Expiration:   expiration,
+			Frozen:       frozen,
+		}
+	}
+
+	candidates := []*PathCandidate{
+		candidate("expired", 5, now.Add(-30*time.Hour), now.Add(-time.Hour), false),
+		candidate("frozen", 5, now.Add(-time.Hour), now.Add(time.Hour), true),
+		candidate("old", 1, now.Add(-10*time.Hour), now.Add(time.Hour), false),
+		candidate("busy", 3, now.Add(-20*time.Hour), now.Add(time.Hour), false),
+		candidate("recent", 1, now.Add(-time.Hour), now.Add(time.Hour), false),
+	}
+	SortPathCandidates(candidates, now)
+
+	var order []string
+	for _, c := range candidates {
+		order = append(order, c.Path.Src.ConnectionID)
+	}
+	require.Equal(t, []string{"busy", "recent", "old", "frozen", "expired"}, order)
+	require.Equal(t, now.Add(-time.Hour), candidates[1].LastUpdated())
+}
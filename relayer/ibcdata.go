@@ -0,0 +1,160 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// IBCDataSchema is the $schema of the chain-registry _IBC files, relative to the _IBC directory.
+const IBCDataSchema = "../ibc_data.schema.json"
+
+// IBCDataFileName returns the name of the chain-registry _IBC file of the path between two chains.
+func IBCDataFileName(chainName1, chainName2 string) string {
+	if chainName2 < chainName1 {
+		chainName1, chainName2 = chainName2, chainName1
+	}
+	return chainName1 + "-" + chainName2 + ".json"
+}
+
+// QueryIBCData queries both ends of the path set on src and dst for its clients, connections and open channels,
+// and returns the path in the chain-registry _IBC format.
+// srcName and dstName are the chain-registry names of the chains.
+func QueryIBCData(ctx context.Context, src, dst *Chain, srcName, dstName string) (*IBCdata, error) {
+	if err := ValidateConnectionPaths(src, dst); err != nil {
+		return nil, err
+	}
+
+	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return checkIBCDataConnection(egCtx, src, dst, srch)
+	})
+	eg.Go(func() error {
+		return checkIBCDataConnection(egCtx, dst, src, dsth)
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	srcChannels, err := src.ChainProvider.QueryConnectionChannels(ctx, srch, src.ConnectionID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channels of connection %s on chain %s: %w", src.ConnectionID(), src.ChainID(), err)
+	}
+
+	var channels []*chantypes.IdentifiedChannel
+	for _, ch := range srcChannels {
+		if ch.State != chantypes.OPEN {
+			continue
+		}
+		// Only channels that are open on both ends are listed.
+		res, err := dst.ChainProvider.QueryChannel(ctx, dsth, ch.Counterparty.ChannelId, ch.Counterparty.PortId)
+		if err != nil || res.Channel == nil || res.Channel.State != chantypes.OPEN {
+			src.log.Info(
+				"Skipping channel that is not open on the counterparty chain",
+				zap.String("chain_id", src.ChainID()),
+				zap.String("channel_id", ch.ChannelId),
+				zap.String("port_id", ch.PortId),
+				zap.Error(err),
+			)
+			continue
+		}
+		channels = append(channels, ch)
+	}
+
+	return NewIBCData(srcName, dstName, src.PathEnd, dst.PathEnd, channels), nil
+}
+
+// checkIBCDataConnection checks that the connection of the path end of c is open,
+// on the client of the path end and with the connection of the counterparty path end.
+func checkIBCDataConnection(ctx context.Context, c, counterparty *Chain, height int64) error {
+	res, err := c.ChainProvider.QueryConnection(ctx, height, c.ConnectionID())
+	if err != nil {
+		return fmt.Errorf("failed to query connection %s on chain %s: %w", c.ConnectionID(), c.ChainID(), err)
+	}
+	conn := res.Connection
+	switch {
+	case conn == nil || conn.State != conntypes.OPEN:
+		return fmt.Errorf("connection %s is not open on chain %s", c.ConnectionID(), c.ChainID())
+	case conn.ClientId != c.ClientID():
+		return fmt.Errorf("connection %s on chain %s is on client %s, not %s", c.ConnectionID(), c.ChainID(), conn.ClientId, c.ClientID())
+	case conn.Counterparty.ConnectionId != counterparty.ConnectionID():
+		return fmt.Errorf("connection %s on chain %s has counterparty connection %s, not %s",
+			c.ConnectionID(), c.ChainID(), conn.Counterparty.ConnectionId, counterparty.ConnectionID())
+	}
+	return nil
+}
+
+// NewIBCData returns the path between the path ends src and dst, with the channels of src, in the chain-registry _IBC format.
+// The chain that sorts first by name is chain_1, and the channels are sorted by their port and channel on chain_1.
+func NewIBCData(srcName, dstName string, src, dst *PathEnd, srcChannels []*chantypes.IdentifiedChannel) *IBCdata {
+	swap := dstName < srcName
+
+	data := &IBCdata{
+		Schema:   IBCDataSchema,
+		Chain1:   IBCChain{ChainName: srcName, ClientID: src.ClientID, ConnectionID: src.ConnectionID},
+		Chain2:   IBCChain{ChainName: dstName, ClientID: dst.ClientID, ConnectionID: dst.ConnectionID},
+		Channels: make([]IBCChannel, 0, len(srcChannels)),
+	}
+	if swap {
+		data.Chain1, data.Chain2 = data.Chain2, data.Chain1
+	}
+
+	for _, ch := range srcChannels {
+		c := IBCChannel{
+			Chain1:   IBCChannelEnd{ChannelID: ch.ChannelId, PortID: ch.PortId},
+			Chain2:   IBCChannelEnd{ChannelID: ch.Counterparty.ChannelId, PortID: ch.Counterparty.PortId},
+			Ordering: strings.ToLower(strings.TrimPrefix(ch.Ordering.String(), "ORDER_")),
+			Version:  ch.Version,
+			Tags:     &IBCChannelTags{Status: "live"},
+		}
+		if swap {
+			c.Chain1, c.Chain2 = c.Chain2, c.Chain1
+		}
+		data.Channels = append(data.Channels, c)
+	}
+
+	sort.Slice(data.Channels, func(i, j int) bool {
+		a, b := data.Channels[i].Chain1, data.Channels[j].Chain1
+		if a.PortID != b.PortID {
+			return a.PortID < b.PortID
+		}
+		seqA, errA := chantypes.ParseChannelSequence(a.ChannelID)
+		seqB, errB := chantypes.ParseChannelSequence(b.ChannelID)
+		if errA != nil || errB != nil {
+			return a.ChannelID < b.ChannelID
+		}
+		return seqA < seqB
+	})
+
+	return data
+}
+
+// ChannelIDs returns the channel IDs on chain_1, or on chain_2 if chain1 is false, of the channels of d
+// that include returns true for. A nil include returns all the channels.
+func (d *IBCdata) ChannelIDs(chain1 bool, include func(IBCChannel) bool) []string {
+	var ids []string
+	for _, ch := range d.Channels {
+		if include != nil && !include(ch) {
+			continue
+		}
+		end := ch.Chain2
+		if chain1 {
+			end = ch.Chain1
+		}
+		if end.ChannelID != "" {
+			ids = append(ids, end.ChannelID)
+		}
+	}
+	return ids
+}
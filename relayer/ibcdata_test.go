@@ -0,0 +1,89 @@
+package relayer
+
+import (
+	"encoding/json"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+)
+
+func identifiedChannel(portID, channelID, counterpartyChannelID string, order chantypes.Order, version string) *chantypes.IdentifiedChannel {
+	return &chantypes.IdentifiedChannel{
+		State:        chantypes.OPEN,
+		Ordering:     order,
+		Counterparty: chantypes.Counterparty{PortId: portID, ChannelId: counterpartyChannelID},
+		Version:      version,
+		PortId:       portID,
+		ChannelId:    channelID,
+	}
+}
+
+func TestNewIBCData(t *testing.T) {
+	osmosis := &PathEnd{ChainID: "osmosis-1", ClientID: "07-tendermint-1", ConnectionID: "connection-1"}
+	hub := &PathEnd{ChainID: "cosmoshub-4", ClientID: "07-tendermint-259", ConnectionID: "connection-257"}
+
+	// The path is exported from osmosis, which sorts after cosmoshub, so its ends are swapped.
+	data := NewIBCData("osmosis", "cosmoshub", osmosis, hub, []*chantypes.IdentifiedChannel{
+		identifiedChannel("transfer", "channel-10", "channel-141", chantypes.UNORDERED, "ics20-1"),
+		identifiedChannel("icahost", "channel-2", "channel-9", chantypes.ORDERED, "ics27-1"),
+		identifiedChannel("transfer", "channel-0", "channel-1", chantypes.UNORDERED, "ics20-1"),
+	})
+	require.Equal(t, "cosmoshub-osmosis.json", IBCDataFileName("osmosis", "cosmoshub"))
+
+	bz, err := json.MarshalIndent(data, "", "  ")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$schema": "../ibc_data.schema.json",
+  "chain_1": {"chain_name": "cosmoshub", "client_id": "07-tendermint-259", "connection_id": "connection-257"},
+  "chain_2": {"chain_name": "osmosis", "client_id": "07-tendermint-1", "connection_id": "connection-1"},
+  "channels": [
+    {
+      "chain_1": {"channel_id": "channel-9", "port_id": "icahost"},
+      "chain_2": {"channel_id": "channel-2", "port_id": "icahost"},
+      "ordering": "ordered",
+      "version": "ics27-1",
+      "tags": {"status": "live"}
+    },
+    {
+      "chain_1": {"channel_id": "channel-1", "port_id": "transfer"},
+      "chain_2": {"channel_id": "channel-0", "port_id": "transfer"},
+      "ordering": "unordered",
+      "version": "ics20-1",
+      "tags": {"status": "live"}
+    },
+    {
+      "chain_1": {"channel_id": "channel-141", "port_id": "transfer"},
+      "chain_2": {"channel_id": "channel-10", "port_id": "transfer"},
+      "ordering": "unordered",
+      "version": "ics20-1",
+      "tags": {"status": "live"}
+    }
+  ]
+}`, string(bz))
+
+	// Exported files can be read back by paths fetch.
+	var read IBCdata
+	require.NoError(t, json.Unmarshal(bz, &read))
+	require.Equal(t, *data, read)
+}
+
+func TestIBCDataChannelIDs(t *testing.T) {
+	data := &IBCdata{Channels: []IBCChannel{
+		{
+			Chain1: IBCChannelEnd{ChannelID: "channel-141", PortID: "transfer"},
+			Chain2: IBCChannelEnd{ChannelID: "channel-0", PortID: "transfer"},
+			Tags:   &IBCChannelTags{Status: "live", Preferred: true},
+		},
+		{
+			Chain1: IBCChannelEnd{ChannelID: "channel-9", PortID: "icahost"},
+			Chain2: IBCChannelEnd{ChannelID: "channel-2", PortID: "icahost"},
+		},
+	}}
+	preferred := func(ch IBCChannel) bool { return ch.Tags != nil && ch.Tags.Preferred }
+
+	require.Equal(t, []string{"channel-141", "channel-9"}, data.ChannelIDs(true, nil))
+	require.Equal(t, []string{"channel-0", "channel-2"}, data.ChannelIDs(false, nil))
+	require.Equal(t, []string{"channel-141"}, data.ChannelIDs(true, preferred))
+	require.Equal(t, []string{"channel-0"}, data.ChannelIDs(false, preferred))
+}
@@ -107,36 +107,42 @@ type ChannelFilter struct {
 	ChannelList []string `yaml:"channel-list" json:"channel-list"`
 }
 
+// IBCdata is a path between two chains in the chain-registry _IBC format.
 type IBCdata struct {
-	Schema string `json:"$schema"`
-	Chain1 struct {
-		ChainName    string `json:"chain_name"`
-		ClientID     string `json:"client_id"`
-		ConnectionID string `json:"connection_id"`
-	} `json:"chain_1"`
-	Chain2 struct {
-		ChainName    string `json:"chain_name"`
-		ClientID     string `json:"client_id"`
-		ConnectionID string `json:"connection_id"`
-	} `json:"chain_2"`
-	Channels []struct {
-		Chain1 struct {
-			ChannelID string `json:"channel_id"`
-			PortID    string `json:"port_id"`
-		} `json:"chain_1"`
-		Chain2 struct {
-			ChannelID string `json:"channel_id"`
-			PortID    string `json:"port_id"`
-		} `json:"chain_2"`
-		Ordering string `json:"ordering"`
-		Version  string `json:"version"`
-		Tags     struct {
-			Status     string `json:"status"`
-			Preferred  bool   `json:"preferred"`
-			Dex        string `json:"dex"`
-			Properties string `json:"properties"`
-		} `json:"tags,omitempty"`
-	} `json:"channels"`
+	Schema   string       `json:"$schema"`
+	Chain1   IBCChain     `json:"chain_1"`
+	Chain2   IBCChain     `json:"chain_2"`
+	Channels []IBCChannel `json:"channels"`
+}
+
+// IBCChain is the client and connection of one chain of an IBCdata path.
+type IBCChain struct {
+	ChainName    string `json:"chain_name"`
+	ClientID     string `json:"client_id"`
+	ConnectionID string `json:"connection_id"`
+}
+
+// IBCChannel is a channel of an IBCdata path.
+type IBCChannel struct {
+	Chain1   IBCChannelEnd   `json:"chain_1"`
+	Chain2   IBCChannelEnd   `json:"chain_2"`
+	Ordering string          `json:"ordering"`
+	Version  string          `json:"version"`
+	Tags     *IBCChannelTags `json:"tags,omitempty"`
+}
+
+// IBCChannelEnd is the end of an IBCChannel on one chain.
+type IBCChannelEnd struct {
+	ChannelID string `json:"channel_id"`
+	PortID    string `json:"port_id"`
+}
+
+// IBCChannelTags are the optional chain-registry tags of an IBCChannel.
+type IBCChannelTags struct {
+	Status     string `json:"status,omitempty"`
+	Preferred  bool   `json:"preferred,omitempty"`
+	Dex        string `json:"dex,omitempty"`
+	Properties string `json:"properties,omitempty"`
 }
 
 // ValidateChannelFilterRule verifies that the configured ChannelFilter rule is set to an appropriate value.
@@ -158,6 +164,42 @@ func (cf *ChannelFilter) InChannelList(channelID string) bool {
 	return false
 }
 
+// Merge returns the filter that combines cf with other, for the same source chain.
+// Allowlists and denylists are merged into their union. When one is an allowlist and the other a denylist,
+// the result allows the channels of the allowlist that are not denied. An empty rule leaves the other filter as is.
+// An error is returned if the denylist denies every channel of the allowlist, since an empty allowlist relays nothing.
+func (cf ChannelFilter) Merge(other ChannelFilter) (ChannelFilter, error) {
+	switch {
+	case other.Rule == "":
+		return cf, nil
+	case cf.Rule == "":
+		return other, nil
+	case cf.Rule == other.Rule:
+		merged := ChannelFilter{Rule: cf.Rule, ChannelList: append([]string(nil), cf.ChannelList...)}
+		for _, channelID := range other.ChannelList {
+			if !merged.InChannelList(channelID) {
+				merged.ChannelList = append(merged.ChannelList, channelID)
+			}
+		}
+		return merged, nil
+	}
+
+	allow, deny := cf, other
+	if cf.Rule == processor.RuleDenyList {
+		allow, deny = other, cf
+	}
+	merged := ChannelFilter{Rule: processor.RuleAllowList, ChannelList: []string{}}
+	for _, channelID := range allow.ChannelList {
+		if !deny.InChannelList(channelID) {
+			merged.ChannelList = append(merged.ChannelList, channelID)
+		}
+	}
+	if len(merged.ChannelList) == 0 {
+		return ChannelFilter{}, fmt.Errorf("every channel of allowlist %v is denied by denylist %v", allow.ChannelList, deny.ChannelList)
+	}
+	return merged, nil
+}
+
 // End returns the proper end given a chainID.
 func (p *Path) End(chainID string) *PathEnd {
 	if p.Dst.ChainID == chainID {
@@ -0,0 +1,420 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"golang.org/x/sync/errgroup"
+)
+
+// AuditFinding is the result of one check of AuditPath.
+type AuditFinding struct {
+	ChainID string `json:"chain_id"`
+	// Subject is what was checked, such as "client 07-tendermint-0" or "channel transfer/channel-0".
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+	Problem bool   `json:"problem"`
+}
+
+// PathAudit holds the findings of AuditPath.
+type PathAudit struct {
+	Findings []AuditFinding `json:"findings"`
+}
+
+func (a *PathAudit) ok(chainID, subject, format string, args ...any) {
+	a.Findings = append(a.Findings, AuditFinding{ChainID: chainID, Subject: subject, Message: fmt.Sprintf(format, args...)})
+}
+
+func (a *PathAudit) problem(chainID, subject, format string, args ...any) {
+	a.Findings = append(a.Findings, AuditFinding{ChainID: chainID, Subject: subject, Message: fmt.Sprintf(format, args...), Problem: true})
+}
+
+// Problems returns the number of findings that are problems.
+func (a *PathAudit) Problems() int {
+	n := 0
+	for _, f := range a.Findings {
+		if f.Problem {
+			n++
+		}
+	}
+	return n
+}
+
+// AuditPath checks the integrity of the path set on src and dst: the clients, the connections,
+// and the channels of the connections that pass filter, along with their backlog of unrelayed packets and acknowledgements.
+// A channel backlog larger than maxBacklog is a problem, unless maxBacklog is negative.
+// An error is only returned if the chains cannot be queried at all, failed checks are problems of the audit.
+func AuditPath(ctx context.Context, src, dst *Chain, filter ChannelFilter, maxBacklog int) (*PathAudit, error) {
+	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &PathAudit{}
+	now := time.Now()
+	for _, c := range []struct {
+		c, counterparty  *Chain
+		h, counterpartyH int64
+	}{
+		{src, dst, srch, dsth},
+		{dst, src, dsth, srch},
+	} {
+		auditClientOf(ctx, audit, c.c, c.counterparty, c.h, c.counterpartyH, now)
+	}
+
+	srcConnOK := auditConnectionOf(ctx, audit, src, dst, srch)
+	dstConnOK := auditConnectionOf(ctx, audit, dst, src, dsth)
+	if !srcConnOK || !dstConnOK {
+		// Channels cannot be checked on connections that do not match.
+		return audit, nil
+	}
+
+	srcChannels, err := src.ChainProvider.QueryConnectionChannels(ctx, srch, src.ConnectionID())
+	if err != nil {
+		audit.problem(src.ChainID(), "connection "+src.ConnectionID(), "failed to query channels: %v", err)
+		return audit, nil
+	}
+	dstChannels, err := dst.ChainProvider.QueryConnectionChannels(ctx, dsth, dst.ConnectionID())
+	if err != nil {
+		audit.problem(dst.ChainID(), "connection "+dst.ConnectionID(), "failed to query channels: %v", err)
+		return audit, nil
+	}
+	srcChannels = applyChannelFilterRule(filter, srcChannels)
+
+	dstByID := make(map[string]*chantypes.IdentifiedChannel, len(dstChannels))
+	for _, ch := range dstChannels {
+		dstByID[ch.PortId+"/"+ch.ChannelId] = ch
+	}
+	srcByID := make(map[string]bool, len(srcChannels))
+	for _, ch := range srcChannels {
+		srcByID[ch.PortId+"/"+ch.ChannelId] = true
+		counterparty := dstByID[ch.Counterparty.PortId+"/"+ch.Counterparty.ChannelId]
+		if !auditChannel(audit, src.ChainID(), dst.ChainID(), ch, counterparty) {
+			continue
+		}
+		if ch.State == chantypes.OPEN && counterparty.State == chantypes.OPEN {
+			auditBacklog(ctx, audit, src, dst, ch, maxBacklog)
+		}
+	}
+	for _, ch := range dstChannels {
+		if !filterAllows(filter, ch.Counterparty.ChannelId) || srcByID[ch.Counterparty.PortId+"/"+ch.Counterparty.ChannelId] {
+			continue
+		}
+		audit.problem(dst.ChainID(), channelSubject(ch), "counterparty channel %s/%s is not on connection %s of %s",
+			ch.Counterparty.PortId, ch.Counterparty.ChannelId, src.ConnectionID(), src.ChainID())
+	}
+
+	return audit, nil
+}
+
+// filterAllows reports whether filter relays the src channel channelID.
+func filterAllows(filter ChannelFilter, channelID string) bool {
+	switch filter.Rule {
+	case processor.RuleAllowList:
+		return filter.InChannelList(channelID)
+	case processor.RuleDenyList:
+		return !filter.InChannelList(channelID)
+	default:
+		return true
+	}
+}
+
+// auditClientOf queries the client of the path end of c and audits it against counterparty.
+func auditClientOf(ctx context.Context, audit *PathAudit, c, counterparty *Chain, h, counterpartyH int64, now time.Time) {
+	subject := "client " + c.ClientID()
+	cs, err := c.ChainProvider.QueryClientState(ctx, h, c.ClientID())
+	if err != nil {
+		audit.problem(c.ChainID(), subject, "failed to query client state: %v", err)
+		return
+	}
+	tmcs, ok := cs.(*tmclient.ClientState)
+	if !ok {
+		audit.problem(c.ChainID(), subject, "not a tendermint client: %T", cs)
+		return
+	}
+	unbonding, err := counterparty.ChainProvider.QueryUnbondingPeriod(ctx)
+	if err != nil {
+		audit.problem(counterparty.ChainID(), "unbonding period", "failed to query unbonding period: %v", err)
+	}
+	updated, err := clientUpdateTime(ctx, c, h, c.ClientID(), tmcs)
+	if err != nil {
+		audit.problem(c.ChainID(), subject, "%v", err)
+	}
+	auditClient(audit, clientAudit{
+		chainID:               c.ChainID(),
+		clientID:              c.ClientID(),
+		clientState:           tmcs,
+		updated:               updated,
+		counterpartyChainID:   counterparty.ChainID(),
+		counterpartyHeight:    counterpartyH,
+		counterpartyUnbonding: unbonding,
+	}, now)
+}
+
+// clientAudit is the state of a client and its counterparty chain checked by auditClient.
+// Zero updated and counterpartyUnbonding values are not checked.
+type clientAudit struct {
+	chainID, clientID     string
+	clientState           *tmclient.ClientState
+	updated               time.Time
+	counterpartyChainID   string
+	counterpartyHeight    int64
+	counterpartyUnbonding time.Duration
+}
+
+func auditClient(audit *PathAudit, c clientAudit, now time.Time) {
+	subject := "client " + c.clientID
+	cs := c.clientState
+
+	if cs.ChainId == c.counterpartyChainID {
+		audit.ok(c.chainID, subject, "tracks %s", cs.ChainId)
+	} else {
+		audit.problem(c.chainID, subject, "tracks chain %s instead of %s", cs.ChainId, c.counterpartyChainID)
+	}
+
+	if c.counterpartyUnbonding != 0 {
+		if cs.TrustingPeriod < c.counterpartyUnbonding {
+			audit.ok(c.chainID, subject, "trusting period %s is shorter than the unbonding period %s of %s",
+				cs.TrustingPeriod, c.counterpartyUnbonding, c.counterpartyChainID)
+		} else {
+			audit.problem(c.chainID, subject, "trusting period %s is not shorter than the unbonding period %s of %s",
+				cs.TrustingPeriod, c.counterpartyUnbonding, c.counterpartyChainID)
+		}
+	}
+
+	if !cs.FrozenHeight.IsZero() {
+		audit.problem(c.chainID, subject, "frozen at height %s", cs.FrozenHeight)
+	}
+	if !c.updated.IsZero() {
+		expiration := c.updated.Add(cs.TrustingPeriod)
+		if now.Before(expiration) {
+			audit.ok(c.chainID, subject, "expires in %s", expiration.Sub(now).Round(time.Second))
+		} else {
+			audit.problem(c.chainID, subject, "expired at %s", expiration.UTC().Format(time.RFC3339))
+		}
+	}
+
+	latest := cs.LatestHeight
+	revision := clienttypes.ParseChainID(c.counterpartyChainID)
+	switch {
+	case latest.RevisionNumber != revision:
+		audit.problem(c.chainID, subject, "latest height %s is on revision %d, but %s is on revision %d",
+			latest, latest.RevisionNumber, c.counterpartyChainID, revision)
+	case int64(latest.RevisionHeight) > c.counterpartyHeight:
+		audit.problem(c.chainID, subject, "latest height %s is ahead of %s at height %d",
+			latest, c.counterpartyChainID, c.counterpartyHeight)
+	default:
+		audit.ok(c.chainID, subject, "latest height %s is %d blocks behind %s",
+			latest, c.counterpartyHeight-int64(latest.RevisionHeight), c.counterpartyChainID)
+	}
+}
+
+// auditConnectionOf queries the connection of the path end of c and audits it against counterparty.
+// It reports whether the connection matches the path.
+func auditConnectionOf(ctx context.Context, audit *PathAudit, c, counterparty *Chain, h int64) bool {
+	res, err := c.ChainProvider.QueryConnection(ctx, h, c.ConnectionID())
+	if err != nil {
+		audit.problem(c.ChainID(), "connection "+c.ConnectionID(), "failed to query connection: %v", err)
+		return false
+	}
+	return auditConnection(audit, c.ChainID(), c.PathEnd, counterparty.PathEnd, res.Connection)
+}
+
+// auditConnection checks that conn, the connection of pe, is open on the client of pe and points back at the counterparty path end.
+func auditConnection(audit *PathAudit, chainID string, pe, counterparty *PathEnd, conn *conntypes.ConnectionEnd) bool {
+	subject := "connection " + pe.ConnectionID
+	problems := 0
+	problem := func(format string, args ...any) {
+		audit.problem(chainID, subject, format, args...)
+		problems++
+	}
+	if conn == nil {
+		problem("not found")
+		return false
+	}
+	if conn.State != conntypes.OPEN {
+		problem("is %s, not OPEN", conn.State)
+	}
+	if conn.ClientId != pe.ClientID {
+		problem("is on client %s instead of %s", conn.ClientId, pe.ClientID)
+	}
+	if conn.Counterparty.ClientId != counterparty.ClientID {
+		problem("counterparty client is %s instead of %s", conn.Counterparty.ClientId, counterparty.ClientID)
+	}
+	if conn.Counterparty.ConnectionId != counterparty.ConnectionID {
+		problem("counterparty connection is %s instead of %s", conn.Counterparty.ConnectionId, counterparty.ConnectionID)
+	}
+	if problems > 0 {
+		return false
+	}
+	audit.ok(chainID, subject, "is open with counterparty connection %s on client %s", counterparty.ConnectionID, counterparty.ClientID)
+	return true
+}
+
+func channelSubject(ch *chantypes.IdentifiedChannel) string {
+	return "channel " + ch.PortId + "/" + ch.ChannelId
+}
+
+// auditChannel checks that ch and its counterparty channel, nil if it is not on the counterparty connection, point at each other
+// and are in the same state. It reports whether the channels match.
+func auditChannel(audit *PathAudit, chainID, counterpartyChainID string, ch, counterparty *chantypes.IdentifiedChannel) bool {
+	subject := channelSubject(ch)
+	if counterparty == nil {
+		audit.problem(chainID, subject, "counterparty channel %s/%s is not on the connection of %s",
+			ch.Counterparty.PortId, ch.Counterparty.ChannelId, counterpartyChainID)
+		return false
+	}
+	if counterparty.Counterparty.PortId != ch.PortId || counterparty.Counterparty.ChannelId != ch.ChannelId {
+		audit.problem(chainID, subject, "counterparty channel %s/%s on %s points at %s/%s",
+			counterparty.PortId, counterparty.ChannelId, counterpartyChainID,
+			counterparty.Counterparty.PortId, counterparty.Counterparty.ChannelId)
+		return false
+	}
+	if ch.State != counterparty.State {
+		audit.problem(chainID, subject, "is %s, but counterparty channel %s/%s on %s is %s",
+			ch.State, counterparty.PortId, counterparty.ChannelId, counterpartyChainID, counterparty.State)
+		return false
+	}
+	audit.ok(chainID, subject, "is %s with counterparty channel %s/%s", ch.State, counterparty.PortId, counterparty.ChannelId)
+	return true
+}
+
+// auditBacklog reports the unrelayed packets and acknowledgements of the open channel ch of src in both directions.
+// A backlog which cannot be queried is a problem.
+func auditBacklog(ctx context.Context, audit *PathAudit, src, dst *Chain, ch *chantypes.IdentifiedChannel, maxBacklog int) {
+	b, err := queryChannelBacklog(ctx, src, dst, ch)
+	if err != nil {
+		audit.problem(src.ChainID(), channelSubject(ch), "failed to query backlog: %v", err)
+		return
+	}
+
+	backlog := len(b.packetsToDst) + len(b.packetsToSrc) + len(b.acksToDst) + len(b.acksToSrc)
+	format := "backlog of %d: %d packets and %d acknowledgements to relay to %s, %d packets and %d acknowledgements to relay from %s"
+	args := []any{backlog, len(b.packetsToDst), len(b.acksToDst), dst.ChainID(), len(b.packetsToSrc), len(b.acksToSrc), dst.ChainID()}
+	if maxBacklog >= 0 && backlog > maxBacklog {
+		audit.problem(src.ChainID(), channelSubject(ch), format+" (max %d)", append(args, maxBacklog)...)
+		return
+	}
+	audit.ok(src.ChainID(), channelSubject(ch), format, args...)
+}
+
+// channelBacklog holds the sequences of the unrelayed packets and acknowledgements of a channel in both directions.
+type channelBacklog struct {
+	packetsToDst, packetsToSrc []uint64
+	acksToDst, acksToSrc       []uint64
+}
+
+// queryChannelBacklog queries the backlog of the channel ch of src. Unlike UnrelayedSequences and UnrelayedAcknowledgements,
+// it fails if any query fails, instead of reporting an empty backlog.
+func queryChannelBacklog(ctx context.Context, src, dst *Chain, ch *chantypes.IdentifiedChannel) (*channelBacklog, error) {
+	srch, dsth, err := QueryLatestHeights(ctx, src, dst)
+	if err != nil {
+		return nil, err
+	}
+	cp := ch.Counterparty
+
+	var srcCommitments, dstCommitments, srcAcks, dstAcks []uint64
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() (err error) {
+		srcCommitments, err = queryCommitmentSequences(egCtx, src, srch, ch.ChannelId, ch.PortId)
+		return err
+	})
+	eg.Go(func() (err error) {
+		dstCommitments, err = queryCommitmentSequences(egCtx, dst, dsth, cp.ChannelId, cp.PortId)
+		return err
+	})
+	eg.Go(func() (err error) {
+		srcAcks, err = queryAcknowledgementSequences(egCtx, src, srch, ch.ChannelId, ch.PortId)
+		return err
+	})
+	eg.Go(func() (err error) {
+		dstAcks, err = queryAcknowledgementSequences(egCtx, dst, dsth, cp.ChannelId, cp.PortId)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	b := &channelBacklog{}
+	eg, egCtx = errgroup.WithContext(ctx)
+	if len(srcCommitments) > 0 {
+		eg.Go(func() (err error) {
+			b.packetsToDst, err = dst.ChainProvider.QueryUnreceivedPackets(egCtx, uint64(dsth), cp.ChannelId, cp.PortId, srcCommitments)
+			if err != nil {
+				return fmt.Errorf("failed to query unreceived packets on %s: %w", dst.ChainID(), err)
+			}
+			return nil
+		})
+	}
+	if len(dstCommitments) > 0 {
+		eg.Go(func() (err error) {
+			b.packetsToSrc, err = src.ChainProvider.QueryUnreceivedPackets(egCtx, uint64(srch), ch.ChannelId, ch.PortId, dstCommitments)
+			if err != nil {
+				return fmt.Errorf("failed to query unreceived packets on %s: %w", src.ChainID(), err)
+			}
+			return nil
+		})
+	}
+	if len(srcAcks) > 0 {
+		eg.Go(func() (err error) {
+			b.acksToDst, err = dst.ChainProvider.QueryUnreceivedAcknowledgements(egCtx, uint64(dsth), cp.ChannelId, cp.PortId, srcAcks)
+			if err != nil {
+				return fmt.Errorf("failed to query unreceived acknowledgements on %s: %w", dst.ChainID(), err)
+			}
+			return nil
+		})
+	}
+	if len(dstAcks) > 0 {
+		eg.Go(func() (err error) {
+			b.acksToSrc, err = src.ChainProvider.QueryUnreceivedAcknowledgements(egCtx, uint64(srch), ch.ChannelId, ch.PortId, dstAcks)
+			if err != nil {
+				return fmt.Errorf("failed to query unreceived acknowledgements on %s: %w", src.ChainID(), err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// queryCommitmentSequences returns the sequences of the packet commitments of a channel of c at height.
+// It fails if the provider returns only a page of the commitments.
+func queryCommitmentSequences(ctx context.Context, c *Chain, height int64, channelID, portID string) ([]uint64, error) {
+	res, err := c.ChainProvider.QueryPacketCommitments(ctx, uint64(height), channelID, portID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packet commitments of %s/%s on %s: %w", portID, channelID, c.ChainID(), err)
+	}
+	if res == nil {
+		return nil, fmt.Errorf("no packet commitments of %s/%s returned by %s", portID, channelID, c.ChainID())
+	}
+	if res.Pagination != nil && len(res.Pagination.NextKey) > 0 {
+		// A partial backlog would be reported as smaller than it is.
+		return nil, fmt.Errorf("only the first %d packet commitments of %s/%s were returned by %s", len(res.Commitments), portID, channelID, c.ChainID())
+	}
+	seqs := make([]uint64, 0, len(res.Commitments))
+	for _, pc := range res.Commitments {
+		seqs = append(seqs, pc.Sequence)
+	}
+	return seqs, nil
+}
+
+// queryAcknowledgementSequences returns the sequences of the packet acknowledgements of a channel of c at height.
+func queryAcknowledgementSequences(ctx context.Context, c *Chain, height int64, channelID, portID string) ([]uint64, error) {
+	res, err := c.ChainProvider.QueryPacketAcknowledgements(ctx, uint64(height), channelID, portID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packet acknowledgements of %s/%s on %s: %w", portID, channelID, c.ChainID(), err)
+	}
+	seqs := make([]uint64, 0, len(res))
+	for _, ack := range res {
+		seqs = append(seqs, ack.Sequence)
+	}
+	return seqs, nil
+}
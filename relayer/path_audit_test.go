@@ -0,0 +1,204 @@
+package relayer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	querytypes "github.com/cosmos/cosmos-sdk/types/query"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// problemMessages returns the messages of the problems found by audit.
+func problemMessages(audit *PathAudit) []string {
+	var msgs []string
+	for _, f := range audit.Findings {
+		if f.Problem {
+			msgs = append(msgs, f.Message)
+		}
+	}
+	return msgs
+}
+
+func TestAuditClient(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	healthy := clientAudit{
+		chainID:  "cosmoshub-4",
+		clientID: "07-tendermint-1",
+		clientState: &tmclient.ClientState{
+			ChainId:        "osmosis-1",
+			TrustingPeriod: 10 * 24 * time.Hour,
+			LatestHeight:   clienttypes.NewHeight(1, 6_000_000),
+		},
+		updated:               now.Add(-time.Hour),
+		counterpartyChainID:   "osmosis-1",
+		counterpartyHeight:    6_000_100,
+		counterpartyUnbonding: 14 * 24 * time.Hour,
+	}
+
+	audit := &PathAudit{}
+	auditClient(audit, healthy, now)
+	require.Zero(t, audit.Problems())
+	require.Contains(t, audit.Findings, AuditFinding{
+		ChainID: "cosmoshub-4",
+		Subject: "client 07-tendermint-1",
+		Message: "latest height 1-6000000 is 100 blocks behind osmosis-1",
+	})
+
+	broken := healthy
+	cs := *healthy.clientState
+	broken.clientState = &cs
+	cs.ChainId = "juno-1"
+	cs.TrustingPeriod = 21 * 24 * time.Hour
+	cs.FrozenHeight = clienttypes.NewHeight(1, 5_000_000)
+	broken.updated = now.Add(-22 * 24 * time.Hour)
+	broken.counterpartyHeight = 5_900_000
+
+	audit = &PathAudit{}
+	auditClient(audit, broken, now)
+	require.Equal(t, []string{
+		"tracks chain juno-1 instead of osmosis-1",
+		"trusting period 504h0m0s is not shorter than the unbonding period 336h0m0s of osmosis-1",
+		"frozen at height 1-5000000",
+		"expired at 2022-09-30T00:00:00Z",
+		"latest height 1-6000000 is ahead of osmosis-1 at height 5900000",
+	}, problemMessages(audit))
+
+	// The counterparty chain was upgraded to a new revision.
+	upgraded := healthy
+	upgraded.counterpartyChainID = "osmosis-2"
+	upgraded.clientState = &tmclient.ClientState{ChainId: "osmosis-2", LatestHeight: clienttypes.NewHeight(1, 6_000_000)}
+	upgraded.updated = time.Time{}
+	upgraded.counterpartyUnbonding = 0
+
+	audit = &PathAudit{}
+	auditClient(audit, upgraded, now)
+	require.Equal(t, []string{
+		"latest height 1-6000000 is on revision 1, but osmosis-2 is on revision 2",
+	}, problemMessages(audit))
+}
+
+func TestAuditConnection(t *testing.T) {
+	pe := &PathEnd{ChainID: "cosmoshub-4", ClientID: "07-tendermint-1", ConnectionID: "connection-0"}
+	counterparty := &PathEnd{ChainID: "osmosis-1", ClientID: "07-tendermint-5", ConnectionID: "connection-10"}
+
+	audit := &PathAudit{}
+	require.True(t, auditConnection(audit, "cosmoshub-4", pe, counterparty, &conntypes.ConnectionEnd{
+		ClientId:     "07-tendermint-1",
+		State:        conntypes.OPEN,
+		Counterparty: conntypes.Counterparty{ClientId: "07-tendermint-5", ConnectionId: "connection-10"},
+	}))
+	require.Zero(t, audit.Problems())
+
+	audit = &PathAudit{}
+	require.False(t, auditConnection(audit, "cosmoshub-4", pe, counterparty, &conntypes.ConnectionEnd{
+		ClientId:     "07-tendermint-2",
+		State:        conntypes.TRYOPEN,
+		Counterparty: conntypes.Counterparty{ClientId: "07-tendermint-5", ConnectionId: "connection-11"},
+	}))
+	require.Equal(t, []string{
+		"is STATE_TRYOPEN, not OPEN",
+		"is on client 07-tendermint-2 instead of 07-tendermint-1",
+		"counterparty connection is connection-11 instead of connection-10",
+	}, problemMessages(audit))
+}
+
+func TestAuditChannel(t *testing.T) {
+	ch := identifiedChannel("transfer", "channel-141", "channel-0", chantypes.UNORDERED, "ics20-1")
+
+	audit := &PathAudit{}
+	require.True(t, auditChannel(audit, "cosmoshub-4", "osmosis-1", ch,
+		identifiedChannel("transfer", "channel-0", "channel-141", chantypes.UNORDERED, "ics20-1")))
+	require.Zero(t, audit.Problems())
+
+	audit = &PathAudit{}
+	require.False(t, auditChannel(audit, "cosmoshub-4", "osmosis-1", ch, nil))
+	require.False(t, auditChannel(audit, "cosmoshub-4", "osmosis-1", ch,
+		identifiedChannel("transfer", "channel-0", "channel-7", chantypes.UNORDERED, "ics20-1")))
+	closed := identifiedChannel("transfer", "channel-0", "channel-141", chantypes.UNORDERED, "ics20-1")
+	closed.State = chantypes.CLOSED
+	require.False(t, auditChannel(audit, "cosmoshub-4", "osmosis-1", ch, closed))
+	require.Equal(t, []string{
+		"counterparty channel transfer/channel-0 is not on the connection of osmosis-1",
+		"counterparty channel transfer/channel-0 on osmosis-1 points at transfer/channel-7",
+		"is STATE_OPEN, but counterparty channel transfer/channel-0 on osmosis-1 is STATE_CLOSED",
+	}, problemMessages(audit))
+}
+
+// backlogProvider serves the packet commitments and acknowledgements of a chain, or fails to if err is set.
+type backlogProvider struct {
+	provider.ChainProvider
+	chainID     string
+	commitments []uint64
+	// truncated is set if the commitments are only the first page of the query.
+	truncated bool
+	err       error
+}
+
+func (p backlogProvider) ChainId() string { return p.chainID }
+
+func (p backlogProvider) QueryLatestHeight(ctx context.Context) (int64, error) { return 100, nil }
+
+func (p backlogProvider) QueryPacketCommitments(ctx context.Context, height uint64, channelid, portid string) (*chantypes.QueryPacketCommitmentsResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	res := &chantypes.QueryPacketCommitmentsResponse{}
+	for _, seq := range p.commitments {
+		res.Commitments = append(res.Commitments, &chantypes.PacketState{Sequence: seq})
+	}
+	if p.truncated {
+		res.Pagination = &querytypes.PageResponse{NextKey: []byte{1}}
+	}
+	return res, nil
+}
+
+func (p backlogProvider) QueryPacketAcknowledgements(ctx context.Context, height uint64, channelid, portid string) ([]*chantypes.PacketState, error) {
+	return []*chantypes.PacketState{}, p.err
+}
+
+func (p backlogProvider) QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error) {
+	return seqs, p.err
+}
+
+func TestAuditBacklog(t *testing.T) {
+	ch := identifiedChannel("transfer", "channel-141", "channel-0", chantypes.UNORDERED, "ics20-1")
+	src := NewChain(zap.NewNop(), backlogProvider{chainID: "cosmoshub-4", commitments: []uint64{1, 2}}, false)
+	dst := NewChain(zap.NewNop(), backlogProvider{chainID: "osmosis-1"}, false)
+
+	audit := &PathAudit{}
+	auditBacklog(context.Background(), audit, src, dst, ch, 1)
+	require.Equal(t, []string{
+		"backlog of 2: 2 packets and 0 acknowledgements to relay to osmosis-1, 0 packets and 0 acknowledgements to relay from osmosis-1 (max 1)",
+	}, problemMessages(audit))
+
+	// A chain which cannot be queried does not have an empty backlog.
+	dst = NewChain(zap.NewNop(), backlogProvider{chainID: "osmosis-1", err: errors.New("connection refused")}, false)
+	audit = &PathAudit{}
+	auditBacklog(context.Background(), audit, src, dst, ch, -1)
+	require.Equal(t, 1, audit.Problems())
+	require.Contains(t, problemMessages(audit)[0], "failed to query backlog")
+	require.Contains(t, problemMessages(audit)[0], "connection refused")
+
+	// Nor does a chain which returns only a page of its commitments have a backlog of one page.
+	src = NewChain(zap.NewNop(), backlogProvider{chainID: "cosmoshub-4", commitments: []uint64{1, 2}, truncated: true}, false)
+	dst = NewChain(zap.NewNop(), backlogProvider{chainID: "osmosis-1"}, false)
+	audit = &PathAudit{}
+	auditBacklog(context.Background(), audit, src, dst, ch, -1)
+	require.Equal(t, 1, audit.Problems())
+	require.Contains(t, problemMessages(audit)[0], "only the first 2 packet commitments of transfer/channel-141 were returned by cosmoshub-4")
+}
+
+func TestFilterAllows(t *testing.T) {
+	require.True(t, filterAllows(ChannelFilter{}, "channel-0"))
+	require.True(t, filterAllows(ChannelFilter{Rule: "allowlist", ChannelList: []string{"channel-0"}}, "channel-0"))
+	require.False(t, filterAllows(ChannelFilter{Rule: "allowlist", ChannelList: []string{"channel-0"}}, "channel-1"))
+	require.False(t, filterAllows(ChannelFilter{Rule: "denylist", ChannelList: []string{"channel-0"}}, "channel-0"))
+}
@@ -0,0 +1,46 @@
+package relayer
+
+import (
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelFilterMerge(t *testing.T) {
+	allow := func(channels ...string) ChannelFilter {
+		return ChannelFilter{Rule: processor.RuleAllowList, ChannelList: channels}
+	}
+	deny := func(channels ...string) ChannelFilter {
+		return ChannelFilter{Rule: processor.RuleDenyList, ChannelList: channels}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		cf, other ChannelFilter
+		want      ChannelFilter
+		wantErr   bool
+	}{
+		{"no filters", ChannelFilter{}, ChannelFilter{}, ChannelFilter{}, false},
+		{"only existing", allow("channel-0"), ChannelFilter{}, allow("channel-0"), false},
+		{"only other", ChannelFilter{}, deny("channel-1"), deny("channel-1"), false},
+		{"allowlists", allow("channel-0", "channel-1"), allow("channel-1", "channel-2"), allow("channel-0", "channel-1", "channel-2"), false},
+		{"denylists", deny("channel-0"), deny("channel-0", "channel-3"), deny("channel-0", "channel-3"), false},
+		{"denylist and allowlist", deny("channel-1"), allow("channel-0", "channel-1"), allow("channel-0"), false},
+		{"allowlist and denylist", allow("channel-0", "channel-1"), deny("channel-0"), allow("channel-1"), false},
+		{"all denied", allow("channel-0"), deny("channel-0"), ChannelFilter{}, true},
+		{"all denied by existing", deny("channel-0", "channel-1"), allow("channel-1"), ChannelFilter{}, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.cf.Merge(tc.other)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want.Rule, got.Rule)
+			require.ElementsMatch(t, tc.want.ChannelList, got.ChannelList)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// processedConsensusState is a consensus state of the client of a path end, along with the latest block
+// of the path end chain when it was observed, which is no earlier than the block that processed it.
+type processedConsensusState struct {
+	height clienttypes.Height
+	// time is the timestamp of the consensus state, zero if unknown.
+	time time.Time
+
+	processedHeight uint64
+	processedTime   time.Time
+}
+
+// delayPassed reports whether the delay period passed at the latest block since the consensus state was processed,
+// both in time and in blocks, so that proofs at its height can be verified on a connection with the delay period.
+func (cs processedConsensusState) delayPassed(latest provider.LatestBlock, delay time.Duration, blockDelay uint64) bool {
+	return !latest.Time.Before(cs.processedTime.Add(delay)) && latest.Height >= cs.processedHeight+blockDelay
+}
+
+// recordConsensusState keeps the latest consensus state of the client, observed at the latest block,
+// so that packets can be proven at its height once the delay period of their connection passed.
+func (pathEnd *pathEndRuntime) recordConsensusState(clientState provider.ClientState, latest provider.LatestBlock) {
+	pathEnd.consensusStates = append(pathEnd.consensusStates, processedConsensusState{
+		height:          clientState.ConsensusHeight,
+		time:            clientState.ConsensusTime,
+		processedHeight: latest.Height,
+		processedTime:   latest.Time,
+	})
+	if len(pathEnd.consensusStates) > consensusStatesToCache {
+		pathEnd.consensusStates = pathEnd.consensusStates[len(pathEnd.consensusStates)-consensusStatesToCache:]
+	}
+}
+
+// connectionDelay returns the delay period of the connection of the channel on the path end chain,
+// and the number of blocks of the max expected time per block of the chain it corresponds to.
+func (pathEnd *pathEndRuntime) connectionDelay(ctx context.Context, k ChannelKey) (time.Duration, uint64, error) {
+	delay, ok := pathEnd.connectionDelays[k]
+	if !ok {
+		channel, err := pathEnd.chainProvider.QueryChannel(ctx, 0, k.ChannelID, k.PortID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error querying channel %s/%s: %w", k.PortID, k.ChannelID, err)
+		}
+		if len(channel.Channel.ConnectionHops) == 0 {
+			return 0, 0, fmt.Errorf("channel %s/%s has no connection", k.PortID, k.ChannelID)
+		}
+		connection, err := pathEnd.chainProvider.QueryConnection(ctx, 0, channel.Channel.ConnectionHops[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("error querying connection %s: %w", channel.Channel.ConnectionHops[0], err)
+		}
+		delay = time.Duration(connection.Connection.DelayPeriod)
+		pathEnd.connectionDelays[k] = delay
+	}
+	if delay == 0 {
+		return 0, 0, nil
+	}
+
+	if pathEnd.maxExpectedTimePerBlock == 0 {
+		maxExpectedTimePerBlock, err := pathEnd.chainProvider.QueryMaxExpectedTimePerBlock(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error querying max expected time per block: %w", err)
+		}
+		if maxExpectedTimePerBlock <= 0 {
+			return 0, 0, fmt.Errorf("invalid max expected time per block: %s", maxExpectedTimePerBlock)
+		}
+		pathEnd.maxExpectedTimePerBlock = maxExpectedTimePerBlock
+	}
+	// Same rounding up as ibc-go uses to compute the block delay of a connection.
+	blockDelay := uint64((delay + pathEnd.maxExpectedTimePerBlock - 1) / pathEnd.maxExpectedTimePerBlock)
+	return delay, blockDelay, nil
+}
+
+// provableAt reports whether the packet message can be proven at a consensus state of the counterparty chain.
+// Receives and acknowledgements are proven after the height of their event on the counterparty chain.
+// Timeouts carry the info of the packet sent on the chain receiving them, so only the timeout of the packet
+// is compared to the consensus state.
+func (msg packetIBCMessage) provableAt(height clienttypes.Height, consensusTime time.Time) bool {
+	switch msg.eventType {
+	case chantypes.EventTypeTimeoutPacket:
+		heightTimedOut := !msg.info.TimeoutHeight.IsZero() && height.GTE(msg.info.TimeoutHeight)
+		timestampTimedOut := msg.info.TimeoutTimestamp != 0 && !consensusTime.IsZero() &&
+			uint64(consensusTime.UnixNano()) >= msg.info.TimeoutTimestamp
+		return heightTimedOut || timestampTimedOut
+	case chantypes.EventTypeTimeoutPacketOnClose:
+		return true
+	default:
+		return height.RevisionHeight > msg.info.Height
+	}
+}
+
+// delayedConsensusState returns the latest consensus state of the client that passed the delay period.
+func (pathEnd *pathEndRuntime) delayedConsensusState(delay time.Duration, blockDelay uint64) (*processedConsensusState, bool) {
+	for i := len(pathEnd.consensusStates) - 1; i >= 0; i-- {
+		if pathEnd.consensusStates[i].delayPassed(pathEnd.latestBlock, delay, blockDelay) {
+			return &pathEnd.consensusStates[i], true
+		}
+	}
+	return nil, false
+}
+
+// delayedProofHeight returns the height of the latest consensus state of the client that passed the delay period,
+// if the packet message can be proven at it.
+func (pathEnd *pathEndRuntime) delayedProofHeight(msg packetIBCMessage, delay time.Duration, blockDelay uint64) (uint64, bool) {
+	cs, ok := pathEnd.delayedConsensusState(delay, blockDelay)
+	// Older consensus states are lower and earlier, so they cannot be used if this one cannot.
+	if !ok || !msg.provableAt(cs.height, cs.time) {
+		return 0, false
+	}
+	return cs.height.RevisionHeight, true
+}
+
+// needsConsensusTime reports whether proving the packet message depends on the timestamp of the consensus state.
+func (msg packetIBCMessage) needsConsensusTime() bool {
+	return msg.eventType == chantypes.EventTypeTimeoutPacket && msg.info.TimeoutTimestamp != 0
+}
+
+// consensusTime returns the timestamp of the consensus state of the client of the path end at height,
+// from the header of the counterparty chain at that height, which is queried if it is not cached.
+func (pathEnd *pathEndRuntime) consensusTime(ctx context.Context, counterparty *pathEndRuntime, height clienttypes.Height) (time.Time, error) {
+	h, ok := counterparty.ibcHeaderCache[height.RevisionHeight]
+	if !ok {
+		var err error
+		h, err = counterparty.chainProvider.QueryIBCHeader(ctx, int64(height.RevisionHeight))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error querying header at height %d: %w", height.RevisionHeight, err)
+		}
+	}
+	return time.Unix(0, int64(h.ConsensusState().GetTimestamp())), nil
+}
+
+// resolveConsensusTimes sets the unknown timestamps of the consensus states that timeouts on a connection
+// with the delay period are compared to: the latest one that passed the delay period, and the latest one of the client.
+// Not all headers are cached, so a missing timestamp does not mean that the consensus state has none.
+func (pathEnd *pathEndRuntime) resolveConsensusTimes(ctx context.Context, counterparty *pathEndRuntime, delay time.Duration, blockDelay uint64) error {
+	if cs, ok := pathEnd.delayedConsensusState(delay, blockDelay); ok && cs.time.IsZero() {
+		t, err := pathEnd.consensusTime(ctx, counterparty, cs.height)
+		if err != nil {
+			return err
+		}
+		cs.time = t
+	}
+	if pathEnd.clientState.ConsensusTime.IsZero() && !pathEnd.clientState.ConsensusHeight.IsZero() {
+		t, err := pathEnd.consensusTime(ctx, counterparty, pathEnd.clientState.ConsensusHeight)
+		if err != nil {
+			return err
+		}
+		pathEnd.clientState.ConsensusTime = t
+	}
+	return nil
+}
+
+// delayPacketMessages holds back the packet messages to dst on connections with a delay period
+// until a consensus state of the client on dst passed it, and sets the height to prove the others at.
+// Held back messages are not tracked, so they don't use up their retries.
+// It reports whether a client update is needed for consensus states to prove the held back messages at.
+func (pp *PathProcessor) delayPacketMessages(
+	ctx context.Context,
+	src, dst *pathEndRuntime,
+	msgs []packetIBCMessage,
+) ([]packetIBCMessage, bool) {
+	ready := make([]packetIBCMessage, 0, len(msgs))
+	var needsClientUpdate bool
+	for _, msg := range msgs {
+		k, err := msg.channelKey()
+		if err != nil {
+			ready = append(ready, msg)
+			continue
+		}
+		delay, blockDelay, err := dst.connectionDelay(ctx, k)
+		if err != nil {
+			pp.log.Error("Error querying connection delay period",
+				zap.String("chain_id", dst.info.ChainID),
+				zap.Inline(k),
+				zap.Error(err),
+			)
+			continue
+		}
+		if delay == 0 {
+			ready = append(ready, msg)
+			continue
+		}
+		if msg.needsConsensusTime() {
+			if err := dst.resolveConsensusTimes(ctx, src, delay, blockDelay); err != nil {
+				pp.log.Error("Error querying consensus state time",
+					zap.String("chain_id", dst.info.ChainID),
+					zap.String("client_id", dst.info.ClientID),
+					zap.Error(err),
+				)
+			}
+		}
+		if proofHeight, ok := dst.delayedProofHeight(msg, delay, blockDelay); ok {
+			msg.proofHeight = proofHeight
+			ready = append(ready, msg)
+			continue
+		}
+		dst.log.Debug("Waiting to relay packet message until connection delay period passed",
+			zap.String("event_type", msg.eventType),
+			zap.Uint64("sequence", msg.info.Sequence),
+			zap.Inline(k),
+			zap.Duration("delay_period", delay),
+			zap.Uint64("delay_blocks", blockDelay),
+		)
+		if !msg.provableAt(dst.clientState.ConsensusHeight, dst.clientState.ConsensusTime) {
+			// There is no consensus state to prove the message at yet, it needs a client update.
+			needsClientUpdate = true
+		}
+	}
+
+	if !needsClientUpdate || len(ready) > 0 {
+		// The client is updated along with the ready messages.
+		return ready, false
+	}
+	if dst.delayClientUpdateHeight != 0 && dst.latestBlock.Height-dst.delayClientUpdateHeight < blocksToRetrySendAfter {
+		// A client update was sent recently and was not observed yet.
+		return ready, false
+	}
+	dst.delayClientUpdateHeight = dst.latestBlock.Height
+	return ready, true
+}
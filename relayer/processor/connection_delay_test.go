@@ -0,0 +1,184 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v5/modules/core/exported"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// The chain sending the packets is far ahead of the counterparty chain, which the client tracks.
+const (
+	delayTestSendHeight  = 5000
+	delayTestProofHeight = 100
+)
+
+var delayTestChannel = ChannelKey{
+	ChannelID:             "channel-0",
+	PortID:                "transfer",
+	CounterpartyChannelID: "channel-1",
+	CounterpartyPortID:    "transfer",
+}
+
+func newDelayTestPathEnd() *pathEndRuntime {
+	pathEnd := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, nil)
+	start := time.Unix(1_600_000_000, 0)
+	pathEnd.recordConsensusState(provider.ClientState{
+		ConsensusHeight: clienttypes.NewHeight(1, delayTestProofHeight),
+		ConsensusTime:   start,
+	}, provider.LatestBlock{Height: delayTestSendHeight, Time: start})
+	pathEnd.latestBlock = provider.LatestBlock{Height: delayTestSendHeight + 10, Time: start.Add(time.Minute)}
+	return pathEnd
+}
+
+func TestDelayedProofHeightTimeoutOnAheadChain(t *testing.T) {
+	pathEnd := newDelayTestPathEnd()
+
+	// The packet was sent at a height far above the proof height, which is irrelevant to its timeout.
+	timeout := packetIBCMessage{
+		eventType: chantypes.EventTypeTimeoutPacket,
+		info: provider.PacketInfo{
+			Height:        delayTestSendHeight,
+			TimeoutHeight: clienttypes.NewHeight(1, delayTestProofHeight-10),
+		},
+	}
+	proofHeight, ok := pathEnd.delayedProofHeight(timeout, 10*time.Second, 5)
+	require.True(t, ok, "timeout should be provable once the consensus state is past the timeout height")
+	require.Equal(t, uint64(delayTestProofHeight), proofHeight)
+
+	timeoutOnClose := timeout
+	timeoutOnClose.eventType = chantypes.EventTypeTimeoutPacketOnClose
+	_, ok = pathEnd.delayedProofHeight(timeoutOnClose, 10*time.Second, 5)
+	require.True(t, ok)
+
+	// Not timed out yet at the consensus state.
+	timeout.info.TimeoutHeight = clienttypes.NewHeight(1, delayTestProofHeight+10)
+	_, ok = pathEnd.delayedProofHeight(timeout, 10*time.Second, 5)
+	require.False(t, ok)
+
+	// The delay period has not passed yet.
+	timeout.info.TimeoutHeight = clienttypes.NewHeight(1, delayTestProofHeight-10)
+	_, ok = pathEnd.delayedProofHeight(timeout, time.Hour, 5)
+	require.False(t, ok)
+}
+
+func TestDelayedProofHeightRecvAfterEvent(t *testing.T) {
+	pathEnd := newDelayTestPathEnd()
+
+	recv := packetIBCMessage{
+		eventType: chantypes.EventTypeRecvPacket,
+		info:      provider.PacketInfo{Height: delayTestProofHeight - 1},
+	}
+	proofHeight, ok := pathEnd.delayedProofHeight(recv, 10*time.Second, 5)
+	require.True(t, ok)
+	require.Equal(t, uint64(delayTestProofHeight), proofHeight)
+
+	// The packet was sent at the consensus state height, so it needs a later one.
+	recv.info.Height = delayTestProofHeight
+	_, ok = pathEnd.delayedProofHeight(recv, 10*time.Second, 5)
+	require.False(t, ok)
+}
+
+func TestDelayPacketMessagesTimeoutNeedsNoClientUpdate(t *testing.T) {
+	pp := &PathProcessor{log: zap.NewNop()}
+	src := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"}, nil)
+	dst := newDelayTestPathEnd()
+	dst.clientState = provider.ClientState{ConsensusHeight: clienttypes.NewHeight(1, delayTestProofHeight)}
+	dst.connectionDelays[delayTestChannel] = time.Hour
+	dst.maxExpectedTimePerBlock = time.Second
+
+	timeout := packetIBCMessage{
+		eventType: chantypes.EventTypeTimeoutPacket,
+		info: provider.PacketInfo{
+			Height:        delayTestSendHeight,
+			Sequence:      1,
+			SourceChannel: delayTestChannel.ChannelID,
+			SourcePort:    delayTestChannel.PortID,
+			DestChannel:   delayTestChannel.CounterpartyChannelID,
+			DestPort:      delayTestChannel.CounterpartyPortID,
+			TimeoutHeight: clienttypes.NewHeight(1, delayTestProofHeight-10),
+		},
+	}
+	// The client already has a consensus state past the timeout, only the delay period is waited for.
+	ready, needsClientUpdate := pp.delayPacketMessages(context.Background(), src, dst, []packetIBCMessage{timeout})
+	require.Empty(t, ready)
+	require.False(t, needsClientUpdate)
+
+	// A consensus state before the timeout needs a client update.
+	timeout.info.TimeoutHeight = clienttypes.NewHeight(1, delayTestProofHeight+10)
+	ready, needsClientUpdate = pp.delayPacketMessages(context.Background(), src, dst, []packetIBCMessage{timeout})
+	require.Empty(t, ready)
+	require.True(t, needsClientUpdate)
+}
+
+// timedIBCHeader is a header with only the timestamp of its consensus state.
+type timedIBCHeader struct {
+	height uint64
+	time   time.Time
+}
+
+func (h timedIBCHeader) Height() uint64 { return h.height }
+func (h timedIBCHeader) ConsensusState() ibcexported.ConsensusState {
+	return &tmclient.ConsensusState{Timestamp: h.time}
+}
+
+func TestDelayPacketMessagesTimestampTimeoutResolvesConsensusTime(t *testing.T) {
+	pp := &PathProcessor{log: zap.NewNop()}
+	consensusTime := time.Unix(1_600_000_000, 0)
+	src := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"}, nil)
+	src.ibcHeaderCache[delayTestProofHeight] = timedIBCHeader{height: delayTestProofHeight, time: consensusTime}
+
+	// The header of the consensus state was not cached when the client state was processed.
+	dst := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, nil)
+	dst.clientState = provider.ClientState{ConsensusHeight: clienttypes.NewHeight(1, delayTestProofHeight)}
+	dst.recordConsensusState(dst.clientState, provider.LatestBlock{Height: delayTestSendHeight, Time: consensusTime})
+	dst.latestBlock = provider.LatestBlock{Height: delayTestSendHeight + 10, Time: consensusTime.Add(time.Minute)}
+	dst.connectionDelays[delayTestChannel] = 10 * time.Second
+	dst.maxExpectedTimePerBlock = time.Second
+
+	timeout := packetIBCMessage{
+		eventType: chantypes.EventTypeTimeoutPacket,
+		info: provider.PacketInfo{
+			Height:           delayTestSendHeight,
+			Sequence:         1,
+			SourceChannel:    delayTestChannel.ChannelID,
+			SourcePort:       delayTestChannel.PortID,
+			DestChannel:      delayTestChannel.CounterpartyChannelID,
+			DestPort:         delayTestChannel.CounterpartyPortID,
+			TimeoutTimestamp: uint64(consensusTime.Add(-time.Second).UnixNano()),
+		},
+	}
+	ready, needsClientUpdate := pp.delayPacketMessages(context.Background(), src, dst, []packetIBCMessage{timeout})
+	require.False(t, needsClientUpdate)
+	require.Len(t, ready, 1)
+	require.Equal(t, uint64(delayTestProofHeight), ready[0].proofHeight)
+	require.Equal(t, consensusTime, dst.clientState.ConsensusTime)
+}
+
+func TestMergeCacheDataKeepsConsensusTime(t *testing.T) {
+	consensusTime := time.Unix(1_600_000_000, 0)
+	counterparty := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"}, nil)
+	counterparty.ibcHeaderCache[delayTestProofHeight] = timedIBCHeader{height: delayTestProofHeight, time: consensusTime}
+	pathEnd := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, nil)
+
+	// The chain processor reports the client state without the consensus time.
+	d := ChainProcessorCacheData{
+		ClientState: provider.ClientState{ClientID: "07-tendermint-0", ConsensusHeight: clienttypes.NewHeight(1, delayTestProofHeight)},
+		LatestBlock: provider.LatestBlock{Height: delayTestSendHeight, Time: consensusTime},
+	}
+	pathEnd.mergeCacheData(context.Background(), func() {}, d, "chain-b", true, nil, counterparty)
+	require.Equal(t, consensusTime, pathEnd.clientState.ConsensusTime)
+
+	// The header is no longer cached, but the consensus height did not change.
+	delete(counterparty.ibcHeaderCache, delayTestProofHeight)
+	d.LatestBlock.Height++
+	pathEnd.mergeCacheData(context.Background(), func() {}, d, "chain-b", true, nil, counterparty)
+	require.Equal(t, consensusTime, pathEnd.clientState.ConsensusTime)
+}
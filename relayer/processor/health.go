@@ -0,0 +1,165 @@
+package processor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLivenessTimeout is how long a ChainProcessor may go without completing
+// a query loop iteration or processing a block before it is considered stuck.
+const DefaultLivenessTimeout = 5 * time.Minute
+
+// Health tracks the progress of the ChainProcessors and the sync state of the PathProcessors
+// so that liveness and readiness can be reported, e.g. to an orchestrator.
+// It is safe for concurrent use, and all methods are no-ops on a nil *Health.
+type Health struct {
+	mu     sync.RWMutex
+	chains map[string]*chainHealth
+	// paths holds the in sync state of each path end, keyed by path name then chain ID.
+	paths map[string]map[string]bool
+}
+
+type chainHealth struct {
+	latestHeight        int64
+	latestQueriedHeight int64
+	// lastAdvanced is the registration time until the ChainProcessor first advanced.
+	lastAdvanced time.Time
+	advanced     bool
+}
+
+// ChainHealth is the reported health of a single ChainProcessor.
+type ChainHealth struct {
+	ChainID             string    `json:"chain_id"`
+	LatestHeight        int64     `json:"latest_height"`
+	LatestQueriedHeight int64     `json:"latest_queried_height"`
+	Lag                 int64     `json:"lag"`
+	LastAdvanced        time.Time `json:"last_advanced"`
+	Live                bool      `json:"live"`
+	Ready               bool      `json:"ready"`
+}
+
+// PathHealth is the reported sync state of a single PathProcessor.
+type PathHealth struct {
+	Path   string          `json:"path"`
+	InSync map[string]bool `json:"in_sync"`
+	Ready  bool            `json:"ready"`
+}
+
+// HealthStatus is a snapshot of the health of all tracked ChainProcessors and PathProcessors.
+type HealthStatus struct {
+	Live   bool          `json:"live"`
+	Ready  bool          `json:"ready"`
+	Chains []ChainHealth `json:"chains"`
+	Paths  []PathHealth  `json:"paths"`
+}
+
+// NewHealth returns a new, empty Health tracker.
+func NewHealth() *Health {
+	return &Health{
+		chains: make(map[string]*chainHealth),
+		paths:  make(map[string]map[string]bool),
+	}
+}
+
+// RegisterChainProcessor adds an expected ChainProcessor so that the relayer is reported as not ready
+// until it first advances, and as not live if it does not advance within the liveness timeout.
+func (h *Health) RegisterChainProcessor(chainID string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.chains[chainID]; !ok {
+		h.chains[chainID] = &chainHealth{lastAdvanced: time.Now()}
+	}
+}
+
+// ChainProcessorAdvanced is called by ChainProcessors whenever their query loop makes progress,
+// with the latest height of the chain and the latest height that has been processed.
+func (h *Health) ChainProcessorAdvanced(chainID string, latestHeight, latestQueriedHeight int64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c, ok := h.chains[chainID]
+	if !ok {
+		c = new(chainHealth)
+		h.chains[chainID] = c
+	}
+	c.latestHeight = latestHeight
+	c.latestQueriedHeight = latestQueriedHeight
+	c.lastAdvanced = time.Now()
+	c.advanced = true
+}
+
+// registerPath adds a path so that it is reported as not ready until both path ends are in sync.
+func (h *Health) registerPath(pathName, chainID1, chainID2 string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.paths[pathName]; !ok {
+		h.paths[pathName] = map[string]bool{chainID1: false, chainID2: false}
+	}
+}
+
+// pathEndInSync records the in sync state of a path end.
+func (h *Health) pathEndInSync(pathName, chainID string, inSync bool) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.paths[pathName]
+	if !ok {
+		p = make(map[string]bool)
+		h.paths[pathName] = p
+	}
+	p[chainID] = inSync
+}
+
+// Status returns a snapshot of the current health.
+// The relayer is live when every ChainProcessor has advanced, or was registered, within livenessTimeout,
+// and ready when every registered ChainProcessor has advanced and every path end of every PathProcessor is in sync.
+func (h *Health) Status(livenessTimeout time.Duration) HealthStatus {
+	status := HealthStatus{Live: true, Ready: true}
+	if h == nil {
+		return status
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	now := time.Now()
+	for chainID, c := range h.chains {
+		live := now.Sub(c.lastAdvanced) <= livenessTimeout
+		status.Chains = append(status.Chains, ChainHealth{
+			ChainID:             chainID,
+			LatestHeight:        c.latestHeight,
+			LatestQueriedHeight: c.latestQueriedHeight,
+			Lag:                 c.latestHeight - c.latestQueriedHeight,
+			LastAdvanced:        c.lastAdvanced,
+			Live:                live,
+			Ready:               c.advanced,
+		})
+		status.Live = status.Live && live
+		status.Ready = status.Ready && c.advanced
+	}
+	sort.Slice(status.Chains, func(i, j int) bool { return status.Chains[i].ChainID < status.Chains[j].ChainID })
+
+	for pathName, p := range h.paths {
+		ph := PathHealth{Path: pathName, InSync: make(map[string]bool, len(p)), Ready: true}
+		for chainID, inSync := range p {
+			ph.InSync[chainID] = inSync
+			ph.Ready = ph.Ready && inSync
+		}
+		status.Paths = append(status.Paths, ph)
+		status.Ready = status.Ready && ph.Ready
+	}
+	sort.Slice(status.Paths, func(i, j int) bool { return status.Paths[i].Path < status.Paths[j].Path })
+
+	return status
+}
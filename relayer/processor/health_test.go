@@ -0,0 +1,72 @@
+package processor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealthStatus(t *testing.T) {
+	var nilHealth *processor.Health
+	nilStatus := nilHealth.Status(time.Minute)
+	require.True(t, nilStatus.Live)
+	require.True(t, nilStatus.Ready)
+
+	h := processor.NewHealth()
+
+	h.ChainProcessorAdvanced("chain-b", 110, 100)
+	h.ChainProcessorAdvanced("chain-a", 50, 49)
+
+	status := h.Status(time.Minute)
+	require.True(t, status.Live)
+	require.True(t, status.Ready, "no paths registered")
+	require.Len(t, status.Chains, 2)
+	require.Equal(t, "chain-a", status.Chains[0].ChainID)
+	require.Equal(t, int64(1), status.Chains[0].Lag)
+	require.Equal(t, int64(10), status.Chains[1].Lag)
+
+	// Chain processors that have not advanced within the timeout are not live.
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, h.Status(time.Millisecond).Live)
+
+	pp := processor.NewPathProcessor(
+		zap.NewNop(),
+		processor.NewPathEnd("a-b", "chain-a", "07-tendermint-0", "", nil),
+		processor.NewPathEnd("a-b", "chain-b", "07-tendermint-1", "", nil),
+		nil, "", 0,
+	)
+	pp.SetHealth(h)
+
+	status = h.Status(time.Minute)
+	require.False(t, status.Ready, "path ends are not in sync until reported")
+	require.Len(t, status.Paths, 1)
+	require.Equal(t, map[string]bool{"chain-a": false, "chain-b": false}, status.Paths[0].InSync)
+}
+
+func TestHealthRegisteredChainProcessor(t *testing.T) {
+	h := processor.NewHealth()
+	h.RegisterChainProcessor("chain-a")
+
+	// Registered chain processors are live, but not ready, until they first advance.
+	status := h.Status(time.Minute)
+	require.True(t, status.Live)
+	require.False(t, status.Ready)
+	require.Len(t, status.Chains, 1)
+	require.False(t, status.Chains[0].Ready)
+
+	// Chain processors which never advance stop being live after the timeout.
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, h.Status(time.Millisecond).Live)
+
+	h.ChainProcessorAdvanced("chain-a", 10, 10)
+	status = h.Status(time.Minute)
+	require.True(t, status.Live)
+	require.True(t, status.Ready)
+
+	// Registering again does not reset the progress.
+	h.RegisterChainProcessor("chain-a")
+	require.True(t, h.Status(time.Minute).Ready)
+}
@@ -10,6 +10,7 @@ type PrometheusMetrics struct {
 	PacketObservedCounter *prometheus.CounterVec
 	PacketRelayedCounter  *prometheus.CounterVec
 	LatestHeightGauge     *prometheus.GaugeVec
+	BlocksBehindGauge     *prometheus.GaugeVec
 	WalletBalance         *prometheus.GaugeVec
 	FeesSpent             *prometheus.GaugeVec
 }
@@ -26,6 +27,10 @@ func (m *PrometheusMetrics) SetLatestHeight(chain string, height int64) {
 	m.LatestHeightGauge.WithLabelValues(chain).Set(float64(height))
 }
 
+func (m *PrometheusMetrics) SetBlocksBehind(chain string, blocks int64) {
+	m.BlocksBehindGauge.WithLabelValues(chain).Set(float64(blocks))
+}
+
 func (m *PrometheusMetrics) SetWalletBalance(chain, key, denom string, balance float64) {
 	m.WalletBalance.WithLabelValues(chain, key, denom).Set(balance)
 }
@@ -54,6 +59,10 @@ func NewPrometheusMetrics() *PrometheusMetrics {
 			Name: "cosmos_relayer_chain_latest_height",
 			Help: "The current height of the chain",
 		}, heightLabels),
+		BlocksBehindGauge: registerer.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cosmos_relayer_chain_blocks_behind",
+			Help: "The number of blocks of the chain yet to be queried by the relayer",
+		}, heightLabels),
 		WalletBalance: registerer.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cosmos_relayer_wallet_balance",
 			Help: "The current balance for the relayer's wallet",
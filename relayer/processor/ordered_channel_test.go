@@ -0,0 +1,106 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var orderedTestChannel = ChannelKey{
+	ChannelID:             "channel-0",
+	PortID:                "transfer",
+	CounterpartyChannelID: "channel-1",
+	CounterpartyPortID:    "transfer",
+}
+
+// nextSeqAckProvider serves the next sequence to acknowledge of ordered channels.
+type nextSeqAckProvider struct {
+	provider.ChainProvider
+	nextSeqAck uint64
+}
+
+func (p nextSeqAckProvider) QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (uint64, error) {
+	return p.nextSeqAck, nil
+}
+
+type testRelayerMessage struct {
+	seq uint64
+}
+
+func (m testRelayerMessage) Type() string              { return "test" }
+func (m testRelayerMessage) MsgBytes() ([]byte, error) { return nil, nil }
+
+func orderedTestPacket(eventType string, order chantypes.Order, seq uint64) packetIBCMessage {
+	return packetIBCMessage{
+		eventType: eventType,
+		info: provider.PacketInfo{
+			Height:        10,
+			Sequence:      seq,
+			SourceChannel: orderedTestChannel.ChannelID,
+			SourcePort:    orderedTestChannel.PortID,
+			DestChannel:   orderedTestChannel.CounterpartyChannelID,
+			DestPort:      orderedTestChannel.CounterpartyPortID,
+			ChannelOrder:  order.String(),
+		},
+	}
+}
+
+func TestGetOrderedMessagesToSendAcksFromNextSeqAck(t *testing.T) {
+	pp := &PathProcessor{log: zap.NewNop()}
+	src := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-a", ClientID: "07-tendermint-0"}, nil)
+	dst := newPathEndRuntime(zap.NewNop(), PathEnd{ChainID: "chain-b", ClientID: "07-tendermint-1"}, nil)
+	src.channelStateCache[orderedTestChannel] = true
+	dst.latestBlock = provider.LatestBlock{Height: 100}
+
+	flow := pathEndPacketFlowMessages{Src: src, Dst: dst, ChannelKey: orderedTestChannel}
+	var acks []packetIBCMessage
+	for _, seq := range []uint64{2, 3, 4, 6} {
+		acks = append(acks, orderedTestPacket(chantypes.EventTypeAcknowledgePacket, chantypes.ORDERED, seq))
+	}
+
+	// Sequence 2 is already acknowledged, and 5 is missing.
+	src.chainProvider = nextSeqAckProvider{nextSeqAck: 3}
+	srcMsgs, dstMsgs := pp.getOrderedMessagesToSend(context.Background(), flow, acks)
+	require.Empty(t, dstMsgs)
+	require.Len(t, srcMsgs, 2)
+	require.Equal(t, uint64(3), srcMsgs[0].info.Sequence)
+	require.Equal(t, uint64(4), srcMsgs[1].info.Sequence)
+
+	// The acknowledgement of sequence 1 is missing, so none can be sent yet.
+	src.chainProvider = nextSeqAckProvider{nextSeqAck: 1}
+	srcMsgs, _ = pp.getOrderedMessagesToSend(context.Background(), flow, acks)
+	require.Empty(t, srcMsgs)
+}
+
+func TestAppendAssembledPacketsStopsAtOrderedGap(t *testing.T) {
+	om := outgoingMessages{}
+	om.pktMsgs = []packetMessageToTrack{
+		{msg: orderedTestPacket(chantypes.EventTypeRecvPacket, chantypes.ORDERED, 1), assembled: true},
+		{msg: orderedTestPacket(chantypes.EventTypeRecvPacket, chantypes.ORDERED, 2)},
+		{msg: orderedTestPacket(chantypes.EventTypeRecvPacket, chantypes.ORDERED, 3), assembled: true},
+		{msg: orderedTestPacket(chantypes.EventTypeRecvPacket, chantypes.UNORDERED, 1)},
+		{msg: orderedTestPacket(chantypes.EventTypeRecvPacket, chantypes.UNORDERED, 2), assembled: true},
+	}
+	assembled := []provider.RelayerMessage{
+		testRelayerMessage{seq: 1},
+		nil,
+		testRelayerMessage{seq: 3},
+		nil,
+		testRelayerMessage{seq: 2},
+	}
+
+	om.AppendAssembledPackets(assembled)
+
+	// Sequence 3 of the ordered channel is dropped since 2 failed, unordered packets are unaffected.
+	require.Equal(t, []provider.RelayerMessage{testRelayerMessage{seq: 1}, testRelayerMessage{seq: 2}}, om.msgs)
+	require.Len(t, om.pktMsgs, 4)
+	for _, m := range om.pktMsgs {
+		if m.msg.info.ChannelOrder == chantypes.ORDERED.String() {
+			require.NotEqual(t, uint64(3), m.msg.info.Sequence, "packet after the gap should not be tracked")
+		}
+	}
+}
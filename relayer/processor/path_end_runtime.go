@@ -2,10 +2,12 @@ package processor
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	conntypes "github.com/cosmos/ibc-go/v5/modules/core/03-connection/types"
 	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/alert"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"go.uber.org/zap"
 )
@@ -45,7 +47,18 @@ type pathEndRuntime struct {
 	// inSync indicates whether queries are in sync with latest height of the chain.
 	inSync bool
 
+	// Observed consensus states of the client and delay periods of the connections of the channels,
+	// to wait for connection delay periods to pass before proving packets.
+	consensusStates         []processedConsensusState
+	connectionDelays        map[ChannelKey]time.Duration
+	maxExpectedTimePerBlock time.Duration
+
+	// delayClientUpdateHeight is the height at which a client update was last sent for packets waiting on a delay period.
+	delayClientUpdateHeight uint64
+
 	metrics *PrometheusMetrics
+
+	notifier *alert.Notifier
 }
 
 func newPathEndRuntime(log *zap.Logger, pathEnd PathEnd, metrics *PrometheusMetrics) *pathEndRuntime {
@@ -65,6 +78,7 @@ func newPathEndRuntime(log *zap.Logger, pathEnd PathEnd, metrics *PrometheusMetr
 		connProcessing:       make(connectionProcessingCache),
 		channelProcessing:    make(channelProcessingCache),
 		connSubscribers:      make(map[string][]func(provider.ConnectionInfo)),
+		connectionDelays:     make(map[ChannelKey]time.Duration),
 		metrics:              metrics,
 	}
 }
@@ -276,14 +290,17 @@ func (pathEnd *pathEndRuntime) mergeCacheData(ctx context.Context, cancel func()
 	pathEnd.inSync = d.InSync
 	pathEnd.latestBlock = d.LatestBlock
 	pathEnd.latestHeader = d.LatestHeader
-	pathEnd.clientState = d.ClientState
 	if d.ClientState.ConsensusHeight != pathEnd.clientState.ConsensusHeight {
-		pathEnd.clientState = d.ClientState
 		ibcHeader, ok := counterParty.ibcHeaderCache[d.ClientState.ConsensusHeight.RevisionHeight]
 		if ok {
-			pathEnd.clientState.ConsensusTime = time.Unix(0, int64(ibcHeader.ConsensusState().GetTimestamp()))
+			d.ClientState.ConsensusTime = time.Unix(0, int64(ibcHeader.ConsensusState().GetTimestamp()))
 		}
+		pathEnd.recordConsensusState(d.ClientState, d.LatestBlock)
+	} else if d.ClientState.ConsensusTime.IsZero() {
+		// The chain processor does not always know the consensus time, keep the one already known for this height.
+		d.ClientState.ConsensusTime = pathEnd.clientState.ConsensusTime
 	}
+	pathEnd.clientState = d.ClientState
 
 	pathEnd.handleCallbacks(d.IBCMessagesCache)
 
@@ -369,6 +386,11 @@ func (pathEnd *pathEndRuntime) shouldSendPacketMessage(message packetIBCMessage,
 			zap.Inline(k),
 			zap.Int("max_retries", maxMessageSendRetries),
 		)
+		pathEnd.notifier.MaxRetriesExceeded(
+			pathEnd.info.PathName, pathEnd.info.ChainID, eventType,
+			fmt.Sprintf("%s/%s sequence %d", k.PortID, k.ChannelID, sequence),
+			maxMessageSendRetries,
+		)
 		pathEnd.removePacketRetention(counterparty, eventType, k, sequence)
 		return false
 	}
@@ -442,6 +464,11 @@ func (pathEnd *pathEndRuntime) shouldSendConnectionMessage(message connectionIBC
 		pathEnd.log.Error("Giving up on sending connection message after max retries",
 			zap.String("event_type", eventType),
 		)
+		pathEnd.notifier.MaxRetriesExceeded(
+			pathEnd.info.PathName, pathEnd.info.ChainID, eventType,
+			fmt.Sprintf("connection %s", k.ConnectionID),
+			maxMessageSendRetries,
+		)
 		// giving up on sending this connection handshake message
 		// remove all retention of this connection handshake in pathEnd.messagesCache.ConnectionHandshake and counterparty
 		toDelete := make(map[string][]ConnectionKey)
@@ -510,6 +537,11 @@ func (pathEnd *pathEndRuntime) shouldSendChannelMessage(message channelIBCMessag
 			zap.String("event_type", eventType),
 			zap.Int("max_retries", maxMessageSendRetries),
 		)
+		pathEnd.notifier.MaxRetriesExceeded(
+			pathEnd.info.PathName, pathEnd.info.ChainID, eventType,
+			fmt.Sprintf("%s/%s", channelKey.PortID, channelKey.ChannelID),
+			maxMessageSendRetries,
+		)
 		// giving up on sending this channel handshake message
 		// remove all retention of this connection handshake in pathEnd.messagesCache.ConnectionHandshake and counterparty
 		toDelete := make(map[string][]ChannelKey)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cosmos/relayer/v2/relayer/alert"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"go.uber.org/zap"
 )
@@ -43,6 +44,10 @@ const (
 	// made to retrieve the client consensus state in order to assemble a
 	// MsgUpdateClient message.
 	clientConsensusHeightUpdateThresholdBlocks = 2
+
+	// How many observed client consensus states to retain for proving packets
+	// on connections with a delay period.
+	consensusStatesToCache = 100
 )
 
 // PathProcessor is a process that handles incoming IBC messages from a pair of chains.
@@ -60,9 +65,16 @@ type PathProcessor struct {
 	// Signals to retry.
 	retryProcess chan struct{}
 
+	// Requests for a snapshot of the runtime state.
+	stateRequests chan chan PathState
+
 	sentInitialMsg bool
 
 	metrics *PrometheusMetrics
+
+	notifier *alert.Notifier
+
+	health *Health
 }
 
 // PathProcessors is a slice of PathProcessor instances
@@ -90,12 +102,31 @@ func NewPathProcessor(
 		pathEnd1:                  newPathEndRuntime(log, pathEnd1, metrics),
 		pathEnd2:                  newPathEndRuntime(log, pathEnd2, metrics),
 		retryProcess:              make(chan struct{}, 2),
+		stateRequests:             make(chan chan PathState),
 		memo:                      memo,
 		clientUpdateThresholdTime: clientUpdateThresholdTime,
 		metrics:                   metrics,
 	}
 }
 
+// SetNotifier sets the notifier used to raise alerts for operational events on this path.
+func (pp *PathProcessor) SetNotifier(n *alert.Notifier) {
+	pp.notifier = n
+	pp.pathEnd1.notifier = n
+	pp.pathEnd2.notifier = n
+}
+
+// SetHealth sets the tracker that the sync state of both path ends is reported to.
+func (pp *PathProcessor) SetHealth(h *Health) {
+	pp.health = h
+	h.registerPath(pp.pathEnd1.info.PathName, pp.pathEnd1.info.ChainID, pp.pathEnd2.info.ChainID)
+}
+
+// SetStateRegistry registers the PathProcessor so that snapshots of its state can be requested.
+func (pp *PathProcessor) SetStateRegistry(r *StateRegistry) {
+	r.register(pp)
+}
+
 // TEST USE ONLY
 func (pp *PathProcessor) PathEnd1Messages(channelKey ChannelKey, message string) PacketSequenceCache {
 	return pp.pathEnd1.messageCache.PacketFlow[channelKey][message]
@@ -226,31 +257,63 @@ func (pp *PathProcessor) HandleNewData(chainID string, cacheData ChainProcessorC
 	}
 }
 
+// PathEnds returns the configuration of both ends of the path.
+func (pp *PathProcessor) PathEnds() (PathEnd, PathEnd) {
+	return pp.pathEnd1.info, pp.pathEnd2.info
+}
+
+// NewData returns the queue that HandleNewData delivers the data of the chain with chainID to,
+// or nil if chainID is not part of the path. Together with BacklogSignals, it allows the data to be
+// forwarded to a PathProcessor in another process instead of running this one.
+func (pp *PathProcessor) NewData(chainID string) <-chan ChainProcessorCacheData {
+	if pp.pathEnd1.info.ChainID == chainID {
+		return pp.pathEnd1.incomingCacheData
+	} else if pp.pathEnd2.info.ChainID == chainID {
+		return pp.pathEnd2.incomingCacheData
+	}
+	return nil
+}
+
+// BacklogSignals returns the queue that ProcessBacklogIfReady signals on.
+func (pp *PathProcessor) BacklogSignals() <-chan struct{} {
+	return pp.retryProcess
+}
+
 // processAvailableSignals will block if signals are not yet available, otherwise it will process one of the available signals.
 // It returns whether or not the pathProcessor should quit.
 func (pp *PathProcessor) processAvailableSignals(ctx context.Context, cancel func(), messageLifecycle MessageLifecycle) bool {
-	select {
-	case <-ctx.Done():
-		pp.log.Debug("Context done, quitting PathProcessor",
-			zap.String("chain_id_1", pp.pathEnd1.info.ChainID),
-			zap.String("chain_id_2", pp.pathEnd2.info.ChainID),
-			zap.String("client_id_1", pp.pathEnd1.info.ClientID),
-			zap.String("client_id_2", pp.pathEnd2.info.ClientID),
-			zap.Error(ctx.Err()),
-		)
-		return true
-	case d := <-pp.pathEnd1.incomingCacheData:
-		// we have new data from ChainProcessor for pathEnd1
-		pp.pathEnd1.mergeCacheData(ctx, cancel, d, pp.pathEnd2.info.ChainID, pp.pathEnd2.inSync, messageLifecycle, pp.pathEnd2)
-
-	case d := <-pp.pathEnd2.incomingCacheData:
-		// we have new data from ChainProcessor for pathEnd2
-		pp.pathEnd2.mergeCacheData(ctx, cancel, d, pp.pathEnd1.info.ChainID, pp.pathEnd1.inSync, messageLifecycle, pp.pathEnd1)
-
-	case <-pp.retryProcess:
-		// No new data to merge in, just retry handling.
+	for {
+		select {
+		case <-ctx.Done():
+			pp.log.Debug("Context done, quitting PathProcessor",
+				zap.String("chain_id_1", pp.pathEnd1.info.ChainID),
+				zap.String("chain_id_2", pp.pathEnd2.info.ChainID),
+				zap.String("client_id_1", pp.pathEnd1.info.ClientID),
+				zap.String("client_id_2", pp.pathEnd2.info.ClientID),
+				zap.Error(ctx.Err()),
+			)
+			return true
+		case d := <-pp.pathEnd1.incomingCacheData:
+			// we have new data from ChainProcessor for pathEnd1
+			pp.pathEnd1.mergeCacheData(ctx, cancel, d, pp.pathEnd2.info.ChainID, pp.pathEnd2.inSync, messageLifecycle, pp.pathEnd2)
+			pp.health.pathEndInSync(pp.pathEnd1.info.PathName, pp.pathEnd1.info.ChainID, pp.pathEnd1.inSync)
+
+		case d := <-pp.pathEnd2.incomingCacheData:
+			// we have new data from ChainProcessor for pathEnd2
+			pp.pathEnd2.mergeCacheData(ctx, cancel, d, pp.pathEnd1.info.ChainID, pp.pathEnd1.inSync, messageLifecycle, pp.pathEnd1)
+			pp.health.pathEndInSync(pp.pathEnd2.info.PathName, pp.pathEnd2.info.ChainID, pp.pathEnd2.inSync)
+
+		case <-pp.retryProcess:
+			// No new data to merge in, just retry handling.
+
+		case reply := <-pp.stateRequests:
+			// Answer state requests from this goroutine so the snapshot is consistent,
+			// then keep waiting since nothing changed that needs processing.
+			reply <- pp.state()
+			continue
+		}
+		return false
 	}
-	return false
 }
 
 // Run executes the main path process.
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
@@ -35,34 +34,21 @@ func (pp *PathProcessor) assemblePacketIBCMessage(
 	return assembled, nil
 }
 
-// getMessagesToSend returns only the lowest sequence message (if it should be sent) for ordered channels,
-// otherwise returns all which should be sent.
+// getMessagesToSend returns the messages which should be sent, in strict sequence order for ordered channels.
 func (pp *PathProcessor) getMessagesToSend(
+	ctx context.Context,
+	pathEndPacketFlowMessages pathEndPacketFlowMessages,
 	msgs []packetIBCMessage,
-	src, dst *pathEndRuntime,
 ) (srcMsgs []packetIBCMessage, dstMsgs []packetIBCMessage) {
 	if len(msgs) == 0 {
 		return
 	}
 	if msgs[0].info.ChannelOrder == chantypes.ORDERED.String() {
-		// for packet messages on ordered channels, only handle the lowest sequence number now.
-		sort.SliceStable(msgs, func(i, j int) bool {
-			return msgs[i].info.Sequence < msgs[j].info.Sequence
-		})
-		firstMsg := msgs[0]
-		switch firstMsg.eventType {
-		case chantypes.EventTypeRecvPacket:
-			if dst.shouldSendPacketMessage(firstMsg, src) {
-				dstMsgs = append(dstMsgs, firstMsg)
-			}
-		default:
-			if src.shouldSendPacketMessage(firstMsg, dst) {
-				srcMsgs = append(srcMsgs, firstMsg)
-			}
-		}
-		return srcMsgs, dstMsgs
+		return pp.getOrderedMessagesToSend(ctx, pathEndPacketFlowMessages, msgs)
 	}
 
+	src, dst := pathEndPacketFlowMessages.Src, pathEndPacketFlowMessages.Dst
+
 	// for unordered channels, can handle multiple simultaneous packets.
 	for _, msg := range msgs {
 		switch msg.eventType {
@@ -79,6 +65,162 @@ func (pp *PathProcessor) getMessagesToSend(
 	return srcMsgs, dstMsgs
 }
 
+// getOrderedMessagesToSend returns the messages which should be sent for an ordered channel, in strict sequence order.
+// Packets are relayed from the next sequence to receive on the destination chain, up to the first sequence which
+// should not be sent now. Packets missing from the cache are queried from the history of the source chain.
+// A timeout closes the channel, so it is only relayed for the next sequence to receive.
+// Acknowledgements are relayed in sequence order, up to the first missing sequence.
+func (pp *PathProcessor) getOrderedMessagesToSend(
+	ctx context.Context,
+	pathEndPacketFlowMessages pathEndPacketFlowMessages,
+	msgs []packetIBCMessage,
+) (srcMsgs []packetIBCMessage, dstMsgs []packetIBCMessage) {
+	src, dst := pathEndPacketFlowMessages.Src, pathEndPacketFlowMessages.Dst
+	k := pathEndPacketFlowMessages.ChannelKey
+
+	acks := make(map[uint64]packetIBCMessage)
+	unreceived := make(map[uint64]packetIBCMessage)
+	var maxUnreceivedSeq uint64
+	for _, msg := range msgs {
+		if msg.eventType == chantypes.EventTypeAcknowledgePacket {
+			acks[msg.info.Sequence] = msg
+			continue
+		}
+		unreceived[msg.info.Sequence] = msg
+		if msg.info.Sequence > maxUnreceivedSeq {
+			maxUnreceivedSeq = msg.info.Sequence
+		}
+	}
+
+	if len(acks) > 0 {
+		srcMsgs = pp.getOrderedAcksToSend(ctx, src, dst, k, acks)
+	}
+
+	if len(unreceived) == 0 {
+		return srcMsgs, dstMsgs
+	}
+
+	nextSeqRecv, err := dst.chainProvider.QueryNextSeqRecv(ctx, 0, k.CounterpartyChannelID, k.CounterpartyPortID)
+	if err != nil {
+		pp.log.Error("Error querying next sequence receive of ordered channel",
+			zap.String("chain_id", dst.info.ChainID),
+			zap.Inline(k.Counterparty()),
+			zap.Error(err),
+		)
+		return srcMsgs, dstMsgs
+	}
+
+	for seq := nextSeqRecv.NextSequenceReceive; seq <= maxUnreceivedSeq; seq++ {
+		msg, ok := unreceived[seq]
+		if !ok {
+			if msg, ok = pp.queryMissingPacket(ctx, pathEndPacketFlowMessages, seq); !ok {
+				break
+			}
+		}
+		if msg.eventType == chantypes.EventTypeRecvPacket {
+			if !dst.shouldSendPacketMessage(msg, src) {
+				break
+			}
+			dstMsgs = append(dstMsgs, msg)
+			continue
+		}
+		// the timeout can only be proven once the packets before it are received.
+		if len(dstMsgs) == 0 && src.shouldSendPacketMessage(msg, dst) {
+			srcMsgs = append(srcMsgs, msg)
+		}
+		break
+	}
+	return srcMsgs, dstMsgs
+}
+
+// getOrderedAcksToSend returns the acknowledgements of an ordered channel which should be sent,
+// from the next sequence to acknowledge on src up to the first one which is missing or should not be sent.
+func (pp *PathProcessor) getOrderedAcksToSend(
+	ctx context.Context,
+	src, dst *pathEndRuntime,
+	k ChannelKey,
+	acks map[uint64]packetIBCMessage,
+) (srcMsgs []packetIBCMessage) {
+	nextSeqAck, err := src.chainProvider.QueryNextSeqAck(ctx, 0, k.ChannelID, k.PortID)
+	if err != nil {
+		pp.log.Error("Error querying next sequence acknowledgement of ordered channel",
+			zap.String("chain_id", src.info.ChainID),
+			zap.Inline(k),
+			zap.Error(err),
+		)
+		return nil
+	}
+	for seq := nextSeqAck; ; seq++ {
+		msg, ok := acks[seq]
+		if !ok || !src.shouldSendPacketMessage(msg, dst) {
+			return srcMsgs
+		}
+		srcMsgs = append(srcMsgs, msg)
+	}
+}
+
+// queryMissingPacket queries a packet of an ordered channel which is missing from the cache,
+// e.g. because it was sent before the initial block history, from the history of the source chain.
+// It returns the message to relay it, if it should be relayed.
+func (pp *PathProcessor) queryMissingPacket(
+	ctx context.Context,
+	pathEndPacketFlowMessages pathEndPacketFlowMessages,
+	seq uint64,
+) (packetIBCMessage, bool) {
+	src := pathEndPacketFlowMessages.Src
+	k := pathEndPacketFlowMessages.ChannelKey
+	msgTransfer, err := src.chainProvider.QuerySendPacket(ctx, k.ChannelID, k.PortID, seq)
+	if err != nil {
+		pp.log.Warn("Error querying missing packet of ordered channel",
+			zap.String("chain_id", src.info.ChainID),
+			zap.Inline(k),
+			zap.Uint64("sequence", seq),
+			zap.Error(err),
+		)
+		return packetIBCMessage{}, false
+	}
+	pp.log.Debug("Queried missing packet of ordered channel",
+		zap.String("chain_id", src.info.ChainID),
+		zap.Inline(k),
+		zap.Uint64("sequence", seq),
+	)
+	src.messageCache.PacketFlow.Retain(k, chantypes.EventTypeSendPacket, msgTransfer)
+	return pp.unrelayedPacketMessage(msgTransfer, src, pathEndPacketFlowMessages.Dst)
+}
+
+// unrelayedPacketMessage returns the message to relay a packet which was not received yet:
+// MsgRecvPacket, or MsgTimeout or MsgTimeoutOnClose if the packet can no longer be received.
+func (pp *PathProcessor) unrelayedPacketMessage(msgTransfer provider.PacketInfo, src, dst *pathEndRuntime) (packetIBCMessage, bool) {
+	if err := dst.chainProvider.ValidatePacket(msgTransfer, dst.latestBlock); err != nil {
+		var timeoutHeightErr *provider.TimeoutHeightError
+		var timeoutTimestampErr *provider.TimeoutTimestampError
+		var timeoutOnCloseErr *provider.TimeoutOnCloseError
+
+		switch {
+		case errors.As(err, &timeoutHeightErr) || errors.As(err, &timeoutTimestampErr):
+			return packetIBCMessage{
+				eventType: chantypes.EventTypeTimeoutPacket,
+				info:      msgTransfer,
+			}, true
+		case errors.As(err, &timeoutOnCloseErr):
+			return packetIBCMessage{
+				eventType: chantypes.EventTypeTimeoutPacketOnClose,
+				info:      msgTransfer,
+			}, true
+		default:
+			pp.log.Error("Packet is invalid",
+				zap.String("chain_id", src.info.ChainID),
+				zap.Error(err),
+			)
+			return packetIBCMessage{}, false
+		}
+	}
+	return packetIBCMessage{
+		eventType: chantypes.EventTypeRecvPacket,
+		info:      msgTransfer,
+	}, true
+}
+
 func (pp *PathProcessor) getUnrelayedPacketsAndAcksAndToDelete(ctx context.Context, pathEndPacketFlowMessages pathEndPacketFlowMessages) pathEndPacketFlowResponse {
 	res := pathEndPacketFlowResponse{
 		ToDeleteSrc:        make(map[string][]uint64),
@@ -165,40 +307,12 @@ MsgTransferLoop:
 			}
 		}
 		// Packet is not yet relayed! need to relay either MsgRecvPacket from src to dst, or MsgTimeout/MsgTimeoutOnClose from dst to src
-		if err := pathEndPacketFlowMessages.Dst.chainProvider.ValidatePacket(msgTransfer, pathEndPacketFlowMessages.Dst.latestBlock); err != nil {
-			var timeoutHeightErr *provider.TimeoutHeightError
-			var timeoutTimestampErr *provider.TimeoutTimestampError
-			var timeoutOnCloseErr *provider.TimeoutOnCloseError
-
-			switch {
-			case errors.As(err, &timeoutHeightErr) || errors.As(err, &timeoutTimestampErr):
-				timeoutMsg := packetIBCMessage{
-					eventType: chantypes.EventTypeTimeoutPacket,
-					info:      msgTransfer,
-				}
-				msgs = append(msgs, timeoutMsg)
-			case errors.As(err, &timeoutOnCloseErr):
-				timeoutOnCloseMsg := packetIBCMessage{
-					eventType: chantypes.EventTypeTimeoutPacketOnClose,
-					info:      msgTransfer,
-				}
-				msgs = append(msgs, timeoutOnCloseMsg)
-			default:
-				pp.log.Error("Packet is invalid",
-					zap.String("chain_id", pathEndPacketFlowMessages.Src.info.ChainID),
-					zap.Error(err),
-				)
-			}
-			continue MsgTransferLoop
+		if msg, ok := pp.unrelayedPacketMessage(msgTransfer, pathEndPacketFlowMessages.Src, pathEndPacketFlowMessages.Dst); ok {
+			msgs = append(msgs, msg)
 		}
-		recvPacketMsg := packetIBCMessage{
-			eventType: chantypes.EventTypeRecvPacket,
-			info:      msgTransfer,
-		}
-		msgs = append(msgs, recvPacketMsg)
 	}
 
-	res.SrcMessages, res.DstMessages = pp.getMessagesToSend(msgs, pathEndPacketFlowMessages.Src, pathEndPacketFlowMessages.Dst)
+	res.SrcMessages, res.DstMessages = pp.getMessagesToSend(ctx, pathEndPacketFlowMessages, msgs)
 
 	// now iterate through packet-flow-complete messages and remove any leftover messages if the MsgTransfer or MsgRecvPacket was in a previous block that we did not query
 	for ackSeq := range pathEndPacketFlowMessages.SrcMsgAcknowledgement {
@@ -450,20 +564,31 @@ func (pp *PathProcessor) assembleMsgUpdateClient(ctx context.Context, src, dst *
 
 // updateClientTrustedState combines the counterparty chains trusted IBC header
 // with the latest client state, which will be used for constructing MsgUpdateClient messages.
-func (pp *PathProcessor) updateClientTrustedState(src *pathEndRuntime, dst *pathEndRuntime) {
+// The header is queried if it is not cached, since chain processors only provide the headers of their latest blocks.
+func (pp *PathProcessor) updateClientTrustedState(ctx context.Context, src *pathEndRuntime, dst *pathEndRuntime) {
 	if src.clientTrustedState.ClientState.ConsensusHeight.GTE(src.clientState.ConsensusHeight) {
 		// current height already trusted
 		return
 	}
 	// need to assemble new trusted state
-	ibcHeader, ok := dst.ibcHeaderCache[src.clientState.ConsensusHeight.RevisionHeight+1]
+	trustedHeight := src.clientState.ConsensusHeight.RevisionHeight + 1
+	ibcHeader, ok := dst.ibcHeaderCache[trustedHeight]
 	if !ok {
-		pp.log.Debug("No cached IBC header for client trusted height",
-			zap.String("chain_id", src.info.ChainID),
-			zap.String("client_id", src.info.ClientID),
-			zap.Uint64("height", src.clientState.ConsensusHeight.RevisionHeight+1),
-		)
-		return
+		if dst.latestBlock.Height < trustedHeight {
+			// the header is not produced yet.
+			return
+		}
+		var err error
+		ibcHeader, err = dst.chainProvider.QueryIBCHeader(ctx, int64(trustedHeight))
+		if err != nil {
+			pp.log.Debug("Failed to query IBC header for client trusted height",
+				zap.String("chain_id", src.info.ChainID),
+				zap.String("client_id", src.info.ClientID),
+				zap.Uint64("height", trustedHeight),
+				zap.Error(err),
+			)
+			return
+		}
 	}
 	src.clientTrustedState = provider.ClientTrustedState{
 		ClientState: src.clientState,
@@ -546,8 +671,8 @@ func (pp *PathProcessor) appendInitialMessageIfNecessary(msg MessageLifecycle, p
 // messages from both pathEnds are needed in order to determine what needs to be relayed for a single pathEnd
 func (pp *PathProcessor) processLatestMessages(ctx context.Context, messageLifecycle MessageLifecycle) error {
 	// Update trusted client state for both pathends
-	pp.updateClientTrustedState(pp.pathEnd1, pp.pathEnd2)
-	pp.updateClientTrustedState(pp.pathEnd2, pp.pathEnd1)
+	pp.updateClientTrustedState(ctx, pp.pathEnd1, pp.pathEnd2)
+	pp.updateClientTrustedState(ctx, pp.pathEnd2, pp.pathEnd1)
 
 	channelPairs := pp.channelPairs()
 
@@ -691,6 +816,7 @@ func (pp *PathProcessor) assembleMessage(
 	msg ibcMessage,
 	src, dst *pathEndRuntime,
 	om *outgoingMessages,
+	assembled []provider.RelayerMessage,
 	i int,
 	wg *sync.WaitGroup,
 ) {
@@ -744,7 +870,29 @@ func (pp *PathProcessor) assembleMessage(
 		pp.log.Error("Error assembling channel message", zap.Error(err))
 		return
 	}
-	om.Append(message)
+	assembled[i] = message
+}
+
+// clientConsensusTime returns the time of the latest consensus state of the client on dst,
+// from the header of src if the client state does not have it.
+func (pp *PathProcessor) clientConsensusTime(ctx context.Context, src, dst *pathEndRuntime) (time.Time, error) {
+	if !dst.clientState.ConsensusTime.IsZero() {
+		return dst.clientState.ConsensusTime, nil
+	}
+	t, err := dst.consensusTime(ctx, src, dst.clientState.ConsensusHeight)
+	if err != nil {
+		return time.Time{}, err
+	}
+	dst.clientState.ConsensusTime = t
+	return t, nil
+}
+
+// notifyClientCloseToExpiration alerts if the client on dst, last updated at consensusHeightTime, is close to expiration.
+func (pp *PathProcessor) notifyClientCloseToExpiration(dst *pathEndRuntime, consensusHeightTime time.Time) {
+	pp.notifier.ClientCloseToExpiration(
+		dst.info.PathName, dst.info.ChainID, dst.info.ClientID,
+		dst.clientState.TrustingPeriod, time.Since(consensusHeightTime),
+	)
 }
 
 func (pp *PathProcessor) assembleAndSendMessages(
@@ -753,17 +901,13 @@ func (pp *PathProcessor) assembleAndSendMessages(
 	messages pathEndMessages,
 ) error {
 	var needsClientUpdate bool
-	if len(messages.packetMessages) == 0 && len(messages.connectionMessages) == 0 && len(messages.channelMessages) == 0 {
-		var consensusHeightTime time.Time
-		if dst.clientState.ConsensusTime.IsZero() {
-			h, err := src.chainProvider.QueryIBCHeader(ctx, int64(dst.clientState.ConsensusHeight.RevisionHeight))
-			if err != nil {
-				return fmt.Errorf("failed to get header height: %w", err)
-			}
-			consensusHeightTime = time.Unix(0, int64(h.ConsensusState().GetTimestamp()))
-		} else {
-			consensusHeightTime = dst.clientState.ConsensusTime
+	messages.packetMessages, needsClientUpdate = pp.delayPacketMessages(ctx, src, dst, messages.packetMessages)
+	if !needsClientUpdate && len(messages.packetMessages) == 0 && len(messages.connectionMessages) == 0 && len(messages.channelMessages) == 0 {
+		consensusHeightTime, err := pp.clientConsensusTime(ctx, src, dst)
+		if err != nil {
+			return fmt.Errorf("failed to get header height: %w", err)
 		}
+		pp.notifyClientCloseToExpiration(dst, consensusHeightTime)
 		clientUpdateThresholdMs := pp.clientUpdateThresholdTime.Milliseconds()
 		if (float64(dst.clientState.TrustingPeriod.Milliseconds())*2/3 < float64(time.Since(consensusHeightTime).Milliseconds())) ||
 			(clientUpdateThresholdMs > 0 && time.Since(consensusHeightTime).Milliseconds() > clientUpdateThresholdMs) {
@@ -778,6 +922,14 @@ func (pp *PathProcessor) assembleAndSendMessages(
 		} else {
 			return nil
 		}
+	} else if pp.notifier != nil {
+		// Busy paths are checked too, since a client can be close to expiration while messages are pending.
+		consensusHeightTime, err := pp.clientConsensusTime(ctx, src, dst)
+		if err != nil {
+			pp.log.Warn("Failed to get client consensus time for the expiration alert", zap.Error(err))
+		} else {
+			pp.notifyClientCloseToExpiration(dst, consensusHeightTime)
+		}
 	}
 	om := outgoingMessages{
 		msgs: make(
@@ -797,34 +949,40 @@ func (pp *PathProcessor) assembleAndSendMessages(
 
 	// connection messages are highest priority
 	om.connMsgs = make([]connectionMessageToTrack, len(messages.connectionMessages))
+	assembled := make([]provider.RelayerMessage, len(messages.connectionMessages))
 	for i, msg := range messages.connectionMessages {
 		wg.Add(1)
-		go pp.assembleMessage(ctx, msg, src, dst, &om, i, &wg)
+		go pp.assembleMessage(ctx, msg, src, dst, &om, assembled, i, &wg)
 	}
 
 	wg.Wait()
+	om.AppendAssembled(assembled)
 
 	if len(om.msgs) == 1 {
 		om.chanMsgs = make([]channelMessageToTrack, len(messages.channelMessages))
 		// only assemble and send channel handshake messages if there are no conn handshake messages
 		// this prioritizes connection handshake messages, useful if a connection handshake needs to occur before a channel handshake
+		assembled := make([]provider.RelayerMessage, len(messages.channelMessages))
 		for i, msg := range messages.channelMessages {
 			wg.Add(1)
-			go pp.assembleMessage(ctx, msg, src, dst, &om, i, &wg)
+			go pp.assembleMessage(ctx, msg, src, dst, &om, assembled, i, &wg)
 		}
 
 		wg.Wait()
+		om.AppendAssembled(assembled)
 	}
 
 	if len(om.msgs) == 1 {
 		om.pktMsgs = make([]packetMessageToTrack, len(messages.packetMessages))
 		// only assemble and send packet messages if there are no handshake messages
+		assembled := make([]provider.RelayerMessage, len(messages.packetMessages))
 		for i, msg := range messages.packetMessages {
 			wg.Add(1)
-			go pp.assembleMessage(ctx, msg, src, dst, &om, i, &wg)
+			go pp.assembleMessage(ctx, msg, src, dst, &om, assembled, i, &wg)
 		}
 
 		wg.Wait()
+		om.AppendAssembledPackets(assembled)
 	}
 
 	if len(om.msgs) == 1 && !needsClientUpdate {
@@ -874,12 +1032,15 @@ func (pp *PathProcessor) sendMessages(ctx context.Context, src, dst *pathEndRunt
 			zap.Object("messages", om),
 			zap.Error(err),
 		)
+		pp.notifier.SendFailed(dst.info.PathName, dst.info.ChainID, err)
 		return
 	}
 	if !txSuccess {
 		dst.log.Error("Error sending messages, transaction was not successful")
+		pp.notifier.SendFailed(dst.info.PathName, dst.info.ChainID, errors.New("transaction was not successful"))
 		return
 	}
+	pp.notifier.MessagesSent(dst.info.PathName, dst.info.ChainID)
 
 	if pp.metrics == nil {
 		return
@@ -934,9 +1095,14 @@ func (pp *PathProcessor) assemblePacketMessage(
 	ctx, cancel := context.WithTimeout(ctx, packetProofQueryTimeout)
 	defer cancel()
 
+	proofHeight := src.latestBlock.Height
+	if msg.proofHeight != 0 {
+		proofHeight = msg.proofHeight
+	}
+
 	var proof provider.PacketProof
 	var err error
-	proof, err = packetProof(ctx, msg.info, src.latestBlock.Height)
+	proof, err = packetProof(ctx, msg.info, proofHeight)
 	if err != nil {
 		return nil, fmt.Errorf("error querying packet proof: %w", err)
 	}
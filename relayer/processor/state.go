@@ -0,0 +1,266 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StateRegistry gives access to snapshots of the runtime state of PathProcessors,
+// for inspecting why packets are or are not being relayed.
+type StateRegistry struct {
+	mu             sync.Mutex
+	pathProcessors []*PathProcessor
+}
+
+// NewStateRegistry returns a new, empty StateRegistry.
+func NewStateRegistry() *StateRegistry {
+	return &StateRegistry{}
+}
+
+func (r *StateRegistry) register(pp *PathProcessor) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pathProcessors = append(r.pathProcessors, pp)
+}
+
+// Snapshot returns the state of the registered PathProcessors, optionally filtered by path name.
+// Each PathProcessor takes its own snapshot between processing signals,
+// so this will block until they are able to respond or ctx is done.
+func (r *StateRegistry) Snapshot(ctx context.Context, pathName string) ([]PathState, error) {
+	if r == nil {
+		return nil, nil
+	}
+	r.mu.Lock()
+	pathProcessors := append([]*PathProcessor(nil), r.pathProcessors...)
+	r.mu.Unlock()
+
+	states := make([]PathState, 0, len(pathProcessors))
+	for _, pp := range pathProcessors {
+		if pathName != "" && pp.pathEnd1.info.PathName != pathName {
+			continue
+		}
+		s, err := pp.State(ctx)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Path < states[j].Path })
+	return states, nil
+}
+
+// PathState is a snapshot of the runtime state of a PathProcessor.
+type PathState struct {
+	Path     string       `json:"path"`
+	PathEnd1 PathEndState `json:"path_end_1"`
+	PathEnd2 PathEndState `json:"path_end_2"`
+}
+
+// PathEndState is a snapshot of the runtime state of one end of a PathProcessor.
+type PathEndState struct {
+	ChainID            string                  `json:"chain_id"`
+	ClientID           string                  `json:"client_id"`
+	InSync             bool                    `json:"in_sync"`
+	LatestHeight       uint64                  `json:"latest_height"`
+	ClientState        ClientStateInfo         `json:"client_state"`
+	ClientTrustedState ClientTrustedStateInfo  `json:"client_trusted_state"`
+	Connections        []ConnectionState       `json:"connections"`
+	Channels           []ChannelState          `json:"channels"`
+	PacketFlow         []PacketFlowState       `json:"packet_flow"`
+	PacketProcessing   []PacketProcessingState `json:"packet_processing"`
+}
+
+// ClientStateInfo describes the state of the client tracking the counterparty chain.
+type ClientStateInfo struct {
+	ClientID        string    `json:"client_id"`
+	ConsensusHeight string    `json:"consensus_height"`
+	TrustingPeriod  string    `json:"trusting_period"`
+	ConsensusTime   time.Time `json:"consensus_time"`
+}
+
+// ClientTrustedStateInfo describes the client state along with the height of the trusted counterparty header.
+type ClientTrustedStateInfo struct {
+	ClientStateInfo
+	TrustedHeaderHeight uint64 `json:"trusted_header_height"`
+}
+
+// ConnectionState describes a connection and whether it is open.
+type ConnectionState struct {
+	ConnectionID             string `json:"connection_id"`
+	ClientID                 string `json:"client_id"`
+	CounterpartyConnectionID string `json:"counterparty_connection_id"`
+	CounterpartyClientID     string `json:"counterparty_client_id"`
+	Open                     bool   `json:"open"`
+}
+
+// ChannelRef identifies a channel and its counterparty.
+type ChannelRef struct {
+	ChannelID             string `json:"channel_id"`
+	PortID                string `json:"port_id"`
+	CounterpartyChannelID string `json:"counterparty_channel_id"`
+	CounterpartyPortID    string `json:"counterparty_port_id"`
+}
+
+// ChannelState describes a channel and whether it is open.
+type ChannelState struct {
+	ChannelRef
+	Open bool `json:"open"`
+}
+
+// PacketFlowState lists the sequences of the packet flow messages of an event type held in the message cache.
+type PacketFlowState struct {
+	ChannelRef
+	EventType string   `json:"event_type"`
+	Sequences []uint64 `json:"sequences"`
+}
+
+// PacketProcessingState describes an in-progress packet message send.
+type PacketProcessingState struct {
+	ChannelRef
+	EventType           string `json:"event_type"`
+	Sequence            uint64 `json:"sequence"`
+	Assembled           bool   `json:"assembled"`
+	RetryCount          uint64 `json:"retry_count"`
+	LastProcessedHeight uint64 `json:"last_processed_height"`
+}
+
+// State returns a snapshot of the PathProcessor state.
+// The snapshot is taken on the PathProcessor goroutine, so this blocks until
+// the PathProcessor is able to respond or ctx is done.
+func (pp *PathProcessor) State(ctx context.Context) (PathState, error) {
+	reply := make(chan PathState, 1)
+	select {
+	case pp.stateRequests <- reply:
+	case <-ctx.Done():
+		return PathState{}, fmt.Errorf("path processor for %s did not accept state request: %w", pp.pathEnd1.info.PathName, ctx.Err())
+	}
+	select {
+	case s := <-reply:
+		return s, nil
+	case <-ctx.Done():
+		return PathState{}, fmt.Errorf("path processor for %s did not respond to state request: %w", pp.pathEnd1.info.PathName, ctx.Err())
+	}
+}
+
+// state must only be called from the PathProcessor goroutine.
+func (pp *PathProcessor) state() PathState {
+	return PathState{
+		Path:     pp.pathEnd1.info.PathName,
+		PathEnd1: pp.pathEnd1.state(),
+		PathEnd2: pp.pathEnd2.state(),
+	}
+}
+
+func channelRef(k ChannelKey) ChannelRef {
+	return ChannelRef{
+		ChannelID:             k.ChannelID,
+		PortID:                k.PortID,
+		CounterpartyChannelID: k.CounterpartyChannelID,
+		CounterpartyPortID:    k.CounterpartyPortID,
+	}
+}
+
+func lessChannelRef(a, b ChannelRef) bool {
+	if a.ChannelID != b.ChannelID {
+		return a.ChannelID < b.ChannelID
+	}
+	return a.PortID < b.PortID
+}
+
+// state returns a deep copy of the path end state. It must only be called from the PathProcessor goroutine.
+func (pathEnd *pathEndRuntime) state() PathEndState {
+	s := PathEndState{
+		ChainID:      pathEnd.info.ChainID,
+		ClientID:     pathEnd.info.ClientID,
+		InSync:       pathEnd.inSync,
+		LatestHeight: pathEnd.latestBlock.Height,
+		ClientState: ClientStateInfo{
+			ClientID:        pathEnd.clientState.ClientID,
+			ConsensusHeight: pathEnd.clientState.ConsensusHeight.String(),
+			TrustingPeriod:  pathEnd.clientState.TrustingPeriod.String(),
+			ConsensusTime:   pathEnd.clientState.ConsensusTime,
+		},
+		ClientTrustedState: ClientTrustedStateInfo{
+			ClientStateInfo: ClientStateInfo{
+				ClientID:        pathEnd.clientTrustedState.ClientState.ClientID,
+				ConsensusHeight: pathEnd.clientTrustedState.ClientState.ConsensusHeight.String(),
+				TrustingPeriod:  pathEnd.clientTrustedState.ClientState.TrustingPeriod.String(),
+				ConsensusTime:   pathEnd.clientTrustedState.ClientState.ConsensusTime,
+			},
+		},
+		Connections:      []ConnectionState{},
+		Channels:         []ChannelState{},
+		PacketFlow:       []PacketFlowState{},
+		PacketProcessing: []PacketProcessingState{},
+	}
+	if h := pathEnd.clientTrustedState.IBCHeader; h != nil {
+		s.ClientTrustedState.TrustedHeaderHeight = h.Height()
+	}
+
+	for k, open := range pathEnd.connectionStateCache {
+		s.Connections = append(s.Connections, ConnectionState{
+			ConnectionID:             k.ConnectionID,
+			ClientID:                 k.ClientID,
+			CounterpartyConnectionID: k.CounterpartyConnID,
+			CounterpartyClientID:     k.CounterpartyClientID,
+			Open:                     open,
+		})
+	}
+	sort.Slice(s.Connections, func(i, j int) bool { return s.Connections[i].ConnectionID < s.Connections[j].ConnectionID })
+
+	for k, open := range pathEnd.channelStateCache {
+		s.Channels = append(s.Channels, ChannelState{ChannelRef: channelRef(k), Open: open})
+	}
+	sort.Slice(s.Channels, func(i, j int) bool { return lessChannelRef(s.Channels[i].ChannelRef, s.Channels[j].ChannelRef) })
+
+	for k, pmc := range pathEnd.messageCache.PacketFlow {
+		for eventType, psc := range pmc {
+			pf := PacketFlowState{ChannelRef: channelRef(k), EventType: eventType, Sequences: make([]uint64, 0, len(psc))}
+			for seq := range psc {
+				pf.Sequences = append(pf.Sequences, seq)
+			}
+			sort.Slice(pf.Sequences, func(i, j int) bool { return pf.Sequences[i] < pf.Sequences[j] })
+			s.PacketFlow = append(s.PacketFlow, pf)
+		}
+	}
+	sort.Slice(s.PacketFlow, func(i, j int) bool {
+		a, b := s.PacketFlow[i], s.PacketFlow[j]
+		if a.ChannelRef != b.ChannelRef {
+			return lessChannelRef(a.ChannelRef, b.ChannelRef)
+		}
+		return a.EventType < b.EventType
+	})
+
+	for k, pcmc := range pathEnd.packetProcessing {
+		for eventType, pmsc := range pcmc {
+			for seq, m := range pmsc {
+				s.PacketProcessing = append(s.PacketProcessing, PacketProcessingState{
+					ChannelRef:          channelRef(k),
+					EventType:           eventType,
+					Sequence:            seq,
+					Assembled:           m.assembled,
+					RetryCount:          m.retryCount,
+					LastProcessedHeight: m.lastProcessedHeight,
+				})
+			}
+		}
+	}
+	sort.Slice(s.PacketProcessing, func(i, j int) bool {
+		a, b := s.PacketProcessing[i], s.PacketProcessing[j]
+		if a.ChannelRef != b.ChannelRef {
+			return lessChannelRef(a.ChannelRef, b.ChannelRef)
+		}
+		if a.EventType != b.EventType {
+			return a.EventType < b.EventType
+		}
+		return a.Sequence < b.Sequence
+	})
+
+	return s
+}
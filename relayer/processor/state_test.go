@@ -0,0 +1,53 @@
+package processor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStateRegistrySnapshot(t *testing.T) {
+	newPathProcessor := func(name string) *processor.PathProcessor {
+		return processor.NewPathProcessor(
+			zap.NewNop(),
+			processor.NewPathEnd(name, "chain-a", "07-tendermint-0", "", nil),
+			processor.NewPathEnd(name, "chain-b", "07-tendermint-1", "", nil),
+			nil, "", 0,
+		)
+	}
+
+	r := processor.NewStateRegistry()
+	ppAB := newPathProcessor("a-b")
+	ppAB.SetStateRegistry(r)
+	ppBA := newPathProcessor("b-a")
+	ppBA.SetStateRegistry(r)
+
+	// Requests time out while the path processors are not running.
+	timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelTimeout()
+	_, err := r.Snapshot(timeoutCtx, "")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ppAB.Run(ctx, cancel, nil)
+	go ppBA.Run(ctx, cancel, nil)
+
+	states, err := r.Snapshot(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	require.Equal(t, "a-b", states[0].Path)
+	require.Equal(t, "chain-a", states[0].PathEnd1.ChainID)
+	require.Equal(t, "07-tendermint-1", states[0].PathEnd2.ClientID)
+	require.False(t, states[0].PathEnd1.InSync)
+	require.Empty(t, states[0].PathEnd1.PacketProcessing)
+
+	states, err = r.Snapshot(ctx, "b-a")
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, "b-a", states[0].Path)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
 	chantypes "github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"go.uber.org/zap/zapcore"
@@ -463,3 +464,26 @@ func ConnectionInfoConnectionKey(info provider.ConnectionInfo) ConnectionKey {
 		CounterpartyConnID:   info.CounterpartyConnID,
 	}
 }
+
+// ChainUpgrade is a scheduled upgrade of a chain with an upgraded client state, after which
+// the clients tracking the chain on its counterparties need to be upgraded.
+type ChainUpgrade struct {
+	// PlanName and PlanHeight are the name and height of the x/upgrade plan.
+	PlanName   string
+	PlanHeight int64
+
+	// ChainID is the chain ID before the upgrade, and NewChainID the one the chain restarted with,
+	// which differs if the upgrade changed the revision of the chain ID.
+	ChainID    string
+	NewChainID string
+
+	// UpgradedClient and UpgradedConsState are the upgraded client and consensus states with their proofs,
+	// queried at the last height before the upgrade while the chain was halted, since the upgrade clears them.
+	UpgradedClient    *clienttypes.QueryClientStateResponse
+	UpgradedConsState *clienttypes.QueryConsensusStateResponse
+}
+
+// RevisionChanged reports whether the chain restarted under a new chain ID revision.
+func (u ChainUpgrade) RevisionChanged() bool {
+	return u.ChainID != u.NewChainID
+}
@@ -27,6 +27,10 @@ type ibcMessage interface {
 type packetIBCMessage struct {
 	info      provider.PacketInfo
 	eventType string
+
+	// proofHeight is the height of the counterparty chain to prove the message at, when the connection has a delay period.
+	// The latest height of the counterparty chain is used if zero.
+	proofHeight uint64
 }
 
 func (packetIBCMessage) ibcMessageIndicator() {}
@@ -239,6 +243,40 @@ func (om *outgoingMessages) Append(msg provider.RelayerMessage) {
 	om.msgs = append(om.msgs, msg)
 }
 
+// AppendAssembled appends the messages which were assembled successfully, i.e. are not nil, in order.
+// Messages are assembled concurrently, but must be sent in order, e.g. packets on ordered channels.
+func (om *outgoingMessages) AppendAssembled(msgs []provider.RelayerMessage) {
+	for _, msg := range msgs {
+		if msg != nil {
+			om.Append(msg)
+		}
+	}
+}
+
+// AppendAssembledPackets appends the packet messages which were assembled successfully, in order.
+// On ordered channels, the packet messages after one which failed to assemble would fail until it is sent,
+// so they are dropped from the messages to send and to track, without using up their retries.
+func (om *outgoingMessages) AppendAssembledPackets(msgs []provider.RelayerMessage) {
+	failedOrdered := make(map[ChannelKey]bool)
+	pktMsgs := make([]packetMessageToTrack, 0, len(om.pktMsgs))
+	for i, m := range om.pktMsgs {
+		if m.msg.info.ChannelOrder == chantypes.ORDERED.String() {
+			k, _ := m.msg.channelKey()
+			if failedOrdered[k] {
+				continue
+			}
+			if msgs[i] == nil {
+				failedOrdered[k] = true
+			}
+		}
+		pktMsgs = append(pktMsgs, m)
+		if msgs[i] != nil {
+			om.Append(msgs[i])
+		}
+	}
+	om.pktMsgs = pktMsgs
+}
+
 type packetMessageToTrack struct {
 	msg       packetIBCMessage
 	assembled bool
@@ -104,6 +104,10 @@ type ConnectionInfo struct {
 	CounterpartyClientID         string
 	CounterpartyConnID           string
 	CounterpartyCommitmentPrefix commitmenttypes.MerklePrefix
+
+	// DelayPeriod doesn't come from any events, it is the delay period in nanoseconds
+	// of the connection created by MsgConnectionOpenInit.
+	DelayPeriod uint64
 }
 
 // ChannelInfo contains relevant properties from channel handshake messages
@@ -137,6 +141,10 @@ type ConnectionProof struct {
 	ClientStateProof     []byte
 	ProofHeight          clienttypes.Height
 	ClientState          ibcexported.ClientState
+
+	// DelayPeriod is the delay period in nanoseconds of the proven connection,
+	// which MsgConnectionOpenTry must agree with.
+	DelayPeriod uint64
 }
 
 type ChannelProof struct {
@@ -187,6 +195,20 @@ type KeyProvider interface {
 	DeleteKey(name string) error
 	KeyExists(name string) bool
 	ExportPrivKeyArmor(keyName string) (armor string, err error)
+	ImportPrivKeyArmor(name, armor, passphrase string) (address string, err error)
+	ImportPrivKeyHex(name, hexKey string) (address string, err error)
+}
+
+// RemoteKeyProvider is implemented by KeyProviders that can hold keys in a remote signer.
+// Remote keys are read-only: they cannot be deleted or exported through the relayer.
+type RemoteKeyProvider interface {
+	IsRemoteKey(name string) bool
+}
+
+// KeySwitcher is implemented by ChainProviders that can switch the key they sign transactions with
+// while they are running.
+type KeySwitcher interface {
+	UseKey(name string) error
 }
 
 type ChainProvider interface {
@@ -383,6 +405,9 @@ type QueryProvider interface {
 	QueryConnection(ctx context.Context, height int64, connectionid string) (*conntypes.QueryConnectionResponse, error)
 	QueryConnections(ctx context.Context) (conns []*conntypes.IdentifiedConnection, err error)
 	QueryConnectionsUsingClient(ctx context.Context, height int64, clientid string) (*conntypes.QueryConnectionsResponse, error)
+	// QueryMaxExpectedTimePerBlock returns the expected time per block, from which the chain derives
+	// the number of blocks to wait for the delay period of a connection before verifying proofs.
+	QueryMaxExpectedTimePerBlock(ctx context.Context) (time.Duration, error)
 	GenerateConnHandshakeProof(ctx context.Context, height int64, clientId, connId string) (clientState ibcexported.ClientState,
 		clientStateProof []byte, consensusProof []byte, connectionProof []byte,
 		connectionProofHeight ibcexported.Height, err error)
@@ -397,6 +422,7 @@ type QueryProvider interface {
 	QueryUnreceivedPackets(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error)
 	QueryUnreceivedAcknowledgements(ctx context.Context, height uint64, channelid, portid string, seqs []uint64) ([]uint64, error)
 	QueryNextSeqRecv(ctx context.Context, height int64, channelid, portid string) (recvRes *chantypes.QueryNextSequenceReceiveResponse, err error)
+	QueryNextSeqAck(ctx context.Context, height int64, channelid, portid string) (uint64, error)
 	QueryPacketCommitment(ctx context.Context, height int64, channelid, portid string, seq uint64) (comRes *chantypes.QueryPacketCommitmentResponse, err error)
 	QueryPacketAcknowledgement(ctx context.Context, height int64, channelid, portid string, seq uint64) (ackRes *chantypes.QueryPacketAcknowledgementResponse, err error)
 	QueryPacketReceipt(ctx context.Context, height int64, channelid, portid string, seq uint64) (recRes *chantypes.QueryPacketReceiptResponse, err error)
@@ -0,0 +1,208 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+)
+
+// ClientHealth is the state of a tendermint client at some time.
+type ClientHealth struct {
+	ClientState *tmclient.ClientState
+
+	// Updated is the time of the latest consensus state of the client.
+	Updated time.Time
+
+	Frozen, Expired bool
+}
+
+// Active reports whether the client can be updated, i.e. it is neither frozen nor expired.
+func (h *ClientHealth) Active() bool {
+	return !h.Frozen && !h.Expired
+}
+
+// QueryClientHealth queries the client clientID on c, and whether it is frozen or expired at now.
+func QueryClientHealth(ctx context.Context, c *Chain, clientID string, now time.Time) (*ClientHealth, error) {
+	height, err := c.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := c.ChainProvider.QueryClientState(ctx, height, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client %s on chain %s: %w", clientID, c.ChainID(), err)
+	}
+	tmcs, ok := cs.(*tmclient.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("client %s on chain %s is a %T, only tendermint clients are supported", clientID, c.ChainID(), cs)
+	}
+	updated, err := clientUpdateTime(ctx, c, height, clientID, tmcs)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientHealth{
+		ClientState: tmcs,
+		Updated:     updated,
+		Frozen:      !tmcs.FrozenHeight.IsZero(),
+		Expired:     !now.Before(updated.Add(tmcs.TrustingPeriod)),
+	}, nil
+}
+
+// substituteClientState returns the state of a client that can substitute the subject client in a client update proposal,
+// which requires both clients to match in all parameters but the chain ID, trusting period, latest and frozen heights.
+// The substitute tracks the chain chainID from height, and keeps the trusting period of the subject unless trustingPeriod is set.
+func substituteClientState(subject *tmclient.ClientState, chainID string, height clienttypes.Height, trustingPeriod time.Duration) *tmclient.ClientState {
+	substitute := *subject
+	substitute.ChainId = chainID
+	substitute.LatestHeight = height
+	substitute.FrozenHeight = clienttypes.ZeroHeight()
+	if trustingPeriod != 0 {
+		substitute.TrustingPeriod = trustingPeriod
+	}
+	return &substitute
+}
+
+// CreateSubstituteClient creates a client on src tracking dst, with the parameters of the subject client on src,
+// to substitute it in a client update proposal. It returns the ID of the substitute client.
+func CreateSubstituteClient(ctx context.Context, src, dst *Chain, subject *tmclient.ClientState, trustingPeriod time.Duration, memo string) (string, error) {
+	var header provider.IBCHeader
+	if err := retry.Do(func() error {
+		dsth, err := dst.ChainProvider.QueryLatestHeight(ctx)
+		if err != nil {
+			return err
+		}
+		header, err = dst.ChainProvider.QueryIBCHeader(ctx, dsth)
+		return err
+	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
+		return "", fmt.Errorf("failed to query header of chain %s: %w", dst.ChainID(), err)
+	}
+
+	height := clienttypes.NewHeight(clienttypes.ParseChainID(dst.ChainID()), header.Height())
+	clientState := substituteClientState(subject, dst.ChainID(), height, trustingPeriod)
+	createMsg, err := src.ChainProvider.MsgCreateClient(clientState, header.ConsensusState())
+	if err != nil {
+		return "", fmt.Errorf("failed to compose CreateClient msg for chain{%s} tracking the state of chain{%s}: %w",
+			src.ChainID(), dst.ChainID(), err)
+	}
+
+	msgs := []provider.RelayerMessage{createMsg}
+	res, success, err := src.ChainProvider.SendMessages(ctx, msgs, memo)
+	if err != nil {
+		src.LogFailedTx(res, err, msgs)
+		return "", fmt.Errorf("failed to send messages on chain{%s}: %w", src.ChainID(), err)
+	}
+	if !success {
+		src.LogFailedTx(res, nil, msgs)
+		return "", fmt.Errorf("tx failed on chain{%s}: %s", src.ChainID(), res.Data)
+	}
+
+	clientID, err := parseClientIDFromEvents(res.Events)
+	if err != nil {
+		return "", err
+	}
+	src.log.Info(
+		"Substitute client created",
+		zap.String("src_chain_id", src.ChainID()),
+		zap.String("substitute_client_id", clientID),
+		zap.String("dst_chain_id", dst.ChainID()),
+	)
+	return clientID, nil
+}
+
+// ClientRecovery follows the recovery of the expired or frozen subject client on Src, tracking Dst,
+// by a client update proposal replacing its state with the state of the substitute client.
+type ClientRecovery struct {
+	Src, Dst *Chain
+
+	SubjectClientID, SubstituteClientID string
+
+	Memo string
+
+	// PollInterval is how often the clients are queried.
+	PollInterval time.Duration
+}
+
+// Watch keeps the substitute client updated until the subject client is active again, then updates the subject client.
+func (r *ClientRecovery) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		subject, err := QueryClientHealth(ctx, r.Src, r.SubjectClientID, time.Now())
+		if err != nil {
+			r.Src.log.Warn("Failed to query subject client", zap.String("client_id", r.SubjectClientID), zap.Error(err))
+		} else if subject.Active() {
+			r.Src.log.Info(
+				"Subject client was recovered",
+				zap.String("chain_id", r.Src.ChainID()),
+				zap.String("client_id", r.SubjectClientID),
+			)
+			return r.updateClient(ctx, r.SubjectClientID)
+		}
+
+		substitute, err := QueryClientHealth(ctx, r.Src, r.SubstituteClientID, time.Now())
+		switch {
+		case err != nil:
+			r.Src.log.Warn("Failed to query substitute client", zap.String("client_id", r.SubstituteClientID), zap.Error(err))
+		case !substitute.Active():
+			return fmt.Errorf("substitute client %s on chain %s expired before the subject client %s was recovered, a new substitute is needed",
+				r.SubstituteClientID, r.Src.ChainID(), r.SubjectClientID)
+		case substituteNeedsUpdate(substitute, time.Now()):
+			if err := r.updateClient(ctx, r.SubstituteClientID); err != nil {
+				r.Src.log.Warn("Failed to update substitute client", zap.String("client_id", r.SubstituteClientID), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// substituteNeedsUpdate reports whether a third of the trusting period of the substitute client elapsed at now since its last update,
+// so that it is always active when the proposal passes.
+func substituteNeedsUpdate(substitute *ClientHealth, now time.Time) bool {
+	return !now.Before(substitute.Updated.Add(substitute.ClientState.TrustingPeriod / 3))
+}
+
+// updateClient updates the client clientID on Src to the latest height of Dst.
+func (r *ClientRecovery) updateClient(ctx context.Context, clientID string) error {
+	// The client is set on a copy of Src, which may be shared.
+	src := *r.Src
+	src.PathEnd = &PathEnd{ChainID: src.ChainID(), ClientID: clientID}
+
+	srch, dsth, err := QueryLatestHeights(ctx, &src, r.Dst)
+	if err != nil {
+		return err
+	}
+	msg, err := MsgUpdateClient(ctx, r.Dst, &src, dsth, srch)
+	if err != nil {
+		return err
+	}
+	msgs := []provider.RelayerMessage{msg}
+	res, success, err := src.ChainProvider.SendMessages(ctx, msgs, r.Memo)
+	if err != nil {
+		src.LogFailedTx(res, err, msgs)
+		return fmt.Errorf("failed to send messages on chain{%s}: %w", src.ChainID(), err)
+	}
+	if !success {
+		src.LogFailedTx(res, nil, msgs)
+		return fmt.Errorf("tx failed on chain{%s}: %s", src.ChainID(), res.Data)
+	}
+
+	src.log.Info(
+		"Client updated",
+		zap.String("chain_id", src.ChainID()),
+		zap.String("client_id", clientID),
+		zap.String("counterparty_chain_id", r.Dst.ChainID()),
+		zap.Int64("counterparty_height", dsth),
+	)
+	return nil
+}
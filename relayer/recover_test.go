@@ -0,0 +1,54 @@
+package relayer
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v5/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v5/modules/core/23-commitment/types"
+	tmclient "github.com/cosmos/ibc-go/v5/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteClientState(t *testing.T) {
+	subject := &tmclient.ClientState{
+		ChainId:         "osmosis-1",
+		TrustLevel:      tmclient.DefaultTrustLevel,
+		TrustingPeriod:  10 * 24 * time.Hour,
+		UnbondingPeriod: 14 * 24 * time.Hour,
+		MaxClockDrift:   20 * time.Second,
+		FrozenHeight:    clienttypes.NewHeight(1, 5_000_000),
+		LatestHeight:    clienttypes.NewHeight(1, 5_000_000),
+		ProofSpecs:      commitmenttypes.GetSDKSpecs(),
+		UpgradePath:     []string{"upgrade", "upgradedIBCState"},
+	}
+	height := clienttypes.NewHeight(2, 100)
+
+	substitute := substituteClientState(subject, "osmosis-2", height, 0)
+	require.True(t, tmclient.IsMatchingClientState(*subject, *substitute))
+	require.Equal(t, "osmosis-2", substitute.ChainId)
+	require.Equal(t, height, substitute.LatestHeight)
+	require.True(t, substitute.FrozenHeight.IsZero())
+	require.Equal(t, subject.TrustingPeriod, substitute.TrustingPeriod)
+	require.Equal(t, "osmosis-1", subject.ChainId, "subject must not be modified")
+
+	substitute = substituteClientState(subject, "osmosis-1", height, 5*24*time.Hour)
+	require.True(t, tmclient.IsMatchingClientState(*subject, *substitute))
+	require.Equal(t, 5*24*time.Hour, substitute.TrustingPeriod)
+}
+
+func TestSubstituteNeedsUpdate(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	health := &ClientHealth{
+		ClientState: &tmclient.ClientState{TrustingPeriod: 9 * time.Hour},
+		Updated:     now.Add(-2 * time.Hour),
+	}
+	require.True(t, health.Active())
+	require.False(t, substituteNeedsUpdate(health, now))
+
+	health.Updated = now.Add(-3 * time.Hour)
+	require.True(t, substituteNeedsUpdate(health, now))
+
+	health.Frozen = true
+	require.False(t, health.Active())
+}
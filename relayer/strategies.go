@@ -10,7 +10,10 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/ibc-go/v5/modules/core/04-channel/types"
+	"github.com/cosmos/relayer/v2/relayer/alert"
 	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/chains/plugin"
+	"github.com/cosmos/relayer/v2/relayer/chaos"
 	"github.com/cosmos/relayer/v2/relayer/processor"
 	"go.uber.org/zap"
 )
@@ -39,15 +42,24 @@ func StartRelayer(
 	processorType string,
 	initialBlockHistory uint64,
 	metrics *processor.PrometheusMetrics,
+	notifier *alert.Notifier,
+	health *processor.Health,
+	states *processor.StateRegistry,
+	clientUpgrades *ClientUpgradeConfig,
 ) chan error {
 	errorChan := make(chan error, 1)
 
 	switch processorType {
 	case ProcessorEvents:
+		var upgrader *clientUpgrader
+		if clientUpgrades != nil {
+			upgrader = newClientUpgrader(log, chains, paths, memo, *clientUpgrades)
+		}
+
 		chainProcessors := make([]processor.ChainProcessor, 0, len(chains))
 
 		for _, chain := range chains {
-			chainProcessors = append(chainProcessors, chain.chainProcessor(log, metrics))
+			chainProcessors = append(chainProcessors, chain.chainProcessor(log, metrics, notifier, health, upgrader))
 		}
 
 		ePaths := make([]path, len(paths))
@@ -70,7 +82,7 @@ func StartRelayer(
 			}
 		}
 
-		go relayerStartEventProcessor(ctx, log, chainProcessors, ePaths, initialBlockHistory, maxTxSize, maxMsgLength, memo, clientUpdateThresholdTime, errorChan, metrics)
+		go relayerStartEventProcessor(ctx, log, chainProcessors, ePaths, initialBlockHistory, maxTxSize, maxMsgLength, memo, clientUpdateThresholdTime, errorChan, metrics, notifier, health, states)
 		return errorChan
 	case ProcessorLegacy:
 		if len(paths) != 1 {
@@ -95,11 +107,26 @@ type path struct {
 }
 
 // chainProcessor returns the corresponding ChainProcessor implementation instance for a pathChain.
-func (chain *Chain) chainProcessor(log *zap.Logger, metrics *processor.PrometheusMetrics) processor.ChainProcessor {
+// If upgrader is not nil, the clients tracking the chain are upgraded after its scheduled upgrades, if supported.
+func (chain *Chain) chainProcessor(log *zap.Logger, metrics *processor.PrometheusMetrics, notifier *alert.Notifier, health *processor.Health, upgrader *clientUpgrader) processor.ChainProcessor {
 	// Handle new ChainProcessor implementations as cases here
 	switch p := chain.ChainProvider.(type) {
 	case *cosmos.CosmosProvider:
-		return cosmos.NewCosmosChainProcessor(log, p, metrics)
+		ccp := cosmos.NewCosmosChainProcessor(log, p, metrics)
+		ccp.SetNotifier(notifier)
+		ccp.SetHealth(health)
+		if upgrader != nil {
+			ccp.SetUpgradeHandler(upgrader.upgradeClients)
+		}
+		return ccp
+	case *chaos.Provider:
+		wrapped := *chain
+		wrapped.ChainProvider = p.Unwrap()
+		return chaos.NewChainProcessor(wrapped.chainProcessor(log, metrics, notifier, health, upgrader), p)
+	case *plugin.Provider:
+		pcp := plugin.NewChainProcessor(log, p)
+		pcp.SetHealth(health)
+		return pcp
 	default:
 		panic(fmt.Errorf("unsupported chain provider type: %T", chain.ChainProvider))
 	}
@@ -118,21 +145,27 @@ func relayerStartEventProcessor(
 	clientUpdateThresholdTime time.Duration,
 	errCh chan<- error,
 	metrics *processor.PrometheusMetrics,
+	notifier *alert.Notifier,
+	health *processor.Health,
+	states *processor.StateRegistry,
 ) {
 	defer close(errCh)
 
 	epb := processor.NewEventProcessor().WithChainProcessors(chainProcessors...)
 
 	for _, p := range paths {
-		epb = epb.
-			WithPathProcessors(processor.NewPathProcessor(
-				log,
-				p.src,
-				p.dst,
-				metrics,
-				memo,
-				clientUpdateThresholdTime,
-			))
+		pp := processor.NewPathProcessor(
+			log,
+			p.src,
+			p.dst,
+			metrics,
+			memo,
+			clientUpdateThresholdTime,
+		)
+		pp.SetNotifier(notifier)
+		pp.SetHealth(health)
+		pp.SetStateRegistry(states)
+		epb = epb.WithPathProcessors(pp)
 	}
 
 	ep := epb.
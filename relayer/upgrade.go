@@ -0,0 +1,117 @@
+package relayer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// ClientUpgradeConfig enables StartRelayer to upgrade the counterparty clients of the relayed chains
+// after their scheduled upgrades with an upgraded client state.
+type ClientUpgradeConfig struct {
+	// OnChainIDChange is called once the clients of a chain which restarted under a new chain ID were upgraded,
+	// e.g. to update the chain ID in the config. The relayer then stops relaying, since paths are relayed by chain ID.
+	OnChainIDChange func(oldChainID, newChainID string) error
+}
+
+// clientUpgrader upgrades the clients tracking an upgraded chain on the other ends of the relayed paths.
+type clientUpgrader struct {
+	log    *zap.Logger
+	chains map[string]*Chain
+	paths  []NamedPath
+	memo   string
+	config ClientUpgradeConfig
+
+	mu sync.Mutex
+	// upgraded holds the clients already upgraded, by plan name, counterparty chain ID and client ID,
+	// so that they are skipped when retrying after some of the clients failed to be upgraded.
+	upgraded map[string]bool
+}
+
+func newClientUpgrader(log *zap.Logger, chains map[string]*Chain, paths []NamedPath, memo string, config ClientUpgradeConfig) *clientUpgrader {
+	return &clientUpgrader{
+		log:      log,
+		chains:   chains,
+		paths:    paths,
+		memo:     memo,
+		config:   config,
+		upgraded: make(map[string]bool),
+	}
+}
+
+// upgradeClients submits MsgUpgradeClient for every counterparty client tracking the upgraded chain on the relayed paths.
+func (u *clientUpgrader) upgradeClients(ctx context.Context, upgrade processor.ChainUpgrade) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	upgraded, ok := u.chains[upgrade.ChainID]
+	if !ok {
+		return fmt.Errorf("chain %s is not relayed", upgrade.ChainID)
+	}
+
+	var errs error
+	for _, np := range u.paths {
+		var pe, counterpartyPE *PathEnd
+		switch upgrade.ChainID {
+		case np.Path.Src.ChainID:
+			pe, counterpartyPE = np.Path.Src, np.Path.Dst
+		case np.Path.Dst.ChainID:
+			pe, counterpartyPE = np.Path.Dst, np.Path.Src
+		default:
+			continue
+		}
+
+		key := upgrade.PlanName + "/" + counterpartyPE.ChainID + "/" + counterpartyPE.ClientID
+		if u.upgraded[key] {
+			continue
+		}
+		counterparty, ok := u.chains[counterpartyPE.ChainID]
+		if !ok {
+			continue
+		}
+
+		// The chains are shared by the paths, so the path ends are set on copies of them.
+		src, dst := *upgraded, *counterparty
+		src.PathEnd, dst.PathEnd = pe, counterpartyPE
+		if err := upgradeClient(ctx, &src, &dst, upgrade, u.memo); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to upgrade client %s on chain %s of path %s: %w",
+				counterpartyPE.ClientID, counterpartyPE.ChainID, np.Name, err))
+			continue
+		}
+		u.upgraded[key] = true
+		u.log.Info("Upgraded client after chain upgrade",
+			zap.String("path_name", np.Name),
+			zap.String("upgraded_chain_id", upgrade.ChainID),
+			zap.String("chain_id", counterpartyPE.ChainID),
+			zap.String("client_id", counterpartyPE.ClientID),
+			zap.String("plan", upgrade.PlanName),
+			zap.Int64("plan_height", upgrade.PlanHeight),
+		)
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if upgrade.RevisionChanged() && u.config.OnChainIDChange != nil {
+		return u.config.OnChainIDChange(upgrade.ChainID, upgrade.NewChainID)
+	}
+	return nil
+}
+
+// upgradeClient upgrades the client on dst with the upgraded client and consensus states of src,
+// queried before the upgrade cleared them, or queried at the last height before the upgrade if they were not.
+func upgradeClient(ctx context.Context, src, dst *Chain, upgrade processor.ChainUpgrade, memo string) error {
+	if upgrade.UpgradedClient == nil || upgrade.UpgradedConsState == nil {
+		return UpgradeClient(ctx, src, dst, upgrade.PlanHeight, memo)
+	}
+
+	dsth, err := dst.ChainProvider.QueryLatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+	return sendUpgradeClient(ctx, src, dst, upgrade.PlanHeight, dsth, upgrade.UpgradedClient, upgrade.UpgradedConsState, memo)
+}
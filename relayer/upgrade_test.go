@@ -0,0 +1,39 @@
+package relayer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cosmos/relayer/v2/relayer/processor"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClientUpgraderOnChainIDChange(t *testing.T) {
+	type chainIDChange struct {
+		oldChainID, newChainID string
+	}
+	var changes []chainIDChange
+
+	u := newClientUpgrader(zap.NewNop(), map[string]*Chain{"osmosis-1": {}}, nil, "", ClientUpgradeConfig{
+		OnChainIDChange: func(oldChainID, newChainID string) error {
+			changes = append(changes, chainIDChange{oldChainID: oldChainID, newChainID: newChainID})
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+
+	// Same revision, the config keeps the chain ID.
+	require.NoError(t, u.upgradeClients(ctx, processor.ChainUpgrade{
+		PlanName: "v2", PlanHeight: 100, ChainID: "osmosis-1", NewChainID: "osmosis-1",
+	}))
+	require.Empty(t, changes)
+
+	require.NoError(t, u.upgradeClients(ctx, processor.ChainUpgrade{
+		PlanName: "v3", PlanHeight: 200, ChainID: "osmosis-1", NewChainID: "osmosis-2",
+	}))
+	require.Equal(t, []chainIDChange{{oldChainID: "osmosis-1", newChainID: "osmosis-2"}}, changes)
+
+	require.Error(t, u.upgradeClients(ctx, processor.ChainUpgrade{ChainID: "cosmoshub-4", NewChainID: "cosmoshub-5"}))
+}